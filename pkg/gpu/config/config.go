@@ -0,0 +1,159 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads the configuration of a GPU deployment from a file
+package config
+
+import (
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/silogen/kaiwo/pkg/gpu/coordinator"
+	"github.com/silogen/kaiwo/pkg/gpu/manager"
+	"github.com/silogen/kaiwo/pkg/gpu/reservation"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// DeploymentConfig is the configuration of every GPU subsystem in a deployment
+type DeploymentConfig struct {
+	GPUManager  manager.GPUManagerConfig
+	Reservation reservation.ReservationManagerConfig
+	Coordinator coordinator.Config
+}
+
+// fileConfig is the on-disk form of a DeploymentConfig. Durations are written as
+// strings such as "30s" or "1h30m".
+type fileConfig struct {
+	GPUManager  gpuManagerFileConfig  `json:"gpuManager"`
+	Reservation reservationFileConfig `json:"reservation"`
+	Coordinator coordinatorFileConfig `json:"coordinator"`
+}
+
+type gpuManagerFileConfig struct {
+	GPUType               types.GPUType            `json:"gpuType"`
+	PollingInterval       metav1.Duration          `json:"pollingInterval"`
+	AllocationTimeout     metav1.Duration          `json:"allocationTimeout"`
+	DefaultStrategy       types.AllocationStrategy `json:"defaultStrategy"`
+	EnableSharing         bool                     `json:"enableSharing"`
+	MaxFraction           float64                  `json:"maxFraction"`
+	MinFraction           float64                  `json:"minFraction"`
+	AllowedIsolationTypes []types.GPUIsolationType `json:"allowedIsolationTypes"`
+	NodeSelector          map[string]string        `json:"nodeSelector,omitempty"`
+	EnableMockGPUs        bool                     `json:"enableMockGpus,omitempty"`
+	Health                manager.HealthConfig     `json:"health,omitempty"`
+}
+
+type reservationFileConfig struct {
+	MaxReservationsPerGPU    int                      `json:"maxReservationsPerGpu"`
+	MaxReservationsPerUser   int                      `json:"maxReservationsPerUser"`
+	DefaultReservationWindow metav1.Duration          `json:"defaultReservationWindow"`
+	ConflictResolutionPolicy string                   `json:"conflictResolutionPolicy"`
+	EnablePreemption         bool                     `json:"enablePreemption"`
+	MaxReservationDuration   metav1.Duration          `json:"maxReservationDuration"`
+	CleanupInterval          metav1.Duration          `json:"cleanupInterval"`
+	ActivationInterval       metav1.Duration          `json:"activationInterval"`
+	ReservationIDTemplate    string                   `json:"reservationIdTemplate"`
+	EnableWaitlist           bool                     `json:"enableWaitlist"`
+	StorePath                string                   `json:"storePath,omitempty"` // Reservations are kept in memory only when empty
+	CapacityPartitions       types.CapacityPartitions `json:"capacityPartitions,omitempty"`
+}
+
+type coordinatorFileConfig struct {
+	ReservationDuration      metav1.Duration `json:"reservationDuration"`
+	IdleUtilizationThreshold float64         `json:"idleUtilizationThreshold"`
+}
+
+// LoadConfig parses a YAML or JSON deployment configuration, applies the defaults
+// the subsystem constructors apply and validates the result. Unknown fields are
+// rejected.
+func LoadConfig(r io.Reader) (*DeploymentConfig, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var file fileConfig
+	if err := k8syaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	config := &DeploymentConfig{
+		GPUManager: manager.GPUManagerConfig{
+			GPUType:               file.GPUManager.GPUType,
+			PollingInterval:       file.GPUManager.PollingInterval.Duration,
+			AllocationTimeout:     file.GPUManager.AllocationTimeout.Duration,
+			DefaultStrategy:       file.GPUManager.DefaultStrategy,
+			EnableSharing:         file.GPUManager.EnableSharing,
+			MaxFraction:           file.GPUManager.MaxFraction,
+			MinFraction:           file.GPUManager.MinFraction,
+			AllowedIsolationTypes: file.GPUManager.AllowedIsolationTypes,
+			NodeSelector:          file.GPUManager.NodeSelector,
+			EnableMockGPUs:        file.GPUManager.EnableMockGPUs,
+			Health:                file.GPUManager.Health,
+		},
+		Reservation: reservation.ReservationManagerConfig{
+			MaxReservationsPerGPU:    file.Reservation.MaxReservationsPerGPU,
+			MaxReservationsPerUser:   file.Reservation.MaxReservationsPerUser,
+			DefaultReservationWindow: file.Reservation.DefaultReservationWindow.Duration,
+			ConflictResolutionPolicy: file.Reservation.ConflictResolutionPolicy,
+			EnablePreemption:         file.Reservation.EnablePreemption,
+			MaxReservationDuration:   file.Reservation.MaxReservationDuration.Duration,
+			CleanupInterval:          file.Reservation.CleanupInterval.Duration,
+			ActivationInterval:       file.Reservation.ActivationInterval.Duration,
+			ReservationIDTemplate:    file.Reservation.ReservationIDTemplate,
+			EnableWaitlist:           file.Reservation.EnableWaitlist,
+			CapacityPartitions:       file.Reservation.CapacityPartitions,
+		},
+		Coordinator: coordinator.Config{
+			ReservationDuration:      file.Coordinator.ReservationDuration.Duration,
+			IdleUtilizationThreshold: file.Coordinator.IdleUtilizationThreshold,
+		},
+	}
+	if file.Reservation.StorePath != "" {
+		config.Reservation.Store = reservation.NewFileReservationStore(file.Reservation.StorePath)
+	}
+
+	reservation.SetReservationManagerConfigDefaults(&config.Reservation)
+	coordinator.SetConfigDefaults(&config.Coordinator)
+
+	if err := ValidateDeploymentConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return config, nil
+}
+
+// ValidateDeploymentConfig validates the configuration of every subsystem in a deployment
+func ValidateDeploymentConfig(config *DeploymentConfig) error {
+	if config == nil {
+		return fmt.Errorf("configuration cannot be nil")
+	}
+
+	if err := manager.ValidateGPUManagerConfig(&config.GPUManager); err != nil {
+		return fmt.Errorf("gpuManager: %w", err)
+	}
+
+	if err := reservation.ValidateReservationManagerConfig(&config.Reservation); err != nil {
+		return fmt.Errorf("reservation: %w", err)
+	}
+
+	if err := coordinator.ValidateConfig(&config.Coordinator); err != nil {
+		return fmt.Errorf("coordinator: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/reservation"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// minimalGPUManager is a gpuManager section setting only the required fields
+const minimalGPUManager = `
+gpuManager:
+  gpuType: amd
+  pollingInterval: 30s
+  allocationTimeout: 5m
+  defaultStrategy: first-fit
+  maxFraction: 1.0
+  minFraction: 0.1
+  allowedIsolationTypes: [time-slicing]
+`
+
+func TestLoadConfig(t *testing.T) {
+	input := `{
+  "gpuManager": {
+    "gpuType": "amd",
+    "pollingInterval": "10s",
+    "allocationTimeout": "2m",
+    "defaultStrategy": "best-fit",
+    "enableSharing": true,
+    "maxFraction": 0.75,
+    "minFraction": 0.25,
+    "allowedIsolationTypes": ["time-slicing", "none"],
+    "nodeSelector": {"gpu": "mi300x"}
+  },
+  "reservation": {
+    "maxReservationsPerGpu": 4,
+    "maxReservationsPerUser": 2,
+    "defaultReservationWindow": "12h",
+    "conflictResolutionPolicy": "flexible",
+    "enablePreemption": true,
+    "maxReservationDuration": "48h",
+    "cleanupInterval": "30m",
+    "activationInterval": "15s",
+    "reservationIdTemplate": "{tenant}-res-{uuid}",
+    "enableWaitlist": true,
+    "capacityPartitions": {"15": 0.25}
+  },
+  "coordinator": {
+    "reservationDuration": "90m",
+    "idleUtilizationThreshold": 10
+  }
+}`
+
+	config, err := LoadConfig(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	gpuManager := config.GPUManager
+	if gpuManager.GPUType != types.GPUTypeAMD || gpuManager.PollingInterval != 10*time.Second ||
+		gpuManager.AllocationTimeout != 2*time.Minute || gpuManager.DefaultStrategy != types.AllocationStrategyBestFit ||
+		!gpuManager.EnableSharing || gpuManager.MaxFraction != 0.75 || gpuManager.MinFraction != 0.25 ||
+		len(gpuManager.AllowedIsolationTypes) != 2 || gpuManager.NodeSelector["gpu"] != "mi300x" {
+		t.Errorf("Unexpected GPU manager config: %+v", gpuManager)
+	}
+
+	reservations := config.Reservation
+	if reservations.MaxReservationsPerGPU != 4 || reservations.MaxReservationsPerUser != 2 ||
+		reservations.DefaultReservationWindow != 12*time.Hour ||
+		reservations.ConflictResolutionPolicy != reservation.ConflictResolutionPolicyFlexible ||
+		!reservations.EnablePreemption || reservations.MaxReservationDuration != 48*time.Hour ||
+		reservations.CleanupInterval != 30*time.Minute || reservations.ActivationInterval != 15*time.Second ||
+		reservations.ReservationIDTemplate != "{tenant}-res-{uuid}" || !reservations.EnableWaitlist ||
+		reservations.CapacityPartitions[15] != 0.25 {
+		t.Errorf("Unexpected reservation config: %+v", reservations)
+	}
+
+	if config.Coordinator.ReservationDuration != 90*time.Minute || config.Coordinator.IdleUtilizationThreshold != 10 {
+		t.Errorf("Unexpected coordinator config: %+v", config.Coordinator)
+	}
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	config, err := LoadConfig(strings.NewReader(minimalGPUManager))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	reservations := config.Reservation
+	if reservations.MaxReservationsPerGPU != 10 || reservations.MaxReservationsPerUser != 5 ||
+		reservations.DefaultReservationWindow != 24*time.Hour ||
+		reservations.ConflictResolutionPolicy != reservation.ConflictResolutionPolicyStrict ||
+		reservations.MaxReservationDuration != 7*24*time.Hour || reservations.CleanupInterval != time.Hour ||
+		reservations.ActivationInterval != time.Minute ||
+		reservations.ReservationIDTemplate != reservation.DefaultReservationIDTemplate {
+		t.Errorf("Expected reservation defaults, got %+v", reservations)
+	}
+	if reservations.Store != nil {
+		t.Error("Expected no reservation store without a store path")
+	}
+
+	if config.Coordinator.ReservationDuration != time.Hour || config.Coordinator.IdleUtilizationThreshold != 5.0 {
+		t.Errorf("Expected coordinator defaults, got %+v", config.Coordinator)
+	}
+}
+
+func TestLoadConfigRejectsUnknownField(t *testing.T) {
+	input := minimalGPUManager + `
+reservation:
+  maxReservationsPerGpu: 4
+  maxReservationsPerTenant: 2
+`
+
+	_, err := LoadConfig(strings.NewReader(input))
+	if err == nil {
+		t.Fatal("Expected error for unknown field")
+	}
+	if !strings.Contains(err.Error(), "maxReservationsPerTenant") {
+		t.Errorf("Expected error to name the unknown field, got %v", err)
+	}
+}
+
+func TestLoadConfigValidates(t *testing.T) {
+	input := minimalGPUManager + `
+reservation:
+  conflictResolutionPolicy: first-come
+`
+
+	if _, err := LoadConfig(strings.NewReader(input)); err == nil {
+		t.Error("Expected error for unknown conflict resolution policy")
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/manager"
+	"github.com/silogen/kaiwo/pkg/gpu/reservation"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// fractionTolerance absorbs floating point error when summing GPU fractions
+const fractionTolerance = 1e-9
+
+// AllocateOrReservePath identifies how an AllocateOrReserve request was satisfied
+type AllocateOrReservePath string
+
+const (
+	// AllocateOrReservePathAllocated means the GPU was allocated immediately
+	AllocateOrReservePathAllocated AllocateOrReservePath = "allocated"
+	// AllocateOrReservePathReserved means a reservation was created for a later slot
+	AllocateOrReservePathReserved AllocateOrReservePath = "reserved"
+)
+
+// AllocateOrReserveResult is the outcome of AllocateOrReserve
+type AllocateOrReserveResult struct {
+	// Path is the path that was taken
+	Path AllocateOrReservePath `json:"path"`
+
+	// Allocation is set when the GPU was allocated immediately
+	Allocation *types.AllocationResult `json:"allocation,omitempty"`
+
+	// Reservation is set when a reservation was created instead
+	Reservation *reservation.GPUReservation `json:"reservation,omitempty"`
+
+	// AllocationError is why immediate allocation failed, when a reservation was made
+	AllocationError string `json:"allocationError,omitempty"`
+}
+
+// Config contains configuration for the coordinator
+type Config struct {
+	// ReservationDuration is how long fallback reservations last when the
+	// request has no expiry of its own
+	ReservationDuration time.Duration `json:"reservationDuration"`
+
+	// IdleUtilizationThreshold is the utilization percentage below which an
+	// allocated GPU is reported as idle
+	IdleUtilizationThreshold float64 `json:"idleUtilizationThreshold"`
+}
+
+// PlacementMatcher is implemented by GPU managers that resolve which GPUs a
+// request's GPU type and node selector allow, such as the AMD and NVIDIA
+// managers with their node label sources
+type PlacementMatcher interface {
+	// MatchPlacement returns the GPUs, of those given, the request may be placed on
+	MatchPlacement(ctx context.Context, request *types.AllocationRequest, gpus []*types.GPUInfo) []*types.GPUInfo
+}
+
+// Coordinator bridges immediate GPU allocation and future reservations
+type Coordinator struct {
+	manager      manager.GPUManager
+	reservations *reservation.GPUReservationManager
+	config       Config
+
+	// utilizationSource provides GPU utilization; the manager is used when nil
+	utilizationSource UtilizationSource
+
+	// now returns the current time, replaceable in tests
+	now func() time.Time
+}
+
+// SetConfigDefaults fills unset fields of a coordinator configuration with their defaults
+func SetConfigDefaults(config *Config) {
+	if config.ReservationDuration == 0 {
+		config.ReservationDuration = time.Hour
+	}
+	if config.IdleUtilizationThreshold == 0 {
+		config.IdleUtilizationThreshold = 5.0
+	}
+}
+
+// ValidateConfig validates a coordinator configuration after defaults have been applied
+func ValidateConfig(config *Config) error {
+	if config.ReservationDuration <= 0 {
+		return fmt.Errorf("reservation duration must be positive, got %v", config.ReservationDuration)
+	}
+	if config.IdleUtilizationThreshold < 0 || config.IdleUtilizationThreshold > 100 {
+		return fmt.Errorf("idle utilization threshold must be between 0 and 100, got %f", config.IdleUtilizationThreshold)
+	}
+	return nil
+}
+
+// NewCoordinator creates a new coordinator over a GPU manager and a reservation manager
+func NewCoordinator(gpuManager manager.GPUManager, reservations *reservation.GPUReservationManager, config Config) *Coordinator {
+	SetConfigDefaults(&config)
+
+	return &Coordinator{
+		manager:      gpuManager,
+		reservations: reservations,
+		config:       config,
+		now:          time.Now,
+	}
+}
+
+// AllocateOrReserve allocates a GPU immediately if capacity is free and otherwise
+// reserves the earliest slot that opens up within maxWait. Only a lack of
+// capacity, manager.ErrInsufficientCapacity, falls back to a reservation; any
+// other allocation error is returned as is.
+func (c *Coordinator) AllocateOrReserve(ctx context.Context, request *types.AllocationRequest, maxWait time.Duration) (*AllocateOrReserveResult, error) {
+	if request == nil || request.GPURequest == nil {
+		return nil, fmt.Errorf("allocation request and GPU request cannot be nil")
+	}
+
+	result, err := c.manager.AllocateGPU(ctx, request)
+	if err == nil {
+		return &AllocateOrReserveResult{
+			Path:       AllocateOrReservePathAllocated,
+			Allocation: result,
+		}, nil
+	}
+	if !errors.Is(err, manager.ErrInsufficientCapacity) {
+		return nil, err
+	}
+
+	created, reserveErr := c.reserveNextSlot(ctx, request, maxWait)
+	if reserveErr != nil {
+		return nil, fmt.Errorf("allocation failed (%v) and no reservation slot found: %w", err, reserveErr)
+	}
+
+	return &AllocateOrReserveResult{
+		Path:            AllocateOrReservePathReserved,
+		Reservation:     created,
+		AllocationError: err.Error(),
+	}, nil
+}
+
+// reserveNextSlot creates a reservation at the earliest time within maxWait at
+// which a GPU matching the request's placement has room for it. Allocation
+// requests carry no user, so the reservation is made for the request's
+// namespace and per-user reservation limits apply per namespace.
+func (c *Coordinator) reserveNextSlot(ctx context.Context, request *types.AllocationRequest, maxWait time.Duration) (*reservation.GPUReservation, error) {
+	now := c.now()
+	deadline := now.Add(maxWait)
+	duration := c.config.ReservationDuration
+	if request.ExpiresAt != nil && request.ExpiresAt.After(request.CreatedAt) && !request.CreatedAt.IsZero() {
+		duration = request.ExpiresAt.Sub(request.CreatedAt)
+	}
+
+	gpus, err := c.manager.ListGPUs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GPUs: %w", err)
+	}
+	gpus = c.matchPlacement(ctx, request, gpus)
+
+	allocations, err := c.manager.ListAllocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+
+	var lastErr error
+	for _, slot := range c.candidateSlots(gpus, allocations, now, deadline) {
+		if !fitsAt(slot.deviceID, allocations, request.GPURequest.Fraction, slot.start) {
+			continue
+		}
+
+		created, err := c.reservations.CreateReservation(ctx, &reservation.ReservationRequest{
+			TenantID:       request.GPURequest.TenantID,
+			UserID:         request.Namespace,
+			WorkloadID:     request.ID,
+			GPUID:          slot.deviceID,
+			Fraction:       request.GPURequest.Fraction,
+			MemoryRequest:  request.GPURequest.MemoryRequest,
+			StartTime:      slot.start,
+			Duration:       duration,
+			Priority:       reservation.ReservationPriority(request.Priority),
+			IsolationType:  string(request.GPURequest.IsolationType),
+			SharingEnabled: request.GPURequest.SharingEnabled,
+		})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return created, nil
+	}
+
+	if lastErr != nil {
+		return nil, fmt.Errorf("no slot within %v: %w", maxWait, lastErr)
+	}
+	return nil, fmt.Errorf("no slot within %v", maxWait)
+}
+
+// matchPlacement returns the GPUs the request's GPU type and node selector
+// allow, matching them as the manager does when it can and otherwise against
+// node hostnames only
+func (c *Coordinator) matchPlacement(ctx context.Context, request *types.AllocationRequest, gpus []*types.GPUInfo) []*types.GPUInfo {
+	if matcher, ok := c.manager.(PlacementMatcher); ok {
+		return matcher.MatchPlacement(ctx, request, gpus)
+	}
+	return manager.MatchPlacement(ctx, nil, request, gpus)
+}
+
+// slot is a candidate start time on a GPU
+type slot struct {
+	deviceID string
+	start    time.Time
+}
+
+// candidateSlots returns the times within (now, deadline] at which capacity may free
+// up on each GPU, earliest first: when allocations expire and when reservations end
+func (c *Coordinator) candidateSlots(gpus []*types.GPUInfo, allocations []*types.GPUAllocation, now, deadline time.Time) []slot {
+	var slots []slot
+
+	addSlot := func(deviceID string, start time.Time) {
+		if start.After(now) && !start.After(deadline) {
+			slots = append(slots, slot{deviceID: deviceID, start: start})
+		}
+	}
+
+	for _, gpu := range gpus {
+		for _, allocation := range allocations {
+			if allocation.DeviceID == gpu.DeviceID && allocation.ExpiresAt > 0 {
+				addSlot(gpu.DeviceID, time.Unix(allocation.ExpiresAt, 0))
+			}
+		}
+
+		existingReservations, _ := c.reservations.ListReservations(&reservation.ReservationFilters{GPUID: gpu.DeviceID})
+		for _, existing := range existingReservations {
+			if existing.Status == reservation.ReservationStatusPending || existing.Status == reservation.ReservationStatusActive {
+				addSlot(gpu.DeviceID, existing.EndTime)
+			}
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].start.Before(slots[j].start)
+	})
+
+	return slots
+}
+
+// fitsAt reports whether a fraction fits on a GPU alongside the allocations that
+// will still be held at the given time
+func fitsAt(deviceID string, allocations []*types.GPUAllocation, fraction float64, at time.Time) bool {
+	used := fraction
+	for _, allocation := range allocations {
+		if allocation.DeviceID != deviceID || allocation.Status != types.GPUAllocationStatusActive {
+			continue
+		}
+		if allocation.ExpiresAt > 0 && !time.Unix(allocation.ExpiresAt, 0).After(at) {
+			continue
+		}
+		used += allocation.Fraction
+	}
+	return used <= 1.0+fractionTolerance
+}
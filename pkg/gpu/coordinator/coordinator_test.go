@@ -0,0 +1,217 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/manager"
+	"github.com/silogen/kaiwo/pkg/gpu/reservation"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// fakeGPUManager is a single-node GPU manager that places allocations by fraction
+type fakeGPUManager struct {
+	manager.GPUManager
+
+	gpus        []*types.GPUInfo
+	allocations []*types.GPUAllocation
+
+	// allocateErr, when set, fails every allocation
+	allocateErr error
+}
+
+func (f *fakeGPUManager) ListGPUs(ctx context.Context) ([]*types.GPUInfo, error) {
+	return f.gpus, nil
+}
+
+func (f *fakeGPUManager) ListAllocations(ctx context.Context) ([]*types.GPUAllocation, error) {
+	return f.allocations, nil
+}
+
+func (f *fakeGPUManager) AllocateGPU(ctx context.Context, request *types.AllocationRequest) (*types.AllocationResult, error) {
+	if f.allocateErr != nil {
+		return nil, f.allocateErr
+	}
+
+	for _, gpu := range f.gpus {
+		used := 0.0
+		for _, allocation := range f.allocations {
+			if allocation.DeviceID == gpu.DeviceID {
+				used += allocation.Fraction
+			}
+		}
+
+		if used+request.GPURequest.Fraction <= 1.0 {
+			allocation := &types.GPUAllocation{
+				ID:       request.ID,
+				DeviceID: gpu.DeviceID,
+				Fraction: request.GPURequest.Fraction,
+				Status:   types.GPUAllocationStatusActive,
+			}
+			f.allocations = append(f.allocations, allocation)
+			return &types.AllocationResult{Success: true, Allocation: allocation, DeviceID: gpu.DeviceID}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no suitable GPU found: %w", manager.ErrInsufficientCapacity)
+}
+
+func newTestCoordinator(t *testing.T, gpuManager manager.GPUManager) (*Coordinator, *reservation.GPUReservationManager) {
+	t.Helper()
+
+	reservations, err := reservation.NewGPUReservationManager(reservation.ReservationManagerConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create reservation manager: %v", err)
+	}
+	t.Cleanup(reservations.Stop)
+
+	return NewCoordinator(gpuManager, reservations, Config{}), reservations
+}
+
+func newTestRequest(id string, fraction float64) *types.AllocationRequest {
+	return &types.AllocationRequest{
+		ID:        id,
+		PodName:   "pod-" + id,
+		Namespace: "default",
+		GPURequest: &types.GPURequest{
+			Fraction:       fraction,
+			IsolationType:  types.GPUIsolationTimeSlicing,
+			SharingEnabled: true,
+		},
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestAllocateOrReserveAllocatesImmediately(t *testing.T) {
+	gpuManager := &fakeGPUManager{gpus: []*types.GPUInfo{{DeviceID: "card0"}}}
+	coordinator, reservations := newTestCoordinator(t, gpuManager)
+
+	result, err := coordinator.AllocateOrReserve(context.Background(), newTestRequest("job-1", 0.5), time.Hour)
+	if err != nil {
+		t.Fatalf("AllocateOrReserve failed: %v", err)
+	}
+
+	if result.Path != AllocateOrReservePathAllocated {
+		t.Errorf("Expected allocated path, got %s", result.Path)
+	}
+
+	if result.Allocation == nil || result.Allocation.DeviceID != "card0" {
+		t.Errorf("Expected allocation on card0, got %+v", result.Allocation)
+	}
+
+	if got, _ := reservations.ListReservations(nil); len(got) != 0 {
+		t.Errorf("Expected no reservations, got %d", len(got))
+	}
+}
+
+func TestAllocateOrReserveFallsBackToReservation(t *testing.T) {
+	now := time.Now()
+	gpuManager := &fakeGPUManager{
+		gpus: []*types.GPUInfo{{DeviceID: "card0"}},
+		allocations: []*types.GPUAllocation{
+			{ID: "short", DeviceID: "card0", Fraction: 0.5, Status: types.GPUAllocationStatusActive, ExpiresAt: now.Add(20 * time.Minute).Unix()},
+			{ID: "long", DeviceID: "card0", Fraction: 0.5, Status: types.GPUAllocationStatusActive, ExpiresAt: now.Add(3 * time.Hour).Unix()},
+		},
+	}
+	coordinator, _ := newTestCoordinator(t, gpuManager)
+	coordinator.now = func() time.Time { return now }
+
+	result, err := coordinator.AllocateOrReserve(context.Background(), newTestRequest("job-1", 0.5), time.Hour)
+	if err != nil {
+		t.Fatalf("AllocateOrReserve failed: %v", err)
+	}
+
+	if result.Path != AllocateOrReservePathReserved {
+		t.Fatalf("Expected reserved path, got %s", result.Path)
+	}
+
+	if result.AllocationError == "" {
+		t.Error("Expected the allocation error to be reported")
+	}
+
+	// The earliest slot is when the short allocation expires
+	expectedStart := time.Unix(now.Add(20*time.Minute).Unix(), 0)
+	if !result.Reservation.StartTime.Equal(expectedStart) {
+		t.Errorf("Expected reservation to start at %v, got %v", expectedStart, result.Reservation.StartTime)
+	}
+
+	if result.Reservation.GPUID != "card0" || result.Reservation.Fraction != 0.5 {
+		t.Errorf("Expected 0.5 of card0 reserved, got %s/%f", result.Reservation.GPUID, result.Reservation.Fraction)
+	}
+
+	// A full GPU request does not fit until the long allocation expires, past maxWait
+	if _, err := coordinator.AllocateOrReserve(context.Background(), newTestRequest("job-2", 1.0), time.Hour); err == nil {
+		t.Error("Expected error when no slot opens within maxWait")
+	}
+}
+
+func TestAllocateOrReserveReturnsNonCapacityErrors(t *testing.T) {
+	invalid := errors.New("invalid allocation request: fraction must be positive")
+	gpuManager := &fakeGPUManager{gpus: []*types.GPUInfo{{DeviceID: "card0"}}, allocateErr: invalid}
+	coordinator, reservations := newTestCoordinator(t, gpuManager)
+
+	_, err := coordinator.AllocateOrReserve(context.Background(), newTestRequest("job-1", 0.5), time.Hour)
+	if err != invalid {
+		t.Errorf("Expected the allocation error to be returned unchanged, got %v", err)
+	}
+	if got, _ := reservations.ListReservations(nil); len(got) != 0 {
+		t.Errorf("Expected no reservations, got %d", len(got))
+	}
+}
+
+func TestAllocateOrReserveMatchesPlacement(t *testing.T) {
+	now := time.Now()
+	full := func(id, deviceID string, expiresIn time.Duration) *types.GPUAllocation {
+		return &types.GPUAllocation{
+			ID:        id,
+			DeviceID:  deviceID,
+			Fraction:  1.0,
+			Status:    types.GPUAllocationStatusActive,
+			ExpiresAt: now.Add(expiresIn).Unix(),
+		}
+	}
+	gpuManager := &fakeGPUManager{
+		gpus: []*types.GPUInfo{
+			{DeviceID: "card0", NodeName: "node-a", Type: types.GPUTypeAMD},
+			{DeviceID: "card1", NodeName: "node-b", Type: types.GPUTypeAMD},
+			{DeviceID: "gpu0", NodeName: "node-b", Type: types.GPUTypeNVIDIA},
+		},
+		allocations: []*types.GPUAllocation{
+			full("a", "card0", 10*time.Minute),
+			full("b", "gpu0", 20*time.Minute),
+			full("c", "card1", 30*time.Minute),
+		},
+	}
+	coordinator, _ := newTestCoordinator(t, gpuManager)
+	coordinator.now = func() time.Time { return now }
+
+	// card0 and gpu0 free up first, but only card1 is an AMD GPU on node-b
+	request := newTestRequest("job-1", 1.0)
+	request.NodeSelector = map[string]string{"kubernetes.io/hostname": "node-b"}
+	request.GPUType = types.GPUTypeAMD
+
+	result, err := coordinator.AllocateOrReserve(context.Background(), request, time.Hour)
+	if err != nil {
+		t.Fatalf("AllocateOrReserve failed: %v", err)
+	}
+	if result.Reservation == nil || result.Reservation.GPUID != "card1" {
+		t.Errorf("Expected a reservation on card1, got %+v", result.Reservation)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/silogen/kaiwo/pkg/gpu/manager"
+	"github.com/silogen/kaiwo/pkg/gpu/reservation"
+)
+
+// Component is a subsystem whose background goroutines are managed by a Lifecycle
+type Component struct {
+	// Name identifies the component in errors
+	Name string
+
+	// Start launches the component's background goroutines. Nil if the component
+	// has nothing to start.
+	Start func(ctx context.Context) error
+
+	// Stop stops the component and blocks until its goroutines have exited or
+	// ctx is done. Nil if the component has nothing to stop.
+	Stop func(ctx context.Context) error
+}
+
+// Lifecycle starts components in dependency order and stops them in reverse
+type Lifecycle struct {
+	components []Component
+
+	// started is the number of leading components that have been started
+	started int
+
+	mu sync.Mutex
+}
+
+// NewLifecycle creates a lifecycle over components listed in dependency order,
+// each depending only on those before it
+func NewLifecycle(components ...Component) *Lifecycle {
+	return &Lifecycle{components: components}
+}
+
+// NewGPULifecycle creates a lifecycle for a GPU manager, the time-slicing
+// scheduler of the GPUs it shares and the reservation manager that allocates
+// from it
+func NewGPULifecycle(gpuManager manager.GPUManager, sharing *manager.AMDGPUSharing, reservations *reservation.GPUReservationManager) *Lifecycle {
+	return NewLifecycle(
+		Component{Name: "gpu-manager", Start: gpuManager.Initialize, Stop: gpuManager.Shutdown},
+		Component{Name: "gpu-sharing", Start: sharing.Start, Stop: sharing.Shutdown},
+		Component{Name: "reservation-manager", Stop: reservations.Shutdown},
+	)
+}
+
+// Start starts every component in order. If a component fails to start, the
+// components already started are stopped again.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.started > 0 {
+		return fmt.Errorf("lifecycle already started")
+	}
+
+	for _, component := range l.components {
+		if component.Start != nil {
+			if err := component.Start(ctx); err != nil {
+				startErr := fmt.Errorf("failed to start %s: %w", component.Name, err)
+				return errors.Join(startErr, l.stop(ctx))
+			}
+		}
+		l.started++
+	}
+
+	return nil
+}
+
+// Stop stops every started component in reverse order, continuing past failures,
+// and returns the aggregated stop errors
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stop(ctx)
+}
+
+// stop stops the started components in reverse order. Callers must hold l.mu.
+func (l *Lifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for ; l.started > 0; l.started-- {
+		component := l.components[l.started-1]
+		if component.Stop == nil {
+			continue
+		}
+		if err := component.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
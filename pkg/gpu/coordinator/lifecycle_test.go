@@ -0,0 +1,162 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/manager"
+	"github.com/silogen/kaiwo/pkg/gpu/reservation"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// monitoringGPUManager is a fake GPU manager that runs a monitoring goroutine
+// between Initialize and Shutdown
+type monitoringGPUManager struct {
+	fakeGPUManager
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (m *monitoringGPUManager) Initialize(ctx context.Context) error {
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (m *monitoringGPUManager) Shutdown(ctx context.Context) error {
+	close(m.stop)
+	<-m.done
+	return nil
+}
+
+func TestGPULifecycleStopsAllGoroutines(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	reservations, err := reservation.NewGPUReservationManager(reservation.ReservationManagerConfig{
+		CleanupInterval:    time.Millisecond,
+		ActivationInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create reservation manager: %v", err)
+	}
+
+	// A shared workload gives the time-slicing scheduler a loop to run
+	sharing := manager.NewAMDGPUSharing()
+	if err := sharing.RegisterGPU("card0", 8*1024*1024*1024); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+	if _, err := sharing.Allocate("card0", &types.AllocationRequest{
+		ID:         "workload-1",
+		GPURequest: &types.GPURequest{Fraction: 0.5, MemoryRequest: 512, SharingEnabled: true},
+	}); err != nil {
+		t.Fatalf("Failed to allocate shared GPU: %v", err)
+	}
+
+	lifecycle := NewGPULifecycle(&monitoringGPUManager{}, sharing, reservations)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := lifecycle.Start(ctx); err != nil {
+		t.Fatalf("Failed to start lifecycle: %v", err)
+	}
+	if err := lifecycle.Start(ctx); err == nil {
+		t.Error("Expected starting twice to fail")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if err := lifecycle.Stop(ctx); err != nil {
+		t.Fatalf("Failed to stop lifecycle: %v", err)
+	}
+
+	// Components wait for their goroutines, so only runtime bookkeeping can lag
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected goroutine count to return to %d, got %d", baseline, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLifecycleOrderAndErrors(t *testing.T) {
+	var events []string
+	component := func(name string, stopErr error) Component {
+		return Component{
+			Name: name,
+			Start: func(ctx context.Context) error {
+				events = append(events, "start "+name)
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				events = append(events, "stop "+name)
+				return stopErr
+			},
+		}
+	}
+
+	errFirst := errors.New("first failed")
+	errSecond := errors.New("second failed")
+	lifecycle := NewLifecycle(component("first", errFirst), component("second", errSecond), component("third", nil))
+
+	if err := lifecycle.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start lifecycle: %v", err)
+	}
+
+	err := lifecycle.Stop(context.Background())
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("Expected both stop errors to be aggregated, got %v", err)
+	}
+
+	expected := "start first,start second,start third,stop third,stop second,stop first"
+	if got := strings.Join(events, ","); got != expected {
+		t.Errorf("Expected events %q, got %q", expected, got)
+	}
+
+	// A failed start stops the components already started
+	events = nil
+	failing := Component{
+		Name:  "failing",
+		Start: func(ctx context.Context) error { return errors.New("boom") },
+	}
+	lifecycle = NewLifecycle(component("first", nil), failing, component("third", nil))
+	if err := lifecycle.Start(context.Background()); err == nil {
+		t.Fatal("Expected start to fail")
+	}
+	if got := strings.Join(events, ","); got != "start first,stop first" {
+		t.Errorf("Expected started components to be stopped on failure, got %q", got)
+	}
+}
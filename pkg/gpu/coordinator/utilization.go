@@ -0,0 +1,114 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// UtilizationSample is a point-in-time utilization reading for a GPU
+type UtilizationSample struct {
+	// Utilization is the GPU utilization percentage (0-100)
+	Utilization float64 `json:"utilization"`
+
+	// SampledAt is when the reading was taken
+	SampledAt time.Time `json:"sampledAt"`
+}
+
+// UtilizationSource provides the current utilization of GPUs, e.g. from a metrics collector
+type UtilizationSource interface {
+	// GetGPUUtilization returns the latest sample for each GPU, keyed by device ID
+	GetGPUUtilization(ctx context.Context) (map[string]UtilizationSample, error)
+}
+
+// AllocationUtilization is an active allocation joined with its GPU's current utilization
+type AllocationUtilization struct {
+	// Allocation is the active allocation
+	Allocation *types.GPUAllocation `json:"allocation"`
+
+	// Sample is the utilization of the allocation's GPU, nil if none was available
+	Sample *UtilizationSample `json:"sample,omitempty"`
+
+	// Idle is true when the GPU's utilization is below the configured idle threshold
+	Idle bool `json:"idle"`
+}
+
+// SetUtilizationSource sets the source of utilization samples. Without one the
+// utilization reported by the GPU manager's discovery is used.
+func (c *Coordinator) SetUtilizationSource(source UtilizationSource) {
+	c.utilizationSource = source
+}
+
+// ListAllocationsWithUtilization returns every active allocation joined with the
+// current utilization of its GPU, ordered by device and allocation ID
+func (c *Coordinator) ListAllocationsWithUtilization(ctx context.Context) ([]*AllocationUtilization, error) {
+	allocations, err := c.manager.ListAllocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+
+	samples, err := c.utilizationSamples(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GPU utilization: %w", err)
+	}
+
+	var result []*AllocationUtilization
+	for _, allocation := range allocations {
+		if allocation.Status != types.GPUAllocationStatusActive {
+			continue
+		}
+
+		joined := &AllocationUtilization{Allocation: allocation}
+		if sample, exists := samples[allocation.DeviceID]; exists {
+			joined.Sample = &sample
+			joined.Idle = sample.Utilization < c.config.IdleUtilizationThreshold
+		}
+		result = append(result, joined)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Allocation.DeviceID != result[j].Allocation.DeviceID {
+			return result[i].Allocation.DeviceID < result[j].Allocation.DeviceID
+		}
+		return result[i].Allocation.ID < result[j].Allocation.ID
+	})
+
+	return result, nil
+}
+
+// utilizationSamples returns the current utilization of each GPU from the
+// configured source, falling back to the GPU manager
+func (c *Coordinator) utilizationSamples(ctx context.Context) (map[string]UtilizationSample, error) {
+	if c.utilizationSource != nil {
+		return c.utilizationSource.GetGPUUtilization(ctx)
+	}
+
+	gpus, err := c.manager.ListGPUs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	samples := make(map[string]UtilizationSample, len(gpus))
+	for _, gpu := range gpus {
+		samples[gpu.DeviceID] = UtilizationSample{Utilization: gpu.Utilization, SampledAt: now}
+	}
+	return samples, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// fakeUtilizationSource returns fixed utilization samples
+type fakeUtilizationSource struct {
+	samples map[string]UtilizationSample
+}
+
+func (f *fakeUtilizationSource) GetGPUUtilization(ctx context.Context) (map[string]UtilizationSample, error) {
+	return f.samples, nil
+}
+
+func TestListAllocationsWithUtilization(t *testing.T) {
+	gpuManager := &fakeGPUManager{
+		gpus: []*types.GPUInfo{{DeviceID: "card0"}, {DeviceID: "card1"}, {DeviceID: "card2"}},
+		allocations: []*types.GPUAllocation{
+			{ID: "busy", DeviceID: "card0", Fraction: 0.5, Status: types.GPUAllocationStatusActive},
+			{ID: "idle", DeviceID: "card1", Fraction: 1.0, Status: types.GPUAllocationStatusActive},
+			{ID: "unsampled", DeviceID: "card2", Fraction: 0.5, Status: types.GPUAllocationStatusActive},
+			{ID: "done", DeviceID: "card0", Fraction: 0.5, Status: types.GPUAllocationStatusCompleted},
+		},
+	}
+	coordinator, _ := newTestCoordinator(t, gpuManager)
+
+	sampledAt := time.Now()
+	coordinator.SetUtilizationSource(&fakeUtilizationSource{samples: map[string]UtilizationSample{
+		"card0": {Utilization: 87, SampledAt: sampledAt},
+		"card1": {Utilization: 1, SampledAt: sampledAt},
+	}})
+
+	joined, err := coordinator.ListAllocationsWithUtilization(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list allocations with utilization: %v", err)
+	}
+
+	if len(joined) != 3 {
+		t.Fatalf("Expected 3 active allocations, got %d", len(joined))
+	}
+
+	busy, idle, unsampled := joined[0], joined[1], joined[2]
+
+	if busy.Allocation.ID != "busy" || busy.Sample == nil || busy.Sample.Utilization != 87 || busy.Idle {
+		t.Errorf("Expected busy allocation joined with 87%% utilization, got %+v", busy)
+	}
+	if idle.Allocation.ID != "idle" || idle.Sample == nil || idle.Sample.Utilization != 1 || !idle.Idle {
+		t.Errorf("Expected idle allocation joined with 1%% utilization and flagged idle, got %+v", idle)
+	}
+	if unsampled.Allocation.ID != "unsampled" || unsampled.Sample != nil || unsampled.Idle {
+		t.Errorf("Expected unsampled allocation without a sample, got %+v", unsampled)
+	}
+}
+
+func TestListAllocationsWithUtilizationFromManager(t *testing.T) {
+	gpuManager := &fakeGPUManager{
+		gpus: []*types.GPUInfo{{DeviceID: "card0", Utilization: 42}},
+		allocations: []*types.GPUAllocation{
+			{ID: "alloc", DeviceID: "card0", Fraction: 0.5, Status: types.GPUAllocationStatusActive},
+		},
+	}
+	coordinator, _ := newTestCoordinator(t, gpuManager)
+
+	joined, err := coordinator.ListAllocationsWithUtilization(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list allocations with utilization: %v", err)
+	}
+
+	if len(joined) != 1 || joined[0].Sample == nil || joined[0].Sample.Utilization != 42 {
+		t.Errorf("Expected utilization 42 from the GPU manager, got %+v", joined)
+	}
+}
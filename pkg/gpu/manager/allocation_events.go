@@ -0,0 +1,204 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// DefaultEventLogCapacity is the number of events an allocation event log keeps
+// when created without a capacity
+const DefaultEventLogCapacity = 1000
+
+// AllocationEventLog records allocation events as an audit trail of who
+// allocated what and when. It keeps the most recent events for polling and
+// sends new events to subscribers. A nil log records nothing.
+type AllocationEventLog struct {
+	// events holds the retained events, oldest first, with their sequence numbers
+	events []sequencedEvent
+
+	// capacity is the number of events retained
+	capacity int
+
+	// sequence is the sequence number of the last recorded event
+	sequence uint64
+
+	// subscribers receive every event recorded after they subscribed
+	subscribers map[int]chan types.AllocationEvent
+
+	// nextSubscriber is the key of the next subscription
+	nextSubscriber int
+
+	// now returns the current time, replaceable in tests
+	now func() time.Time
+
+	// mu guards all of the fields above
+	mu sync.Mutex
+}
+
+// sequencedEvent is an event with the sequence number it was recorded under
+type sequencedEvent struct {
+	sequence uint64
+	event    types.AllocationEvent
+}
+
+// NewAllocationEventLog creates an event log retaining the given number of most
+// recent events, or DefaultEventLogCapacity if capacity is not positive
+func NewAllocationEventLog(capacity int) *AllocationEventLog {
+	if capacity <= 0 {
+		capacity = DefaultEventLogCapacity
+	}
+	return &AllocationEventLog{
+		capacity:    capacity,
+		subscribers: make(map[int]chan types.AllocationEvent),
+		now:         time.Now,
+	}
+}
+
+// Record stores an event under the next sequence number, which becomes its ID,
+// stamping it with the current time unless it has a timestamp. Subscribers whose
+// buffers are full miss the event rather than block the caller.
+func (l *AllocationEventLog) Record(event types.AllocationEvent) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sequence++
+	event.ID = strconv.FormatUint(l.sequence, 10)
+	if event.Timestamp.IsZero() {
+		event.Timestamp = l.now()
+	}
+
+	if len(l.events) == l.capacity {
+		l.events = append(l.events[:0], l.events[1:]...)
+	}
+	l.events = append(l.events, sequencedEvent{sequence: l.sequence, event: event})
+
+	for _, subscriber := range l.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
+
+// EventsSince returns the retained events recorded after sequence number after,
+// oldest first, and the sequence number to pass to the next call. Polling from
+// 0 returns every retained event. A nil log has no events.
+func (l *AllocationEventLog) EventsSince(after uint64) ([]types.AllocationEvent, uint64) {
+	if l == nil {
+		return nil, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var events []types.AllocationEvent
+	for _, recorded := range l.events {
+		if recorded.sequence > after {
+			events = append(events, recorded.event)
+		}
+	}
+	return events, l.sequence
+}
+
+// Subscribe returns a channel receiving every event recorded from now on,
+// buffering up to buffer events, and a function that ends the subscription and
+// closes the channel. Subscribing to a nil log returns a closed channel.
+func (l *AllocationEventLog) Subscribe(buffer int) (<-chan types.AllocationEvent, func()) {
+	if l == nil {
+		closed := make(chan types.AllocationEvent)
+		close(closed)
+		return closed, func() {}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := l.nextSubscriber
+	l.nextSubscriber++
+	subscriber := make(chan types.AllocationEvent, max(buffer, 0))
+	l.subscribers[key] = subscriber
+
+	var once sync.Once
+	return subscriber, func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			delete(l.subscribers, key)
+			close(subscriber)
+		})
+	}
+}
+
+// requestEvent builds an event about an allocation request, for a device if one
+// was chosen
+func requestEvent(eventType types.AllocationEventType, request *types.AllocationRequest, deviceID, message string) types.AllocationEvent {
+	metadata := make(map[string]string)
+	if deviceID != "" {
+		metadata["deviceId"] = deviceID
+	}
+	if request.GPURequest != nil {
+		metadata["fraction"] = strconv.FormatFloat(request.GPURequest.Fraction, 'g', -1, 64)
+		metadata["memoryRequest"] = strconv.FormatInt(request.GPURequest.MemoryRequest, 10)
+		if request.GPURequest.TenantID != "" {
+			metadata["tenantId"] = request.GPURequest.TenantID
+		}
+	}
+	if request.ReservationID != "" {
+		metadata["reservationId"] = request.ReservationID
+	}
+
+	return types.AllocationEvent{
+		Type:         eventType,
+		AllocationID: request.ID,
+		PodName:      request.PodName,
+		Namespace:    request.Namespace,
+		Message:      message,
+		Metadata:     metadata,
+	}
+}
+
+// allocationEvent builds an event about an allocation
+func allocationEvent(eventType types.AllocationEventType, allocation *types.GPUAllocation, message string) types.AllocationEvent {
+	metadata := map[string]string{
+		"deviceId":      allocation.DeviceID,
+		"fraction":      strconv.FormatFloat(allocation.Fraction, 'g', -1, 64),
+		"memoryRequest": strconv.FormatInt(allocation.MemoryRequest, 10),
+	}
+	if allocation.TenantID != "" {
+		metadata["tenantId"] = allocation.TenantID
+	}
+	if allocation.ReservationID != "" {
+		metadata["reservationId"] = allocation.ReservationID
+	}
+
+	return types.AllocationEvent{
+		Type:         eventType,
+		AllocationID: allocation.ID,
+		PodName:      allocation.PodName,
+		Namespace:    allocation.Namespace,
+		Message:      message,
+		Metadata:     metadata,
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// eventTypes returns the types of events in order
+func eventTypes(events []types.AllocationEvent) []types.AllocationEventType {
+	eventTypes := make([]types.AllocationEventType, 0, len(events))
+	for _, event := range events {
+		eventTypes = append(eventTypes, event.Type)
+	}
+	return eventTypes
+}
+
+func TestAMDGPUManagerAllocationEvents(t *testing.T) {
+	manager := newTestAMDGPUManager(t, newTestGPUInfo("card0", 8*1024*1024*1024))
+	ctx := context.Background()
+
+	subscription, unsubscribe := manager.EventLog().Subscribe(10)
+	defer unsubscribe()
+
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("alloc-1", 0.5)); err != nil {
+		t.Fatalf("Allocation failed: %v", err)
+	}
+	if err := manager.ReleaseGPU(ctx, "alloc-1"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("too-large", 2.0)); err == nil {
+		t.Fatal("Expected an allocation above the maximum fraction to fail")
+	}
+
+	events, cursor := manager.EventLog().EventsSince(0)
+	want := []types.AllocationEventType{
+		types.AllocationEventTypeRequested,
+		types.AllocationEventTypeAllocated,
+		types.AllocationEventTypeReleased,
+		types.AllocationEventTypeRequested,
+		types.AllocationEventTypeFailed,
+	}
+	if got := eventTypes(events); !slices.Equal(got, want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+
+	for i, event := range events[:3] {
+		if event.AllocationID != "alloc-1" || event.PodName != "pod-alloc-1" || event.Namespace != "default" {
+			t.Errorf("Event %d does not identify alloc-1 and its pod: %+v", i, event)
+		}
+		if event.Timestamp.IsZero() || (i > 0 && event.Timestamp.Before(events[i-1].Timestamp)) {
+			t.Errorf("Event %d has timestamp %v out of order", i, event.Timestamp)
+		}
+	}
+	if events[1].Metadata["deviceId"] != "card0" || events[1].Metadata["fraction"] != "0.5" {
+		t.Errorf("Expected the allocated event to record card0 and fraction 0.5, got %v", events[1].Metadata)
+	}
+	if events[4].AllocationID != "too-large" || events[4].Message == "" {
+		t.Errorf("Expected the failed event to explain why too-large failed, got %+v", events[4])
+	}
+
+	// Subscribers received the same events as they happened
+	for i, event := range events {
+		select {
+		case received := <-subscription:
+			if received.ID != event.ID || received.Type != event.Type {
+				t.Errorf("Subscriber event %d: expected %s %s, got %s %s", i, event.ID, event.Type, received.ID, received.Type)
+			}
+		default:
+			t.Fatalf("Expected subscriber to have received event %d", i)
+		}
+	}
+
+	// Polling from the returned cursor only returns newer events
+	if newer, _ := manager.EventLog().EventsSince(cursor); len(newer) != 0 {
+		t.Errorf("Expected no events after the cursor, got %v", eventTypes(newer))
+	}
+}
+
+func TestFractionalAllocatorAllocationEvents(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	if err := allocator.RegisterGPU("card0", 8*1024*1024*1024, false); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+	log := NewAllocationEventLog(0)
+	allocator.SetEventLog(log)
+
+	if _, err := allocator.Allocate("card0", newTestAllocationRequest("alloc-1", 0.5)); err != nil {
+		t.Fatalf("Allocation failed: %v", err)
+	}
+	if err := allocator.Release("alloc-1"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := allocator.Allocate("card1", newTestAllocationRequest("unregistered", 0.5)); err == nil {
+		t.Fatal("Expected allocation on an unregistered GPU to fail")
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	request := newTestAllocationRequest("expiring", 0.5)
+	request.ExpiresAt = &expired
+	if _, err := allocator.Allocate("card0", request); err != nil {
+		t.Fatalf("Allocation failed: %v", err)
+	}
+	allocator.CleanupExpiredAllocations()
+
+	events, _ := log.EventsSince(0)
+	want := []types.AllocationEventType{
+		types.AllocationEventTypeAllocated,
+		types.AllocationEventTypeReleased,
+		types.AllocationEventTypeFailed,
+		types.AllocationEventTypeAllocated,
+		types.AllocationEventTypeExpired,
+	}
+	if got := eventTypes(events); !slices.Equal(got, want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+	if events[2].Metadata["deviceId"] != "card1" {
+		t.Errorf("Expected the failed event to name card1, got %v", events[2].Metadata)
+	}
+}
+
+func TestAllocationEventLogRetention(t *testing.T) {
+	log := NewAllocationEventLog(2)
+	subscription, unsubscribe := log.Subscribe(0)
+
+	for _, id := range []string{"a", "b", "c"} {
+		log.Record(types.AllocationEvent{Type: types.AllocationEventTypeRequested, AllocationID: id})
+	}
+
+	// Only the two most recent events are kept, under their original IDs
+	events, cursor := log.EventsSince(0)
+	if len(events) != 2 || events[0].AllocationID != "b" || events[0].ID != "2" || events[1].ID != "3" {
+		t.Errorf("Expected events 2 and 3 for b and c, got %+v", events)
+	}
+	if cursor != 3 {
+		t.Errorf("Expected cursor 3, got %d", cursor)
+	}
+	if events, _ := log.EventsSince(2); len(events) != 1 || events[0].AllocationID != "c" {
+		t.Errorf("Expected only c after event 2, got %+v", events)
+	}
+
+	// An unbuffered subscriber that is not receiving misses events instead of blocking
+	select {
+	case event := <-subscription:
+		t.Errorf("Expected no event to be buffered, got %+v", event)
+	default:
+	}
+	unsubscribe()
+	unsubscribe()
+	if _, open := <-subscription; open {
+		t.Error("Expected the subscription to be closed")
+	}
+
+	// A nil log records nothing and has nothing to report
+	var nilLog *AllocationEventLog
+	nilLog.Record(types.AllocationEvent{Type: types.AllocationEventTypeRequested})
+	if events, cursor := nilLog.EventsSince(0); events != nil || cursor != 0 {
+		t.Errorf("Expected no events from a nil log, got %v at %d", events, cursor)
+	}
+	nilSubscription, nilUnsubscribe := nilLog.Subscribe(1)
+	nilUnsubscribe()
+	if _, open := <-nilSubscription; open {
+		t.Error("Expected a nil log's subscription to be closed")
+	}
+}
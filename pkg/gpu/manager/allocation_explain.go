@@ -0,0 +1,177 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// CandidateFilterReason explains why a GPU was not considered for a request
+type CandidateFilterReason string
+
+const (
+	// CandidateFilterUnhealthy means the GPU is overheating or reported unavailable
+	CandidateFilterUnhealthy CandidateFilterReason = "unhealthy"
+	// CandidateFilterInsufficientMemory means the GPU lacks the requested memory
+	CandidateFilterInsufficientMemory CandidateFilterReason = "insufficient-memory"
+	// CandidateFilterInsufficientCapacity means existing allocations leave too small a fraction of the GPU
+	CandidateFilterInsufficientCapacity CandidateFilterReason = "insufficient-capacity"
+	// CandidateFilterPlacement means the GPU's type or node does not match the request's GPU type or node selector
+	CandidateFilterPlacement CandidateFilterReason = "placement"
+	// CandidateFilterPolicy means a manager policy, such as the per-GPU allocation limit, excludes the GPU
+	CandidateFilterPolicy CandidateFilterReason = "policy"
+)
+
+// AllocationCandidate is a GPU that passed filtering, with the score the
+// request's strategy ranked it by
+type AllocationCandidate struct {
+	DeviceID string  `json:"deviceId"`
+	Score    float64 `json:"score"` // Fit score for best/worst fit, load score for load-balanced, 0 for strategies that do not score
+	Selected bool    `json:"selected"`
+}
+
+// FilteredCandidate is a GPU excluded before scoring
+type FilteredCandidate struct {
+	DeviceID string                `json:"deviceId"`
+	Reason   CandidateFilterReason `json:"reason"`
+	Detail   string                `json:"detail"`
+}
+
+// AllocationExplanation describes how a request would be placed
+type AllocationExplanation struct {
+	RequestID     string                   `json:"requestId"`
+	Strategy      types.AllocationStrategy `json:"strategy"`
+	Candidates    []AllocationCandidate    `json:"candidates"`
+	Filtered      []FilteredCandidate      `json:"filtered"`
+	SelectedGPU   string                   `json:"selectedGpu"` // Empty when no GPU can take the request
+	SelectedScore float64                  `json:"selectedScore"`
+}
+
+// ExplainAllocation runs GPU selection for a request without allocating,
+// reporting why each GPU was filtered out or how it scored and which GPU
+// would be chosen. GPUs are evaluated in device ID order.
+func (a *AMDGPUManager) ExplainAllocation(ctx context.Context, request *types.AllocationRequest) (*AllocationExplanation, error) {
+	if err := a.ValidateAllocation(ctx, request); err != nil {
+		return nil, fmt.Errorf("invalid allocation request: %v", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	gpus := a.listGPUs(ctx)
+	sort.Slice(gpus, func(i, j int) bool {
+		return gpus[i].DeviceID < gpus[j].DeviceID
+	})
+
+	explanation := &AllocationExplanation{
+		RequestID: request.ID,
+		Strategy:  request.Strategy,
+	}
+
+	placement := newPlacementFilter(ctx, a.nodeLabels, request)
+	var availableGPUs []*types.GPUInfo
+	for _, gpu := range gpus {
+		if detail := placement.mismatch(gpu); detail != "" {
+			explanation.Filtered = append(explanation.Filtered, FilteredCandidate{
+				DeviceID: gpu.DeviceID,
+				Reason:   CandidateFilterPlacement,
+				Detail:   detail,
+			})
+			continue
+		}
+		if reason, detail := a.filterCandidate(gpu, request); reason != "" {
+			explanation.Filtered = append(explanation.Filtered, FilteredCandidate{
+				DeviceID: gpu.DeviceID,
+				Reason:   reason,
+				Detail:   detail,
+			})
+			continue
+		}
+		availableGPUs = append(availableGPUs, gpu)
+	}
+
+	if len(availableGPUs) == 0 {
+		return explanation, nil
+	}
+
+	selected, err := a.selectGPU(availableGPUs, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select GPU: %v", err)
+	}
+
+	for _, gpu := range availableGPUs {
+		candidate := AllocationCandidate{
+			DeviceID: gpu.DeviceID,
+			Score:    a.strategyScore(gpu, request),
+			Selected: gpu == selected,
+		}
+		if candidate.Selected {
+			explanation.SelectedGPU = candidate.DeviceID
+			explanation.SelectedScore = candidate.Score
+		}
+		explanation.Candidates = append(explanation.Candidates, candidate)
+	}
+
+	return explanation, nil
+}
+
+// filterCandidate returns why findAvailableGPU would skip a GPU for a request,
+// or an empty reason if the GPU is a candidate
+func (a *AMDGPUManager) filterCandidate(gpu *types.GPUInfo, request *types.AllocationRequest) (CandidateFilterReason, string) {
+	if !gpu.IsAvailable {
+		if err := a.health.checkHealth(gpu); err != nil {
+			return CandidateFilterUnhealthy, err.Error()
+		}
+		if a.health.isFull(gpu.ActiveAllocations) {
+			return CandidateFilterPolicy, fmt.Sprintf("%d active allocations reach the per-GPU limit of %d",
+				gpu.ActiveAllocations, a.health.MaxAllocations)
+		}
+		return CandidateFilterUnhealthy, "GPU reported unavailable"
+	}
+
+	if request.GPURequest.MemoryRequest > 0 && gpu.AvailableMemory < request.GPURequest.MemoryRequest*1024*1024 {
+		return CandidateFilterInsufficientMemory, fmt.Sprintf("requested %d MiB, %d MiB available",
+			request.GPURequest.MemoryRequest, gpu.AvailableMemory/(1024*1024))
+	}
+
+	if canAllocate, err := a.fractional.CanAllocate(gpu.DeviceID, request.GPURequest); !canAllocate {
+		switch {
+		case request.GPURequest.Fraction > a.fractional.GetAvailableFraction(gpu.DeviceID):
+			return CandidateFilterInsufficientCapacity, err.Error()
+		case request.GPURequest.MemoryRequest*1024*1024 > a.fractional.GetAvailableMemory(gpu.DeviceID):
+			return CandidateFilterInsufficientMemory, err.Error()
+		default:
+			return CandidateFilterPolicy, err.Error()
+		}
+	}
+
+	return "", ""
+}
+
+// strategyScore returns the score the request's strategy ranks a GPU by
+func (a *AMDGPUManager) strategyScore(gpu *types.GPUInfo, request *types.AllocationRequest) float64 {
+	switch request.Strategy {
+	case types.AllocationStrategyBestFit, types.AllocationStrategyWorstFit:
+		return a.calculateFitScore(gpu, request)
+	case types.AllocationStrategyLoadBalanced:
+		return a.calculateLoadScore(gpu)
+	default:
+		return 0
+	}
+}
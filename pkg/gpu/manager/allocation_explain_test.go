@@ -0,0 +1,94 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+func TestExplainAllocation(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+
+	hot := newTestGPUInfo("card0", 64*gib)
+	hot.Temperature = 95
+	hot.IsAvailable = false
+
+	small := newTestGPUInfo("card1", 64*gib)
+	small.AvailableMemory = 4 * gib
+
+	full := newTestGPUInfo("card2", 64*gib)
+	full.ActiveAllocations = 10
+	full.IsAvailable = false
+
+	busy := newTestGPUInfo("card3", 64*gib)
+	busy.Utilization = 80
+	busy.AvailableMemory = 32 * gib
+
+	idle := newTestGPUInfo("card4", 64*gib)
+	idle.Utilization = 10
+	idle.AvailableMemory = 48 * gib
+
+	manager := newTestAMDGPUManager(t, hot, small, full, busy, idle)
+
+	request := newTestAllocationRequest("explain", 0.5)
+	request.GPURequest.MemoryRequest = 8 * 1024 // MiB
+	request.Strategy = types.AllocationStrategyBestFit
+
+	explanation, err := manager.ExplainAllocation(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Failed to explain allocation: %v", err)
+	}
+
+	wantFiltered := map[string]CandidateFilterReason{
+		"card0": CandidateFilterUnhealthy,
+		"card1": CandidateFilterInsufficientMemory,
+		"card2": CandidateFilterPolicy,
+	}
+	if len(explanation.Filtered) != len(wantFiltered) {
+		t.Fatalf("Expected %d filtered GPUs, got %+v", len(wantFiltered), explanation.Filtered)
+	}
+	for _, filtered := range explanation.Filtered {
+		if filtered.Reason != wantFiltered[filtered.DeviceID] {
+			t.Errorf("Expected %s filtered as %q, got %q", filtered.DeviceID, wantFiltered[filtered.DeviceID], filtered.Reason)
+		}
+		if filtered.Detail == "" {
+			t.Errorf("Expected a detail for filtered %s", filtered.DeviceID)
+		}
+	}
+
+	if len(explanation.Candidates) != 2 {
+		t.Fatalf("Expected 2 scored candidates, got %+v", explanation.Candidates)
+	}
+
+	// Best fit picks the lowest fit score: 0.1 + 0.25 for card4 against 0.8 + 0.5 for card3
+	if explanation.SelectedGPU != "card4" {
+		t.Errorf("Expected card4 to be selected, got %q", explanation.SelectedGPU)
+	}
+	if want := manager.calculateFitScore(idle, request); explanation.SelectedScore != want {
+		t.Errorf("Expected selected score %f, got %f", want, explanation.SelectedScore)
+	}
+	for _, candidate := range explanation.Candidates {
+		if candidate.Selected != (candidate.DeviceID == "card4") {
+			t.Errorf("Unexpected selection flag on %+v", candidate)
+		}
+	}
+
+	if idle.ActiveAllocations != 0 {
+		t.Errorf("Expected explain not to allocate, got %d active allocations", idle.ActiveAllocations)
+	}
+}
@@ -24,35 +24,95 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/silogen/kaiwo/pkg/gpu/types"
 )
 
+// GPUDiscovery finds the GPUs on a node and refreshes their metrics
+type GPUDiscovery interface {
+	// DiscoverGPUs returns the GPUs currently present
+	DiscoverGPUs(ctx context.Context) ([]*types.GPUInfo, error)
+
+	// UpdateGPUMetrics refreshes utilization, temperature, power and memory of known GPUs
+	UpdateGPUMetrics(ctx context.Context, gpus map[string]*types.GPUInfo)
+}
+
 // AMDGPUDiscovery handles real AMD GPU discovery using ROCm tools
 type AMDGPUDiscovery struct {
+	// amdSMIPath is the path to amd-smi executable
+	amdSMIPath string
+
 	// rocmSMIPath is the path to rocm-smi executable
 	rocmSMIPath string
 
 	// sysClassDRMPath is the path to /sys/class/drm
 	sysClassDRMPath string
 
+	// sysBusPCIPath is the path to /sys/bus/pci/devices, used to find the DRM
+	// card of a GPU that amd-smi or rocm-smi reports by PCI bus address
+	sysBusPCIPath string
+
 	// timeout for commands
 	timeout time.Duration
+
+	// lastGoodReadings holds the last plausible sensor readings per GPU, used in
+	// place of readings that fail validation
+	lastGoodReadings map[string]*sensorReadings
+
+	// readingsMu guards lastGoodReadings
+	readingsMu sync.Mutex
+
+	// health sets when a discovered GPU is reported available
+	health HealthConfig
+}
+
+// Plausible sensor ranges. Readings outside them are treated as sensor glitches.
+const (
+	minPlausibleTemperature = 0.0    // °C
+	maxPlausibleTemperature = 150.0  // °C
+	minPlausiblePower       = 0.0    // W
+	maxPlausiblePower       = 2000.0 // W
+)
+
+// sensorReadings are the validated sensor values for a GPU
+type sensorReadings struct {
+	temperature float64
+	power       float64
 }
 
 // NewAMDGPUDiscovery creates a new AMD GPU discovery instance
 func NewAMDGPUDiscovery() *AMDGPUDiscovery {
 	return &AMDGPUDiscovery{
-		rocmSMIPath:     findROCmSMI(),
-		sysClassDRMPath: "/sys/class/drm",
-		timeout:         30 * time.Second,
+		amdSMIPath:       findAMDSMI(),
+		rocmSMIPath:      findROCmSMI(),
+		sysClassDRMPath:  "/sys/class/drm",
+		sysBusPCIPath:    "/sys/bus/pci/devices",
+		timeout:          30 * time.Second,
+		lastGoodReadings: make(map[string]*sensorReadings),
+		health:           HealthConfig{}.withDefaults(),
 	}
 }
 
+// SetHealthConfig sets the thresholds used to decide whether discovered GPUs
+// are available. Unset thresholds keep their defaults.
+func (d *AMDGPUDiscovery) SetHealthConfig(health HealthConfig) {
+	d.health = health.withDefaults()
+}
+
 // DiscoverGPUs discovers AMD GPUs using multiple methods
 func (d *AMDGPUDiscovery) DiscoverGPUs(ctx context.Context) ([]*types.GPUInfo, error) {
-	// Try ROCm SMI first (most comprehensive)
+	// Try amd-smi first, it replaces rocm-smi on newer ROCm releases
+	if d.amdSMIPath != "" {
+		gpus, err := d.discoverWithAMDSMI(ctx)
+		if err == nil && len(gpus) > 0 {
+			return gpus, nil
+		}
+		fmt.Printf("AMD SMI discovery failed: %v, falling back to ROCm SMI\n", err)
+	}
+
+	// Then ROCm SMI
 	if d.rocmSMIPath != "" {
 		gpus, err := d.discoverWithROCmSMI(ctx)
 		if err == nil && len(gpus) > 0 {
@@ -77,7 +137,7 @@ func (d *AMDGPUDiscovery) discoverWithROCmSMI(ctx context.Context) ([]*types.GPU
 	defer cancel()
 
 	// Execute rocm-smi with JSON output
-	cmd := exec.CommandContext(cmdCtx, d.rocmSMIPath, "--showallinfo", "--json")
+	cmd := exec.CommandContext(cmdCtx, d.rocmSMIPath, "--showallinfo", "--showmeminfo", "vram", "--json")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute rocm-smi: %v", err)
@@ -114,8 +174,15 @@ func (d *AMDGPUDiscovery) discoverWithROCmSMI(ctx context.Context) ([]*types.GPU
 	return gpus, nil
 }
 
-// convertROCmSMIToGPUInfo converts ROCm SMI data to GPUInfo
+// convertROCmSMIToGPUInfo converts ROCm SMI data to GPUInfo. The GPU is named
+// after its DRM card when sysfs lists one at its PCI bus address and after
+// rocm-smi's cardID otherwise.
 func (d *AMDGPUDiscovery) convertROCmSMIToGPUInfo(cardID string, cardMap map[string]interface{}) (*types.GPUInfo, error) {
+	drmCard, resolved := d.drmCardForBus(d.getStringValue(cardMap, "PCI Bus", ""))
+	if resolved {
+		cardID = drmCard
+	}
+
 	// Extract values from the map
 	temperature := d.getFloatValue(cardMap, "Temperature (Sensor edge) (C)", 0.0)
 	utilization := d.getFloatValue(cardMap, "GPU use (%)", 0.0)
@@ -124,38 +191,258 @@ func (d *AMDGPUDiscovery) convertROCmSMIToGPUInfo(cardID string, cardMap map[str
 	cardModel := d.getStringValue(cardMap, "Card Model", "Unknown")
 	memoryAllocated := d.getFloatValue(cardMap, "GPU Memory Allocated (VRAM%)", 0.0)
 
-	// Calculate memory (estimate based on allocation percentage)
-	// For AMD Instinct GPUs, we'll use typical memory sizes
-	var totalMemory int64
-	switch {
-	case strings.Contains(strings.ToLower(cardSeries), "instinct"):
-		totalMemory = 32 * 1024 * 1024 * 1024 // 32GB for Instinct
-	case strings.Contains(strings.ToLower(cardSeries), "radeon"):
-		totalMemory = 8 * 1024 * 1024 * 1024 // 8GB for Radeon
-	default:
-		totalMemory = 16 * 1024 * 1024 * 1024 // 16GB default
+	utilization, temperature, power = d.sanitizeReadings(cardID, utilization, temperature, power)
+
+	// Use the VRAM size and usage rocm-smi reports, as the sysfs path does with
+	// mem_info_vram_total and mem_info_vram_used
+	totalMemory, ok := d.getInt64Value(cardMap, "VRAM Total Memory (B)")
+	if !ok || totalMemory <= 0 {
+		totalMemory = estimateTotalMemory(cardSeries)
 	}
 
-	usedMemory := int64(float64(totalMemory) * memoryAllocated / 100.0)
+	usedMemory, ok := d.getInt64Value(cardMap, "VRAM Total Used Memory (B)")
+	if !ok || usedMemory < 0 || usedMemory > totalMemory {
+		usedMemory = int64(float64(totalMemory) * memoryAllocated / 100.0)
+	}
 	availableMemory := totalMemory - usedMemory
 
+	correctable, uncorrectable := sumROCmSMIECCCounts(cardMap)
+
 	// Get node name
 	nodeName, _ := os.Hostname()
 
-	return &types.GPUInfo{
-		DeviceID:          cardID,
-		Type:              types.GPUTypeAMD,
-		Model:             fmt.Sprintf("%s %s", cardSeries, cardModel),
-		TotalMemory:       totalMemory,
-		AvailableMemory:   availableMemory,
-		Utilization:       utilization,
-		Temperature:       temperature,
-		Power:             power,
-		NodeName:          nodeName,
-		IsAvailable:       d.isGPUHealthy(temperature, utilization),
-		IsolationType:     types.GPUIsolationNone,
-		ActiveAllocations: 0,
-	}, nil
+	gpu := &types.GPUInfo{
+		DeviceID:               cardID,
+		Type:                   types.GPUTypeAMD,
+		Model:                  fmt.Sprintf("%s %s", cardSeries, cardModel),
+		TotalMemory:            totalMemory,
+		AvailableMemory:        availableMemory,
+		Utilization:            utilization,
+		Temperature:            temperature,
+		Power:                  power,
+		ClockMHz:               parseClockMHz(d.getStringValue(cardMap, "sclk clock speed:", "")),
+		MemoryClockMHz:         parseClockMHz(d.getStringValue(cardMap, "mclk clock speed:", "")),
+		FanSpeedPercent:        d.getFloatValue(cardMap, "Fan speed (%)", 0.0),
+		ECCErrorsCorrectable:   correctable,
+		ECCErrorsUncorrectable: uncorrectable,
+		NodeName:               nodeName,
+		IsolationType:          types.GPUIsolationNone,
+		ActiveAllocations:      0,
+	}
+	if resolved {
+		gpu.PartitionMode, gpu.MemoryMode = d.readPartitionModes(drmCard)
+	}
+	gpu.IsAvailable = d.isGPUHealthy(gpu)
+
+	return gpu, nil
+}
+
+// sumROCmSMIECCCounts totals the correctable and uncorrectable error counts
+// rocm-smi reports per RAS block
+func sumROCmSMIECCCounts(cardMap map[string]interface{}) (int64, int64) {
+	var correctable, uncorrectable int64
+	for key, val := range cardMap {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		count, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+		if err != nil || count < 0 {
+			continue
+		}
+
+		lowerKey := strings.ToLower(key)
+		switch {
+		case strings.Contains(lowerKey, "uncorrectable"):
+			uncorrectable += count
+		case strings.Contains(lowerKey, "correctable"):
+			correctable += count
+		}
+	}
+	return correctable, uncorrectable
+}
+
+// parseClockMHz parses a clock frequency as rocm-smi and sysfs print it, e.g.
+// "(1000Mhz)" or "1000Mhz", returning 0 if it cannot be parsed
+func parseClockMHz(s string) float64 {
+	s = strings.Trim(strings.TrimSpace(s), "()")
+	s = strings.TrimSuffix(strings.ToLower(s), "mhz")
+	clock, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	return clock
+}
+
+// discoverWithAMDSMI uses amd-smi to discover GPUs, combining static device
+// information with current metrics
+func (d *AMDGPUDiscovery) discoverWithAMDSMI(ctx context.Context) ([]*types.GPUInfo, error) {
+	staticData, err := d.runAMDSMI(ctx, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	metricData, err := d.runAMDSMI(ctx, "metric")
+	if err != nil {
+		return nil, err
+	}
+
+	metricsByGPU := make(map[int]map[string]interface{}, len(metricData))
+	for _, metrics := range metricData {
+		if index, ok := amdSMIGPUIndex(metrics); ok {
+			metricsByGPU[index] = metrics
+		}
+	}
+
+	var gpus []*types.GPUInfo
+	for _, static := range staticData {
+		index, ok := amdSMIGPUIndex(static)
+		if !ok {
+			continue
+		}
+
+		gpu, err := d.convertAMDSMIToGPUInfo(fmt.Sprintf("card%d", index), static, metricsByGPU[index])
+		if err != nil {
+			fmt.Printf("Failed to convert AMD SMI data for GPU %d: %v\n", index, err)
+			continue
+		}
+		gpus = append(gpus, gpu)
+	}
+
+	return gpus, nil
+}
+
+// runAMDSMI runs an amd-smi subcommand with JSON output and returns one map per GPU
+func (d *AMDGPUDiscovery) runAMDSMI(ctx context.Context, subcommand string) ([]map[string]interface{}, error) {
+	cmdCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, d.amdSMIPath, subcommand, "--json")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute amd-smi %s: %v", subcommand, err)
+	}
+
+	gpus, err := parseAMDSMIOutput(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amd-smi %s JSON output: %v", subcommand, err)
+	}
+	return gpus, nil
+}
+
+// parseAMDSMIOutput parses amd-smi JSON output, which is either an array of
+// per-GPU objects or, on newer releases, an object holding that array under "gpu_data"
+func parseAMDSMIOutput(output []byte) ([]map[string]interface{}, error) {
+	var parsed interface{}
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, err
+	}
+
+	if wrapper, ok := parsed.(map[string]interface{}); ok {
+		parsed = wrapper["gpu_data"]
+	}
+
+	list, ok := parsed.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list of GPUs")
+	}
+
+	gpus := make([]map[string]interface{}, 0, len(list))
+	for _, entry := range list {
+		if gpu, ok := entry.(map[string]interface{}); ok {
+			gpus = append(gpus, gpu)
+		}
+	}
+	return gpus, nil
+}
+
+// amdSMIGPUIndex returns the GPU index of an amd-smi per-GPU object
+func amdSMIGPUIndex(m map[string]interface{}) (int, bool) {
+	index, ok := m["gpu"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(index), true
+}
+
+// convertAMDSMIToGPUInfo converts amd-smi static and metric data for one GPU to
+// GPUInfo. The GPU is named after its DRM card when sysfs lists one at its PCI
+// bus address and after amd-smi's cardID otherwise.
+func (d *AMDGPUDiscovery) convertAMDSMIToGPUInfo(cardID string, static, metrics map[string]interface{}) (*types.GPUInfo, error) {
+	if metrics == nil {
+		return nil, fmt.Errorf("no metrics reported")
+	}
+
+	drmCard, resolved := d.drmCardForBus(d.getStringValue(static, "bus.bdf", ""))
+	if resolved {
+		cardID = drmCard
+	}
+
+	model := d.getStringValue(static, "asic.market_name", "AMD GPU")
+	utilization := d.getFloatValue(metrics, "usage.gfx_activity", 0.0)
+	power := d.getFloatValue(metrics, "power.socket_power", 0.0)
+
+	// MI300 series report no edge sensor, only the hotspot (junction) temperature
+	temperature, ok := d.getQuantity(metrics, "temperature.edge")
+	if !ok {
+		temperature, _ = d.getQuantity(metrics, "temperature.hotspot")
+	}
+
+	utilization, temperature, power = d.sanitizeReadings(cardID, utilization, temperature, power)
+
+	totalMemory, ok := d.getMemoryValue(metrics, "mem_usage.total_vram")
+	if !ok {
+		totalMemory, ok = d.getMemoryValue(static, "vram.size")
+	}
+	if !ok || totalMemory <= 0 {
+		totalMemory = estimateTotalMemory(model)
+	}
+
+	usedMemory, ok := d.getMemoryValue(metrics, "mem_usage.used_vram")
+	if !ok || usedMemory < 0 || usedMemory > totalMemory {
+		usedMemory = 0
+	}
+
+	// Get node name
+	nodeName, _ := os.Hostname()
+
+	gpu := &types.GPUInfo{
+		DeviceID:               cardID,
+		Type:                   types.GPUTypeAMD,
+		Model:                  model,
+		TotalMemory:            totalMemory,
+		AvailableMemory:        totalMemory - usedMemory,
+		Utilization:            utilization,
+		Temperature:            temperature,
+		Power:                  power,
+		ClockMHz:               d.getFloatValue(metrics, "clock.gfx_0.clk", 0.0),
+		MemoryClockMHz:         d.getFloatValue(metrics, "clock.mem_0.clk", 0.0),
+		FanSpeedPercent:        d.getFloatValue(metrics, "fan.usage", 0.0),
+		ECCErrorsCorrectable:   int64(d.getFloatValue(metrics, "ecc.total_correctable_count", 0.0)),
+		ECCErrorsUncorrectable: int64(d.getFloatValue(metrics, "ecc.total_uncorrectable_count", 0.0)),
+		NodeName:               nodeName,
+		IsolationType:          types.GPUIsolationNone,
+		ActiveAllocations:      0,
+	}
+	if resolved {
+		gpu.PartitionMode, gpu.MemoryMode = d.readPartitionModes(drmCard)
+	}
+	gpu.IsAvailable = d.isGPUHealthy(gpu)
+
+	return gpu, nil
+}
+
+// estimateTotalMemory guesses a GPU's VRAM size from its card series, for when
+// rocm-smi does not report it
+func estimateTotalMemory(cardSeries string) int64 {
+	switch {
+	case strings.Contains(strings.ToLower(cardSeries), "instinct"):
+		return 32 * 1024 * 1024 * 1024 // 32GB for Instinct
+	case strings.Contains(strings.ToLower(cardSeries), "radeon"):
+		return 8 * 1024 * 1024 * 1024 // 8GB for Radeon
+	default:
+		return 16 * 1024 * 1024 * 1024 // 16GB default
+	}
 }
 
 // discoverWithSysfs uses /sys/class/drm to discover GPUs
@@ -187,6 +474,9 @@ func (d *AMDGPUDiscovery) discoverWithSysfs(ctx context.Context) ([]*types.GPUIn
 	return gpus, nil
 }
 
+// drmCardPattern matches the names of DRM card nodes, e.g. card1
+var drmCardPattern = regexp.MustCompile(`^card\d+$`)
+
 // findAMDCards finds AMD GPU cards in /sys/class/drm
 func (d *AMDGPUDiscovery) findAMDCards() ([]string, error) {
 	entries, err := os.ReadDir(d.sysClassDRMPath)
@@ -195,10 +485,8 @@ func (d *AMDGPUDiscovery) findAMDCards() ([]string, error) {
 	}
 
 	var amdCards []string
-	cardRegex := regexp.MustCompile(`^card\d+$`)
-
 	for _, entry := range entries {
-		if !cardRegex.MatchString(entry.Name()) {
+		if !drmCardPattern.MatchString(entry.Name()) {
 			continue
 		}
 
@@ -305,10 +593,14 @@ func (d *AMDGPUDiscovery) parseCardFromSysfs(cardPath string) (*types.GPUInfo, e
 		}
 	}
 
+	utilization, temperature, power = d.sanitizeReadings(deviceID, utilization, temperature, power)
+
+	partitionMode, memoryMode := d.readPartitionModes(deviceID)
+
 	// Get node name
 	nodeName, _ := os.Hostname()
 
-	return &types.GPUInfo{
+	gpu := &types.GPUInfo{
 		DeviceID:          deviceID,
 		Type:              types.GPUTypeAMD,
 		Model:             model,
@@ -318,10 +610,116 @@ func (d *AMDGPUDiscovery) parseCardFromSysfs(cardPath string) (*types.GPUInfo, e
 		Temperature:       temperature,
 		Power:             power,
 		NodeName:          nodeName,
-		IsAvailable:       d.isGPUHealthy(temperature, utilization),
 		IsolationType:     types.GPUIsolationNone,
 		ActiveAllocations: 0,
-	}, nil
+		PartitionMode:     partitionMode,
+		MemoryMode:        memoryMode,
+	}
+	d.readSysfsHealthMetrics(devicePath, gpu)
+	gpu.IsAvailable = d.isGPUHealthy(gpu)
+
+	return gpu, nil
+}
+
+// drmCardForBus returns the DRM card node, e.g. card1, of the GPU at a PCI bus
+// address such as 0000:c1:00.0, reporting whether sysfs lists one. The indices
+// amd-smi and rocm-smi number GPUs by are not DRM minors, so a GPU they report
+// is matched to its sysfs files through its bus address.
+func (d *AMDGPUDiscovery) drmCardForBus(busID string) (string, bool) {
+	if busID == "" {
+		return "", false
+	}
+
+	entries, err := os.ReadDir(filepath.Join(d.sysBusPCIPath, strings.ToLower(busID), "drm"))
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if drmCardPattern.MatchString(entry.Name()) {
+			return entry.Name(), true
+		}
+	}
+	return "", false
+}
+
+// readPartitionModes reads a DRM card's compute and memory partition modes
+// from sysfs. Only partitionable GPUs such as MI300X expose them; others
+// return empty modes.
+func (d *AMDGPUDiscovery) readPartitionModes(drmCard string) (string, string) {
+	devicePath := filepath.Join(d.sysClassDRMPath, drmCard, "device")
+	partitionMode := strings.ToUpper(d.readSysfsFile(filepath.Join(devicePath, "current_compute_partition")))
+	memoryMode := strings.ToUpper(d.readSysfsFile(filepath.Join(devicePath, "current_memory_partition")))
+	return partitionMode, memoryMode
+}
+
+// readSysfsHealthMetrics reads clock, fan and ECC metrics from sysfs into gpu.
+// Metrics the card does not expose are left at 0.
+func (d *AMDGPUDiscovery) readSysfsHealthMetrics(devicePath string, gpu *types.GPUInfo) {
+	gpu.ClockMHz = d.readCurrentClockMHz(filepath.Join(devicePath, "pp_dpm_sclk"))
+	gpu.MemoryClockMHz = d.readCurrentClockMHz(filepath.Join(devicePath, "pp_dpm_mclk"))
+	gpu.FanSpeedPercent = d.readFanSpeedPercent(devicePath)
+	gpu.ECCErrorsCorrectable, gpu.ECCErrorsUncorrectable = d.readECCCounts(devicePath)
+}
+
+// readCurrentClockMHz returns the active level of a pp_dpm_* clock table, whose
+// lines look like "1: 1000Mhz *" with the active level marked by "*"
+func (d *AMDGPUDiscovery) readCurrentClockMHz(path string) float64 {
+	for _, line := range strings.Split(d.readSysfsFile(path), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[2] == "*" {
+			return parseClockMHz(fields[1])
+		}
+	}
+	return 0
+}
+
+// readFanSpeedPercent returns the fan PWM duty cycle as a percentage
+func (d *AMDGPUDiscovery) readFanSpeedPercent(devicePath string) float64 {
+	matches, _ := filepath.Glob(filepath.Join(devicePath, "hwmon", "hwmon*", "pwm1"))
+	if len(matches) == 0 {
+		return 0
+	}
+
+	pwm, err := strconv.ParseFloat(d.readSysfsFile(matches[0]), 64)
+	if err != nil {
+		return 0
+	}
+
+	pwmMax := 255.0
+	if maxStr := d.readSysfsFile(matches[0] + "_max"); maxStr != "" {
+		if value, err := strconv.ParseFloat(maxStr, 64); err == nil && value > 0 {
+			pwmMax = value
+		}
+	}
+
+	return min(max(pwm/pwmMax*100, 0), 100)
+}
+
+// readECCCounts totals the correctable ("ce") and uncorrectable ("ue") error
+// counts of every RAS block under device/ras
+func (d *AMDGPUDiscovery) readECCCounts(devicePath string) (int64, int64) {
+	matches, _ := filepath.Glob(filepath.Join(devicePath, "ras", "*_err_count"))
+
+	var correctable, uncorrectable int64
+	for _, path := range matches {
+		for _, line := range strings.Split(d.readSysfsFile(path), "\n") {
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			count, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				continue
+			}
+			switch strings.TrimSpace(name) {
+			case "ce":
+				correctable += count
+			case "ue":
+				uncorrectable += count
+			}
+		}
+	}
+	return correctable, uncorrectable
 }
 
 // readSysfsFile safely reads a sysfs file
@@ -333,10 +731,70 @@ func (d *AMDGPUDiscovery) readSysfsFile(path string) string {
 	return strings.TrimSpace(string(content))
 }
 
-// isGPUHealthy determines if a GPU is healthy based on temperature and utilization
-func (d *AMDGPUDiscovery) isGPUHealthy(temperature, utilization float64) bool {
-	// Check temperature threshold (< 90°C)
-	return temperature <= 90.0
+// sanitizeReadings clamps utilization to [0, 100] and replaces implausible
+// temperature and power readings with the last good reading for the GPU, or 0
+// (unknown) if there is none. Discarded readings are logged.
+func (d *AMDGPUDiscovery) sanitizeReadings(deviceID string, utilization, temperature, power float64) (float64, float64, float64) {
+	d.readingsMu.Lock()
+	defer d.readingsMu.Unlock()
+
+	if utilization < 0 || utilization > 100 {
+		fmt.Printf("GPU %s reported out of range utilization %.1f%%, clamping\n", deviceID, utilization)
+		utilization = min(max(utilization, 0), 100)
+	}
+
+	lastGood := d.lastGoodReadings[deviceID]
+	if lastGood == nil {
+		lastGood = &sensorReadings{}
+		d.lastGoodReadings[deviceID] = lastGood
+	}
+
+	if temperature < minPlausibleTemperature || temperature > maxPlausibleTemperature {
+		fmt.Printf("GPU %s reported implausible temperature %.1f°C, using last good value %.1f°C\n",
+			deviceID, temperature, lastGood.temperature)
+		temperature = lastGood.temperature
+	} else {
+		lastGood.temperature = temperature
+	}
+
+	if power < minPlausiblePower || power > maxPlausiblePower {
+		fmt.Printf("GPU %s reported implausible power %.1fW, using last good value %.1fW\n",
+			deviceID, power, lastGood.power)
+		power = lastGood.power
+	} else {
+		lastGood.power = power
+	}
+
+	return utilization, temperature, power
+}
+
+// isGPUHealthy determines if a GPU is healthy based on temperature, power and ECC errors
+func (d *AMDGPUDiscovery) isGPUHealthy(gpu *types.GPUInfo) bool {
+	return d.health.checkHealth(gpu) == nil
+}
+
+// findAMDSMI finds the amd-smi executable
+func findAMDSMI() string {
+	// Common paths for amd-smi
+	commonPaths := []string{
+		"/opt/rocm/bin/amd-smi",
+		"/usr/bin/amd-smi",
+		"/usr/local/bin/amd-smi",
+	}
+
+	// Check PATH first
+	if path, err := exec.LookPath("amd-smi"); err == nil {
+		return path
+	}
+
+	// Check common paths
+	for _, path := range commonPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
 }
 
 // findROCmSMI finds the rocm-smi executable
@@ -372,9 +830,30 @@ func parseFloat(s string) (float64, error) {
 	return strconv.ParseFloat(s, 64)
 }
 
+// lookupValue returns the value stored under key. Keys not present verbatim
+// are treated as dot-separated paths into nested objects, as amd-smi reports
+// them (e.g. "usage.gfx_activity").
+func lookupValue(m map[string]interface{}, key string) (interface{}, bool) {
+	if val, exists := m[key]; exists {
+		return val, true
+	}
+
+	var current interface{} = m
+	for _, part := range strings.Split(key, ".") {
+		nested, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = nested[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
 // getStringValue safely extracts a string value from a map
 func (d *AMDGPUDiscovery) getStringValue(m map[string]interface{}, key, defaultValue string) string {
-	if val, exists := m[key]; exists {
+	if val, exists := lookupValue(m, key); exists {
 		if str, ok := val.(string); ok {
 			return strings.TrimSpace(str)
 		}
@@ -382,18 +861,97 @@ func (d *AMDGPUDiscovery) getStringValue(m map[string]interface{}, key, defaultV
 	return defaultValue
 }
 
+// getInt64Value gets an integer value from a map, reporting whether it was present and valid
+func (d *AMDGPUDiscovery) getInt64Value(m map[string]interface{}, key string) (int64, bool) {
+	str, ok := m[key].(string)
+	if !ok {
+		return 0, false
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
 // getFloatValue safely extracts a float value from a map
 func (d *AMDGPUDiscovery) getFloatValue(m map[string]interface{}, key string, defaultValue float64) float64 {
-	if val, exists := m[key]; exists {
+	if val, exists := lookupValue(m, key); exists {
 		if str, ok := val.(string); ok {
 			if f, err := parseFloat(str); err == nil {
 				return f
 			}
 		}
 	}
+	if value, ok := d.getQuantity(m, key); ok {
+		return value
+	}
 	return defaultValue
 }
 
+// getQuantity extracts a numeric amd-smi value, given either as a bare number
+// or as a {"value": ..., "unit": ...} object. "N/A" values are reported as missing.
+func (d *AMDGPUDiscovery) getQuantity(m map[string]interface{}, key string) (float64, bool) {
+	value, _, ok := d.getQuantityWithUnit(m, key)
+	return value, ok
+}
+
+// getQuantityWithUnit is getQuantity that also returns the value's unit, if any
+func (d *AMDGPUDiscovery) getQuantityWithUnit(m map[string]interface{}, key string) (float64, string, bool) {
+	val, exists := lookupValue(m, key)
+	if !exists {
+		return 0, "", false
+	}
+
+	var unit string
+	if quantity, ok := val.(map[string]interface{}); ok {
+		val = quantity["value"]
+		unit, _ = quantity["unit"].(string)
+	}
+
+	switch v := val.(type) {
+	case float64:
+		return v, unit, true
+	case string:
+		// Older amd-smi releases print quantities as "196592 MB"
+		fields := strings.Fields(v)
+		if len(fields) == 0 {
+			return 0, "", false
+		}
+		f, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, "", false
+		}
+		if len(fields) > 1 {
+			unit = fields[1]
+		}
+		return f, unit, true
+	default:
+		return 0, "", false
+	}
+}
+
+// getMemoryValue extracts an amd-smi memory quantity in bytes. amd-smi reports
+// memory in MB, meaning MiB, unless another unit is given.
+func (d *AMDGPUDiscovery) getMemoryValue(m map[string]interface{}, key string) (int64, bool) {
+	value, unit, ok := d.getQuantityWithUnit(m, key)
+	if !ok {
+		return 0, false
+	}
+
+	switch strings.ToUpper(unit) {
+	case "B":
+		return int64(value), true
+	case "KB":
+		return int64(value * 1024), true
+	case "GB":
+		return int64(value * 1024 * 1024 * 1024), true
+	default:
+		return int64(value * 1024 * 1024), true
+	}
+}
+
 // MonitorGPUs continuously monitors GPU metrics
 func (d *AMDGPUDiscovery) MonitorGPUs(ctx context.Context, gpus map[string]*types.GPUInfo, interval time.Duration) {
 	ticker := time.NewTicker(interval)
@@ -404,29 +962,36 @@ func (d *AMDGPUDiscovery) MonitorGPUs(ctx context.Context, gpus map[string]*type
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			d.updateGPUMetrics(ctx, gpus)
+			d.UpdateGPUMetrics(ctx, gpus)
 		}
 	}
 }
 
-// updateGPUMetrics updates metrics for existing GPUs
-func (d *AMDGPUDiscovery) updateGPUMetrics(ctx context.Context, gpus map[string]*types.GPUInfo) {
-	// If ROCm SMI is available, use it for detailed metrics
-	if d.rocmSMIPath != "" {
-		d.updateMetricsWithROCmSMI(ctx, gpus)
-	} else {
+// UpdateGPUMetrics updates metrics for existing GPUs
+func (d *AMDGPUDiscovery) UpdateGPUMetrics(ctx context.Context, gpus map[string]*types.GPUInfo) {
+	// If amd-smi or ROCm SMI is available, use it for detailed metrics
+	switch {
+	case d.amdSMIPath != "":
+		discoveredGPUs, err := d.discoverWithAMDSMI(ctx)
+		if err != nil {
+			fmt.Printf("Failed to update metrics with AMD SMI: %v\n", err)
+			return
+		}
+		d.applyDiscoveredMetrics(gpus, discoveredGPUs)
+	case d.rocmSMIPath != "":
+		discoveredGPUs, err := d.discoverWithROCmSMI(ctx)
+		if err != nil {
+			fmt.Printf("Failed to update metrics with ROCm SMI: %v\n", err)
+			return
+		}
+		d.applyDiscoveredMetrics(gpus, discoveredGPUs)
+	default:
 		d.updateMetricsWithSysfs(ctx, gpus)
 	}
 }
 
-// updateMetricsWithROCmSMI updates metrics using ROCm SMI
-func (d *AMDGPUDiscovery) updateMetricsWithROCmSMI(ctx context.Context, gpus map[string]*types.GPUInfo) {
-	discoveredGPUs, err := d.discoverWithROCmSMI(ctx)
-	if err != nil {
-		fmt.Printf("Failed to update metrics with ROCm SMI: %v\n", err)
-		return
-	}
-
+// applyDiscoveredMetrics copies freshly discovered metrics onto known GPUs
+func (d *AMDGPUDiscovery) applyDiscoveredMetrics(gpus map[string]*types.GPUInfo, discoveredGPUs []*types.GPUInfo) {
 	for _, discoveredGPU := range discoveredGPUs {
 		if existingGPU, exists := gpus[discoveredGPU.DeviceID]; exists {
 			// Update metrics while preserving allocation info
@@ -434,8 +999,15 @@ func (d *AMDGPUDiscovery) updateMetricsWithROCmSMI(ctx context.Context, gpus map
 			existingGPU.Temperature = discoveredGPU.Temperature
 			existingGPU.Power = discoveredGPU.Power
 			existingGPU.AvailableMemory = discoveredGPU.AvailableMemory
-			existingGPU.IsAvailable = d.isGPUHealthy(existingGPU.Temperature, existingGPU.Utilization) &&
-				existingGPU.ActiveAllocations < 10 // Allocation limit
+			existingGPU.ClockMHz = discoveredGPU.ClockMHz
+			existingGPU.MemoryClockMHz = discoveredGPU.MemoryClockMHz
+			existingGPU.FanSpeedPercent = discoveredGPU.FanSpeedPercent
+			existingGPU.ECCErrorsCorrectable = discoveredGPU.ECCErrorsCorrectable
+			existingGPU.ECCErrorsUncorrectable = discoveredGPU.ECCErrorsUncorrectable
+			existingGPU.PartitionMode = discoveredGPU.PartitionMode
+			existingGPU.MemoryMode = discoveredGPU.MemoryMode
+			existingGPU.IsAvailable = d.isGPUHealthy(existingGPU) &&
+				!d.health.isFull(existingGPU.ActiveAllocations)
 		}
 	}
 }
@@ -446,10 +1018,12 @@ func (d *AMDGPUDiscovery) updateMetricsWithSysfs(ctx context.Context, gpus map[s
 		cardPath := filepath.Join(d.sysClassDRMPath, deviceID)
 		devicePath := filepath.Join(cardPath, "device")
 
+		utilization, temperature, power := gpu.Utilization, gpu.Temperature, gpu.Power
+
 		// Update utilization
 		if utilStr := d.readSysfsFile(filepath.Join(devicePath, "gpu_busy_percent")); utilStr != "" {
 			if util, err := strconv.ParseFloat(utilStr, 64); err == nil {
-				gpu.Utilization = util
+				utilization = util
 			}
 		}
 
@@ -462,7 +1036,7 @@ func (d *AMDGPUDiscovery) updateMetricsWithSysfs(ctx context.Context, gpus map[s
 			if matches, _ := filepath.Glob(tempPattern); len(matches) > 0 {
 				if tempStr := d.readSysfsFile(matches[0]); tempStr != "" {
 					if temp, err := strconv.ParseFloat(tempStr, 64); err == nil {
-						gpu.Temperature = temp / 1000.0
+						temperature = temp / 1000.0
 						break
 					}
 				}
@@ -478,15 +1052,19 @@ func (d *AMDGPUDiscovery) updateMetricsWithSysfs(ctx context.Context, gpus map[s
 			if matches, _ := filepath.Glob(powerPattern); len(matches) > 0 {
 				if powerStr := d.readSysfsFile(matches[0]); powerStr != "" {
 					if pow, err := strconv.ParseFloat(powerStr, 64); err == nil {
-						gpu.Power = pow / 1000000.0
+						power = pow / 1000000.0
 						break
 					}
 				}
 			}
 		}
 
+		gpu.Utilization, gpu.Temperature, gpu.Power = d.sanitizeReadings(deviceID, utilization, temperature, power)
+		d.readSysfsHealthMetrics(devicePath, gpu)
+		gpu.PartitionMode, gpu.MemoryMode = d.readPartitionModes(deviceID)
+
 		// Update availability
-		gpu.IsAvailable = d.isGPUHealthy(gpu.Temperature, gpu.Utilization) &&
-			gpu.ActiveAllocations < 10
+		gpu.IsAvailable = d.isGPUHealthy(gpu) &&
+			!d.health.isFull(gpu.ActiveAllocations)
 	}
 }
@@ -0,0 +1,541 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// writeSysfsCard creates a fake AMD card under drmPath with the given raw
+// sysfs sensor values
+func writeSysfsCard(t *testing.T, drmPath, card, busyPercent, tempMilliC, powerMicroW string) {
+	t.Helper()
+
+	devicePath := filepath.Join(drmPath, card, "device")
+	hwmonPath := filepath.Join(devicePath, "hwmon", "hwmon0")
+	if err := os.MkdirAll(hwmonPath, 0o755); err != nil {
+		t.Fatalf("Failed to create sysfs tree: %v", err)
+	}
+
+	files := map[string]string{
+		filepath.Join(devicePath, "vendor"):           "0x1002",
+		filepath.Join(devicePath, "gpu_busy_percent"): busyPercent,
+		filepath.Join(hwmonPath, "temp1_input"):       tempMilliC,
+		filepath.Join(hwmonPath, "power1_average"):    powerMicroW,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+}
+
+func newTestAMDGPUDiscovery(drmPath string) *AMDGPUDiscovery {
+	discovery := NewAMDGPUDiscovery()
+	discovery.amdSMIPath = ""
+	discovery.rocmSMIPath = ""
+	discovery.sysClassDRMPath = drmPath
+	discovery.sysBusPCIPath = filepath.Join(drmPath, "pci-devices") // Isolated from the host's PCI devices
+	return discovery
+}
+
+// writePCIDRMCard links a PCI bus address to a DRM card under pciPath, as
+// /sys/bus/pci/devices/<address>/drm/<card> does
+func writePCIDRMCard(t *testing.T, pciPath, busID, card string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(pciPath, busID, "drm", card), 0o755); err != nil {
+		t.Fatalf("Failed to create PCI device tree: %v", err)
+	}
+}
+
+func TestAMDGPUDiscovery_SanitizesOutOfRangeSysfsValues(t *testing.T) {
+	drmPath := t.TempDir()
+	// 250% busy, 511°C and 5kW are all sensor glitches
+	writeSysfsCard(t, drmPath, "card0", "250", "511000", "5000000000")
+
+	discovery := newTestAMDGPUDiscovery(drmPath)
+	gpus, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if len(gpus) != 1 {
+		t.Fatalf("Expected 1 GPU, got %d", len(gpus))
+	}
+
+	gpu := gpus[0]
+	if gpu.Utilization != 100 {
+		t.Errorf("Expected utilization clamped to 100, got %f", gpu.Utilization)
+	}
+	if gpu.Temperature != 0 {
+		t.Errorf("Expected unknown (0) temperature, got %f", gpu.Temperature)
+	}
+	if gpu.Power != 0 {
+		t.Errorf("Expected unknown (0) power, got %f", gpu.Power)
+	}
+	if !gpu.IsAvailable {
+		t.Error("Expected GPU to stay available when glitched temperature is discarded")
+	}
+}
+
+func TestAMDGPUDiscovery_PreservesLastGoodValues(t *testing.T) {
+	drmPath := t.TempDir()
+	writeSysfsCard(t, drmPath, "card0", "40", "65000", "300000000")
+
+	discovery := newTestAMDGPUDiscovery(drmPath)
+	discovered, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+
+	gpus := map[string]*types.GPUInfo{discovered[0].DeviceID: discovered[0]}
+
+	writeSysfsCard(t, drmPath, "card0", "-5", "511000", "-1")
+	discovery.UpdateGPUMetrics(context.Background(), gpus)
+
+	gpu := gpus["card0"]
+	if gpu.Utilization != 0 {
+		t.Errorf("Expected utilization clamped to 0, got %f", gpu.Utilization)
+	}
+	if gpu.Temperature != 65 {
+		t.Errorf("Expected last good temperature 65, got %f", gpu.Temperature)
+	}
+	if gpu.Power != 300 {
+		t.Errorf("Expected last good power 300, got %f", gpu.Power)
+	}
+}
+
+func TestAMDGPUDiscovery_SanitizesROCmSMIValues(t *testing.T) {
+	discovery := newTestAMDGPUDiscovery(t.TempDir())
+
+	gpu, err := discovery.convertROCmSMIToGPUInfo("card0", map[string]interface{}{
+		"Temperature (Sensor edge) (C)":             "72.0",
+		"GPU use (%)":                               "55",
+		"Current Socket Graphics Package Power (W)": "450.0",
+	})
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if gpu.Temperature != 72 || gpu.Utilization != 55 || gpu.Power != 450 {
+		t.Fatalf("Unexpected in-range values: %+v", gpu)
+	}
+
+	gpu, err = discovery.convertROCmSMIToGPUInfo("card0", map[string]interface{}{
+		"Temperature (Sensor edge) (C)":             "511.0",
+		"GPU use (%)":                               "180",
+		"Current Socket Graphics Package Power (W)": "99999",
+	})
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if gpu.Utilization != 100 {
+		t.Errorf("Expected utilization clamped to 100, got %f", gpu.Utilization)
+	}
+	if gpu.Temperature != 72 {
+		t.Errorf("Expected last good temperature 72, got %f", gpu.Temperature)
+	}
+	if gpu.Power != 450 {
+		t.Errorf("Expected last good power 450, got %f", gpu.Power)
+	}
+}
+
+func TestAMDGPUDiscovery_ParsesROCmSMIVRAM(t *testing.T) {
+	// Trimmed `rocm-smi --showallinfo --showmeminfo vram --json` output for an MI300X
+	output := `{
+  "card0": {
+    "Device Name": "AMD Instinct MI300X",
+    "Card Series": "AMD Instinct MI300X",
+    "Card Model": "0x74a1",
+    "Temperature (Sensor edge) (C)": "45.0",
+    "GPU use (%)": "12",
+    "Current Socket Graphics Package Power (W)": "180.0",
+    "GPU Memory Allocated (VRAM%)": "2",
+    "VRAM Total Memory (B)": "206141652992",
+    "VRAM Total Used Memory (B)": "10307082650"
+  },
+  "system": {
+    "Driver version": "6.7.0"
+  }
+}`
+	rocmSMI := filepath.Join(t.TempDir(), "rocm-smi")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + output + "\nEOF\n"
+	if err := os.WriteFile(rocmSMI, []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to write fake rocm-smi: %v", err)
+	}
+
+	discovery := newTestAMDGPUDiscovery(t.TempDir())
+	discovery.rocmSMIPath = rocmSMI
+
+	gpus, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if len(gpus) != 1 {
+		t.Fatalf("Expected 1 GPU, got %d", len(gpus))
+	}
+
+	gpu := gpus[0]
+	if gpu.TotalMemory != 206141652992 {
+		t.Errorf("Expected TotalMemory 206141652992 (192 GiB), got %d", gpu.TotalMemory)
+	}
+	if gpu.AvailableMemory != 206141652992-10307082650 {
+		t.Errorf("Expected AvailableMemory %d, got %d", int64(206141652992-10307082650), gpu.AvailableMemory)
+	}
+}
+
+func TestAMDGPUDiscovery_FallsBackToSeriesMemoryEstimate(t *testing.T) {
+	discovery := newTestAMDGPUDiscovery(t.TempDir())
+
+	gpu, err := discovery.convertROCmSMIToGPUInfo("card0", map[string]interface{}{
+		"Card Series":           "AMD Instinct MI210",
+		"VRAM Total Memory (B)": "N/A",
+	})
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if gpu.TotalMemory != 32*1024*1024*1024 {
+		t.Errorf("Expected series estimate of 32 GiB, got %d", gpu.TotalMemory)
+	}
+}
+
+// Trimmed `amd-smi static --json` and `amd-smi metric --json` output for an MI300X
+const (
+	amdSMIStaticMI300X = `[
+  {
+    "gpu": 0,
+    "asic": {
+      "market_name": "AMD Instinct MI300X",
+      "vendor_id": "0x1002",
+      "device_id": "0x74a1"
+    },
+    "vram": {
+      "type": "HBM",
+      "size": {"value": 196592, "unit": "MB"}
+    }
+  }
+]`
+	amdSMIMetricMI300X = `[
+  {
+    "gpu": 0,
+    "usage": {
+      "gfx_activity": {"value": 37, "unit": "%"},
+      "umc_activity": {"value": 4, "unit": "%"}
+    },
+    "power": {
+      "socket_power": {"value": 412, "unit": "W"}
+    },
+    "temperature": {
+      "edge": {"value": "N/A", "unit": "C"},
+      "hotspot": {"value": 58, "unit": "C"},
+      "mem": {"value": 49, "unit": "C"}
+    },
+    "mem_usage": {
+      "total_vram": {"value": 196592, "unit": "MB"},
+      "used_vram": {"value": 24576, "unit": "MB"}
+    }
+  }
+]`
+)
+
+// writeFakeAMDSMI writes an amd-smi script that prints static or metric JSON
+// depending on its subcommand
+func writeFakeAMDSMI(t *testing.T, static, metric string) string {
+	t.Helper()
+
+	amdSMI := filepath.Join(t.TempDir(), "amd-smi")
+	script := "#!/bin/sh\ncase \"$1\" in\nstatic) cat <<'EOF'\n" + static + "\nEOF\n;;\nmetric) cat <<'EOF'\n" + metric + "\nEOF\n;;\n*) exit 1 ;;\nesac\n"
+	if err := os.WriteFile(amdSMI, []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to write fake amd-smi: %v", err)
+	}
+	return amdSMI
+}
+
+func TestAMDGPUDiscovery_ParsesAMDSMI(t *testing.T) {
+	discovery := newTestAMDGPUDiscovery(t.TempDir())
+	discovery.amdSMIPath = writeFakeAMDSMI(t, amdSMIStaticMI300X, amdSMIMetricMI300X)
+	// amd-smi is preferred, so a broken rocm-smi must not be consulted
+	discovery.rocmSMIPath = "/nonexistent/rocm-smi"
+
+	gpus, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if len(gpus) != 1 {
+		t.Fatalf("Expected 1 GPU, got %d", len(gpus))
+	}
+
+	gpu := gpus[0]
+	if gpu.DeviceID != "card0" {
+		t.Errorf("Expected device ID card0, got %q", gpu.DeviceID)
+	}
+	if gpu.Model != "AMD Instinct MI300X" {
+		t.Errorf("Expected model AMD Instinct MI300X, got %q", gpu.Model)
+	}
+	if gpu.TotalMemory != 196592*1024*1024 {
+		t.Errorf("Expected TotalMemory %d, got %d", int64(196592*1024*1024), gpu.TotalMemory)
+	}
+	if gpu.AvailableMemory != (196592-24576)*1024*1024 {
+		t.Errorf("Expected AvailableMemory %d, got %d", int64((196592-24576)*1024*1024), gpu.AvailableMemory)
+	}
+	if gpu.Utilization != 37 || gpu.Power != 412 {
+		t.Errorf("Unexpected utilization %f or power %f", gpu.Utilization, gpu.Power)
+	}
+	if gpu.Temperature != 58 {
+		t.Errorf("Expected hotspot temperature 58 when edge is N/A, got %f", gpu.Temperature)
+	}
+}
+
+func TestAMDGPUDiscovery_ReadsPartitionModesWithAMDSMI(t *testing.T) {
+	// amd-smi numbers the GPU 0, but its DRM card is card1
+	drmPath := t.TempDir()
+	devicePath := filepath.Join(drmPath, "card1", "device")
+	if err := os.MkdirAll(devicePath, 0o755); err != nil {
+		t.Fatalf("Failed to create sysfs tree: %v", err)
+	}
+	writePartitionModes := func(compute, memory string) {
+		t.Helper()
+		for name, content := range map[string]string{
+			"current_compute_partition": compute + "\n",
+			"current_memory_partition":  memory + "\n",
+		} {
+			if err := os.WriteFile(filepath.Join(devicePath, name), []byte(content), 0o644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+	}
+	writePartitionModes("CPX", "NPS4")
+
+	discovery := newTestAMDGPUDiscovery(drmPath)
+	writePCIDRMCard(t, discovery.sysBusPCIPath, "0000:c1:00.0", "card1")
+	static := strings.Replace(amdSMIStaticMI300X, `"gpu": 0,`, `"gpu": 0, "bus": {"bdf": "0000:c1:00.0"},`, 1)
+	discovery.amdSMIPath = writeFakeAMDSMI(t, static, amdSMIMetricMI300X)
+
+	gpus, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if len(gpus) != 1 {
+		t.Fatalf("Expected 1 GPU, got %d", len(gpus))
+	}
+
+	gpu := gpus[0]
+	if gpu.DeviceID != "card1" {
+		t.Errorf("Expected the GPU to be named after its DRM card card1, got %s", gpu.DeviceID)
+	}
+	if gpu.PartitionMode != "CPX" || gpu.MemoryMode != "NPS4" {
+		t.Errorf("Expected CPX/NPS4 from sysfs, got %q/%q", gpu.PartitionMode, gpu.MemoryMode)
+	}
+	config := MI300XPartitionConfigFromGPU(gpu)
+	if config.ComputeMode != MI300XPartitionModeCPX || config.MemoryMode != MI300XMemoryModeNPS4 {
+		t.Errorf("Expected CPX/NPS4 partition config, got %+v", config)
+	}
+
+	// Repartitioning is picked up when metrics are refreshed
+	writePartitionModes("DPX", "NPS1")
+	discovery.UpdateGPUMetrics(context.Background(), map[string]*types.GPUInfo{gpu.DeviceID: gpu})
+	if gpu.PartitionMode != "DPX" || gpu.MemoryMode != "NPS1" {
+		t.Errorf("Expected DPX/NPS1 after refresh, got %q/%q", gpu.PartitionMode, gpu.MemoryMode)
+	}
+
+	// Without a DRM card at its bus address, card0's partition modes are not
+	// taken for the GPU amd-smi numbers 0
+	if err := os.MkdirAll(filepath.Join(drmPath, "card0", "device"), 0o755); err != nil {
+		t.Fatalf("Failed to create sysfs tree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(drmPath, "card0", "device", "current_compute_partition"), []byte("CPX\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write partition mode: %v", err)
+	}
+	discovery.amdSMIPath = writeFakeAMDSMI(t, amdSMIStaticMI300X, amdSMIMetricMI300X)
+	gpus, err = discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if gpus[0].DeviceID != "card0" || gpus[0].PartitionMode != "" {
+		t.Errorf("Expected card0 without partition modes, got %s with %q", gpus[0].DeviceID, gpus[0].PartitionMode)
+	}
+}
+
+func TestAMDGPUDiscovery_ParsesWrappedAMDSMIOutput(t *testing.T) {
+	// Newer amd-smi releases nest the per-GPU array under "gpu_data" and print
+	// some quantities as plain strings
+	static := `{"gpu_data": [{"gpu": 0, "asic": {"market_name": "AMD Instinct MI300X"}, "vram": {"size": "196592 MB"}}]}`
+	metric := `{"gpu_data": [{"gpu": 0, "usage": {"gfx_activity": 5}, "temperature": {"edge": 40}, "mem_usage": {"used_vram": "1024 MB"}}]}`
+
+	discovery := newTestAMDGPUDiscovery(t.TempDir())
+	discovery.amdSMIPath = writeFakeAMDSMI(t, static, metric)
+
+	gpus, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if len(gpus) != 1 {
+		t.Fatalf("Expected 1 GPU, got %d", len(gpus))
+	}
+
+	gpu := gpus[0]
+	if gpu.TotalMemory != 196592*1024*1024 {
+		t.Errorf("Expected TotalMemory from static vram size, got %d", gpu.TotalMemory)
+	}
+	if gpu.AvailableMemory != (196592-1024)*1024*1024 {
+		t.Errorf("Expected AvailableMemory %d, got %d", int64((196592-1024)*1024*1024), gpu.AvailableMemory)
+	}
+	if gpu.Utilization != 5 || gpu.Temperature != 40 {
+		t.Errorf("Unexpected utilization %f or temperature %f", gpu.Utilization, gpu.Temperature)
+	}
+}
+
+func TestAMDGPUDiscovery_DetectsPartitionModes(t *testing.T) {
+	drmPath := t.TempDir()
+	writeSysfsCard(t, drmPath, "card0", "10", "45000", "150000000")
+	writeSysfsCard(t, drmPath, "card1", "10", "45000", "150000000")
+
+	// Only card0 exposes partition state, card1 stands in for an older card
+	devicePath := filepath.Join(drmPath, "card0", "device")
+	for name, content := range map[string]string{
+		"current_compute_partition": "CPX\n",
+		"current_memory_partition":  "NPS4\n",
+	} {
+		if err := os.WriteFile(filepath.Join(devicePath, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	discovery := newTestAMDGPUDiscovery(drmPath)
+	discovered, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+
+	gpus := make(map[string]*types.GPUInfo)
+	for _, gpu := range discovered {
+		gpus[gpu.DeviceID] = gpu
+	}
+
+	partitioned := gpus["card0"]
+	if partitioned.PartitionMode != "CPX" || partitioned.MemoryMode != "NPS4" {
+		t.Errorf("Expected CPX/NPS4, got %q/%q", partitioned.PartitionMode, partitioned.MemoryMode)
+	}
+
+	config := MI300XPartitionConfigFromGPU(partitioned)
+	if config.ComputeMode != MI300XPartitionModeCPX || config.MemoryMode != MI300XMemoryModeNPS4 {
+		t.Errorf("Expected CPX/NPS4 partition config, got %+v", config)
+	}
+	allocator := NewMI300XFractionalAllocator()
+	if err := allocator.RegisterMI300XGPU(partitioned.DeviceID, partitioned.TotalMemory, config); err != nil {
+		t.Errorf("Failed to register GPU with detected partition config: %v", err)
+	}
+
+	legacy := gpus["card1"]
+	if legacy.PartitionMode != "" || legacy.MemoryMode != "" {
+		t.Errorf("Expected no partition modes without sysfs files, got %q/%q", legacy.PartitionMode, legacy.MemoryMode)
+	}
+	config = MI300XPartitionConfigFromGPU(legacy)
+	if config.ComputeMode != MI300XPartitionModeSPX || config.MemoryMode != MI300XMemoryModeNPS1 {
+		t.Errorf("Expected SPX/NPS1 defaults, got %+v", config)
+	}
+}
+
+func TestAMDGPUDiscovery_ParsesROCmSMIClockFanAndECC(t *testing.T) {
+	discovery := newTestAMDGPUDiscovery(t.TempDir())
+
+	gpu, err := discovery.convertROCmSMIToGPUInfo("card0", map[string]interface{}{
+		"Temperature (Sensor edge) (C)":  "50.0",
+		"sclk clock speed:":              "(2100Mhz)",
+		"mclk clock speed:":              "(1300Mhz)",
+		"Fan speed (%)":                  "42",
+		"UMC correctable error count":    "3",
+		"UMC uncorrectable error count":  "1",
+		"GFX correctable error count":    "2",
+		"SDMA uncorrectable error count": "0",
+	})
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	if gpu.ClockMHz != 2100 || gpu.MemoryClockMHz != 1300 {
+		t.Errorf("Expected clocks 2100/1300 MHz, got %f/%f", gpu.ClockMHz, gpu.MemoryClockMHz)
+	}
+	if gpu.FanSpeedPercent != 42 {
+		t.Errorf("Expected fan speed 42%%, got %f", gpu.FanSpeedPercent)
+	}
+	if gpu.ECCErrorsCorrectable != 5 || gpu.ECCErrorsUncorrectable != 1 {
+		t.Errorf("Expected 5 correctable and 1 uncorrectable ECC errors, got %d/%d",
+			gpu.ECCErrorsCorrectable, gpu.ECCErrorsUncorrectable)
+	}
+	if gpu.IsAvailable {
+		t.Error("Expected GPU with an uncorrectable ECC error to be unhealthy")
+	}
+}
+
+func TestAMDGPUDiscovery_ReadsSysfsClockFanAndECC(t *testing.T) {
+	drmPath := t.TempDir()
+	writeSysfsCard(t, drmPath, "card0", "30", "60000", "200000000")
+
+	devicePath := filepath.Join(drmPath, "card0", "device")
+	if err := os.MkdirAll(filepath.Join(devicePath, "ras"), 0o755); err != nil {
+		t.Fatalf("Failed to create ras directory: %v", err)
+	}
+	files := map[string]string{
+		filepath.Join(devicePath, "pp_dpm_sclk"):                 "0: 500Mhz\n1: 1800Mhz *\n2: 2100Mhz\n",
+		filepath.Join(devicePath, "pp_dpm_mclk"):                 "0: 900Mhz\n1: 1300Mhz *\n",
+		filepath.Join(devicePath, "hwmon", "hwmon0", "pwm1"):     "128",
+		filepath.Join(devicePath, "hwmon", "hwmon0", "pwm1_max"): "255",
+		filepath.Join(devicePath, "ras", "umc_err_count"):        "ue: 0\nce: 4\n",
+		filepath.Join(devicePath, "ras", "gfx_err_count"):        "ue: 0\nce: 1\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	discovery := newTestAMDGPUDiscovery(drmPath)
+	gpus, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+
+	gpu := gpus[0]
+	if gpu.ClockMHz != 1800 || gpu.MemoryClockMHz != 1300 {
+		t.Errorf("Expected active clocks 1800/1300 MHz, got %f/%f", gpu.ClockMHz, gpu.MemoryClockMHz)
+	}
+	if math.Abs(gpu.FanSpeedPercent-128.0/255.0*100) > 0.01 {
+		t.Errorf("Expected fan speed of about 50%%, got %f", gpu.FanSpeedPercent)
+	}
+	if gpu.ECCErrorsCorrectable != 5 || gpu.ECCErrorsUncorrectable != 0 {
+		t.Errorf("Expected 5 correctable and 0 uncorrectable ECC errors, got %d/%d",
+			gpu.ECCErrorsCorrectable, gpu.ECCErrorsUncorrectable)
+	}
+	if !gpu.IsAvailable {
+		t.Error("Expected correctable ECC errors alone to leave the GPU healthy")
+	}
+
+	// A new uncorrectable error is picked up on the next refresh
+	if err := os.WriteFile(filepath.Join(devicePath, "ras", "umc_err_count"), []byte("ue: 1\nce: 4\n"), 0o644); err != nil {
+		t.Fatalf("Failed to update umc_err_count: %v", err)
+	}
+	discovery.UpdateGPUMetrics(context.Background(), map[string]*types.GPUInfo{gpu.DeviceID: gpu})
+	if gpu.ECCErrorsUncorrectable != 1 || gpu.IsAvailable {
+		t.Errorf("Expected GPU to become unhealthy after an uncorrectable ECC error, got %d errors, available=%v",
+			gpu.ECCErrorsUncorrectable, gpu.IsAvailable)
+	}
+}
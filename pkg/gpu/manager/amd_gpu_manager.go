@@ -17,17 +17,16 @@ package manager
 import (
 	"context"
 	"fmt"
-	"time"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/silogen/kaiwo/pkg/gpu/types"
 )
 
 // AMDGPUManager manages AMD GPUs
 type AMDGPUManager struct {
-	*BaseGPUManager
-	gpus       map[string]*types.GPUInfo
-	lastUpdate time.Time
-	discovery  *AMDGPUDiscovery
+	*gpuManagerCore
 }
 
 // NewAMDGPUManager creates a new AMD GPU manager
@@ -40,228 +39,66 @@ func NewAMDGPUManager(config *GPUManagerConfig) (*AMDGPUManager, error) {
 		return nil, fmt.Errorf("invalid configuration: %v", err)
 	}
 
-	return &AMDGPUManager{
-		BaseGPUManager: NewBaseGPUManager(config),
-		gpus:           make(map[string]*types.GPUInfo),
-		lastUpdate:     time.Now(),
-		discovery:      NewAMDGPUDiscovery(),
-	}, nil
-}
-
-// Initialize initializes the AMD GPU manager
-func (a *AMDGPUManager) Initialize(ctx context.Context) error {
-	// Discover AMD GPUs
-	if err := a.discoverGPUs(ctx); err != nil {
-		return fmt.Errorf("failed to discover GPUs: %v", err)
-	}
-
-	// Start GPU monitoring with real discovery
-	go a.monitorGPUs(ctx)
-
-	return nil
-}
-
-// Shutdown shuts down the AMD GPU manager
-func (a *AMDGPUManager) Shutdown(ctx context.Context) error {
-	// Release all allocations
-	for allocationID := range a.BaseGPUManager.allocations {
-		if err := a.ReleaseGPU(ctx, allocationID); err != nil {
-			// Log error but continue
-			fmt.Printf("Error releasing allocation %s: %v\n", allocationID, err)
-		}
-	}
-
-	return nil
-}
-
-// ListGPUs lists all available AMD GPUs
-func (a *AMDGPUManager) ListGPUs(ctx context.Context) ([]*types.GPUInfo, error) {
-	// Update GPU information if needed
-	if time.Since(a.lastUpdate) > a.config.PollingInterval {
-		a.updateGPUInfo(ctx)
-	}
-
-	gpus := make([]*types.GPUInfo, 0, len(a.gpus))
-	for _, gpu := range a.gpus {
-		gpus = append(gpus, gpu)
-	}
-
-	return gpus, nil
-}
-
-// GetGPUInfo gets information about a specific AMD GPU
-func (a *AMDGPUManager) GetGPUInfo(ctx context.Context, deviceID string) (*types.GPUInfo, error) {
-	_, exists := a.gpus[deviceID]
-	if !exists {
-		return nil, fmt.Errorf("GPU %s not found", deviceID)
-	}
+	health := config.Health.withDefaults()
 
-	// Update GPU information
-	if err := a.updateSingleGPUInfo(context.Background(), deviceID); err != nil {
-		return nil, fmt.Errorf("failed to update GPU info: %v", err)
-	}
+	discovery := NewAMDGPUDiscovery()
+	discovery.SetHealthConfig(health)
 
-	return a.gpus[deviceID], nil
+	a := &AMDGPUManager{gpuManagerCore: newGPUManagerCore(config, "AMD", discovery, health)}
+	a.vendor = a
+	return a, nil
 }
 
-// AllocateGPU allocates an AMD GPU for a request
-func (a *AMDGPUManager) AllocateGPU(ctx context.Context, request *types.AllocationRequest) (*types.AllocationResult, error) {
-	// Validate the request
-	if err := a.ValidateAllocation(ctx, request); err != nil {
-		return nil, fmt.Errorf("invalid allocation request: %v", err)
-	}
-
-	// Find available GPU
-	selectedGPU, err := a.findAvailableGPU(ctx, request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find available GPU: %v", err)
-	}
-
-	// Create allocation
-	allocation := &types.GPUAllocation{
-		ID:            request.ID,
-		DeviceID:      selectedGPU.DeviceID,
-		Fraction:      request.GPURequest.Fraction,
-		MemoryRequest: request.GPURequest.MemoryRequest,
-		IsolationType: request.GPURequest.IsolationType,
-		PodName:       request.PodName,
-		Namespace:     request.Namespace,
-		ContainerName: request.ContainerName,
-		Status:        types.GPUAllocationStatusActive,
-		CreatedAt:     time.Now().Unix(),
-		ExpiresAt:     0, // No expiration by default
-	}
-
-	// Set expiration if specified
-	if request.ExpiresAt != nil {
-		allocation.ExpiresAt = request.ExpiresAt.Unix()
-	}
-
-	// Add allocation to manager
-	a.addAllocation(allocation)
-
-	// Update GPU information
-	selectedGPU.ActiveAllocations++
-	selectedGPU.IsAvailable = a.isGPUAvailable(selectedGPU)
-
-	// Create result
-	result := &types.AllocationResult{
-		Success:     true,
-		Allocation:  allocation,
-		DeviceID:    selectedGPU.DeviceID,
-		NodeName:    selectedGPU.NodeName,
-		AllocatedAt: time.Now(),
+// discover discovers AMD GPUs in the system, falling back to mock GPUs when
+// none are found and EnableMockGPUs is set. Callers must hold a.mu.
+func (a *AMDGPUManager) discover(ctx context.Context) ([]*types.GPUInfo, error) {
+	discoveredGPUs, err := a.discovery.DiscoverGPUs(ctx)
+	if err == nil && len(discoveredGPUs) == 0 {
+		err = fmt.Errorf("no AMD GPUs found")
 	}
-
-	return result, nil
-}
-
-// GetGPUStats gets AMD GPU statistics
-func (a *AMDGPUManager) GetGPUStats(ctx context.Context) (*types.GPUStats, error) {
-	gpus, err := a.ListGPUs(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list GPUs: %v", err)
-	}
-
-	stats := &types.GPUStats{
-		TotalGPUs:          len(gpus),
-		AvailableGPUs:      0,
-		TotalMemory:        0,
-		AvailableMemory:    0,
-		AverageUtilization: 0,
-		AverageTemperature: 0,
-		AveragePower:       0,
-		ActiveAllocations:  int(a.metrics.ActiveAllocations),
-	}
-
-	if len(gpus) == 0 {
-		return stats, nil
-	}
-
-	var totalUtilization, totalTemperature, totalPower float64
-
-	for _, gpu := range gpus {
-		if gpu.IsAvailable {
-			stats.AvailableGPUs++
+		if !a.config.EnableMockGPUs {
+			return nil, fmt.Errorf("failed to discover AMD GPUs: %w", err)
 		}
 
-		stats.TotalMemory += gpu.TotalMemory
-		stats.AvailableMemory += gpu.AvailableMemory
-		totalUtilization += gpu.Utilization
-		totalTemperature += gpu.Temperature
-		totalPower += gpu.Power
+		fmt.Printf("GPU discovery failed: %v, using mock GPUs\n", err)
+		a.mockGPUs = true
+		return newMockGPUs(), nil
 	}
 
-	stats.AverageUtilization = totalUtilization / float64(len(gpus))
-	stats.AverageTemperature = totalTemperature / float64(len(gpus))
-	stats.AveragePower = totalPower / float64(len(gpus))
-
-	return stats, nil
+	return discoveredGPUs, nil
 }
 
-// UpdateGPUInfo updates AMD GPU information
-func (a *AMDGPUManager) UpdateGPUInfo(ctx context.Context, deviceID string) error {
-	return a.updateSingleGPUInfo(ctx, deviceID)
-}
+// newMockGPUs returns simulated MI250X GPUs for machines without AMD hardware
+func newMockGPUs() []*types.GPUInfo {
+	nodeName, _ := os.Hostname()
 
-// discoverGPUs discovers AMD GPUs in the system using real GPU discovery
-func (a *AMDGPUManager) discoverGPUs(ctx context.Context) error {
-	// Use real AMD GPU discovery
-	discoveredGPUs, err := a.discovery.DiscoverGPUs(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to discover AMD GPUs: %w", err)
-	}
-
-	// Store discovered GPUs
-	for _, gpu := range discoveredGPUs {
-		a.gpus[gpu.DeviceID] = gpu
+	gpus := make([]*types.GPUInfo, 0, 2)
+	for i := 0; i < 2; i++ {
+		gpus = append(gpus, &types.GPUInfo{
+			DeviceID:        fmt.Sprintf("card%d", i),
+			Type:            types.GPUTypeAMD,
+			Model:           "AMD Instinct MI250X (mock)",
+			TotalMemory:     64 * 1024 * 1024 * 1024,
+			AvailableMemory: 64 * 1024 * 1024 * 1024,
+			NodeName:        nodeName,
+			IsAvailable:     true,
+			IsolationType:   types.GPUIsolationNone,
+		})
 	}
 
-	fmt.Printf("Discovered %d AMD GPUs\n", len(discoveredGPUs))
-	return nil
+	return gpus
 }
 
-// updateGPUInfo updates information for all GPUs using real discovery
-func (a *AMDGPUManager) updateGPUInfo(ctx context.Context) {
-	// Use the discovery monitoring to update all GPU metrics
-	a.discovery.updateGPUMetrics(ctx, a.gpus)
-	a.lastUpdate = time.Now()
-}
-
-// updateSingleGPUInfo updates information for a single GPU using real discovery
-func (a *AMDGPUManager) updateSingleGPUInfo(ctx context.Context, deviceID string) error {
-	_, exists := a.gpus[deviceID]
-	if !exists {
-		return fmt.Errorf("GPU %s not found", deviceID)
-	}
-
-	// Use the discovery system to update metrics for this specific GPU
-	// For now, we update all GPUs as most discovery systems work globally
-	a.discovery.updateGPUMetrics(ctx, a.gpus)
-
+// validateFraction accepts any fraction of an AMD GPU; the fractional
+// allocator enforces its remaining capacity
+func (a *AMDGPUManager) validateFraction(_ *types.GPUInfo, _ float64) error {
 	return nil
 }
 
-// findAvailableGPU finds an available GPU for allocation
-func (a *AMDGPUManager) findAvailableGPU(ctx context.Context, request *types.AllocationRequest) (*types.GPUInfo, error) {
-	gpus, err := a.ListGPUs(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list GPUs: %v", err)
-	}
-
-	// Filter available GPUs
-	var availableGPUs []*types.GPUInfo
-	for _, gpu := range gpus {
-		if gpu.IsAvailable && a.canGPUHandleRequest(gpu, request) {
-			availableGPUs = append(availableGPUs, gpu)
-		}
-	}
-
-	if len(availableGPUs) == 0 {
-		return nil, fmt.Errorf("no available GPUs found for request")
-	}
-
-	// Apply allocation strategy
+// selectGPU applies the request's allocation strategy to GPUs that can all
+// handle it
+func (a *AMDGPUManager) selectGPU(availableGPUs []*types.GPUInfo, request *types.AllocationRequest) (*types.GPUInfo, error) {
 	switch request.Strategy {
 	case types.AllocationStrategyFirstFit:
 		return availableGPUs[0], nil
@@ -278,39 +115,6 @@ func (a *AMDGPUManager) findAvailableGPU(ctx context.Context, request *types.All
 	}
 }
 
-// canGPUHandleRequest checks if a GPU can handle the allocation request
-func (a *AMDGPUManager) canGPUHandleRequest(gpu *types.GPUInfo, request *types.AllocationRequest) bool {
-	// Check if GPU has enough memory
-	if request.GPURequest.MemoryRequest > 0 {
-		if gpu.AvailableMemory < request.GPURequest.MemoryRequest*1024*1024 { // Convert MiB to bytes
-			return false
-		}
-	}
-
-	// Check if GPU can handle the fraction
-	// This is a simplified check - in practice, you'd need to check current allocations
-	if request.GPURequest.Fraction > 1.0 {
-		return false
-	}
-
-	return true
-}
-
-// isGPUAvailable checks if a GPU is available for allocation
-func (a *AMDGPUManager) isGPUAvailable(gpu *types.GPUInfo) bool {
-	// Check if GPU is healthy
-	if gpu.Temperature > 90.0 { // Overheating
-		return false
-	}
-
-	// Check if GPU has too many allocations
-	if gpu.ActiveAllocations >= 10 { // Arbitrary limit
-		return false
-	}
-
-	return true
-}
-
 // findBestFitGPU finds the GPU with the best fit for the request
 func (a *AMDGPUManager) findBestFitGPU(gpus []*types.GPUInfo, request *types.AllocationRequest) (*types.GPUInfo, error) {
 	if len(gpus) == 0 {
@@ -351,15 +155,20 @@ func (a *AMDGPUManager) findWorstFitGPU(gpus []*types.GPUInfo, request *types.Al
 	return worstGPU, nil
 }
 
-// findRoundRobinGPU finds the next GPU in round-robin fashion
+// findRoundRobinGPU finds the next GPU in round-robin fashion. Candidates are
+// taken in device ID order, indexed by the number of round-robin allocations
+// so far; AllocateGPU advances the count.
 func (a *AMDGPUManager) findRoundRobinGPU(gpus []*types.GPUInfo, request *types.AllocationRequest) (*types.GPUInfo, error) {
 	if len(gpus) == 0 {
 		return nil, fmt.Errorf("no GPUs available")
 	}
 
-	// Simple round-robin implementation
-	// In practice, you'd maintain a counter across requests
-	return gpus[0], nil
+	ordered := slices.Clone(gpus)
+	slices.SortFunc(ordered, func(x, y *types.GPUInfo) int {
+		return strings.Compare(x.DeviceID, y.DeviceID)
+	})
+
+	return ordered[a.roundRobin.Load()%uint64(len(ordered))], nil
 }
 
 // findLoadBalancedGPU finds the GPU with the best load balance
@@ -395,22 +204,7 @@ func (a *AMDGPUManager) calculateFitScore(gpu *types.GPUInfo, _ *types.Allocatio
 func (a *AMDGPUManager) calculateLoadScore(gpu *types.GPUInfo) float64 {
 	// Simple load score based on utilization and active allocations
 	utilizationScore := gpu.Utilization / 100.0
-	allocationScore := float64(gpu.ActiveAllocations) / 10.0 // Normalize to 0-1
+	allocationScore := allocationLoad(gpu.ActiveAllocations, a.health.MaxAllocations)
 
 	return utilizationScore + allocationScore
 }
-
-// monitorGPUs monitors GPU health and performance
-func (a *AMDGPUManager) monitorGPUs(ctx context.Context) {
-	ticker := time.NewTicker(a.config.PollingInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			a.updateGPUInfo(ctx)
-		}
-	}
-}
@@ -0,0 +1,624 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// newTestAMDGPUManager creates an AMD GPU manager backed by the given GPUs
+// instead of running discovery against the host
+func newTestAMDGPUManager(t *testing.T, gpus ...*types.GPUInfo) *AMDGPUManager {
+	t.Helper()
+	return newTestAMDGPUManagerWithConfig(t, newTestGPUManagerConfig(), gpus...)
+}
+
+func newTestGPUManagerConfig() *GPUManagerConfig {
+	return &GPUManagerConfig{
+		GPUType:               types.GPUTypeAMD,
+		PollingInterval:       time.Hour,
+		AllocationTimeout:     5 * time.Minute,
+		DefaultStrategy:       types.AllocationStrategyFirstFit,
+		EnableSharing:         true,
+		MaxFraction:           1.0,
+		MinFraction:           0.1,
+		AllowedIsolationTypes: []types.GPUIsolationType{types.GPUIsolationTimeSlicing, types.GPUIsolationNone},
+	}
+}
+
+func newTestAMDGPUManagerWithConfig(t *testing.T, config *GPUManagerConfig, gpus ...*types.GPUInfo) *AMDGPUManager {
+	t.Helper()
+
+	manager, err := NewAMDGPUManager(config)
+	if err != nil {
+		t.Fatalf("Failed to create AMD GPU manager: %v", err)
+	}
+
+	for _, gpu := range gpus {
+		if err := manager.addGPU(gpu); err != nil {
+			t.Fatalf("Failed to add GPU: %v", err)
+		}
+	}
+
+	return manager
+}
+
+func newTestGPUInfo(deviceID string, totalMemory int64) *types.GPUInfo {
+	return &types.GPUInfo{
+		DeviceID:        deviceID,
+		Type:            types.GPUTypeAMD,
+		Model:           "AMD Instinct MI300X",
+		TotalMemory:     totalMemory,
+		AvailableMemory: totalMemory,
+		NodeName:        "node-1",
+		IsAvailable:     true,
+		IsolationType:   types.GPUIsolationNone,
+	}
+}
+
+func newTestAllocationRequest(id string, fraction float64) *types.AllocationRequest {
+	return &types.AllocationRequest{
+		ID:            id,
+		PodName:       "pod-" + id,
+		Namespace:     "default",
+		ContainerName: "main",
+		GPURequest: &types.GPURequest{
+			Fraction:       fraction,
+			IsolationType:  types.GPUIsolationTimeSlicing,
+			SharingEnabled: true,
+		},
+		Strategy:  types.AllocationStrategyFirstFit,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestAMDGPUManagerConcurrentAllocation(t *testing.T) {
+	gpu := newTestGPUInfo("card0", 8*1024*1024*1024)
+	manager := newTestAMDGPUManager(t, gpu)
+	ctx := context.Background()
+
+	const workers = 50
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			request := newTestAllocationRequest(fmt.Sprintf("alloc-%d", i), 0.1)
+			if _, err := manager.AllocateGPU(ctx, request); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// isGPUAvailable caps a GPU at 10 active allocations
+	if succeeded != 10 {
+		t.Errorf("Expected 10 successful allocations, got %d", succeeded)
+	}
+
+	if gpu.ActiveAllocations != 10 {
+		t.Errorf("Expected GPU to hold 10 active allocations, got %d", gpu.ActiveAllocations)
+	}
+
+	allocations, err := manager.ListAllocations(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list allocations: %v", err)
+	}
+
+	if len(allocations) != succeeded {
+		t.Errorf("Expected %d tracked allocations, got %d", succeeded, len(allocations))
+	}
+}
+
+func TestAMDGPUManagerPerRequestTimeout(t *testing.T) {
+	// A rocm-smi that hangs stands in for a slow GPU refresh during allocation
+	rocmSMI := filepath.Join(t.TempDir(), "rocm-smi")
+	if err := os.WriteFile(rocmSMI, []byte("#!/bin/sh\nexec sleep 5\n"), 0o755); err != nil {
+		t.Fatalf("Failed to write fake rocm-smi: %v", err)
+	}
+
+	manager := newTestAMDGPUManager(t, newTestGPUInfo("card0", 8*1024*1024*1024))
+	discovery := NewAMDGPUDiscovery()
+	discovery.amdSMIPath = ""
+	discovery.rocmSMIPath = rocmSMI
+	manager.SetDiscovery(discovery)
+	manager.lastUpdate = time.Time{} // Force a refresh on the next allocation
+
+	request := newTestAllocationRequest("short-timeout", 0.5)
+	request.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := manager.AllocateGPU(context.Background(), request)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected allocation to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected per-request timeout to fire well before the %v default, took %v",
+			manager.config.AllocationTimeout, elapsed)
+	}
+}
+
+func TestAMDGPUManagerTimeoutCoversLockWait(t *testing.T) {
+	manager := newTestAMDGPUManager(t, newTestGPUInfo("card0", 8*1024*1024*1024))
+
+	// Another operation holds the lock for longer than the request may wait
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	request := newTestAllocationRequest("lock-wait", 0.5)
+	request.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := manager.AllocateGPU(context.Background(), request)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected allocation to time out waiting for the lock, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected allocation to give up after its 100ms timeout, took %v", elapsed)
+	}
+
+	// An already cancelled context fails without waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("cancelled", 0.5)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled allocation to fail with context.Canceled, got %v", err)
+	}
+}
+
+func TestAMDGPUManagerAllocationTimeoutFallback(t *testing.T) {
+	manager := newTestAMDGPUManager(t)
+
+	request := newTestAllocationRequest("default-timeout", 0.5)
+	if timeout := manager.allocationTimeout(request); timeout != manager.config.AllocationTimeout {
+		t.Errorf("Expected unset timeout to fall back to %v, got %v", manager.config.AllocationTimeout, timeout)
+	}
+
+	request.Timeout = time.Second
+	if timeout := manager.allocationTimeout(request); timeout != time.Second {
+		t.Errorf("Expected per-request timeout 1s, got %v", timeout)
+	}
+
+	request.Timeout = -time.Second
+	if _, err := manager.AllocateGPU(context.Background(), request); err == nil {
+		t.Error("Expected negative per-request timeout to be rejected")
+	}
+}
+
+// fakeGPUDiscovery is a GPUDiscovery returning fixed GPUs. UpdateGPUMetrics
+// replaces every GPU with a fresh copy reporting the configured utilization, as
+// a real discovery pass would.
+type fakeGPUDiscovery struct {
+	gpus        []*types.GPUInfo
+	err         error
+	utilization float64
+}
+
+func (f *fakeGPUDiscovery) DiscoverGPUs(ctx context.Context) ([]*types.GPUInfo, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	gpus := make([]*types.GPUInfo, 0, len(f.gpus))
+	for _, gpu := range f.gpus {
+		discovered := *gpu
+		gpus = append(gpus, &discovered)
+	}
+	return gpus, nil
+}
+
+func (f *fakeGPUDiscovery) UpdateGPUMetrics(ctx context.Context, gpus map[string]*types.GPUInfo) {
+	for deviceID, gpu := range gpus {
+		refreshed := *gpu
+		refreshed.Utilization = f.utilization
+		refreshed.ActiveAllocations = 0
+		gpus[deviceID] = &refreshed
+	}
+}
+
+func TestAMDGPUManagerUsesInjectedDiscovery(t *testing.T) {
+	manager := newTestAMDGPUManager(t)
+	manager.SetDiscovery(&fakeGPUDiscovery{gpus: []*types.GPUInfo{
+		newTestGPUInfo("gpu-a", 8*1024*1024*1024),
+		newTestGPUInfo("gpu-b", 8*1024*1024*1024),
+	}})
+
+	ctx := context.Background()
+	if err := manager.Initialize(ctx); err != nil {
+		t.Fatalf("Failed to initialize manager: %v", err)
+	}
+	t.Cleanup(func() { manager.Shutdown(ctx) })
+
+	gpus, err := manager.ListGPUs(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list GPUs: %v", err)
+	}
+
+	var deviceIDs []string
+	for _, gpu := range gpus {
+		deviceIDs = append(deviceIDs, gpu.DeviceID)
+	}
+	slices.Sort(deviceIDs)
+	if !slices.Equal(deviceIDs, []string{"gpu-a", "gpu-b"}) {
+		t.Errorf("Expected discovered GPUs [gpu-a gpu-b], got %v", deviceIDs)
+	}
+}
+
+func TestAMDGPUManagerMockGPUFallback(t *testing.T) {
+	ctx := context.Background()
+
+	manager := newTestAMDGPUManager(t)
+	manager.SetDiscovery(&fakeGPUDiscovery{err: errors.New("no rocm-smi")})
+	if err := manager.Initialize(ctx); err == nil {
+		t.Fatal("Expected initialization to fail without GPUs when mock GPUs are disabled")
+	}
+
+	manager = newTestAMDGPUManager(t)
+	manager.config.EnableMockGPUs = true
+	manager.SetDiscovery(&fakeGPUDiscovery{})
+	if err := manager.Initialize(ctx); err != nil {
+		t.Fatalf("Failed to initialize manager with mock GPUs: %v", err)
+	}
+	t.Cleanup(func() { manager.Shutdown(ctx) })
+
+	gpus, err := manager.ListGPUs(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list GPUs: %v", err)
+	}
+	if len(gpus) == 0 {
+		t.Fatal("Expected mock GPUs when discovery finds none")
+	}
+}
+
+func TestAMDGPUManagerPreservesActiveAllocationsOnRefresh(t *testing.T) {
+	manager := newTestAMDGPUManager(t)
+	discovery := &fakeGPUDiscovery{gpus: []*types.GPUInfo{newTestGPUInfo("gpu-a", 8*1024*1024*1024)}}
+	manager.SetDiscovery(discovery)
+
+	ctx := context.Background()
+	if err := manager.Initialize(ctx); err != nil {
+		t.Fatalf("Failed to initialize manager: %v", err)
+	}
+	t.Cleanup(func() { manager.Shutdown(ctx) })
+
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("alloc-1", 0.5)); err != nil {
+		t.Fatalf("Failed to allocate GPU: %v", err)
+	}
+
+	discovery.utilization = 42
+	if err := manager.UpdateGPUInfo(ctx, "gpu-a"); err != nil {
+		t.Fatalf("Failed to update GPU info: %v", err)
+	}
+
+	gpu, err := manager.GetGPUInfo(ctx, "gpu-a")
+	if err != nil {
+		t.Fatalf("Failed to get GPU info: %v", err)
+	}
+	if gpu.Utilization != 42 {
+		t.Errorf("Expected refreshed utilization 42, got %f", gpu.Utilization)
+	}
+	if gpu.ActiveAllocations != 1 {
+		t.Errorf("Expected 1 active allocation to survive the refresh, got %d", gpu.ActiveAllocations)
+	}
+}
+
+func TestAMDGPUManagerCustomHealthThresholds(t *testing.T) {
+	config := newTestGPUManagerConfig()
+	config.Health = HealthConfig{MaxTemperature: 105, MaxAllocations: 2}
+
+	// 95°C is within the MI300X envelope, though above the 90°C default
+	warm := newTestGPUInfo("card0", 8*1024*1024*1024)
+	warm.Temperature = 95
+	manager := newTestAMDGPUManagerWithConfig(t, config, warm)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest(fmt.Sprintf("alloc-%d", i), 0.1)); err != nil {
+			t.Fatalf("Expected allocation %d on a 95°C GPU to succeed: %v", i, err)
+		}
+	}
+
+	if warm.IsAvailable {
+		t.Error("Expected GPU to be full at the custom limit of 2 allocations")
+	}
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("alloc-2", 0.1)); err == nil {
+		t.Error("Expected allocation beyond the custom allocation limit to fail")
+	}
+
+	hot := newTestGPUInfo("card1", 8*1024*1024*1024)
+	hot.Temperature = 110
+	if manager.isGPUAvailable(hot) {
+		t.Error("Expected GPU above the custom temperature limit to be unhealthy")
+	}
+}
+
+func TestAMDGPUManagerLoadScoreUsesAllocationLimit(t *testing.T) {
+	config := newTestGPUManagerConfig()
+	config.Health = HealthConfig{MaxAllocations: 20}
+	manager := newTestAMDGPUManagerWithConfig(t, config)
+
+	idle := newTestGPUInfo("card0", 8*1024*1024*1024)
+	idle.ActiveAllocations = 10
+	if score := manager.calculateLoadScore(idle); math.Abs(score-0.5) > 1e-9 {
+		t.Errorf("Expected 10 of 20 allocations to score 0.5, got %f", score)
+	}
+
+	// Allocations past the limit do not push the score beyond a full GPU's
+	idle.ActiveAllocations = 40
+	if score := manager.calculateLoadScore(idle); math.Abs(score-1) > 1e-9 {
+		t.Errorf("Expected allocations beyond the limit to score 1, got %f", score)
+	}
+}
+
+func TestAMDGPUDiscoveryCustomHealthThresholds(t *testing.T) {
+	drmPath := t.TempDir()
+	writeSysfsCard(t, drmPath, "card0", "10", "75000", "300000000")
+
+	discovery := newTestAMDGPUDiscovery(drmPath)
+	discovery.SetHealthConfig(HealthConfig{MaxTemperature: 70})
+
+	gpus, err := discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if gpus[0].IsAvailable {
+		t.Error("Expected GPU at 75°C to be unhealthy with a 70°C limit")
+	}
+
+	discovery.SetHealthConfig(HealthConfig{MaxTemperature: 80, MaxPower: 250})
+	gpus, err = discovery.DiscoverGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if gpus[0].IsAvailable {
+		t.Error("Expected GPU drawing 300W to be unhealthy with a 250W limit")
+	}
+}
+
+func TestInvalidHealthConfig(t *testing.T) {
+	config := newTestGPUManagerConfig()
+	config.Health = HealthConfig{MaxAllocations: -1}
+
+	if _, err := NewAMDGPUManager(config); err == nil {
+		t.Error("Expected negative allocation limit to be rejected")
+	}
+}
+
+func TestAMDGPUManagerReleaseFreesGPU(t *testing.T) {
+	gpu := newTestGPUInfo("card0", 8*1024*1024*1024)
+	manager := newTestAMDGPUManager(t, gpu)
+	ctx := context.Background()
+
+	// Three times the per-GPU allocation limit, so leaked slots would exhaust the GPU
+	for i := 0; i < 3*DefaultMaxAllocations; i++ {
+		request := newTestAllocationRequest(fmt.Sprintf("cycle-%d", i), 0.5)
+		if _, err := manager.AllocateGPU(ctx, request); err != nil {
+			t.Fatalf("Allocation %d failed: %v", i, err)
+		}
+		if err := manager.ReleaseGPU(ctx, request.ID); err != nil {
+			t.Fatalf("Release %d failed: %v", i, err)
+		}
+	}
+
+	if gpu.ActiveAllocations != 0 {
+		t.Errorf("Expected no active allocations after releasing them all, got %d", gpu.ActiveAllocations)
+	}
+	if !gpu.IsAvailable {
+		t.Error("Expected GPU to stay available")
+	}
+
+	// A GPU filled to the limit becomes available again once one allocation is released
+	for i := 0; i < DefaultMaxAllocations; i++ {
+		if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest(fmt.Sprintf("fill-%d", i), 0.1)); err != nil {
+			t.Fatalf("Allocation %d failed: %v", i, err)
+		}
+	}
+	if gpu.IsAvailable {
+		t.Fatal("Expected GPU to be full")
+	}
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("overflow", 0.1)); !errors.Is(err, ErrInsufficientCapacity) {
+		t.Errorf("Expected allocation on a full GPU to fail with ErrInsufficientCapacity, got %v", err)
+	}
+	if err := manager.ReleaseGPU(ctx, "fill-0"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if !gpu.IsAvailable || gpu.ActiveAllocations != DefaultMaxAllocations-1 {
+		t.Errorf("Expected GPU available with %d allocations, got available=%v with %d",
+			DefaultMaxAllocations-1, gpu.IsAvailable, gpu.ActiveAllocations)
+	}
+
+	if err := manager.ReleaseGPU(ctx, "fill-0"); err == nil {
+		t.Error("Expected releasing an already released allocation to fail")
+	}
+}
+
+func TestAMDGPUManagerReleaseAfterExpiry(t *testing.T) {
+	gpu := newTestGPUInfo("card0", 8*1024*1024*1024)
+	manager := newTestAMDGPUManager(t, gpu)
+	ctx := context.Background()
+
+	expired := time.Now().Add(-time.Minute)
+	request := newTestAllocationRequest("expiring", 0.5)
+	request.ExpiresAt = &expired
+	if _, err := manager.AllocateGPU(ctx, request); err != nil {
+		t.Fatalf("Allocation failed: %v", err)
+	}
+
+	// The allocator expires its fraction before the manager releases the allocation
+	manager.fractional.CleanupExpiredAllocations()
+
+	if err := manager.ReleaseGPU(ctx, request.ID); err != nil {
+		t.Fatalf("Expected release of an expired allocation to succeed, got %v", err)
+	}
+	if _, err := manager.GetAllocation(ctx, request.ID); err == nil {
+		t.Error("Expected the released allocation to be forgotten")
+	}
+	if gpu.ActiveAllocations != 0 {
+		t.Errorf("Expected no active allocations, got %d", gpu.ActiveAllocations)
+	}
+}
+
+func TestAMDGPUManagerRoundRobin(t *testing.T) {
+	manager := newTestAMDGPUManager(t,
+		newTestGPUInfo("card0", 8*1024*1024*1024),
+		newTestGPUInfo("card1", 8*1024*1024*1024),
+		newTestGPUInfo("card2", 8*1024*1024*1024),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 7; i++ {
+		request := newTestAllocationRequest(fmt.Sprintf("rr-%d", i), 0.1)
+		request.Strategy = types.AllocationStrategyRoundRobin
+
+		// Explaining a placement must not advance the rotation
+		explanation, err := manager.ExplainAllocation(ctx, request)
+		if err != nil {
+			t.Fatalf("Failed to explain allocation %d: %v", i, err)
+		}
+
+		result, err := manager.AllocateGPU(ctx, request)
+		if err != nil {
+			t.Fatalf("Allocation %d failed: %v", i, err)
+		}
+
+		expected := fmt.Sprintf("card%d", i%3)
+		if result.DeviceID != expected {
+			t.Errorf("Expected allocation %d on %s, got %s", i, expected, result.DeviceID)
+		}
+		if explanation.SelectedGPU != result.DeviceID {
+			t.Errorf("Expected explanation to predict %s, got %s", result.DeviceID, explanation.SelectedGPU)
+		}
+	}
+}
+
+func TestAMDGPUManagerTracksFractionalCapacity(t *testing.T) {
+	gpu := newTestGPUInfo("card0", 8*1024*1024*1024)
+	manager := newTestAMDGPUManager(t, gpu)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest(fmt.Sprintf("half-%d", i), 0.5)); err != nil {
+			t.Fatalf("Allocation %d failed: %v", i, err)
+		}
+	}
+
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("half-2", 0.5)); err == nil {
+		t.Fatal("Expected a third half-GPU allocation on a full card to fail")
+	}
+
+	stats, err := manager.GetGPUStats(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get GPU stats: %v", err)
+	}
+	if stats.AllocatedFraction != 1.0 {
+		t.Errorf("Expected allocated fraction 1.0, got %f", stats.AllocatedFraction)
+	}
+
+	explanation, err := manager.ExplainAllocation(ctx, newTestAllocationRequest("half-2", 0.5))
+	if err != nil {
+		t.Fatalf("Failed to explain allocation: %v", err)
+	}
+	if len(explanation.Filtered) != 1 || explanation.Filtered[0].Reason != CandidateFilterInsufficientCapacity {
+		t.Errorf("Expected the full card to be filtered for insufficient capacity, got %+v", explanation.Filtered)
+	}
+
+	// Releasing one half makes room again
+	if err := manager.ReleaseGPU(ctx, "half-0"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("half-2", 0.5)); err != nil {
+		t.Errorf("Expected allocation to succeed after a release: %v", err)
+	}
+}
+
+func TestAMDGPUManagerTracksAllocatedMemory(t *testing.T) {
+	manager := newTestAMDGPUManager(t, newTestGPUInfo("card0", 8*1024*1024*1024))
+	ctx := context.Background()
+
+	first := newTestAllocationRequest("mem-0", 0.2)
+	first.GPURequest.MemoryRequest = 6 * 1024 // MiB
+	if _, err := manager.AllocateGPU(ctx, first); err != nil {
+		t.Fatalf("First allocation failed: %v", err)
+	}
+
+	// Plenty of fractional capacity is left, but only 2 GiB of unallocated memory
+	second := newTestAllocationRequest("mem-1", 0.2)
+	second.GPURequest.MemoryRequest = 4 * 1024 // MiB
+	if _, err := manager.AllocateGPU(ctx, second); err == nil {
+		t.Error("Expected allocation exceeding the unallocated memory to fail")
+	}
+}
+
+func TestAMDGPUManagerReportsAvailableCapacity(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	manager := newTestAMDGPUManager(t, newTestGPUInfo("card0", 8*gib), newTestGPUInfo("card1", 8*gib))
+	ctx := context.Background()
+
+	request := newTestAllocationRequest("half", 0.5)
+	request.GPURequest.MemoryRequest = 2 * 1024 // MiB
+	result, err := manager.AllocateGPU(ctx, request)
+	if err != nil {
+		t.Fatalf("Allocation failed: %v", err)
+	}
+	allocated := result.Allocation.DeviceID
+
+	capacity, err := manager.GetAvailableCapacity(ctx)
+	if err != nil {
+		t.Fatalf("GetAvailableCapacity failed: %v", err)
+	}
+	if len(capacity) != 2 || capacity[0].DeviceID != "card0" || capacity[1].DeviceID != "card1" {
+		t.Fatalf("Expected capacity for card0 and card1 in order, got %+v", capacity)
+	}
+	for _, free := range capacity {
+		wantFraction, wantMemory := 1.0, int64(8*gib)
+		if free.DeviceID == allocated {
+			wantFraction, wantMemory = 0.5, 6*gib
+		}
+		if math.Abs(free.FreeFraction-wantFraction) > 1e-9 || free.FreeMemory != wantMemory || !free.Available {
+			t.Errorf("Expected %s to have %.1f and %d bytes free, got %+v", free.DeviceID, wantFraction, wantMemory, free)
+		}
+	}
+
+	if err := manager.ReleaseGPU(ctx, result.Allocation.ID); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	capacity, err = manager.GetAvailableCapacity(ctx)
+	if err != nil {
+		t.Fatalf("GetAvailableCapacity failed: %v", err)
+	}
+	for _, free := range capacity {
+		if free.FreeFraction != 1.0 || free.FreeMemory != 8*gib {
+			t.Errorf("Expected %s to be free again after release, got %+v", free.DeviceID, free)
+		}
+	}
+}
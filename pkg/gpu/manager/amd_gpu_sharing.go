@@ -15,6 +15,7 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -31,13 +32,35 @@ type AMDGPUSharing struct {
 	// gpuMemoryUsage tracks memory usage per GPU
 	gpuMemoryUsage map[string]int64
 
+	// gpuTotalMemory tracks the memory capacity of each registered GPU in bytes
+	gpuTotalMemory map[string]int64
+
 	// gpuScheduling tracks time-slicing information
 	gpuScheduling map[string]*GPUScheduler
 
+	// timeSliceConfigs holds per-GPU adaptive time-slice settings
+	timeSliceConfigs map[string]TimeSliceConfig
+
+	// now returns the current time, replaceable in tests
+	now func() time.Time
+
+	// loopCtx is the context of the scheduling loops started by Start, nil when stopped
+	loopCtx context.Context
+
+	// stopLoops cancels loopCtx
+	stopLoops context.CancelFunc
+
+	// loops tracks the running per-GPU scheduling loops
+	loops sync.WaitGroup
+
 	// mutex for thread safety
 	mu sync.RWMutex
 }
 
+// defaultTimeSlice is the time slice a GPU starts with when its time-slice
+// settings do not give one
+const defaultTimeSlice = 30 * time.Second
+
 // GPUScheduler manages time-slicing for AMD GPUs
 type GPUScheduler struct {
 	// timeSlice is the time slice allocated to each workload (in seconds)
@@ -51,36 +74,111 @@ type GPUScheduler struct {
 
 	// lastSwitch is the last time we switched workloads
 	lastSwitch time.Time
+
+	// switchCount is the number of workload switches performed
+	switchCount int64
+
+	// grantedSlices is the number of completed time slices
+	grantedSlices int64
+
+	// grantedTime is the total time granted across completed time slices
+	grantedTime time.Duration
+
+	// switchOverhead is the smoothed estimate of the cost of a workload switch
+	switchOverhead time.Duration
+
+	// stopLoop cancels the GPU's scheduling loop, nil when none is running
+	stopLoop context.CancelFunc
+}
+
+// TimeSliceConfig bounds the adaptive time slice of a GPU
+type TimeSliceConfig struct {
+	// InitialSlice is the time slice a GPU starts with, before any switch overhead is
+	// recorded; zero means 30s. It is clamped to MinSlice and MaxSlice.
+	InitialSlice time.Duration `json:"initialSlice,omitempty"`
+	// MinSlice is the shortest time slice the scheduler may use
+	MinSlice time.Duration `json:"minSlice"`
+	// MaxSlice is the longest time slice the scheduler may use
+	MaxSlice time.Duration `json:"maxSlice"`
+	// TargetOverheadRatio is the share of each slice that switch overhead should take up
+	TargetOverheadRatio float64 `json:"targetOverheadRatio"`
+}
+
+// DefaultTimeSliceConfig returns the time-slice settings used when none are configured
+func DefaultTimeSliceConfig() TimeSliceConfig {
+	return TimeSliceConfig{
+		InitialSlice:        defaultTimeSlice,
+		MinSlice:            5 * time.Second,
+		MaxSlice:            5 * time.Minute,
+		TargetOverheadRatio: 0.05,
+	}
+}
+
+// SchedulerStats reports time-slicing behavior for a GPU
+type SchedulerStats struct {
+	DeviceID         string        `json:"deviceId"`
+	SwitchCount      int64         `json:"switchCount"`
+	AverageSlice     time.Duration `json:"averageSlice"`
+	QueueLength      int           `json:"queueLength"`
+	TimeSlice        time.Duration `json:"timeSlice"`
+	ActiveWorkloadID string        `json:"activeWorkloadId,omitempty"`
 }
 
 // NewAMDGPUSharing creates a new AMD GPU sharing manager
 func NewAMDGPUSharing() *AMDGPUSharing {
 	return &AMDGPUSharing{
-		gpuWorkloads:   make(map[string][]*types.GPUAllocation),
-		gpuMemoryUsage: make(map[string]int64),
-		gpuScheduling:  make(map[string]*GPUScheduler),
+		gpuWorkloads:     make(map[string][]*types.GPUAllocation),
+		gpuMemoryUsage:   make(map[string]int64),
+		gpuTotalMemory:   make(map[string]int64),
+		gpuScheduling:    make(map[string]*GPUScheduler),
+		timeSliceConfigs: make(map[string]TimeSliceConfig),
+		now:              time.Now,
 	}
 }
 
+// RegisterGPU registers a GPU and its total memory in bytes, which bounds the
+// memory of the workloads sharing it. Registering a GPU that is already
+// registered fails with ErrDeviceAlreadyRegistered.
+func (a *AMDGPUSharing) RegisterGPU(deviceID string, totalMemory int64) error {
+	if err := validateGPURegistration(deviceID, totalMemory); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, exists := a.gpuTotalMemory[deviceID]; exists {
+		return fmt.Errorf("%w: %s", ErrDeviceAlreadyRegistered, deviceID)
+	}
+	a.gpuTotalMemory[deviceID] = totalMemory
+
+	return nil
+}
+
 // CanAllocate checks if an AMD GPU can handle the allocation request
 // Note: AMD GPUs don't support true fractional allocation like NVIDIA MIG
 func (a *AMDGPUSharing) CanAllocate(deviceID string, request *types.GPURequest) (bool, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.canAllocate(deviceID, request)
+}
+
+// canAllocate checks if an allocation fits in the GPU's free memory. Callers must hold a.mu.
+func (a *AMDGPUSharing) canAllocate(deviceID string, request *types.GPURequest) (bool, error) {
 	if request == nil {
 		return false, fmt.Errorf("GPU request cannot be nil")
 	}
 
-	a.mu.RLock()
-	defer a.mu.RUnlock()
+	totalMemory, exists := a.gpuTotalMemory[deviceID]
+	if !exists {
+		return false, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
 
 	// Check memory availability (this is the main constraint for AMD GPUs)
 	requestedMemory := request.MemoryRequest * 1024 * 1024 // Convert MiB to bytes
 	usedMemory := a.gpuMemoryUsage[deviceID]
 
-	// Get GPU info to check total memory
-	// This would need to be passed in or retrieved from the GPU manager
-	// For now, we'll use a conservative estimate
-	totalMemory := int64(8 * 1024 * 1024 * 1024) // 8GB default
-
 	availableMemory := totalMemory - usedMemory
 	if requestedMemory > availableMemory {
 		return false, fmt.Errorf("insufficient memory: requested %d bytes, available %d bytes",
@@ -94,7 +192,12 @@ func (a *AMDGPUSharing) CanAllocate(deviceID string, request *types.GPURequest)
 
 // Allocate allocates GPU resources for AMD GPUs using time-slicing
 func (a *AMDGPUSharing) Allocate(deviceID string, request *types.AllocationRequest) (*types.GPUAllocation, error) {
-	canAllocate, err := a.CanAllocate(deviceID, request.GPURequest)
+	// Admission and the memory update happen under one lock so concurrent
+	// requests cannot overcommit the GPU
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	canAllocate, err := a.canAllocate(deviceID, request.GPURequest)
 	if err != nil {
 		return nil, err
 	}
@@ -103,9 +206,6 @@ func (a *AMDGPUSharing) Allocate(deviceID string, request *types.AllocationReque
 		return nil, fmt.Errorf("cannot allocate GPU resources for request")
 	}
 
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
 	// Create allocation (no hardware partitioning, just resource tracking)
 	allocation := &types.GPUAllocation{
 		ID:            request.ID,
@@ -116,7 +216,7 @@ func (a *AMDGPUSharing) Allocate(deviceID string, request *types.AllocationReque
 		PodName:       request.PodName,
 		Namespace:     request.Namespace,
 		Status:        types.GPUAllocationStatusPending, // Will be scheduled for time-slicing
-		CreatedAt:     time.Now().Unix(),
+		CreatedAt:     a.now().Unix(),
 	}
 
 	// Add to workload queue
@@ -131,13 +231,16 @@ func (a *AMDGPUSharing) Allocate(deviceID string, request *types.AllocationReque
 	// Initialize scheduler if needed
 	if a.gpuScheduling[deviceID] == nil {
 		a.gpuScheduling[deviceID] = &GPUScheduler{
-			timeSlice:  30 * time.Second, // 30-second time slices
-			lastSwitch: time.Now(),
+			timeSlice:  a.timeSliceConfig(deviceID).initialSlice(),
+			lastSwitch: a.now(),
 		}
 	}
 
 	// Add to scheduling queue
 	a.gpuScheduling[deviceID].workloadQueue = append(a.gpuScheduling[deviceID].workloadQueue, allocation)
+	if a.loopCtx != nil && a.gpuScheduling[deviceID].stopLoop == nil {
+		a.startLoop(deviceID)
+	}
 
 	return allocation, nil
 }
@@ -170,6 +273,12 @@ func (a *AMDGPUSharing) Release(deviceID, allocationID string) error {
 				if scheduler.activeWorkload != nil && scheduler.activeWorkload.ID == allocationID {
 					scheduler.activeWorkload = nil
 				}
+
+				// A GPU without workloads has nothing to time-slice
+				if len(a.gpuWorkloads[deviceID]) == 0 && scheduler.stopLoop != nil {
+					scheduler.stopLoop()
+					scheduler.stopLoop = nil
+				}
 			}
 
 			return nil
@@ -199,25 +308,196 @@ func (a *AMDGPUSharing) GetMemoryUsage(deviceID string) int64 {
 	return a.gpuMemoryUsage[deviceID]
 }
 
-// GetSchedulerInfo returns scheduling information for a GPU
+// GetSchedulerInfo returns a snapshot of the scheduling information for a GPU.
+// The workloads in it are copies, so their statuses can be read while the
+// scheduler keeps switching.
 func (a *AMDGPUSharing) GetSchedulerInfo(deviceID string) *GPUScheduler {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
 	if scheduler, exists := a.gpuScheduling[deviceID]; exists {
 		// Return a copy to avoid race conditions
+		workloadQueue := make([]*types.GPUAllocation, 0, len(scheduler.workloadQueue))
+		for _, workload := range scheduler.workloadQueue {
+			queued := *workload
+			workloadQueue = append(workloadQueue, &queued)
+		}
+
+		var activeWorkload *types.GPUAllocation
+		if scheduler.activeWorkload != nil {
+			active := *scheduler.activeWorkload
+			activeWorkload = &active
+		}
+
 		return &GPUScheduler{
 			timeSlice:      scheduler.timeSlice,
-			workloadQueue:  append([]*types.GPUAllocation{}, scheduler.workloadQueue...),
-			activeWorkload: scheduler.activeWorkload,
+			workloadQueue:  workloadQueue,
+			activeWorkload: activeWorkload,
 			lastSwitch:     scheduler.lastSwitch,
+			switchCount:    scheduler.switchCount,
+			grantedSlices:  scheduler.grantedSlices,
+			grantedTime:    scheduler.grantedTime,
+			switchOverhead: scheduler.switchOverhead,
 		}
 	}
 	return nil
 }
 
-// UpdateScheduling updates the time-slicing schedule
-// This would be called periodically to manage workload switching
+// ActiveWorkload returns the workload currently holding the GPU, or nil if none does
+func (s *GPUScheduler) ActiveWorkload() *types.GPUAllocation {
+	return s.activeWorkload
+}
+
+// WorkloadQueue returns the workloads waiting for GPU time, next first
+func (s *GPUScheduler) WorkloadQueue() []*types.GPUAllocation {
+	return s.workloadQueue
+}
+
+// TimeSlice returns the time each workload holds the GPU before the next one is switched in
+func (s *GPUScheduler) TimeSlice() time.Duration {
+	return s.timeSlice
+}
+
+// GetSchedulerStats returns time-slicing statistics for a GPU, or nil if the GPU
+// has no scheduler
+func (a *AMDGPUSharing) GetSchedulerStats(deviceID string) *SchedulerStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	scheduler, exists := a.gpuScheduling[deviceID]
+	if !exists {
+		return nil
+	}
+
+	return schedulerStats(deviceID, scheduler)
+}
+
+// GetAllSchedulerStats returns time-slicing statistics for every scheduled GPU
+func (a *AMDGPUSharing) GetAllSchedulerStats() map[string]*SchedulerStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	stats := make(map[string]*SchedulerStats, len(a.gpuScheduling))
+	for deviceID, scheduler := range a.gpuScheduling {
+		stats[deviceID] = schedulerStats(deviceID, scheduler)
+	}
+
+	return stats
+}
+
+// schedulerStats builds the statistics for a scheduler. Callers must hold a.mu.
+func schedulerStats(deviceID string, scheduler *GPUScheduler) *SchedulerStats {
+	stats := &SchedulerStats{
+		DeviceID:    deviceID,
+		SwitchCount: scheduler.switchCount,
+		QueueLength: len(scheduler.workloadQueue),
+		TimeSlice:   scheduler.timeSlice,
+	}
+
+	if scheduler.grantedSlices > 0 {
+		stats.AverageSlice = scheduler.grantedTime / time.Duration(scheduler.grantedSlices)
+	}
+
+	if scheduler.activeWorkload != nil {
+		stats.ActiveWorkloadID = scheduler.activeWorkload.ID
+	}
+
+	return stats
+}
+
+// SetTimeSliceConfig sets the adaptive time-slice bounds for a GPU
+func (a *AMDGPUSharing) SetTimeSliceConfig(deviceID string, config TimeSliceConfig) error {
+	if config.MinSlice <= 0 {
+		return fmt.Errorf("minimum time slice must be positive, got %v", config.MinSlice)
+	}
+
+	if config.MaxSlice < config.MinSlice {
+		return fmt.Errorf("maximum time slice %v is below minimum %v", config.MaxSlice, config.MinSlice)
+	}
+
+	if config.InitialSlice < 0 {
+		return fmt.Errorf("initial time slice must be non-negative, got %v", config.InitialSlice)
+	}
+
+	if config.TargetOverheadRatio <= 0 || config.TargetOverheadRatio >= 1 {
+		return fmt.Errorf("target overhead ratio must be between 0 and 1, got %f", config.TargetOverheadRatio)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.timeSliceConfigs[deviceID] = config
+	if scheduler := a.gpuScheduling[deviceID]; scheduler != nil {
+		scheduler.timeSlice = config.clamp(scheduler.timeSlice)
+	}
+
+	return nil
+}
+
+// RecordSwitchOverhead feeds a measured or estimated workload switch overhead for a
+// GPU into the scheduler and adapts its time slice so the overhead stays near the
+// configured share of each slice
+func (a *AMDGPUSharing) RecordSwitchOverhead(deviceID string, overhead time.Duration) error {
+	if overhead < 0 {
+		return fmt.Errorf("switch overhead must be non-negative, got %v", overhead)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	scheduler := a.gpuScheduling[deviceID]
+	if scheduler == nil {
+		return fmt.Errorf("no scheduler for GPU %s", deviceID)
+	}
+
+	// Smooth the overhead so a single outlier does not swing the slice
+	if scheduler.switchOverhead == 0 {
+		scheduler.switchOverhead = overhead
+	} else {
+		scheduler.switchOverhead = (scheduler.switchOverhead + overhead) / 2
+	}
+
+	config := a.timeSliceConfig(deviceID)
+	target := time.Duration(float64(scheduler.switchOverhead) / config.TargetOverheadRatio)
+	scheduler.timeSlice = config.clamp(target)
+
+	return nil
+}
+
+// GetTimeSlice returns the current time slice for a GPU, or 0 if it has no scheduler
+func (a *AMDGPUSharing) GetTimeSlice(deviceID string) time.Duration {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if scheduler := a.gpuScheduling[deviceID]; scheduler != nil {
+		return scheduler.timeSlice
+	}
+	return 0
+}
+
+// timeSliceConfig returns the time-slice settings for a GPU. Callers must hold a.mu.
+func (a *AMDGPUSharing) timeSliceConfig(deviceID string) TimeSliceConfig {
+	if config, exists := a.timeSliceConfigs[deviceID]; exists {
+		return config
+	}
+	return DefaultTimeSliceConfig()
+}
+
+// initialSlice returns the time slice a new scheduler starts with
+func (c TimeSliceConfig) initialSlice() time.Duration {
+	if c.InitialSlice == 0 {
+		return c.clamp(defaultTimeSlice)
+	}
+	return c.clamp(c.InitialSlice)
+}
+
+// clamp limits a time slice to the configured bounds
+func (c TimeSliceConfig) clamp(slice time.Duration) time.Duration {
+	return min(max(slice, c.MinSlice), c.MaxSlice)
+}
+
+// UpdateScheduling switches in the next queued workload on a GPU once the active
+// one has had its time slice. Start calls it whenever a slice expires.
 func (a *AMDGPUSharing) UpdateScheduling(deviceID string) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -228,18 +508,25 @@ func (a *AMDGPUSharing) UpdateScheduling(deviceID string) {
 	}
 
 	// Check if it's time to switch workloads
-	if time.Since(scheduler.lastSwitch) >= scheduler.timeSlice {
+	now := a.now()
+	if now.Sub(scheduler.lastSwitch) >= scheduler.timeSlice {
 		// Switch to next workload in queue
 		if len(scheduler.workloadQueue) > 0 {
 			// Move current active workload to end of queue (round-robin)
 			if scheduler.activeWorkload != nil {
+				scheduler.activeWorkload.Status = types.GPUAllocationStatusPending
 				scheduler.workloadQueue = append(scheduler.workloadQueue, scheduler.activeWorkload)
+
+				// Record the slice the outgoing workload actually received
+				scheduler.grantedSlices++
+				scheduler.grantedTime += now.Sub(scheduler.lastSwitch)
 			}
 
 			// Set next workload as active
 			scheduler.activeWorkload = scheduler.workloadQueue[0]
 			scheduler.workloadQueue = scheduler.workloadQueue[1:]
-			scheduler.lastSwitch = time.Now()
+			scheduler.lastSwitch = now
+			scheduler.switchCount++
 
 			// Update allocation status
 			if scheduler.activeWorkload != nil {
@@ -249,6 +536,117 @@ func (a *AMDGPUSharing) UpdateScheduling(deviceID string) {
 	}
 }
 
+// Start runs time-slicing on every GPU with workloads, including GPUs scheduled
+// later, until ctx is cancelled or Stop is called. Each GPU switches workloads
+// when its own time slice expires, so adapted slices take effect on the next switch.
+// A GPU's loop stops once its last workload is released.
+func (a *AMDGPUSharing) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.loopCtx != nil {
+		return fmt.Errorf("time-slicing scheduler already started")
+	}
+
+	loopCtx, stopLoops := context.WithCancel(ctx)
+	a.loopCtx, a.stopLoops = loopCtx, stopLoops
+	for deviceID := range a.gpuScheduling {
+		if len(a.gpuWorkloads[deviceID]) > 0 {
+			a.startLoop(deviceID)
+		}
+	}
+
+	// Forget the loops once ctx is cancelled so Start can be called again
+	context.AfterFunc(loopCtx, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		if a.loopCtx == loopCtx {
+			a.clearLoops()
+		}
+	})
+
+	return nil
+}
+
+// Stop stops the scheduling loops started by Start and waits for them to exit.
+// It is safe to call more than once, and Start may be called again afterwards.
+func (a *AMDGPUSharing) Stop() {
+	a.mu.Lock()
+	a.clearLoops()
+	a.mu.Unlock()
+
+	a.loops.Wait()
+}
+
+// Shutdown stops the scheduling loops and waits for them to exit or ctx to be done
+func (a *AMDGPUSharing) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		a.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for time-slicing scheduler to stop: %w", ctx.Err())
+	}
+}
+
+// clearLoops cancels the scheduling loops and forgets them. Callers must hold a.mu.
+func (a *AMDGPUSharing) clearLoops() {
+	if a.stopLoops != nil {
+		a.stopLoops()
+	}
+	a.loopCtx, a.stopLoops = nil, nil
+	for _, scheduler := range a.gpuScheduling {
+		scheduler.stopLoop = nil
+	}
+}
+
+// startLoop starts the scheduling loop of a GPU. Callers must hold a.mu.
+func (a *AMDGPUSharing) startLoop(deviceID string) {
+	ctx, stopLoop := context.WithCancel(a.loopCtx)
+	a.gpuScheduling[deviceID].stopLoop = stopLoop
+
+	a.loops.Add(1)
+	go a.runScheduler(ctx, deviceID)
+}
+
+// runScheduler switches workloads on a GPU each time its time slice expires
+// until ctx is cancelled
+func (a *AMDGPUSharing) runScheduler(ctx context.Context, deviceID string) {
+	defer a.loops.Done()
+
+	timer := time.NewTimer(a.untilNextSwitch(deviceID))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			a.UpdateScheduling(deviceID)
+			timer.Reset(a.untilNextSwitch(deviceID))
+		}
+	}
+}
+
+// untilNextSwitch returns how long until the active workload on a GPU has had
+// its time slice, or a whole slice if no workload is waiting to be switched in
+func (a *AMDGPUSharing) untilNextSwitch(deviceID string) time.Duration {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	scheduler := a.gpuScheduling[deviceID]
+	if len(scheduler.workloadQueue) == 0 {
+		return scheduler.timeSlice
+	}
+	return max(scheduler.lastSwitch.Add(scheduler.timeSlice).Sub(a.now()), 0)
+}
+
 // GetAMDGPUSharingCapabilities returns the capabilities of AMD GPU sharing
 func GetAMDGPUSharingCapabilities() map[string]interface{} {
 	return map[string]interface{}{
@@ -265,7 +663,7 @@ func GetAMDGPUSharingCapabilities() map[string]interface{} {
 				"concurrent_execution":  true,
 				"resource_guarantees":   true,
 				"xcd_isolation":         true,
-				"compute_modes":         []string{"SPX", "CPX"},
+				"compute_modes":         []string{"SPX", "TPX", "CPX"},
 				"memory_modes":          []string{"NPS1", "NPS4"},
 				"xcd_count":             8,
 				"hbm_stacks":            8,
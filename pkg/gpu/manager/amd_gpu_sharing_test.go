@@ -15,14 +15,27 @@
 package manager
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/silogen/kaiwo/pkg/gpu/types"
 )
 
-func TestAMDGPUSharing(t *testing.T) {
+// newTestAMDGPUSharing returns a sharing manager with card0 registered with totalMemory bytes
+func newTestAMDGPUSharing(t *testing.T, totalMemory int64) *AMDGPUSharing {
+	t.Helper()
+
 	sharing := NewAMDGPUSharing()
+	if err := sharing.RegisterGPU("card0", totalMemory); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+	return sharing
+}
+
+func TestAMDGPUSharing(t *testing.T) {
+	sharing := newTestAMDGPUSharing(t, 8*1024*1024*1024)
 
 	// Test capabilities
 	capabilities := GetAMDGPUSharingCapabilities()
@@ -128,7 +141,7 @@ func TestAMDGPUSharing(t *testing.T) {
 }
 
 func TestAMDGPUSharingMultipleWorkloads(t *testing.T) {
-	sharing := NewAMDGPUSharing()
+	sharing := newTestAMDGPUSharing(t, 8*1024*1024*1024)
 
 	// Create multiple allocation requests
 	requests := []*types.AllocationRequest{
@@ -206,7 +219,7 @@ func TestAMDGPUSharingMultipleWorkloads(t *testing.T) {
 }
 
 func TestAMDGPUSharingMemoryLimits(t *testing.T) {
-	sharing := NewAMDGPUSharing()
+	sharing := newTestAMDGPUSharing(t, 8*1024*1024*1024)
 
 	// Try to allocate more memory than available
 	largeRequest := &types.AllocationRequest{
@@ -215,7 +228,7 @@ func TestAMDGPUSharingMemoryLimits(t *testing.T) {
 		Namespace: "default",
 		GPURequest: &types.GPURequest{
 			Fraction:       1.0,
-			MemoryRequest:  16384, // 16GB (more than our 8GB default)
+			MemoryRequest:  16384, // 16GB (more than the registered 8GB)
 			IsolationType:  types.GPUIsolationTimeSlicing,
 			SharingEnabled: true,
 		},
@@ -286,3 +299,333 @@ func TestAMDGPUSharingCapabilities(t *testing.T) {
 		}
 	}
 }
+
+func TestAMDGPUSharingSchedulerStats(t *testing.T) {
+	sharing := newTestAMDGPUSharing(t, 8*1024*1024*1024)
+
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sharing.now = func() time.Time { return clock }
+
+	if stats := sharing.GetSchedulerStats("card0"); stats != nil {
+		t.Errorf("Expected nil stats for unscheduled GPU, got %+v", stats)
+	}
+
+	for _, id := range []string{"workload-1", "workload-2", "workload-3"} {
+		request := &types.AllocationRequest{
+			ID:        id,
+			PodName:   "pod-" + id,
+			Namespace: "default",
+			GPURequest: &types.GPURequest{
+				Fraction:       0.3,
+				MemoryRequest:  512,
+				IsolationType:  types.GPUIsolationTimeSlicing,
+				SharingEnabled: true,
+			},
+		}
+		if _, err := sharing.Allocate("card0", request); err != nil {
+			t.Fatalf("Failed to allocate %s: %v", id, err)
+		}
+	}
+
+	// Before any slice elapses nothing is switched in
+	sharing.UpdateScheduling("card0")
+	stats := sharing.GetSchedulerStats("card0")
+	if stats.SwitchCount != 0 || stats.QueueLength != 3 || stats.ActiveWorkloadID != "" {
+		t.Errorf("Expected no switches and 3 queued workloads, got %+v", stats)
+	}
+
+	// Advance through four switches; the first starts a workload and the rest
+	// complete slices of 30s, 40s and 50s
+	for _, elapsed := range []time.Duration{30 * time.Second, 30 * time.Second, 40 * time.Second, 50 * time.Second} {
+		clock = clock.Add(elapsed)
+		sharing.UpdateScheduling("card0")
+	}
+
+	stats = sharing.GetSchedulerStats("card0")
+	if stats.SwitchCount != 4 {
+		t.Errorf("Expected 4 switches, got %d", stats.SwitchCount)
+	}
+
+	if stats.AverageSlice != 40*time.Second {
+		t.Errorf("Expected average slice 40s, got %v", stats.AverageSlice)
+	}
+
+	if stats.QueueLength != 2 {
+		t.Errorf("Expected queue length 2, got %d", stats.QueueLength)
+	}
+
+	// Round-robin order is workload-1, workload-2, workload-3, workload-1
+	if stats.ActiveWorkloadID != "workload-1" {
+		t.Errorf("Expected active workload 'workload-1', got %q", stats.ActiveWorkloadID)
+	}
+
+	// A call before the slice expires does not switch
+	clock = clock.Add(10 * time.Second)
+	sharing.UpdateScheduling("card0")
+	if stats := sharing.GetSchedulerStats("card0"); stats.SwitchCount != 4 {
+		t.Errorf("Expected switch count to stay at 4, got %d", stats.SwitchCount)
+	}
+
+	if all := sharing.GetAllSchedulerStats(); len(all) != 1 || all["card0"] == nil {
+		t.Errorf("Expected stats for card0 only, got %v", all)
+	}
+}
+
+func TestAMDGPUSharingAdaptiveTimeSlice(t *testing.T) {
+	sharing := newTestAMDGPUSharing(t, 8*1024*1024*1024)
+
+	request := &types.AllocationRequest{
+		ID:        "workload-1",
+		PodName:   "pod-1",
+		Namespace: "default",
+		GPURequest: &types.GPURequest{
+			Fraction:       0.5,
+			MemoryRequest:  512,
+			IsolationType:  types.GPUIsolationTimeSlicing,
+			SharingEnabled: true,
+		},
+	}
+	if _, err := sharing.Allocate("card0", request); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+
+	if slice := sharing.GetTimeSlice("card0"); slice != 30*time.Second {
+		t.Errorf("Expected initial time slice 30s, got %v", slice)
+	}
+
+	config := TimeSliceConfig{
+		MinSlice:            10 * time.Second,
+		MaxSlice:            2 * time.Minute,
+		TargetOverheadRatio: 0.1,
+	}
+	if err := sharing.SetTimeSliceConfig("card0", config); err != nil {
+		t.Fatalf("Failed to set time slice config: %v", err)
+	}
+
+	// High overhead (5s per switch) lengthens the slice to keep overhead at 10%
+	if err := sharing.RecordSwitchOverhead("card0", 5*time.Second); err != nil {
+		t.Fatalf("Failed to record overhead: %v", err)
+	}
+	if slice := sharing.GetTimeSlice("card0"); slice != 50*time.Second {
+		t.Errorf("Expected time slice 50s under high overhead, got %v", slice)
+	}
+
+	// Very high overhead is capped at the maximum slice
+	if err := sharing.RecordSwitchOverhead("card0", 35*time.Second); err != nil {
+		t.Fatalf("Failed to record overhead: %v", err)
+	}
+	if slice := sharing.GetTimeSlice("card0"); slice != 2*time.Minute {
+		t.Errorf("Expected time slice capped at 2m, got %v", slice)
+	}
+
+	// Low overhead shrinks the slice down to the minimum as the estimate settles
+	for i := 0; i < 10; i++ {
+		if err := sharing.RecordSwitchOverhead("card0", 100*time.Millisecond); err != nil {
+			t.Fatalf("Failed to record overhead: %v", err)
+		}
+	}
+	if slice := sharing.GetTimeSlice("card0"); slice != 10*time.Second {
+		t.Errorf("Expected time slice at minimum 10s under low overhead, got %v", slice)
+	}
+
+	if stats := sharing.GetSchedulerStats("card0"); stats.TimeSlice != 10*time.Second {
+		t.Errorf("Expected scheduler stats to report 10s slice, got %v", stats.TimeSlice)
+	}
+
+	if err := sharing.RecordSwitchOverhead("card1", time.Second); err == nil {
+		t.Error("Expected error for GPU without a scheduler")
+	}
+
+	if err := sharing.SetTimeSliceConfig("card0", TimeSliceConfig{MinSlice: time.Minute, MaxSlice: time.Second, TargetOverheadRatio: 0.1}); err == nil {
+		t.Error("Expected error for max slice below min slice")
+	}
+}
+
+func TestAMDGPUSharingSchedulingLoop(t *testing.T) {
+	sharing := newTestAMDGPUSharing(t, 8*1024*1024*1024)
+
+	config := TimeSliceConfig{
+		InitialSlice:        20 * time.Millisecond,
+		MinSlice:            10 * time.Millisecond,
+		MaxSlice:            time.Second,
+		TargetOverheadRatio: 0.05,
+	}
+	if err := sharing.SetTimeSliceConfig("card0", config); err != nil {
+		t.Fatalf("Failed to set time slice config: %v", err)
+	}
+
+	workloads := []string{"workload-1", "workload-2", "workload-3"}
+	for _, id := range workloads {
+		request := &types.AllocationRequest{
+			ID:        id,
+			PodName:   "pod-" + id,
+			Namespace: "default",
+			GPURequest: &types.GPURequest{
+				Fraction:       0.3,
+				MemoryRequest:  512,
+				IsolationType:  types.GPUIsolationTimeSlicing,
+				SharingEnabled: true,
+			},
+		}
+		if _, err := sharing.Allocate("card0", request); err != nil {
+			t.Fatalf("Failed to allocate %s: %v", id, err)
+		}
+	}
+	if slice := sharing.GetTimeSlice("card0"); slice != 20*time.Millisecond {
+		t.Fatalf("Expected the configured initial slice of 20ms, got %v", slice)
+	}
+
+	if err := sharing.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start scheduling: %v", err)
+	}
+	t.Cleanup(sharing.Stop)
+	if err := sharing.Start(context.Background()); err == nil {
+		t.Error("Expected starting twice to fail")
+	}
+
+	// Watch the active workload rotate round-robin until every workload has had
+	// a slice and the first is back
+	deadline := time.Now().Add(5 * time.Second)
+	seen := make(map[string]bool)
+	for {
+		info := sharing.GetSchedulerInfo("card0")
+		if switches := info.switchCount; switches > 0 {
+			active := info.ActiveWorkload()
+			if want := workloads[(switches-1)%int64(len(workloads))]; active.ID != want {
+				t.Fatalf("Expected %s active after %d switches, got %s", want, switches, active.ID)
+			}
+			if active.Status != types.GPUAllocationStatusActive {
+				t.Errorf("Expected the active workload to be active, got %s", active.Status)
+			}
+			for _, queued := range info.WorkloadQueue() {
+				if queued.Status != types.GPUAllocationStatusPending {
+					t.Errorf("Expected queued workload %s to be pending, got %s", queued.ID, queued.Status)
+				}
+			}
+			seen[active.ID] = true
+			if switches > int64(len(workloads)) {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the workloads to rotate, saw %v after %d switches", seen, info.switchCount)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(seen) != len(workloads) {
+		t.Errorf("Expected every workload to be observed active, saw %v", seen)
+	}
+
+	// Workloads stop rotating once stopped
+	sharing.Stop()
+	switches := sharing.GetSchedulerInfo("card0").switchCount
+	time.Sleep(100 * time.Millisecond)
+	if after := sharing.GetSchedulerInfo("card0").switchCount; after != switches {
+		t.Errorf("Expected no switches after Stop, went from %d to %d", switches, after)
+	}
+}
+
+func TestAMDGPUSharingLoopLifetime(t *testing.T) {
+	sharing := newTestAMDGPUSharing(t, 8*1024*1024*1024)
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	sharing.now = func() time.Time { return created }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := sharing.Start(ctx); err != nil {
+		t.Fatalf("Failed to start scheduling: %v", err)
+	}
+	t.Cleanup(sharing.Stop)
+
+	running := func() bool {
+		sharing.mu.RLock()
+		defer sharing.mu.RUnlock()
+		return sharing.gpuScheduling["card0"].stopLoop != nil
+	}
+
+	request := &types.AllocationRequest{
+		ID: "workload-1",
+		GPURequest: &types.GPURequest{
+			Fraction:       0.5,
+			MemoryRequest:  512,
+			IsolationType:  types.GPUIsolationTimeSlicing,
+			SharingEnabled: true,
+		},
+	}
+	allocation, err := sharing.Allocate("card0", request)
+	if err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	if allocation.CreatedAt != created.Unix() {
+		t.Errorf("Expected CreatedAt from the sharing clock %d, got %d", created.Unix(), allocation.CreatedAt)
+	}
+	if !running() {
+		t.Fatal("Expected a scheduling loop once the GPU has a workload")
+	}
+
+	// Releasing the last workload stops the GPU's loop and allocating restarts it
+	if err := sharing.Release("card0", "workload-1"); err != nil {
+		t.Fatalf("Failed to release: %v", err)
+	}
+	if running() {
+		t.Error("Expected the scheduling loop to stop with no workloads left")
+	}
+	if _, err := sharing.Allocate("card0", request); err != nil {
+		t.Fatalf("Failed to allocate again: %v", err)
+	}
+	if !running() {
+		t.Error("Expected the scheduling loop to restart for a new workload")
+	}
+
+	// Cancelling the parent context forgets the loops so Start works again
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for sharing.Start(context.Background()) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected Start to succeed after the parent context was cancelled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if !running() {
+		t.Error("Expected the restarted scheduler to run a loop for the queued workload")
+	}
+}
+
+func TestAMDGPUSharingRegisteredMemory(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	sharing := newTestAMDGPUSharing(t, 192*gib) // MI300X
+
+	// 100GB fits on the registered 192GB, where an 8GB assumption would reject it
+	request := &types.AllocationRequest{
+		ID:        "large",
+		PodName:   "pod-large",
+		Namespace: "default",
+		GPURequest: &types.GPURequest{
+			Fraction:       0.5,
+			MemoryRequest:  100 * 1024,
+			IsolationType:  types.GPUIsolationTimeSlicing,
+			SharingEnabled: true,
+		},
+	}
+	if _, err := sharing.Allocate("card0", request); err != nil {
+		t.Fatalf("Expected 100GB to be admitted on a 192GB GPU: %v", err)
+	}
+	if usage := sharing.GetMemoryUsage("card0"); usage != 100*gib {
+		t.Errorf("Expected 100GB in use, got %d bytes", usage)
+	}
+
+	// The remaining 92GB cannot take another 100GB
+	request.ID = "second"
+	if _, err := sharing.Allocate("card0", request); err == nil {
+		t.Error("Expected a second 100GB request to be rejected")
+	}
+
+	if _, err := sharing.CanAllocate("card1", request.GPURequest); !errors.Is(err, ErrDeviceNotRegistered) {
+		t.Errorf("Expected ErrDeviceNotRegistered for an unregistered GPU, got %v", err)
+	}
+	if err := sharing.RegisterGPU("card0", 192*gib); !errors.Is(err, ErrDeviceAlreadyRegistered) {
+		t.Errorf("Expected ErrDeviceAlreadyRegistered, got %v", err)
+	}
+	if err := sharing.RegisterGPU("card1", 0); err == nil {
+		t.Error("Expected registering a GPU without memory to fail")
+	}
+}
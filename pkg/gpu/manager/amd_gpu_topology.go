@@ -0,0 +1,287 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// GetTopology discovers the links between the node's AMD GPUs, preferring the
+// matrices printed by rocm-smi and falling back to grouping GPUs by the XGMI
+// hive sysfs reports them in. Device IDs match those from DiscoverGPUs.
+func (d *AMDGPUDiscovery) GetTopology(ctx context.Context) (*types.GPUTopology, error) {
+	if d.rocmSMIPath != "" {
+		topology, err := d.topologyWithROCmSMI(ctx)
+		if err == nil && len(topology.Links) > 0 {
+			return topology, nil
+		}
+		fmt.Printf("ROCm SMI topology discovery failed: %v, falling back to sysfs\n", err)
+	}
+
+	topology, err := d.topologyWithSysfs()
+	if err != nil {
+		return nil, fmt.Errorf("all GPU topology discovery methods failed: %v", err)
+	}
+
+	return topology, nil
+}
+
+// topologyWithROCmSMI reads the GPU link matrices from rocm-smi
+func (d *AMDGPUDiscovery) topologyWithROCmSMI(ctx context.Context) (*types.GPUTopology, error) {
+	cmdCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, d.rocmSMIPath, "--showtopo", "--shownodesbw")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute rocm-smi --showtopo: %v", err)
+	}
+
+	topology, err := parseROCmSMITopology(output)
+	if err != nil {
+		return nil, err
+	}
+
+	return renameTopologyDevices(topology, d.rocmSMIDRMCards(ctx)), nil
+}
+
+// rocmSMIDRMCards maps rocm-smi's cardN names to the DRM cards at the GPUs'
+// PCI bus addresses, which DiscoverGPUs names GPUs after. GPUs whose DRM card
+// is unknown are left out.
+func (d *AMDGPUDiscovery) rocmSMIDRMCards(ctx context.Context) map[string]string {
+	cmdCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, d.rocmSMIPath, "--showbus", "--json")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var buses map[string]interface{}
+	if err := json.Unmarshal(output, &buses); err != nil {
+		return nil
+	}
+
+	drmCards := make(map[string]string)
+	for cardID, cardData := range buses {
+		cardMap, ok := cardData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if drmCard, ok := d.drmCardForBus(d.getStringValue(cardMap, "PCI Bus", "")); ok {
+			drmCards[cardID] = drmCard
+		}
+	}
+	return drmCards
+}
+
+// renameTopologyDevices renames the GPUs of a topology, keeping the names of
+// GPUs without a new one
+func renameTopologyDevices(topology *types.GPUTopology, names map[string]string) *types.GPUTopology {
+	if len(names) == 0 {
+		return topology
+	}
+
+	rename := func(deviceID string) string {
+		if name, ok := names[deviceID]; ok {
+			return name
+		}
+		return deviceID
+	}
+
+	renamed := &types.GPUTopology{Links: make(map[string][]types.GPULink, len(topology.Links))}
+	for deviceID, links := range topology.Links {
+		renamedLinks := make([]types.GPULink, 0, len(links))
+		for _, link := range links {
+			link.PeerID = rename(link.PeerID)
+			renamedLinks = append(renamedLinks, link)
+		}
+		slices.SortFunc(renamedLinks, func(a, b types.GPULink) int {
+			return strings.Compare(a.PeerID, b.PeerID)
+		})
+		renamed.Links[rename(deviceID)] = renamedLinks
+	}
+	return renamed
+}
+
+// parseROCmSMITopology parses the weight, hops, link type and bandwidth
+// matrices of `rocm-smi --showtopo --shownodesbw` into a topology. Each
+// matrix follows a banner naming it, has a header row of GPU columns and one
+// row per GPU. GPU N is reported as cardN, as rocm-smi's JSON output names it;
+// topologyWithROCmSMI renames it after its DRM card.
+func parseROCmSMITopology(output []byte) (*types.GPUTopology, error) {
+	links := make(map[[2]string]*types.GPULink)
+	link := func(from, to string) *types.GPULink {
+		key := [2]string{from, to}
+		if links[key] == nil {
+			links[key] = &types.GPULink{PeerID: to}
+		}
+		return links[key]
+	}
+
+	var section string
+	var columns []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "=") {
+			section = strings.ToLower(strings.Trim(line, "= "))
+			columns = nil
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// The header row lists the GPU columns and nothing else
+		if _, ok := rocmSMITopologyDevice(fields[0]); ok && columns == nil {
+			for _, field := range fields {
+				device, ok := rocmSMITopologyDevice(field)
+				if !ok {
+					return nil, fmt.Errorf("unexpected column %q in %s header", field, section)
+				}
+				columns = append(columns, device)
+			}
+			continue
+		}
+
+		from, ok := rocmSMITopologyDevice(fields[0])
+		if !ok || columns == nil {
+			continue
+		}
+		if len(fields)-1 != len(columns) {
+			return nil, fmt.Errorf("row %s of %s has %d values, expected %d", fields[0], section, len(fields)-1, len(columns))
+		}
+
+		for i, value := range fields[1:] {
+			to := columns[i]
+			if to == from || value == "N/A" {
+				continue
+			}
+
+			var err error
+			switch {
+			case strings.HasPrefix(section, "weight"):
+				link(from, to).Weight, err = strconv.Atoi(value)
+			case strings.HasPrefix(section, "hops"):
+				link(from, to).Hops, err = strconv.Atoi(value)
+			case strings.HasPrefix(section, "link type"):
+				link(from, to).Type = types.GPULinkType(strings.ToUpper(value))
+			case strings.HasPrefix(section, "bandwidth"):
+				l := link(from, to)
+				l.MinBandwidth, l.MaxBandwidth, err = parseBandwidthRange(value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s between %s and %s: %v", section, from, to, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rocm-smi topology output: %v", err)
+	}
+	if len(links) == 0 {
+		return nil, fmt.Errorf("no GPU links found in rocm-smi topology output")
+	}
+
+	topology := &types.GPUTopology{Links: make(map[string][]types.GPULink)}
+	for key, l := range links {
+		topology.Links[key[0]] = append(topology.Links[key[0]], *l)
+	}
+	for _, deviceLinks := range topology.Links {
+		slices.SortFunc(deviceLinks, func(a, b types.GPULink) int {
+			return strings.Compare(a.PeerID, b.PeerID)
+		})
+	}
+	return topology, nil
+}
+
+// rocmSMITopologyDevice maps a GPUN matrix label to its device ID
+func rocmSMITopologyDevice(label string) (string, bool) {
+	index, ok := strings.CutPrefix(label, "GPU")
+	if !ok {
+		return "", false
+	}
+	if _, err := strconv.Atoi(index); err != nil {
+		return "", false
+	}
+	return "card" + index, true
+}
+
+// parseBandwidthRange parses a min-max bandwidth cell such as 50000-100000
+func parseBandwidthRange(value string) (int64, int64, error) {
+	minValue, maxValue, ok := strings.Cut(value, "-")
+	if !ok {
+		maxValue = minValue
+	}
+
+	minBandwidth, err := strconv.ParseInt(minValue, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxBandwidth, err := strconv.ParseInt(maxValue, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return minBandwidth, maxBandwidth, nil
+}
+
+// topologyWithSysfs links the AMD cards that sysfs places in the same XGMI
+// hive. GPUs in a hive are fully connected, so each pair is one XGMI hop
+// apart. Sysfs does not report link bandwidth.
+func (d *AMDGPUDiscovery) topologyWithSysfs() (*types.GPUTopology, error) {
+	cards, err := d.findAMDCards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find AMD cards: %v", err)
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("no AMD GPUs found in sysfs")
+	}
+
+	hives := make(map[string][]string)
+	for _, cardPath := range cards {
+		hiveID := d.readSysfsFile(filepath.Join(cardPath, "device", "xgmi_hive_info", "xgmi_hive_id"))
+		if hiveID == "" || hiveID == "0" {
+			continue
+		}
+		hives[hiveID] = append(hives[hiveID], filepath.Base(cardPath))
+	}
+
+	topology := &types.GPUTopology{Links: make(map[string][]types.GPULink)}
+	for _, members := range hives {
+		slices.Sort(members)
+		for _, from := range members {
+			for _, to := range members {
+				if from != to {
+					topology.Links[from] = append(topology.Links[from], types.GPULink{PeerID: to, Type: types.GPULinkTypeXGMI, Hops: 1})
+				}
+			}
+		}
+	}
+	return topology, nil
+}
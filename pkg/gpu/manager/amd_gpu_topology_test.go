@@ -0,0 +1,188 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// sampleROCmSMITopology is `rocm-smi --showtopo --shownodesbw` output for a
+// node with two XGMI-linked GPUs and a third reached over PCIe
+const sampleROCmSMITopology = `
+============================ ROCm System Management Interface ============================
+================================ Weight between two GPUs =================================
+       GPU0         GPU1         GPU2
+GPU0   0            15           40
+GPU1   15           0            40
+GPU2   40           40           0
+
+================================= Hops between two GPUs ==================================
+       GPU0         GPU1         GPU2
+GPU0   0            1            2
+GPU1   1            0            2
+GPU2   2            2            0
+
+=============================== Link Type between two GPUs ===============================
+       GPU0         GPU1         GPU2
+GPU0   0            XGMI         PCIE
+GPU1   XGMI         0            PCIE
+GPU2   PCIE         PCIE         0
+
+======================================= Numa Nodes =======================================
+GPU[0]		: (Topology) Numa Node: 0
+GPU[0]		: (Topology) Numa Affinity: 0
+======================================= Bandwidth ========================================
+       GPU0         GPU1         GPU2
+GPU0   N/A          50000-100000 N/A
+GPU1   50000-100000 N/A          N/A
+GPU2   N/A          N/A          N/A
+================================== End of ROCm SMI Log ===================================
+`
+
+func TestParseROCmSMITopology(t *testing.T) {
+	topology, err := parseROCmSMITopology([]byte(sampleROCmSMITopology))
+	if err != nil {
+		t.Fatalf("Failed to parse topology: %v", err)
+	}
+
+	xgmi := types.GPULink{PeerID: "card1", Type: types.GPULinkTypeXGMI, Hops: 1, Weight: 15, MinBandwidth: 50000, MaxBandwidth: 100000}
+	if link, ok := topology.Link("card0", "card1"); !ok || link != xgmi {
+		t.Errorf("Expected card0 -> card1 link %+v, got %+v", xgmi, link)
+	}
+
+	pcie := types.GPULink{PeerID: "card0", Type: types.GPULinkTypePCIe, Hops: 2, Weight: 40}
+	if link, ok := topology.Link("card2", "card0"); !ok || link != pcie {
+		t.Errorf("Expected card2 -> card0 link %+v, got %+v", pcie, link)
+	}
+
+	if len(topology.Links) != 3 || len(topology.Links["card1"]) != 2 {
+		t.Errorf("Expected every GPU to link to both others, got %+v", topology.Links)
+	}
+	if _, ok := topology.Link("card0", "card0"); ok {
+		t.Error("Expected no link from a GPU to itself")
+	}
+
+	if !topology.DirectlyLinked("card1", "card0") || topology.DirectlyLinked("card0", "card2") {
+		t.Error("Expected only card0 and card1 to be directly linked")
+	}
+	if peers := topology.XGMIPeers("card0"); !slices.Equal(peers, []string{"card1"}) {
+		t.Errorf("Expected card0 XGMI peers [card1], got %v", peers)
+	}
+	if peers := topology.XGMIPeers("card2"); len(peers) != 0 {
+		t.Errorf("Expected card2 to have no XGMI peers, got %v", peers)
+	}
+}
+
+func TestParseROCmSMITopologyRejectsMalformedTables(t *testing.T) {
+	for name, output := range map[string]string{
+		"no matrices": "==== ROCm System Management Interface ====\n==== End of ROCm SMI Log ====\n",
+		"short row":   "==== Hops between two GPUs ====\n  GPU0  GPU1\nGPU0  0\n",
+		"bad value":   "==== Weight between two GPUs ====\n  GPU0  GPU1\nGPU0  0  far\n",
+	} {
+		if _, err := parseROCmSMITopology([]byte(output)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestAMDGPUDiscovery_GetTopologyWithROCmSMI(t *testing.T) {
+	rocmSMI := filepath.Join(t.TempDir(), "rocm-smi")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + sampleROCmSMITopology + "\nEOF\n"
+	if err := os.WriteFile(rocmSMI, []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to write fake rocm-smi: %v", err)
+	}
+
+	discovery := newTestAMDGPUDiscovery(t.TempDir())
+	discovery.rocmSMIPath = rocmSMI
+
+	topology, err := discovery.GetTopology(context.Background())
+	if err != nil {
+		t.Fatalf("Topology discovery failed: %v", err)
+	}
+	if link, ok := topology.Link("card1", "card0"); !ok || link.MaxBandwidth != 100000 {
+		t.Errorf("Expected card1 -> card0 link with 100000 MB/s, got %+v", link)
+	}
+}
+
+func TestAMDGPUDiscovery_GetTopologyNamesGPUsAfterDRMCards(t *testing.T) {
+	// rocm-smi numbers the GPUs 0-2, but their DRM cards are card1-card3
+	buses := `{"card0": {"PCI Bus": "0000:C1:00.0"}, "card1": {"PCI Bus": "0000:C2:00.0"}, "card2": {"PCI Bus": "0000:C3:00.0"}}`
+	rocmSMI := filepath.Join(t.TempDir(), "rocm-smi")
+	script := "#!/bin/sh\nif [ \"$1\" = --showbus ]; then\ncat <<'EOF'\n" + buses + "\nEOF\nelse\ncat <<'EOF'\n" +
+		sampleROCmSMITopology + "\nEOF\nfi\n"
+	if err := os.WriteFile(rocmSMI, []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to write fake rocm-smi: %v", err)
+	}
+
+	discovery := newTestAMDGPUDiscovery(t.TempDir())
+	discovery.rocmSMIPath = rocmSMI
+	for i, busID := range []string{"0000:c1:00.0", "0000:c2:00.0", "0000:c3:00.0"} {
+		writePCIDRMCard(t, discovery.sysBusPCIPath, busID, fmt.Sprintf("card%d", i+1))
+	}
+
+	topology, err := discovery.GetTopology(context.Background())
+	if err != nil {
+		t.Fatalf("Topology discovery failed: %v", err)
+	}
+	if link, ok := topology.Link("card2", "card1"); !ok || link.Type != types.GPULinkTypeXGMI || link.MaxBandwidth != 100000 {
+		t.Errorf("Expected card2 -> card1 XGMI link with 100000 MB/s, got %+v", link)
+	}
+	if link, ok := topology.Link("card1", "card3"); !ok || link.Type != types.GPULinkTypePCIe {
+		t.Errorf("Expected card1 -> card3 PCIe link, got %+v", link)
+	}
+	if _, ok := topology.Links["card0"]; ok {
+		t.Errorf("Expected no GPU named after rocm-smi's index card0, got %+v", topology.Links)
+	}
+}
+
+func TestAMDGPUDiscovery_GetTopologyFromSysfsHives(t *testing.T) {
+	drmPath := t.TempDir()
+	hives := map[string]string{"card0": "0x1234", "card1": "0x1234", "card2": "0x5678", "card3": ""}
+	for card, hiveID := range hives {
+		writeSysfsCard(t, drmPath, card, "0", "40000", "100000000")
+		if hiveID == "" {
+			continue
+		}
+		hivePath := filepath.Join(drmPath, card, "device", "xgmi_hive_info")
+		if err := os.MkdirAll(hivePath, 0o755); err != nil {
+			t.Fatalf("Failed to create hive directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(hivePath, "xgmi_hive_id"), []byte(hiveID), 0o644); err != nil {
+			t.Fatalf("Failed to write hive ID: %v", err)
+		}
+	}
+
+	topology, err := newTestAMDGPUDiscovery(drmPath).GetTopology(context.Background())
+	if err != nil {
+		t.Fatalf("Topology discovery failed: %v", err)
+	}
+
+	if link, ok := topology.Link("card0", "card1"); !ok || link.Type != types.GPULinkTypeXGMI || link.Hops != 1 {
+		t.Errorf("Expected card0 and card1 to share an XGMI link, got %+v", link)
+	}
+	if peers := topology.XGMIPeers("card1"); !slices.Equal(peers, []string{"card0"}) {
+		t.Errorf("Expected card1 XGMI peers [card0], got %v", peers)
+	}
+	if len(topology.XGMIPeers("card2")) != 0 || len(topology.XGMIPeers("card3")) != 0 {
+		t.Errorf("Expected card2 and card3 to have no XGMI peers, got %+v", topology.Links)
+	}
+}
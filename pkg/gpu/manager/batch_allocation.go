@@ -0,0 +1,183 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// BatchAllocationError reports the request that stopped a batch allocation.
+// None of the batch's requests are allocated when it is returned.
+type BatchAllocationError struct {
+	// Index is the position of the failed request in the batch
+	Index int
+	// RequestID is the ID of the failed request
+	RequestID string
+	// Err is why the request could not be placed
+	Err error
+}
+
+// Error implements error
+func (e *BatchAllocationError) Error() string {
+	return fmt.Sprintf("batch request %d (%s) cannot be placed: %v", e.Index, e.RequestID, e.Err)
+}
+
+// Unwrap returns why the request could not be placed
+func (e *BatchAllocationError) Unwrap() error {
+	return e.Err
+}
+
+// validateBatch checks that every request in a batch is complete, that no two
+// requests share an ID and that none reuses the ID of a live allocation
+func validateBatch(requests []*types.AllocationRequest, live func(allocationID string) bool) error {
+	seen := make(map[string]bool, len(requests))
+	for i, request := range requests {
+		if request == nil || request.GPURequest == nil {
+			return &BatchAllocationError{Index: i, Err: fmt.Errorf("GPU request cannot be nil")}
+		}
+		if seen[request.ID] {
+			return &BatchAllocationError{Index: i, RequestID: request.ID, Err: fmt.Errorf("duplicate request ID in batch")}
+		}
+		if live(request.ID) {
+			return &BatchAllocationError{Index: i, RequestID: request.ID, Err: fmt.Errorf("allocation ID is already in use")}
+		}
+		seen[request.ID] = true
+	}
+	return nil
+}
+
+// AllocateBatch places every request on the GPU that fits it best, all or
+// nothing, for jobs such as distributed training that need all of their GPUs
+// at once. Requests are placed in order, each seeing the allocations made for
+// the ones before it. If any request cannot be placed, the allocations already
+// made for the batch are removed and a *BatchAllocationError names the request
+// that failed. Requests may not reuse the ID of a live allocation.
+func (f *FractionalAllocator) AllocateBatch(requests []*types.AllocationRequest) ([]*types.GPUAllocation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := validateBatch(requests, f.hasAllocation); err != nil {
+		return nil, err
+	}
+
+	allocations := make([]*types.GPUAllocation, 0, len(requests))
+	for i, request := range requests {
+		allocation, err := f.allocateBestFit(request)
+		if err != nil {
+			for _, allocated := range allocations {
+				f.remove(allocated)
+			}
+			f.recordOutcome("", request, nil, err)
+			return nil, &BatchAllocationError{Index: i, RequestID: request.ID, Err: err}
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	for i, allocation := range allocations {
+		f.recordOutcome(allocation.DeviceID, requests[i], allocation, nil)
+	}
+	return allocations, nil
+}
+
+// allocateBestFit allocates a request on the GPU that fits it best. Callers
+// must hold f.mu.
+func (f *FractionalAllocator) allocateBestFit(request *types.AllocationRequest) (*types.GPUAllocation, error) {
+	deviceID, err := f.findBestFitGPU(request.GPURequest)
+	if err != nil {
+		return nil, err
+	}
+	return f.allocate(deviceID, request)
+}
+
+// AllocateBatch places every request on the first GPU, in device ID order,
+// whose partitioning can host it, all or nothing. It behaves like
+// FractionalAllocator.AllocateBatch.
+func (f *MI300XFractionalAllocator) AllocateBatch(requests []*types.AllocationRequest) ([]*types.GPUAllocation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := validateBatch(requests, f.hasAllocation); err != nil {
+		return nil, err
+	}
+
+	allocations := make([]*types.GPUAllocation, 0, len(requests))
+	for i, request := range requests {
+		allocation, err := f.allocateFirstFit(request)
+		if err != nil {
+			for _, allocated := range allocations {
+				f.remove(allocated)
+			}
+			f.recordOutcome("", request, nil, err)
+			return nil, &BatchAllocationError{Index: i, RequestID: request.ID, Err: err}
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	for i, allocation := range allocations {
+		f.recordOutcome(allocation.DeviceID, requests[i], allocation, nil)
+	}
+	return allocations, nil
+}
+
+// allocateFirstFit allocates a request on the first GPU, in device ID order,
+// that can host it. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) allocateFirstFit(request *types.AllocationRequest) (*types.GPUAllocation, error) {
+	for _, deviceID := range slices.Sorted(maps.Keys(f.gpuCapacity)) {
+		if _, err := f.canAllocate(deviceID, request.GPURequest); err == nil {
+			return f.allocate(deviceID, request)
+		}
+	}
+	return nil, fmt.Errorf("no suitable GPU found for allocation")
+}
+
+// hasAllocation reports whether an allocation with the given ID is held.
+// Callers must hold f.mu.
+func (f *FractionalAllocator) hasAllocation(allocationID string) bool {
+	_, _, allocation := f.findAllocation(allocationID)
+	return allocation != nil
+}
+
+// remove removes exactly the given allocation from its GPU. Callers must hold f.mu.
+func (f *FractionalAllocator) remove(allocation *types.GPUAllocation) {
+	f.allocations[allocation.DeviceID] = slices.DeleteFunc(f.allocations[allocation.DeviceID],
+		func(candidate *types.GPUAllocation) bool { return candidate == allocation })
+}
+
+// hasAllocation reports whether an allocation with the given ID is held.
+// Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) hasAllocation(allocationID string) bool {
+	for _, allocations := range f.allocations {
+		if slices.ContainsFunc(allocations, func(allocation *types.GPUAllocation) bool {
+			return allocation.ID == allocationID
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// remove removes exactly the given allocation from its GPU, along with its
+// XCDs. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) remove(allocation *types.GPUAllocation) {
+	f.allocations[allocation.DeviceID] = slices.DeleteFunc(f.allocations[allocation.DeviceID],
+		func(candidate *types.GPUAllocation) bool { return candidate == allocation })
+	if config := f.partitionConfig[allocation.DeviceID]; config != nil && config.usesXCDAllocations() {
+		f.releaseXCDs(allocation.DeviceID, allocation)
+	}
+}
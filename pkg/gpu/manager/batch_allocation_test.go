@@ -0,0 +1,169 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// newTestBatch returns whole-GPU requests with the given IDs
+func newTestBatch(ids ...string) []*types.AllocationRequest {
+	requests := make([]*types.AllocationRequest, 0, len(ids))
+	for _, id := range ids {
+		requests = append(requests, newTestAllocationRequest(id, 1.0))
+	}
+	return requests
+}
+
+// assertBatchFailed checks that err reports the request at index as the one that failed
+func assertBatchFailed(t *testing.T, err error, index int, requestID string) {
+	t.Helper()
+
+	var batchErr *BatchAllocationError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected a BatchAllocationError, got %v", err)
+	}
+	if batchErr.Index != index || batchErr.RequestID != requestID {
+		t.Errorf("Expected request %d (%s) to fail, got %d (%s)", index, requestID, batchErr.Index, batchErr.RequestID)
+	}
+}
+
+func TestFractionalAllocatorAllocateBatch(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	for _, deviceID := range []string{"card0", "card1", "card2"} {
+		if err := allocator.RegisterGPU(deviceID, 16*1024*1024*1024, false); err != nil {
+			t.Fatalf("RegisterGPU failed: %v", err)
+		}
+	}
+
+	allocations, err := allocator.AllocateBatch(newTestBatch("rank-0", "rank-1"))
+	if err != nil {
+		t.Fatalf("AllocateBatch failed: %v", err)
+	}
+	if len(allocations) != 2 || allocations[0].DeviceID == allocations[1].DeviceID {
+		t.Fatalf("Expected two allocations on different GPUs, got %+v", allocations)
+	}
+
+	// Only card2 is free, so the second request of this batch cannot be placed
+	_, err = allocator.AllocateBatch(newTestBatch("job-b-0", "job-b-1"))
+	assertBatchFailed(t, err, 1, "job-b-1")
+	if remaining := allocator.GetGPUAllocations("card2"); len(remaining) != 0 {
+		t.Errorf("Expected the failed batch to leave card2 free, got %d allocations", len(remaining))
+	}
+
+	for _, allocation := range allocations {
+		if err := allocator.Release(allocation.ID); err != nil {
+			t.Fatalf("Release failed: %v", err)
+		}
+	}
+
+	// A batch needing more GPUs than exist leaves nothing behind
+	_, err = allocator.AllocateBatch(newTestBatch("job-c-0", "job-c-1", "job-c-2", "job-c-3"))
+	assertBatchFailed(t, err, 3, "job-c-3")
+	for deviceID, remaining := range allocator.GetAllGPUAllocations() {
+		if len(remaining) != 0 {
+			t.Errorf("Expected no allocations on %s after a failed batch, got %d", deviceID, len(remaining))
+		}
+	}
+
+	_, err = allocator.AllocateBatch(newTestBatch("same", "same"))
+	assertBatchFailed(t, err, 1, "same")
+}
+
+func TestMI300XAllocateBatch(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+	for _, deviceID := range []string{"card0", "card1"} {
+		if err := allocator.RegisterMI300XGPU(deviceID, 192*1024*1024*1024, nil); err != nil {
+			t.Fatalf("Failed to register GPU: %v", err)
+		}
+	}
+
+	allocations, err := allocator.AllocateBatch(newTestBatch("rank-0", "rank-1"))
+	if err != nil {
+		t.Fatalf("AllocateBatch failed: %v", err)
+	}
+	if allocations[0].DeviceID != "card0" || allocations[1].DeviceID != "card1" {
+		t.Errorf("Expected ranks on card0 and card1, got %s and %s", allocations[0].DeviceID, allocations[1].DeviceID)
+	}
+	for _, allocation := range allocations {
+		if err := allocator.Release(allocation.ID); err != nil {
+			t.Fatalf("Release failed: %v", err)
+		}
+	}
+
+	// SPX GPUs only take whole-GPU requests, so the half-GPU request fails
+	requests := newTestBatch("whole-0", "half", "whole-1")
+	requests[1].GPURequest.Fraction = 0.5
+	_, err = allocator.AllocateBatch(requests)
+	assertBatchFailed(t, err, 1, "half")
+	for _, deviceID := range []string{"card0", "card1"} {
+		stats, err := allocator.GetGPUUtilization(deviceID)
+		if err != nil {
+			t.Fatalf("GetGPUUtilization failed: %v", err)
+		}
+		if stats.ActiveAllocations != 0 {
+			t.Errorf("Expected no allocations on %s after a failed batch, got %d", deviceID, stats.ActiveAllocations)
+		}
+	}
+}
+
+func TestAllocateBatchRejectsLiveAllocationIDs(t *testing.T) {
+	fractional := NewFractionalAllocator()
+	mi300x := NewMI300XFractionalAllocator()
+	for _, deviceID := range []string{"card0", "card1", "card2"} {
+		if err := fractional.RegisterGPU(deviceID, 16*1024*1024*1024, false); err != nil {
+			t.Fatalf("RegisterGPU failed: %v", err)
+		}
+		if err := mi300x.RegisterMI300XGPU(deviceID, 192*1024*1024*1024, nil); err != nil {
+			t.Fatalf("Failed to register GPU: %v", err)
+		}
+	}
+
+	// The batch reuses a live ID, so it must fail without touching the
+	// allocation that already holds it
+	existing, err := fractional.Allocate("card0", newTestAllocationRequest("existing", 1.0))
+	if err != nil {
+		t.Fatalf("Allocation failed: %v", err)
+	}
+	_, err = fractional.AllocateBatch(newTestBatch("new-0", "existing"))
+	assertBatchFailed(t, err, 1, "existing")
+	for deviceID, remaining := range fractional.GetAllGPUAllocations() {
+		if deviceID == "card0" {
+			if len(remaining) != 1 || remaining[0] != existing {
+				t.Errorf("Expected the existing allocation to survive on card0, got %+v", remaining)
+			}
+		} else if len(remaining) != 0 {
+			t.Errorf("Expected the failed batch to leave %s free, got %d allocations", deviceID, len(remaining))
+		}
+	}
+
+	if _, err := mi300x.Allocate("card0", newTestAllocationRequest("existing", 1.0)); err != nil {
+		t.Fatalf("Allocation failed: %v", err)
+	}
+	_, err = mi300x.AllocateBatch(newTestBatch("new-0", "existing"))
+	assertBatchFailed(t, err, 1, "existing")
+	for deviceID, expected := range map[string]int{"card0": 1, "card1": 0, "card2": 0} {
+		stats, err := mi300x.GetGPUUtilization(deviceID)
+		if err != nil {
+			t.Fatalf("GetGPUUtilization failed: %v", err)
+		}
+		if stats.ActiveAllocations != expected {
+			t.Errorf("Expected %d allocations on %s, got %d", expected, deviceID, stats.ActiveAllocations)
+		}
+	}
+}
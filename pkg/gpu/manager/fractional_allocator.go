@@ -15,14 +15,39 @@
 package manager
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"math"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/silogen/kaiwo/pkg/gpu/types"
 )
 
-// FractionalAllocator manages fractional GPU allocations
+// fractionTolerance absorbs floating point error when summing GPU fractions
+const fractionTolerance = 1e-9
+
+// ErrDeviceNotRegistered is returned when an operation references a GPU that
+// has not been registered with an allocator
+var ErrDeviceNotRegistered = errors.New("GPU is not registered")
+
+// ErrDeviceAlreadyRegistered is returned when registering a GPU that an
+// allocator already manages without forcing the registration
+var ErrDeviceAlreadyRegistered = errors.New("GPU is already registered")
+
+// ErrInsufficientCapacity is returned when a GPU lacks the free fraction or
+// memory for a request
+var ErrInsufficientCapacity = errors.New("insufficient GPU capacity")
+
+// ErrAllocationNotFound is returned when releasing an allocation an allocator
+// does not hold, e.g. because it has already expired
+var ErrAllocationNotFound = errors.New("allocation not found")
+
+// FractionalAllocator manages fractional GPU allocations. It is safe for
+// concurrent use.
 type FractionalAllocator struct {
 	// allocations tracks fractional allocations per GPU
 	allocations map[string][]*types.GPUAllocation
@@ -32,6 +57,26 @@ type FractionalAllocator struct {
 
 	// gpuMemoryCapacity tracks the memory capacity of each GPU
 	gpuMemoryCapacity map[string]int64
+
+	// oversubscription is how many times its capacity each GPU's fractions may
+	// add up to; GPUs without an entry are not oversubscribed
+	oversubscription map[string]float64
+
+	// singleTenantPerGPU prevents different tenants from sharing a GPU
+	singleTenantPerGPU bool
+
+	// partitions reserves capacity on every GPU for higher priority allocations
+	partitions types.CapacityPartitions
+
+	// events records allocation outcomes; nil records none
+	events *AllocationEventLog
+
+	// maxAllocations is the number of active allocations at which a GPU is
+	// fully loaded when scoring load
+	maxAllocations int
+
+	// mu guards all of the fields above
+	mu sync.RWMutex
 }
 
 // NewFractionalAllocator creates a new fractional allocator
@@ -40,25 +85,154 @@ func NewFractionalAllocator() *FractionalAllocator {
 		allocations:       make(map[string][]*types.GPUAllocation),
 		gpuCapacity:       make(map[string]float64),
 		gpuMemoryCapacity: make(map[string]int64),
+		oversubscription:  make(map[string]float64),
+		maxAllocations:    DefaultMaxAllocations,
 	}
 }
 
-// RegisterGPU registers a GPU with the fractional allocator
-func (f *FractionalAllocator) RegisterGPU(deviceID string, totalMemory int64) {
+// SetEventLog makes the allocator record its allocations, failed requests,
+// releases and expiries in log. A nil log stops recording.
+func (f *FractionalAllocator) SetEventLog(log *AllocationEventLog) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = log
+}
+
+// RegisterGPU registers a GPU with the fractional allocator. Registering a GPU
+// that is already registered fails with ErrDeviceAlreadyRegistered unless force
+// is set, in which case the GPU is registered anew and its allocations dropped.
+func (f *FractionalAllocator) RegisterGPU(deviceID string, totalMemory int64, force bool) error {
+	if err := validateGPURegistration(deviceID, totalMemory); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.registerGPU(deviceID, totalMemory, force)
+}
+
+// validateGPURegistration checks the device ID and memory of a GPU to register
+func validateGPURegistration(deviceID string, totalMemory int64) error {
+	if deviceID == "" {
+		return fmt.Errorf("device ID cannot be empty")
+	}
+	if totalMemory <= 0 {
+		return fmt.Errorf("total memory of GPU %s must be positive, got %d", deviceID, totalMemory)
+	}
+	return nil
+}
+
+// registerGPU registers a GPU, dropping any allocations and oversubscription it
+// had if force is set. Callers must hold f.mu.
+func (f *FractionalAllocator) registerGPU(deviceID string, totalMemory int64, force bool) error {
+	if _, exists := f.gpuCapacity[deviceID]; exists && !force {
+		return fmt.Errorf("%w: %s", ErrDeviceAlreadyRegistered, deviceID)
+	}
+
 	f.gpuCapacity[deviceID] = 1.0 // Full GPU capacity
 	f.gpuMemoryCapacity[deviceID] = totalMemory
 	f.allocations[deviceID] = make([]*types.GPUAllocation, 0)
+	delete(f.oversubscription, deviceID)
+	return nil
+}
+
+// RegisterOversubscribedGPU registers a GPU whose allocated fractions may add
+// up to factor times its capacity, for time-sliced GPUs whose workloads rarely
+// all run at once. Memory is never oversubscribed. A factor of 1.0 is the same
+// as RegisterGPU, and force has the same meaning.
+func (f *FractionalAllocator) RegisterOversubscribedGPU(deviceID string, totalMemory int64, factor float64, force bool) error {
+	if err := validateGPURegistration(deviceID, totalMemory); err != nil {
+		return err
+	}
+	if factor < 1.0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		return fmt.Errorf("oversubscription factor must be a finite number of at least 1.0, got %f", factor)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.registerGPU(deviceID, totalMemory, force); err != nil {
+		return err
+	}
+	if factor > 1.0 {
+		f.oversubscription[deviceID] = factor
+	}
+	return nil
+}
+
+// oversubscriptionFactor returns how many times its capacity a GPU's fractions
+// may add up to. Callers must hold f.mu.
+func (f *FractionalAllocator) oversubscriptionFactor(deviceID string) float64 {
+	if factor, exists := f.oversubscription[deviceID]; exists {
+		return factor
+	}
+	return 1.0
+}
+
+// IsRegistered reports whether a GPU has been registered
+func (f *FractionalAllocator) IsRegistered(deviceID string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	_, exists := f.gpuCapacity[deviceID]
+	return exists
+}
+
+// SetSingleTenantPerGPU enables or disables single-tenant mode. When enabled, a GPU
+// hosting an active allocation for one tenant rejects allocations for any other tenant.
+func (f *FractionalAllocator) SetSingleTenantPerGPU(enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.singleTenantPerGPU = enabled
+}
+
+// SetMaxAllocations sets the number of active allocations at which load
+// balancing treats a GPU as fully loaded. Zero selects DefaultMaxAllocations.
+func (f *FractionalAllocator) SetMaxAllocations(maxAllocations int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.maxAllocations = maxAllocations
+}
+
+// SetCapacityPartitions reserves a share of every GPU's capacity for allocations
+// at or above each priority tier
+func (f *FractionalAllocator) SetCapacityPartitions(partitions types.CapacityPartitions) error {
+	if err := types.ValidateCapacityPartitions(partitions); err != nil {
+		return fmt.Errorf("invalid capacity partitions: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.partitions = partitions
+	return nil
 }
 
 // UnregisterGPU unregisters a GPU from the fractional allocator
 func (f *FractionalAllocator) UnregisterGPU(deviceID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	delete(f.gpuCapacity, deviceID)
 	delete(f.gpuMemoryCapacity, deviceID)
 	delete(f.allocations, deviceID)
+	delete(f.oversubscription, deviceID)
 }
 
 // CanAllocate checks if a fractional allocation is possible
 func (f *FractionalAllocator) CanAllocate(deviceID string, request *types.GPURequest) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.canAllocate(deviceID, request)
+}
+
+// canAllocate checks if a fractional allocation is possible. Callers must hold f.mu.
+func (f *FractionalAllocator) canAllocate(deviceID string, request *types.GPURequest) (bool, error) {
 	if request == nil {
 		return false, fmt.Errorf("GPU request cannot be nil")
 	}
@@ -69,22 +243,42 @@ func (f *FractionalAllocator) CanAllocate(deviceID string, request *types.GPUReq
 
 	// Check if GPU is registered
 	if _, exists := f.gpuCapacity[deviceID]; !exists {
-		return false, fmt.Errorf("GPU %s is not registered", deviceID)
+		return false, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
+
+	// Check tenant isolation
+	if f.singleTenantPerGPU {
+		if tenant, occupied := f.getActiveTenant(deviceID, request.TenantID); occupied {
+			return false, fmt.Errorf("GPU %s is occupied by tenant %q, cannot place allocation for tenant %q",
+				deviceID, tenant, request.TenantID)
+		}
 	}
 
 	// Check fractional capacity
 	availableFraction := f.getAvailableFraction(deviceID)
 	if request.Fraction > availableFraction {
-		return false, fmt.Errorf("insufficient fractional capacity: requested %f, available %f",
-			request.Fraction, availableFraction)
+		return false, fmt.Errorf("%w: requested fraction %f, available %f",
+			ErrInsufficientCapacity, request.Fraction, availableFraction)
+	}
+
+	// Check priority partitions, which are shares of the GPU's oversubscribed capacity
+	if len(f.partitions) > 0 {
+		factor := f.oversubscriptionFactor(deviceID)
+		used := f.getUsedFractionByPriority(deviceID)
+		for priority := range used {
+			used[priority] /= factor
+		}
+		if err := f.partitions.Check(request.Priority, request.Fraction/factor, used); err != nil {
+			return false, fmt.Errorf("GPU %s: %w", deviceID, err)
+		}
 	}
 
 	// Check memory capacity
 	if request.MemoryRequest > 0 {
 		availableMemory := f.getAvailableMemory(deviceID)
 		if request.MemoryRequest*1024*1024 > availableMemory { // Convert MiB to bytes
-			return false, fmt.Errorf("insufficient memory: requested %d MiB, available %d bytes",
-				request.MemoryRequest, availableMemory)
+			return false, fmt.Errorf("%w: requested %d MiB of memory, available %d bytes",
+				ErrInsufficientCapacity, request.MemoryRequest, availableMemory)
 		}
 	}
 
@@ -93,7 +287,26 @@ func (f *FractionalAllocator) CanAllocate(deviceID string, request *types.GPUReq
 
 // Allocate performs a fractional allocation
 func (f *FractionalAllocator) Allocate(deviceID string, request *types.AllocationRequest) (*types.GPUAllocation, error) {
-	canAllocate, err := f.CanAllocate(deviceID, request.GPURequest)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	allocation, err := f.allocate(deviceID, request)
+	f.recordOutcome(deviceID, request, allocation, err)
+	return allocation, err
+}
+
+// recordOutcome records an allocation or why a request failed. Callers must hold f.mu.
+func (f *FractionalAllocator) recordOutcome(deviceID string, request *types.AllocationRequest, allocation *types.GPUAllocation, err error) {
+	if err != nil {
+		f.events.Record(requestEvent(types.AllocationEventTypeFailed, request, deviceID, err.Error()))
+		return
+	}
+	f.events.Record(allocationEvent(types.AllocationEventTypeAllocated, allocation, "allocation placed"))
+}
+
+// allocate performs a fractional allocation. Callers must hold f.mu.
+func (f *FractionalAllocator) allocate(deviceID string, request *types.AllocationRequest) (*types.GPUAllocation, error) {
+	canAllocate, err := f.canAllocate(deviceID, request.GPURequest)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +325,9 @@ func (f *FractionalAllocator) Allocate(deviceID string, request *types.Allocatio
 		PodName:       request.PodName,
 		Namespace:     request.Namespace,
 		ContainerName: request.ContainerName,
+		TenantID:      request.GPURequest.TenantID,
+		ReservationID: request.ReservationID,
+		Priority:      request.GPURequest.Priority,
 		Status:        types.GPUAllocationStatusActive,
 		CreatedAt:     time.Now().Unix(),
 		ExpiresAt:     0, // No expiration by default
@@ -128,24 +344,233 @@ func (f *FractionalAllocator) Allocate(deviceID string, request *types.Allocatio
 	return allocation, nil
 }
 
+// AllocateWithPreemption allocates like Allocate, but when the GPU lacks the
+// fraction or memory for the request it preempts active allocations of lower
+// priority to make room. The lowest-priority allocations are preempted first,
+// the newest first among equal priorities, and no more of them than the
+// request needs. Preempted allocations are marked GPUAllocationStatusPreempted
+// and removed from the allocator; they are returned so that the caller can stop
+// their workloads. Allocations of equal or higher priority are never preempted.
+func (f *FractionalAllocator) AllocateWithPreemption(deviceID string, request *types.AllocationRequest) (*types.GPUAllocation, []*types.GPUAllocation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	allocation, victims, err := f.allocateWithPreemption(deviceID, request)
+	f.recordOutcome(deviceID, request, allocation, err)
+	for _, victim := range victims {
+		f.events.Record(allocationEvent(types.AllocationEventTypeReleased, victim,
+			fmt.Sprintf("allocation preempted by %s", request.ID)))
+	}
+	return allocation, victims, err
+}
+
+// allocateWithPreemption allocates, preempting lower-priority allocations if
+// needed. Callers must hold f.mu.
+func (f *FractionalAllocator) allocateWithPreemption(deviceID string, request *types.AllocationRequest) (*types.GPUAllocation, []*types.GPUAllocation, error) {
+	_, err := f.canAllocate(deviceID, request.GPURequest)
+	if err == nil {
+		allocation, err := f.allocate(deviceID, request)
+		return allocation, nil, err
+	}
+	if !errors.Is(err, ErrInsufficientCapacity) {
+		return nil, nil, err
+	}
+
+	victims := f.selectPreemptionVictims(deviceID, request.GPURequest)
+	if victims == nil {
+		return nil, nil, fmt.Errorf("preempting lower-priority allocations on GPU %s would not free enough capacity: %w", deviceID, err)
+	}
+
+	// Other checks, such as capacity partitions, must pass without the victims too
+	for _, victim := range victims {
+		victim.Status = types.GPUAllocationStatusPreempted
+	}
+	if _, err := f.canAllocate(deviceID, request.GPURequest); err != nil {
+		for _, victim := range victims {
+			victim.Status = types.GPUAllocationStatusActive
+		}
+		return nil, nil, fmt.Errorf("cannot allocate on GPU %s even after preemption: %w", deviceID, err)
+	}
+
+	f.allocations[deviceID] = slices.DeleteFunc(f.allocations[deviceID], func(allocation *types.GPUAllocation) bool {
+		return allocation.Status == types.GPUAllocationStatusPreempted
+	})
+
+	allocation, err := f.allocate(deviceID, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return allocation, victims, nil
+}
+
+// selectPreemptionVictims returns the fewest active allocations of lower
+// priority than the request, lowest priority and newest first, whose release
+// frees enough fraction and memory for it, or nil if preempting all of them
+// would not be enough. Callers must hold f.mu.
+func (f *FractionalAllocator) selectPreemptionVictims(deviceID string, request *types.GPURequest) []*types.GPUAllocation {
+	neededFraction := request.Fraction - f.getAvailableFraction(deviceID)
+	neededMemory := request.MemoryRequest*1024*1024 - f.getAvailableMemory(deviceID) // Convert MiB to bytes
+
+	// Allocations are appended as they are made, so walking them backwards
+	// puts the newest first among those created in the same second
+	var candidates []*types.GPUAllocation
+	allocations := f.allocations[deviceID]
+	for i := len(allocations) - 1; i >= 0; i-- {
+		if allocation := allocations[i]; allocation.Status == types.GPUAllocationStatusActive && allocation.Priority < request.Priority {
+			candidates = append(candidates, allocation)
+		}
+	}
+	slices.SortStableFunc(candidates, func(x, y *types.GPUAllocation) int {
+		if x.Priority != y.Priority {
+			return x.Priority - y.Priority
+		}
+		return int(y.CreatedAt - x.CreatedAt)
+	})
+
+	enough := func(fraction float64, memory int64) bool {
+		return fraction >= neededFraction-fractionTolerance && memory >= neededMemory
+	}
+
+	// Take candidates in order until enough is freed
+	var victims []*types.GPUAllocation
+	var freedFraction float64
+	var freedMemory int64
+	for _, candidate := range candidates {
+		if enough(freedFraction, freedMemory) {
+			break
+		}
+		victims = append(victims, candidate)
+		freedFraction += candidate.Fraction
+		freedMemory += candidate.MemoryRequest * 1024 * 1024
+	}
+	if !enough(freedFraction, freedMemory) {
+		return nil
+	}
+
+	// Spare victims taken early that later, larger ones made unnecessary,
+	// highest priority first
+	for i := len(victims) - 1; i >= 0; i-- {
+		victim := victims[i]
+		if enough(freedFraction-victim.Fraction, freedMemory-victim.MemoryRequest*1024*1024) {
+			freedFraction -= victim.Fraction
+			freedMemory -= victim.MemoryRequest * 1024 * 1024
+			victims = slices.Delete(victims, i, i+1)
+		}
+	}
+
+	return victims
+}
+
 // Release releases a fractional allocation
 func (f *FractionalAllocator) Release(allocationID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	allocation, err := f.release(allocationID)
+	if err != nil {
+		return err
+	}
+
+	f.events.Record(allocationEvent(types.AllocationEventTypeReleased, allocation, "allocation released"))
+	return nil
+}
+
+// release releases a fractional allocation and returns it. Callers must hold f.mu.
+func (f *FractionalAllocator) release(allocationID string) (*types.GPUAllocation, error) {
 	for deviceID, allocations := range f.allocations {
 		for i, allocation := range allocations {
 			if allocation.ID == allocationID {
 				// Remove allocation from slice
 				f.allocations[deviceID] = append(allocations[:i], allocations[i+1:]...)
-				return nil
+				return allocation, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrAllocationNotFound, allocationID)
+}
+
+// MigrateAllocation moves an active allocation to another GPU, keeping its ID and
+// metadata. The target must be able to fit the allocation; rescheduling the
+// workload itself is left to the caller.
+func (f *FractionalAllocator) MigrateAllocation(allocationID, targetDeviceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sourceDeviceID, index, allocation := f.findAllocation(allocationID)
+	if allocation == nil {
+		return fmt.Errorf("%w: %s", ErrAllocationNotFound, allocationID)
+	}
+
+	if allocation.Status != types.GPUAllocationStatusActive {
+		return fmt.Errorf("cannot migrate allocation %s in status %s", allocationID, allocation.Status)
+	}
+
+	if sourceDeviceID == targetDeviceID {
+		return fmt.Errorf("allocation %s is already on GPU %s", allocationID, targetDeviceID)
+	}
+
+	if _, err := f.canAllocate(targetDeviceID, migrationRequest(allocation)); err != nil {
+		return fmt.Errorf("cannot migrate allocation %s to GPU %s: %w", allocationID, targetDeviceID, err)
+	}
+
+	sourceAllocations := f.allocations[sourceDeviceID]
+	f.allocations[sourceDeviceID] = append(sourceAllocations[:index], sourceAllocations[index+1:]...)
+
+	allocation.DeviceID = targetDeviceID
+	f.allocations[targetDeviceID] = append(f.allocations[targetDeviceID], allocation)
+
+	return nil
+}
+
+// findAllocation returns the GPU, slice index and allocation for an allocation ID,
+// or a nil allocation if it is not tracked. Callers must hold f.mu.
+func (f *FractionalAllocator) findAllocation(allocationID string) (string, int, *types.GPUAllocation) {
+	for deviceID, allocations := range f.allocations {
+		for i, allocation := range allocations {
+			if allocation.ID == allocationID {
+				return deviceID, i, allocation
 			}
 		}
 	}
+	return "", 0, nil
+}
 
-	return fmt.Errorf("allocation %s not found", allocationID)
+// migrationRequest builds the GPU request an existing allocation needs on a new GPU
+func migrationRequest(allocation *types.GPUAllocation) *types.GPURequest {
+	return &types.GPURequest{
+		Fraction:      allocation.Fraction,
+		MemoryRequest: allocation.MemoryRequest,
+		IsolationType: allocation.IsolationType,
+		TenantID:      allocation.TenantID,
+		Priority:      allocation.Priority,
+	}
 }
 
-// GetAvailableFraction returns the available fractional capacity for a GPU
+// getActiveTenant returns a tenant other than the given one that holds an active
+// allocation on the GPU, and whether such a tenant exists. Callers must hold f.mu.
+func (f *FractionalAllocator) getActiveTenant(deviceID, tenantID string) (string, bool) {
+	for _, allocation := range f.allocations[deviceID] {
+		if allocation.Status == types.GPUAllocationStatusActive && allocation.TenantID != tenantID {
+			return allocation.TenantID, true
+		}
+	}
+	return "", false
+}
+
+// GetAvailableFraction returns the fractional capacity still allocatable on a
+// GPU, including any oversubscription
+func (f *FractionalAllocator) GetAvailableFraction(deviceID string) float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.getAvailableFraction(deviceID)
+}
+
+// getAvailableFraction returns the fractional capacity still allocatable on a
+// GPU. Callers must hold f.mu.
 func (f *FractionalAllocator) getAvailableFraction(deviceID string) float64 {
-	totalCapacity := f.gpuCapacity[deviceID]
+	totalCapacity := f.gpuCapacity[deviceID] * f.oversubscriptionFactor(deviceID)
 	usedCapacity := f.getUsedFraction(deviceID)
 
 	available := totalCapacity - usedCapacity
@@ -157,6 +582,14 @@ func (f *FractionalAllocator) getAvailableFraction(deviceID string) float64 {
 }
 
 // GetAvailableMemory returns the available memory for a GPU
+func (f *FractionalAllocator) GetAvailableMemory(deviceID string) int64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.getAvailableMemory(deviceID)
+}
+
+// getAvailableMemory returns the available memory for a GPU. Callers must hold f.mu.
 func (f *FractionalAllocator) getAvailableMemory(deviceID string) int64 {
 	totalMemory := f.gpuMemoryCapacity[deviceID]
 	usedMemory := f.getUsedMemory(deviceID)
@@ -170,6 +603,14 @@ func (f *FractionalAllocator) getAvailableMemory(deviceID string) int64 {
 }
 
 // GetUsedFraction returns the used fractional capacity for a GPU
+func (f *FractionalAllocator) GetUsedFraction(deviceID string) float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.getUsedFraction(deviceID)
+}
+
+// getUsedFraction returns the used fractional capacity for a GPU. Callers must hold f.mu.
 func (f *FractionalAllocator) getUsedFraction(deviceID string) float64 {
 	allocations := f.allocations[deviceID]
 	var used float64
@@ -183,7 +624,21 @@ func (f *FractionalAllocator) getUsedFraction(deviceID string) float64 {
 	return used
 }
 
-// GetUsedMemory returns the used memory for a GPU
+// getUsedFractionByPriority returns the fractional capacity in use on a GPU by
+// allocation priority. Callers must hold f.mu.
+func (f *FractionalAllocator) getUsedFractionByPriority(deviceID string) map[int]float64 {
+	used := make(map[int]float64)
+
+	for _, allocation := range f.allocations[deviceID] {
+		if allocation.Status == types.GPUAllocationStatusActive {
+			used[allocation.Priority] += allocation.Fraction
+		}
+	}
+
+	return used
+}
+
+// getUsedMemory returns the used memory for a GPU. Callers must hold f.mu.
 func (f *FractionalAllocator) getUsedMemory(deviceID string) int64 {
 	allocations := f.allocations[deviceID]
 	var used int64
@@ -199,6 +654,14 @@ func (f *FractionalAllocator) getUsedMemory(deviceID string) int64 {
 
 // GetGPUUtilization returns the utilization statistics for a GPU
 func (f *FractionalAllocator) GetGPUUtilization(deviceID string) *GPUUtilizationStats {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.getGPUUtilization(deviceID)
+}
+
+// getGPUUtilization returns the utilization statistics for a GPU. Callers must hold f.mu.
+func (f *FractionalAllocator) getGPUUtilization(deviceID string) *GPUUtilizationStats {
 	allocations := f.allocations[deviceID]
 
 	stats := &GPUUtilizationStats{
@@ -211,6 +674,8 @@ func (f *FractionalAllocator) GetGPUUtilization(deviceID string) *GPUUtilization
 		UtilizationRate:       0.0,
 		MemoryUtilizationRate: 0.0,
 	}
+	stats.OversubscriptionFactor = f.oversubscriptionFactor(deviceID)
+	stats.Oversubscribed = stats.UsedFraction > stats.TotalCapacity+fractionTolerance
 
 	// Count active allocations
 	for _, allocation := range allocations {
@@ -237,11 +702,20 @@ func (f *FractionalAllocator) FindBestFitGPU(request *types.GPURequest) (string,
 		return "", fmt.Errorf("GPU request cannot be nil")
 	}
 
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.findBestFitGPU(request)
+}
+
+// findBestFitGPU finds the GPU with the best fit for the allocation request,
+// the lowest device ID among equally good fits. Callers must hold f.mu.
+func (f *FractionalAllocator) findBestFitGPU(request *types.GPURequest) (string, error) {
 	var bestGPU string
 	var bestScore float64 = math.MaxFloat64
 
-	for deviceID := range f.gpuCapacity {
-		canAllocate, err := f.CanAllocate(deviceID, request)
+	for _, deviceID := range slices.Sorted(maps.Keys(f.gpuCapacity)) {
+		canAllocate, err := f.canAllocate(deviceID, request)
 		if err != nil {
 			continue // Skip this GPU if there's an error
 		}
@@ -270,11 +744,14 @@ func (f *FractionalAllocator) FindLoadBalancedGPU(request *types.GPURequest) (st
 		return "", fmt.Errorf("GPU request cannot be nil")
 	}
 
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
 	var bestGPU string
 	var bestLoad float64 = math.MaxFloat64
 
 	for deviceID := range f.gpuCapacity {
-		canAllocate, err := f.CanAllocate(deviceID, request)
+		canAllocate, err := f.canAllocate(deviceID, request)
 		if err != nil {
 			continue
 		}
@@ -297,9 +774,10 @@ func (f *FractionalAllocator) FindLoadBalancedGPU(request *types.GPURequest) (st
 	return bestGPU, nil
 }
 
-// calculateFitScore calculates a fit score for a GPU (lower is better)
+// calculateFitScore calculates a fit score for a GPU (lower is better). Callers
+// must hold f.mu.
 func (f *FractionalAllocator) calculateFitScore(deviceID string, _ *types.GPURequest) float64 {
-	stats := f.GetGPUUtilization(deviceID)
+	stats := f.getGPUUtilization(deviceID)
 
 	// Calculate fit score based on utilization and available resources
 	utilizationScore := stats.UtilizationRate
@@ -311,13 +789,14 @@ func (f *FractionalAllocator) calculateFitScore(deviceID string, _ *types.GPUReq
 	return fitScore
 }
 
-// calculateLoadScore calculates a load score for a GPU (lower is better)
+// calculateLoadScore calculates a load score for a GPU (lower is better). Callers
+// must hold f.mu.
 func (f *FractionalAllocator) calculateLoadScore(deviceID string) float64 {
-	stats := f.GetGPUUtilization(deviceID)
+	stats := f.getGPUUtilization(deviceID)
 
 	// Calculate load score based on utilization and number of allocations
 	utilizationScore := stats.UtilizationRate
-	allocationScore := float64(stats.ActiveAllocations) / 10.0 // Normalize to 0-1
+	allocationScore := allocationLoad(stats.ActiveAllocations, f.maxAllocations)
 
 	// Weight the scores
 	loadScore := utilizationScore*0.7 + allocationScore*0.3
@@ -327,6 +806,9 @@ func (f *FractionalAllocator) calculateLoadScore(deviceID string) float64 {
 
 // CleanupExpiredAllocations removes expired allocations
 func (f *FractionalAllocator) CleanupExpiredAllocations() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	now := time.Now().Unix()
 
 	for deviceID, allocations := range f.allocations {
@@ -336,6 +818,7 @@ func (f *FractionalAllocator) CleanupExpiredAllocations() {
 			if allocation.ExpiresAt > 0 && allocation.ExpiresAt <= now {
 				// Mark as expired
 				allocation.Status = types.GPUAllocationStatusExpired
+				f.events.Record(allocationEvent(types.AllocationEventTypeExpired, allocation, "allocation expired"))
 			} else {
 				validAllocations = append(validAllocations, allocation)
 			}
@@ -345,8 +828,41 @@ func (f *FractionalAllocator) CleanupExpiredAllocations() {
 	}
 }
 
+// StartCleanup runs CleanupExpiredAllocations every interval in the background,
+// so expired allocations free their capacity without a manual call, until ctx
+// is cancelled
+func (f *FractionalAllocator) StartCleanup(ctx context.Context, interval time.Duration) error {
+	return startCleanup(ctx, interval, f.CleanupExpiredAllocations)
+}
+
+// startCleanup starts a goroutine calling cleanup every interval until ctx is cancelled
+func startCleanup(ctx context.Context, interval time.Duration, cleanup func()) error {
+	if interval <= 0 {
+		return fmt.Errorf("cleanup interval must be positive, got %v", interval)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cleanup()
+			}
+		}
+	}()
+
+	return nil
+}
+
 // GetGPUAllocations returns all allocations for a GPU
 func (f *FractionalAllocator) GetGPUAllocations(deviceID string) []*types.GPUAllocation {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
 	allocations, exists := f.allocations[deviceID]
 	if !exists {
 		return []*types.GPUAllocation{}
@@ -359,8 +875,29 @@ func (f *FractionalAllocator) GetGPUAllocations(deviceID string) []*types.GPUAll
 	return result
 }
 
+// GetAllocationsForReservation returns all allocations created for a reservation
+func (f *FractionalAllocator) GetAllocationsForReservation(reservationID string) []*types.GPUAllocation {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var result []*types.GPUAllocation
+
+	for _, allocations := range f.allocations {
+		for _, allocation := range allocations {
+			if allocation.ReservationID == reservationID {
+				result = append(result, allocation)
+			}
+		}
+	}
+
+	return result
+}
+
 // GetAllGPUAllocations returns all allocations across all GPUs
 func (f *FractionalAllocator) GetAllGPUAllocations() map[string][]*types.GPUAllocation {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
 	result := make(map[string][]*types.GPUAllocation)
 
 	for deviceID, allocations := range f.allocations {
@@ -381,15 +918,63 @@ type GPUUtilizationStats struct {
 	ActiveAllocations     int     `json:"activeAllocations"`
 	UtilizationRate       float64 `json:"utilizationRate"`
 	MemoryUtilizationRate float64 `json:"memoryUtilizationRate"`
+	QuadrantMemoryUsage   []int64 `json:"quadrantMemoryUsage,omitempty"` // Used bytes per NPS4 quadrant (MI300X only)
+	// OversubscriptionFactor is how many times TotalCapacity the GPU's fractions may add up to
+	OversubscriptionFactor float64 `json:"oversubscriptionFactor,omitempty"`
+	// Oversubscribed is set when UsedFraction exceeds TotalCapacity; UtilizationRate is then above 1
+	Oversubscribed bool `json:"oversubscribed,omitempty"`
 }
 
 // GetUtilizationStats returns utilization statistics for all GPUs
 func (f *FractionalAllocator) GetUtilizationStats() map[string]*GPUUtilizationStats {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
 	stats := make(map[string]*GPUUtilizationStats)
 
 	for deviceID := range f.gpuCapacity {
-		stats[deviceID] = f.GetGPUUtilization(deviceID)
+		stats[deviceID] = f.getGPUUtilization(deviceID)
 	}
 
 	return stats
 }
+
+// FragmentationReport describes how free GPU memory is spread across the fleet
+type FragmentationReport struct {
+	TotalFreeMemory    int64   `json:"totalFreeMemory"`    // Free bytes summed over all GPUs
+	LargestFreeBlock   int64   `json:"largestFreeBlock"`   // Most free bytes on any single GPU
+	LargestFreeGPU     string  `json:"largestFreeGpu"`     // GPU holding the largest free block
+	FragmentationRatio float64 `json:"fragmentationRatio"` // 0 when all free memory is on one GPU, approaching 1 as it spreads thin
+}
+
+// CanFit reports whether a single allocation of memory bytes fits on any one GPU
+func (r FragmentationReport) CanFit(memory int64) bool {
+	return memory <= r.LargestFreeBlock
+}
+
+// GetMemoryFragmentation reports total free memory, the largest single-GPU free
+// block and how fragmented free memory is. A memory request larger than the
+// largest block cannot be placed even if total free memory would cover it.
+func (f *FractionalAllocator) GetMemoryFragmentation() FragmentationReport {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var report FragmentationReport
+
+	for deviceID := range f.gpuMemoryCapacity {
+		available := f.getAvailableMemory(deviceID)
+		report.TotalFreeMemory += available
+
+		if available > report.LargestFreeBlock ||
+			(available == report.LargestFreeBlock && (report.LargestFreeGPU == "" || deviceID < report.LargestFreeGPU)) {
+			report.LargestFreeBlock = available
+			report.LargestFreeGPU = deviceID
+		}
+	}
+
+	if report.TotalFreeMemory > 0 {
+		report.FragmentationRatio = 1 - float64(report.LargestFreeBlock)/float64(report.TotalFreeMemory)
+	}
+
+	return report
+}
@@ -0,0 +1,576 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+func TestFractionalAllocatorSingleTenantPerGPU(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 128*1024*1024*1024, false)
+	allocator.RegisterGPU("card1", 128*1024*1024*1024, false)
+	allocator.SetSingleTenantPerGPU(true)
+
+	tenantA := newTestAllocationRequest("tenant-a-1", 0.25)
+	tenantA.GPURequest.TenantID = "tenant-a"
+
+	allocation, err := allocator.Allocate("card0", tenantA)
+	if err != nil {
+		t.Fatalf("Failed to allocate for tenant-a: %v", err)
+	}
+
+	if allocation.TenantID != "tenant-a" {
+		t.Errorf("Expected allocation tenant 'tenant-a', got %q", allocation.TenantID)
+	}
+
+	// The same tenant may keep sharing the GPU
+	sameTenant := newTestAllocationRequest("tenant-a-2", 0.25)
+	sameTenant.GPURequest.TenantID = "tenant-a"
+	if _, err := allocator.Allocate("card0", sameTenant); err != nil {
+		t.Errorf("Expected same tenant to share card0, got %v", err)
+	}
+
+	// A different tenant is refused even though capacity remains
+	tenantB := newTestAllocationRequest("tenant-b-1", 0.25)
+	tenantB.GPURequest.TenantID = "tenant-b"
+
+	canAllocate, err := allocator.CanAllocate("card0", tenantB.GPURequest)
+	if err == nil || canAllocate {
+		t.Error("Expected tenant-b to be refused on card0")
+	}
+
+	if _, err := allocator.Allocate("card0", tenantB); err == nil {
+		t.Error("Expected allocation for tenant-b on card0 to fail")
+	}
+
+	// Tenant-b can use a GPU no other tenant occupies
+	if _, err := allocator.Allocate("card1", tenantB); err != nil {
+		t.Errorf("Expected tenant-b to allocate on card1, got %v", err)
+	}
+
+	// Once tenant-a leaves card0, tenant-b may use it
+	for _, id := range []string{"tenant-a-1", "tenant-a-2"} {
+		if err := allocator.Release(id); err != nil {
+			t.Fatalf("Failed to release %s: %v", id, err)
+		}
+	}
+
+	tenantB.ID = "tenant-b-2"
+	if _, err := allocator.Allocate("card0", tenantB); err != nil {
+		t.Errorf("Expected tenant-b to allocate on released card0, got %v", err)
+	}
+}
+
+func TestFractionalAllocatorSharedTenantsByDefault(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 128*1024*1024*1024, false)
+
+	tenantA := newTestAllocationRequest("tenant-a-1", 0.5)
+	tenantA.GPURequest.TenantID = "tenant-a"
+	tenantB := newTestAllocationRequest("tenant-b-1", 0.5)
+	tenantB.GPURequest.TenantID = "tenant-b"
+
+	if _, err := allocator.Allocate("card0", tenantA); err != nil {
+		t.Fatalf("Failed to allocate for tenant-a: %v", err)
+	}
+
+	if _, err := allocator.Allocate("card0", tenantB); err != nil {
+		t.Errorf("Expected tenants to share a GPU without single-tenant mode, got %v", err)
+	}
+}
+
+func TestFractionalAllocatorLoadScoreUsesAllocationLimit(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 128*1024*1024*1024, false)
+	allocator.SetMaxAllocations(2)
+
+	for i := 0; i < 4; i++ {
+		if _, err := allocator.Allocate("card0", newTestAllocationRequest(fmt.Sprintf("alloc-%d", i), 0.1)); err != nil {
+			t.Fatalf("Failed to allocate: %v", err)
+		}
+	}
+
+	allocator.mu.RLock()
+	score := allocator.calculateLoadScore("card0")
+	allocator.mu.RUnlock()
+
+	// Four allocations against a limit of two count as a fully loaded GPU
+	expected := allocator.GetGPUUtilization("card0").UtilizationRate*0.7 + 0.3
+	if math.Abs(score-expected) > 1e-9 {
+		t.Errorf("Expected load score %f, got %f", expected, score)
+	}
+}
+
+func TestFractionalAllocatorMigrateAllocation(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 128*1024*1024*1024, false)
+	allocator.RegisterGPU("card1", 128*1024*1024*1024, false)
+
+	request := newTestAllocationRequest("migrating", 0.5)
+	request.GPURequest.MemoryRequest = 1024
+	request.GPURequest.TenantID = "tenant-a"
+	if _, err := allocator.Allocate("card0", request); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+
+	if err := allocator.MigrateAllocation("migrating", "card1"); err != nil {
+		t.Fatalf("Failed to migrate allocation: %v", err)
+	}
+
+	if allocations := allocator.GetGPUAllocations("card0"); len(allocations) != 0 {
+		t.Errorf("Expected card0 to be empty after migration, got %d allocations", len(allocations))
+	}
+
+	allocations := allocator.GetGPUAllocations("card1")
+	if len(allocations) != 1 {
+		t.Fatalf("Expected 1 allocation on card1, got %d", len(allocations))
+	}
+
+	migrated := allocations[0]
+	if migrated.ID != "migrating" || migrated.DeviceID != "card1" {
+		t.Errorf("Expected allocation 'migrating' on card1, got %s on %s", migrated.ID, migrated.DeviceID)
+	}
+	if migrated.PodName != "pod-migrating" || migrated.TenantID != "tenant-a" || migrated.MemoryRequest != 1024 {
+		t.Errorf("Expected allocation metadata to be preserved, got %+v", migrated)
+	}
+
+	if stats := allocator.GetGPUUtilization("card0"); stats.UsedFraction != 0 {
+		t.Errorf("Expected card0 capacity to be freed, got used fraction %f", stats.UsedFraction)
+	}
+}
+
+func TestFractionalAllocatorMigrateAllocationTargetFull(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 128*1024*1024*1024, false)
+	allocator.RegisterGPU("card1", 128*1024*1024*1024, false)
+
+	if _, err := allocator.Allocate("card0", newTestAllocationRequest("migrating", 0.6)); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	if _, err := allocator.Allocate("card1", newTestAllocationRequest("occupant", 0.5)); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+
+	if err := allocator.MigrateAllocation("migrating", "card1"); err == nil {
+		t.Fatal("Expected migration to a GPU without capacity to fail")
+	}
+
+	// The source allocation is left untouched
+	allocations := allocator.GetGPUAllocations("card0")
+	if len(allocations) != 1 || allocations[0].DeviceID != "card0" {
+		t.Errorf("Expected allocation to remain on card0, got %v", allocations)
+	}
+
+	if err := allocator.MigrateAllocation("migrating", "card0"); err == nil {
+		t.Error("Expected migration to the same GPU to fail")
+	}
+	if err := allocator.MigrateAllocation("missing", "card1"); !errors.Is(err, ErrAllocationNotFound) {
+		t.Errorf("Expected ErrAllocationNotFound, got %v", err)
+	}
+	if err := allocator.MigrateAllocation("migrating", "unknown"); !errors.Is(err, ErrDeviceNotRegistered) {
+		t.Errorf("Expected ErrDeviceNotRegistered, got %v", err)
+	}
+}
+
+func TestFractionalAllocatorMemoryFragmentation(t *testing.T) {
+	const gib = int64(1024 * 1024 * 1024)
+
+	allocator := NewFractionalAllocator()
+
+	// Leave 16, 24, 8 and 16 GiB free on four 64 GiB GPUs
+	usedGiB := map[string]int64{"card0": 48, "card1": 40, "card2": 56, "card3": 48}
+	for deviceID, used := range usedGiB {
+		allocator.RegisterGPU(deviceID, 64*gib, false)
+
+		request := newTestAllocationRequest("fill-"+deviceID, 0.5)
+		request.GPURequest.MemoryRequest = used * 1024 // MiB
+		if _, err := allocator.Allocate(deviceID, request); err != nil {
+			t.Fatalf("Failed to allocate on %s: %v", deviceID, err)
+		}
+	}
+
+	report := allocator.GetMemoryFragmentation()
+	if report.TotalFreeMemory != 64*gib {
+		t.Errorf("Expected 64 GiB free in total, got %d", report.TotalFreeMemory)
+	}
+	if report.LargestFreeBlock != 24*gib || report.LargestFreeGPU != "card1" {
+		t.Errorf("Expected largest free block of 24 GiB on card1, got %d on %s", report.LargestFreeBlock, report.LargestFreeGPU)
+	}
+	if math.Abs(report.FragmentationRatio-0.625) > 1e-9 {
+		t.Errorf("Expected fragmentation ratio 0.625, got %f", report.FragmentationRatio)
+	}
+
+	// 48 GiB is free across the fleet but does not fit on any one GPU
+	if report.CanFit(48 * gib) {
+		t.Error("Expected a 48 GiB request not to fit despite 64 GiB free in total")
+	}
+	if !report.CanFit(24 * gib) {
+		t.Error("Expected a 24 GiB request to fit on the largest free block")
+	}
+}
+
+func TestFractionalAllocatorMemoryFragmentationEmpty(t *testing.T) {
+	allocator := NewFractionalAllocator()
+
+	report := allocator.GetMemoryFragmentation()
+	if report.TotalFreeMemory != 0 || report.LargestFreeBlock != 0 || report.FragmentationRatio != 0 {
+		t.Errorf("Expected empty report without GPUs, got %+v", report)
+	}
+}
+
+func TestFractionalAllocatorRegisterDuplicateGPU(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	if err := allocator.RegisterGPU("card0", 16*1024*1024*1024, false); err != nil {
+		t.Fatalf("RegisterGPU failed: %v", err)
+	}
+	if _, err := allocator.Allocate("card0", newTestAllocationRequest("first", 0.5)); err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+
+	// A second registration is rejected and keeps the existing allocations
+	err := allocator.RegisterGPU("card0", 32*1024*1024*1024, false)
+	if !errors.Is(err, ErrDeviceAlreadyRegistered) {
+		t.Fatalf("Expected ErrDeviceAlreadyRegistered, got %v", err)
+	}
+	if err := allocator.RegisterOversubscribedGPU("card0", 16*1024*1024*1024, 2.0, false); !errors.Is(err, ErrDeviceAlreadyRegistered) {
+		t.Errorf("Expected ErrDeviceAlreadyRegistered for an oversubscribed registration, got %v", err)
+	}
+	if allocations := allocator.GetGPUAllocations("card0"); len(allocations) != 1 {
+		t.Errorf("Expected the allocation to survive a rejected registration, got %d allocations", len(allocations))
+	}
+
+	// Forcing the registration starts the GPU afresh
+	if err := allocator.RegisterGPU("card0", 32*1024*1024*1024, true); err != nil {
+		t.Fatalf("Forced RegisterGPU failed: %v", err)
+	}
+	stats := allocator.GetGPUUtilization("card0")
+	if stats.ActiveAllocations != 0 || stats.TotalMemory != 32*1024*1024*1024 {
+		t.Errorf("Expected an empty 32 GiB GPU after a forced registration, got %+v", stats)
+	}
+}
+
+func TestFractionalAllocatorRegisterInvalidGPU(t *testing.T) {
+	allocator := NewFractionalAllocator()
+
+	tests := []struct {
+		name        string
+		deviceID    string
+		totalMemory int64
+	}{
+		{"zero memory", "card0", 0},
+		{"negative memory", "card0", -1},
+		{"empty device ID", "", 16 * 1024 * 1024 * 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := allocator.RegisterGPU(tt.deviceID, tt.totalMemory, true); err == nil {
+				t.Error("Expected RegisterGPU to fail")
+			}
+			if err := allocator.RegisterOversubscribedGPU(tt.deviceID, tt.totalMemory, 2.0, true); err == nil {
+				t.Error("Expected RegisterOversubscribedGPU to fail")
+			}
+			if allocator.IsRegistered(tt.deviceID) {
+				t.Errorf("Expected %q not to be registered", tt.deviceID)
+			}
+		})
+	}
+}
+
+func TestFractionalAllocatorCapacityPartitions(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 128*1024*1024*1024, false)
+	if err := allocator.SetCapacityPartitions(types.CapacityPartitions{15: 0.25}); err != nil {
+		t.Fatalf("Failed to set capacity partitions: %v", err)
+	}
+
+	normal := newTestAllocationRequest("normal", 0.5)
+	normal.GPURequest.Priority = 5
+	if _, err := allocator.Allocate("card0", normal); err != nil {
+		t.Fatalf("Failed to allocate normal priority: %v", err)
+	}
+
+	encroaching := newTestAllocationRequest("encroaching", 0.3)
+	encroaching.GPURequest.Priority = 5
+	if _, err := allocator.Allocate("card0", encroaching); err == nil {
+		t.Error("Expected normal allocation encroaching on the urgent partition to be rejected")
+	}
+
+	urgent := newTestAllocationRequest("urgent", 0.5)
+	urgent.GPURequest.Priority = 15
+	if _, err := allocator.Allocate("card0", urgent); err != nil {
+		t.Errorf("Expected urgent allocation to use the urgent partition, got %v", err)
+	}
+}
+
+func TestFractionalAllocatorOversubscription(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	if err := allocator.RegisterOversubscribedGPU("card0", 16*1024*1024*1024, 2.0, false); err != nil {
+		t.Fatalf("Failed to register oversubscribed GPU: %v", err)
+	}
+
+	// Fractions may add up to twice the GPU's capacity
+	for i, fraction := range []float64{1.0, 0.5, 0.5} {
+		request := newTestAllocationRequest(fmt.Sprintf("inference-%d", i), fraction)
+		if _, err := allocator.Allocate("card0", request); err != nil {
+			t.Fatalf("Expected fraction %.1f to fit on the oversubscribed GPU: %v", fraction, err)
+		}
+	}
+
+	if _, err := allocator.Allocate("card0", newTestAllocationRequest("over", 0.1)); err == nil {
+		t.Error("Expected a total fraction of 2.1 to exceed the 2.0x oversubscription")
+	}
+
+	stats := allocator.GetGPUUtilization("card0")
+	if !stats.Oversubscribed || stats.OversubscriptionFactor != 2.0 {
+		t.Errorf("Expected card0 reported oversubscribed at 2.0x, got %+v", stats)
+	}
+	if stats.TotalCapacity != 1.0 || stats.UtilizationRate != 2.0 {
+		t.Errorf("Expected a utilization rate of 2.0 against capacity 1.0, got %+v", stats)
+	}
+
+	// Memory is never oversubscribed
+	if err := allocator.RegisterOversubscribedGPU("card1", 16*1024*1024*1024, 2.0, false); err != nil {
+		t.Fatalf("Failed to register oversubscribed GPU: %v", err)
+	}
+	request := newTestAllocationRequest("memory", 0.5)
+	request.GPURequest.MemoryRequest = 16 * 1024 // MiB
+	if _, err := allocator.Allocate("card1", request); err != nil {
+		t.Fatalf("Expected the GPU's full memory to fit: %v", err)
+	}
+	request = newTestAllocationRequest("memory-over", 0.5)
+	request.GPURequest.MemoryRequest = 1
+	if canAllocate, _ := allocator.CanAllocate("card1", request.GPURequest); canAllocate {
+		t.Error("Expected memory beyond the GPU's capacity to be refused")
+	}
+	if stats := allocator.GetGPUUtilization("card1"); stats.Oversubscribed {
+		t.Errorf("Expected card1 at half its capacity not to be oversubscribed, got %+v", stats)
+	}
+
+	if err := allocator.RegisterOversubscribedGPU("card2", 16*1024*1024*1024, 0.5, false); err == nil {
+		t.Error("Expected a factor below 1.0 to be rejected")
+	}
+	if allocator.IsRegistered("card2") {
+		t.Error("Expected a GPU with an invalid factor not to be registered")
+	}
+}
+
+// newTestPriorityRequest creates an allocation request at a priority
+func newTestPriorityRequest(id string, fraction float64, priority int) *types.AllocationRequest {
+	request := newTestAllocationRequest(id, fraction)
+	request.GPURequest.Priority = priority
+	return request
+}
+
+func TestFractionalAllocatorPreemption(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 16*1024*1024*1024, false)
+
+	// Fill the card with low-priority allocations
+	for i := 0; i < 4; i++ {
+		if _, err := allocator.Allocate("card0", newTestPriorityRequest(fmt.Sprintf("batch-%d", i), 0.25, 1)); err != nil {
+			t.Fatalf("Failed to allocate batch-%d: %v", i, err)
+		}
+	}
+
+	// A request at the same priority preempts nothing
+	if _, _, err := allocator.AllocateWithPreemption("card0", newTestPriorityRequest("peer", 0.25, 1)); err == nil {
+		t.Fatal("Expected a request of equal priority not to preempt")
+	} else if !errors.Is(err, ErrInsufficientCapacity) {
+		t.Errorf("Expected an insufficient capacity error, got %v", err)
+	}
+	if used := allocator.GetUsedFraction("card0"); used != 1.0 {
+		t.Fatalf("Expected the card untouched after a failed preemption, got %.2f used", used)
+	}
+
+	// A high-priority request for half the card preempts exactly two, newest first
+	allocation, victims, err := allocator.AllocateWithPreemption("card0", newTestPriorityRequest("serving", 0.5, 10))
+	if err != nil {
+		t.Fatalf("AllocateWithPreemption failed: %v", err)
+	}
+	if allocation.ID != "serving" || allocation.Status != types.GPUAllocationStatusActive {
+		t.Errorf("Unexpected allocation: %+v", allocation)
+	}
+	var victimIDs []string
+	for _, victim := range victims {
+		victimIDs = append(victimIDs, victim.ID)
+		if victim.Status != types.GPUAllocationStatusPreempted {
+			t.Errorf("Expected %s marked preempted, got %s", victim.ID, victim.Status)
+		}
+	}
+	if !slices.Equal(victimIDs, []string{"batch-3", "batch-2"}) {
+		t.Errorf("Expected batch-3 and batch-2 preempted, got %v", victimIDs)
+	}
+	if remaining := allocator.GetGPUAllocations("card0"); len(remaining) != 3 {
+		t.Errorf("Expected 3 allocations left on card0, got %d", len(remaining))
+	}
+
+	// Failures other than missing capacity preempt nothing
+	if _, victims, err := allocator.AllocateWithPreemption("card1", newTestPriorityRequest("missing", 0.5, 10)); err == nil || victims != nil {
+		t.Errorf("Expected an unregistered GPU to fail without victims, got %v, %v", victims, err)
+	}
+}
+
+func TestFractionalAllocatorPreemptsFewestVictims(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 16*1024*1024*1024, false)
+
+	for _, request := range []*types.AllocationRequest{
+		newTestPriorityRequest("low", 0.25, 1),
+		newTestPriorityRequest("medium", 0.5, 2),
+		newTestPriorityRequest("high", 0.25, 3),
+	} {
+		if _, err := allocator.Allocate("card0", request); err != nil {
+			t.Fatalf("Failed to allocate %s: %v", request.ID, err)
+		}
+	}
+
+	// Freeing low alone is not enough; once medium is taken, low can be spared
+	_, victims, err := allocator.AllocateWithPreemption("card0", newTestPriorityRequest("urgent", 0.5, 5))
+	if err != nil {
+		t.Fatalf("AllocateWithPreemption failed: %v", err)
+	}
+	if len(victims) != 1 || victims[0].ID != "medium" {
+		t.Errorf("Expected only medium preempted, got %+v", victims)
+	}
+
+	// Nothing of lower priority can free the whole card
+	if _, _, err := allocator.AllocateWithPreemption("card0", newTestPriorityRequest("whole", 1.0, 2)); err == nil {
+		t.Error("Expected preemption to fail when lower-priority allocations free too little")
+	}
+}
+
+func TestFractionalAllocatorConcurrentAllocateRelease(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	devices := []string{"card0", "card1", "card2", "card3"}
+	for _, deviceID := range devices {
+		allocator.RegisterGPU(deviceID, 8*1024*1024*1024, false)
+	}
+
+	// Eight workers per GPU contend for four quarter-GPU slots while readers
+	// check that no GPU is ever allocated beyond its capacity
+	const workersPerGPU = 8
+	const iterations = 200
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(devices)*workersPerGPU+1)
+	for _, deviceID := range devices {
+		for worker := 0; worker < workersPerGPU; worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < iterations; i++ {
+					id := fmt.Sprintf("%s-%d-%d", deviceID, worker, i)
+					if _, err := allocator.Allocate(deviceID, newTestAllocationRequest(id, 0.25)); err != nil {
+						if !errors.Is(err, ErrInsufficientCapacity) {
+							errs <- fmt.Errorf("allocate %s: %w", id, err)
+							return
+						}
+						continue
+					}
+					if err := allocator.Release(id); err != nil {
+						errs <- fmt.Errorf("release %s: %w", id, err)
+						return
+					}
+				}
+			}()
+		}
+	}
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for deviceID, stats := range allocator.GetUtilizationStats() {
+				if stats.UsedFraction > stats.TotalCapacity+fractionTolerance {
+					errs <- fmt.Errorf("GPU %s allocated %f of capacity %f", deviceID, stats.UsedFraction, stats.TotalCapacity)
+					return
+				}
+			}
+			_, _ = allocator.FindBestFitGPU(&types.GPURequest{Fraction: 0.25})
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+	readers.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for deviceID, allocations := range allocator.GetAllGPUAllocations() {
+		if len(allocations) != 0 {
+			t.Errorf("Expected every allocation on %s released, got %d left", deviceID, len(allocations))
+		}
+	}
+}
+
+func TestFractionalAllocatorStartCleanup(t *testing.T) {
+	allocator := NewFractionalAllocator()
+	if err := allocator.RegisterGPU("card0", 8*1024*1024*1024, false); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	request := newTestAllocationRequest("expired", 0.75)
+	request.ExpiresAt = &expired
+	if _, err := allocator.Allocate("card0", request); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	if _, err := allocator.Allocate("card0", newTestAllocationRequest("blocked", 0.5)); err == nil {
+		t.Fatal("Expected the expired allocation to hold its capacity until cleaned up")
+	}
+
+	if err := allocator.StartCleanup(context.Background(), 0); err == nil {
+		t.Error("Expected a non-positive interval to be rejected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := allocator.StartCleanup(ctx, 10*time.Millisecond); err != nil {
+		t.Fatalf("Failed to start cleanup: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for allocator.GetAvailableFraction("card0") < 1.0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the expired allocation to be cleaned up, %f still available",
+				allocator.GetAvailableFraction("card0"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := allocator.Allocate("card0", newTestAllocationRequest("admitted", 0.5)); err != nil {
+		t.Errorf("Expected the freed capacity to be allocatable: %v", err)
+	}
+}
@@ -17,6 +17,7 @@ package manager
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/silogen/kaiwo/pkg/gpu/types"
@@ -48,6 +49,9 @@ type GPUManager interface {
 	// GetGPUStats gets GPU statistics
 	GetGPUStats(ctx context.Context) (*types.GPUStats, error)
 
+	// GetAvailableCapacity reports the fraction and memory still free on each GPU
+	GetAvailableCapacity(ctx context.Context) ([]*types.GPUCapacity, error)
+
 	// UpdateGPUInfo updates GPU information
 	UpdateGPUInfo(ctx context.Context, deviceID string) error
 
@@ -92,6 +96,92 @@ type GPUManagerConfig struct {
 
 	// NodeSelector is the node selector for GPU discovery
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// EnableMockGPUs falls back to simulated GPUs when discovery finds none, for
+	// tests and development machines without AMD hardware
+	EnableMockGPUs bool `json:"enableMockGpus,omitempty"`
+
+	// Health sets the thresholds beyond which a GPU is not allocated to
+	Health HealthConfig `json:"health,omitempty"`
+}
+
+// Default health thresholds, used for HealthConfig fields left unset
+const (
+	DefaultMaxTemperature = 90.0 // °C
+	DefaultMaxAllocations = 10
+)
+
+// HealthConfig sets the thresholds beyond which a GPU is considered unhealthy
+// or full. Zero values select the defaults.
+type HealthConfig struct {
+	// MaxTemperature is the hottest a healthy GPU may run, in Celsius
+	MaxTemperature float64 `json:"maxTemperature,omitempty"`
+
+	// MaxPower is the most a healthy GPU may draw, in watts. Zero means no limit.
+	MaxPower float64 `json:"maxPower,omitempty"`
+
+	// MaxAllocations is the number of active allocations at which a GPU is full
+	MaxAllocations int `json:"maxAllocations,omitempty"`
+
+	// MaxUncorrectableECCErrors is the most uncorrectable ECC errors a healthy GPU
+	// may have reported. The default of zero treats any uncorrectable error as unhealthy.
+	MaxUncorrectableECCErrors int64 `json:"maxUncorrectableEccErrors,omitempty"`
+}
+
+// withDefaults returns the health configuration with unset thresholds filled in
+func (h HealthConfig) withDefaults() HealthConfig {
+	if h.MaxTemperature == 0 {
+		h.MaxTemperature = DefaultMaxTemperature
+	}
+	if h.MaxAllocations == 0 {
+		h.MaxAllocations = DefaultMaxAllocations
+	}
+	return h
+}
+
+// checkHealth returns why a GPU is unhealthy, or nil if it is healthy
+func (h HealthConfig) checkHealth(gpu *types.GPUInfo) error {
+	if gpu.Temperature > h.MaxTemperature {
+		return fmt.Errorf("temperature %.1f°C exceeds %.1f°C", gpu.Temperature, h.MaxTemperature)
+	}
+	if h.MaxPower > 0 && gpu.Power > h.MaxPower {
+		return fmt.Errorf("power %.1fW exceeds %.1fW", gpu.Power, h.MaxPower)
+	}
+	if gpu.ECCErrorsUncorrectable > h.MaxUncorrectableECCErrors {
+		return fmt.Errorf("%d uncorrectable ECC errors exceed %d", gpu.ECCErrorsUncorrectable, h.MaxUncorrectableECCErrors)
+	}
+	return nil
+}
+
+// isFull reports whether a GPU with the given number of active allocations is full
+func (h HealthConfig) isFull(activeAllocations int) bool {
+	return activeAllocations >= h.MaxAllocations
+}
+
+// allocationLoad normalizes a number of active allocations to 0-1 against the
+// per-GPU limit, reaching 1 when the GPU is full
+func allocationLoad(activeAllocations, maxAllocations int) float64 {
+	if maxAllocations <= 0 {
+		maxAllocations = DefaultMaxAllocations
+	}
+	return min(float64(activeAllocations)/float64(maxAllocations), 1)
+}
+
+// validateHealthConfig validates health thresholds
+func validateHealthConfig(health HealthConfig) error {
+	if health.MaxTemperature < 0 {
+		return fmt.Errorf("max temperature must be non-negative, got %f", health.MaxTemperature)
+	}
+	if health.MaxPower < 0 {
+		return fmt.Errorf("max power must be non-negative, got %f", health.MaxPower)
+	}
+	if health.MaxAllocations < 0 {
+		return fmt.Errorf("max allocations must be non-negative, got %d", health.MaxAllocations)
+	}
+	if health.MaxUncorrectableECCErrors < 0 {
+		return fmt.Errorf("max uncorrectable ECC errors must be non-negative, got %d", health.MaxUncorrectableECCErrors)
+	}
+	return nil
 }
 
 // GPUManagerFactory creates GPU managers
@@ -108,6 +198,12 @@ type BaseGPUManager struct {
 	config      *GPUManagerConfig
 	allocations map[string]*types.GPUAllocation
 	metrics     *types.AllocationMetrics
+
+	// events records allocation requests, outcomes and releases
+	events *AllocationEventLog
+
+	// mu guards allocations and metrics
+	mu sync.RWMutex
 }
 
 // NewBaseGPUManager creates a new base GPU manager
@@ -118,6 +214,27 @@ func NewBaseGPUManager(config *GPUManagerConfig) *BaseGPUManager {
 		metrics: &types.AllocationMetrics{
 			LastUpdated: time.Now(),
 		},
+		events: NewAllocationEventLog(DefaultEventLogCapacity),
+	}
+}
+
+// EventLog returns the log of the manager's allocation events, to poll or
+// subscribe to
+func (b *BaseGPUManager) EventLog() *AllocationEventLog {
+	return b.events
+}
+
+// recordRequested records that an allocation was requested
+func (b *BaseGPUManager) recordRequested(request *types.AllocationRequest) {
+	if request != nil {
+		b.events.Record(requestEvent(types.AllocationEventTypeRequested, request, "", "allocation requested"))
+	}
+}
+
+// recordFailed records why an allocation request failed
+func (b *BaseGPUManager) recordFailed(request *types.AllocationRequest, err error) {
+	if request != nil {
+		b.events.Record(requestEvent(types.AllocationEventTypeFailed, request, "", err.Error()))
 	}
 }
 
@@ -146,6 +263,11 @@ func (b *BaseGPUManager) ValidateAllocation(ctx context.Context, request *types.
 		return fmt.Errorf("GPU sharing is not enabled")
 	}
 
+	// An allocation places a request on a single device
+	if request.GPURequest.Count > 1 {
+		return fmt.Errorf("allocation requests a single GPU, got a request for %d GPUs", request.GPURequest.Count)
+	}
+
 	// Check fraction limits
 	if request.GPURequest.Fraction < b.config.MinFraction {
 		return fmt.Errorf("GPU fraction %f is below minimum %f", request.GPURequest.Fraction, b.config.MinFraction)
@@ -163,8 +285,20 @@ func (b *BaseGPUManager) ValidateAllocation(ctx context.Context, request *types.
 	return nil
 }
 
+// allocationTimeout returns the timeout for a request, preferring the request's
+// own timeout over the configured default
+func (b *BaseGPUManager) allocationTimeout(request *types.AllocationRequest) time.Duration {
+	if request.Timeout > 0 {
+		return request.Timeout
+	}
+	return b.config.AllocationTimeout
+}
+
 // GetAllocation gets information about a specific allocation
 func (b *BaseGPUManager) GetAllocation(ctx context.Context, allocationID string) (*types.GPUAllocation, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	allocation, exists := b.allocations[allocationID]
 	if !exists {
 		return nil, fmt.Errorf("allocation %s not found", allocationID)
@@ -175,6 +309,9 @@ func (b *BaseGPUManager) GetAllocation(ctx context.Context, allocationID string)
 
 // ListAllocations lists all active allocations
 func (b *BaseGPUManager) ListAllocations(ctx context.Context) ([]*types.GPUAllocation, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	allocations := make([]*types.GPUAllocation, 0, len(b.allocations))
 	for _, allocation := range b.allocations {
 		allocations = append(allocations, allocation)
@@ -185,6 +322,9 @@ func (b *BaseGPUManager) ListAllocations(ctx context.Context) ([]*types.GPUAlloc
 
 // GetMetrics gets allocation metrics
 func (b *BaseGPUManager) GetMetrics(ctx context.Context) (*types.AllocationMetrics, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	// Update metrics
 	b.updateMetrics()
 
@@ -193,6 +333,9 @@ func (b *BaseGPUManager) GetMetrics(ctx context.Context) (*types.AllocationMetri
 
 // ReleaseGPU releases a GPU allocation
 func (b *BaseGPUManager) ReleaseGPU(ctx context.Context, allocationID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	allocation, exists := b.allocations[allocationID]
 	if !exists {
 		return fmt.Errorf("allocation %s not found", allocationID)
@@ -207,9 +350,31 @@ func (b *BaseGPUManager) ReleaseGPU(ctx context.Context, allocationID string) er
 	// Update metrics
 	b.metrics.ActiveAllocations--
 
+	b.events.Record(allocationEvent(types.AllocationEventTypeReleased, allocation, "allocation released"))
+
 	return nil
 }
 
+// allocationIDs returns the IDs of all tracked allocations
+func (b *BaseGPUManager) allocationIDs() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ids := make([]string, 0, len(b.allocations))
+	for id := range b.allocations {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// activeAllocationCount returns the number of active allocations
+func (b *BaseGPUManager) activeAllocationCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return int(b.metrics.ActiveAllocations)
+}
+
 // isIsolationTypeAllowed checks if an isolation type is allowed
 func (b *BaseGPUManager) isIsolationTypeAllowed(isolationType types.GPUIsolationType) bool {
 	for _, allowed := range b.config.AllowedIsolationTypes {
@@ -220,7 +385,7 @@ func (b *BaseGPUManager) isIsolationTypeAllowed(isolationType types.GPUIsolation
 	return false
 }
 
-// updateMetrics updates allocation metrics
+// updateMetrics updates allocation metrics. Callers must hold b.mu.
 func (b *BaseGPUManager) updateMetrics() {
 	b.metrics.ActiveAllocations = int64(len(b.allocations))
 	b.metrics.LastUpdated = time.Now()
@@ -228,9 +393,14 @@ func (b *BaseGPUManager) updateMetrics() {
 
 // addAllocation adds an allocation to the manager
 func (b *BaseGPUManager) addAllocation(allocation *types.GPUAllocation) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	b.allocations[allocation.ID] = allocation
 	b.metrics.ActiveAllocations++
 	b.metrics.SuccessfulAllocations++
+
+	b.events.Record(allocationEvent(types.AllocationEventTypeAllocated, allocation, "allocation placed"))
 }
 
 // DefaultGPUManagerFactory is the default GPU manager factory
@@ -246,6 +416,8 @@ func (f *DefaultGPUManagerFactory) CreateManager(config *GPUManagerConfig) (GPUM
 	switch config.GPUType {
 	case types.GPUTypeAMD:
 		return NewAMDGPUManager(config)
+	case types.GPUTypeNVIDIA:
+		return NewNvidiaGPUManager(config)
 	default:
 		return nil, fmt.Errorf("unsupported GPU type: %s", config.GPUType)
 	}
@@ -255,6 +427,7 @@ func (f *DefaultGPUManagerFactory) CreateManager(config *GPUManagerConfig) (GPUM
 func (f *DefaultGPUManagerFactory) GetSupportedTypes() []types.GPUType {
 	return []types.GPUType{
 		types.GPUTypeAMD,
+		types.GPUTypeNVIDIA,
 	}
 }
 
@@ -265,7 +438,7 @@ func ValidateGPUManagerConfig(config *GPUManagerConfig) error {
 	}
 
 	switch config.GPUType {
-	case types.GPUTypeAMD:
+	case types.GPUTypeAMD, types.GPUTypeNVIDIA:
 		// Valid GPU type
 	default:
 		return fmt.Errorf("unsupported GPU type: %s", config.GPUType)
@@ -304,13 +477,14 @@ func ValidateGPUManagerConfig(config *GPUManagerConfig) error {
 	}
 
 	for _, isolationType := range config.AllowedIsolationTypes {
-		switch isolationType {
-		case types.GPUIsolationTimeSlicing, types.GPUIsolationMIG, types.GPUIsolationNone:
-			// Valid isolation type
-		default:
+		if !isolationType.IsValid() {
 			return fmt.Errorf("invalid isolation type: %s", isolationType)
 		}
 	}
 
+	if err := validateHealthConfig(config.Health); err != nil {
+		return fmt.Errorf("invalid health config: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,533 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// lockPollInterval is how often lockContext retries a contended lock
+const lockPollInterval = 5 * time.Millisecond
+
+// gpuVendor supplies the vendor-specific parts of GPU management to a
+// gpuManagerCore
+type gpuVendor interface {
+	// discover returns the GPUs to manage. Callers must hold the core's mu.
+	discover(ctx context.Context) ([]*types.GPUInfo, error)
+
+	// validateFraction returns why a GPU cannot be allocated the given
+	// fraction of itself, or nil if it can
+	validateFraction(gpu *types.GPUInfo, fraction float64) error
+
+	// selectGPU applies the request's allocation strategy to GPUs, ordered by
+	// device ID, that can all handle it
+	selectGPU(gpus []*types.GPUInfo, request *types.AllocationRequest) (*types.GPUInfo, error)
+}
+
+// gpuManagerCore implements the allocation, release and monitoring shared by
+// the vendor GPU managers, deferring discovery, fraction validation and GPU
+// selection to its vendor
+type gpuManagerCore struct {
+	*BaseGPUManager
+	vendor     gpuVendor
+	name       string // Vendor name used in messages, e.g. "AMD"
+	gpus       map[string]*types.GPUInfo
+	lastUpdate time.Time
+	discovery  GPUDiscovery
+	health     HealthConfig // config.Health with defaults applied
+
+	// fractional tracks the fractional capacity and memory allocated on each GPU
+	fractional *FractionalAllocator
+
+	// nodeLabels resolves node labels for requests with a node selector; nil
+	// matches selectors against node hostnames only
+	nodeLabels NodeLabelSource
+
+	// mockGPUs is set when simulated GPUs are managed instead of discovered
+	// ones; their metrics are not refreshed
+	mockGPUs bool
+
+	// roundRobin counts round-robin allocations; it picks the next candidate GPU
+	roundRobin atomic.Uint64
+
+	// mu guards gpus and lastUpdate. It is held for the whole of AllocateGPU so
+	// that the availability check and the per-GPU bookkeeping happen atomically.
+	mu sync.Mutex
+
+	// stopMonitor cancels the monitoring goroutine, which closes monitorDone on exit
+	stopMonitor context.CancelFunc
+	monitorDone chan struct{}
+}
+
+// newGPUManagerCore creates the shared core of a vendor GPU manager. The
+// caller sets vendor before using it.
+func newGPUManagerCore(config *GPUManagerConfig, name string, discovery GPUDiscovery, health HealthConfig) *gpuManagerCore {
+	fractional := NewFractionalAllocator()
+	fractional.SetMaxAllocations(health.MaxAllocations)
+
+	return &gpuManagerCore{
+		BaseGPUManager: NewBaseGPUManager(config),
+		name:           name,
+		gpus:           make(map[string]*types.GPUInfo),
+		lastUpdate:     time.Now(),
+		discovery:      discovery,
+		health:         health,
+		fractional:     fractional,
+	}
+}
+
+// SetDiscovery replaces the GPU discovery used by Initialize and monitoring. It
+// must be called before Initialize.
+func (c *gpuManagerCore) SetDiscovery(discovery GPUDiscovery) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.discovery = discovery
+}
+
+// SetNodeLabelSource sets where the labels matched by requests' node selectors
+// come from
+func (c *gpuManagerCore) SetNodeLabelSource(source NodeLabelSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nodeLabels = source
+}
+
+// MatchPlacement returns the GPUs, of those given, that the request's GPU type
+// and node selector allow it to be placed on, as AllocateGPU matches them
+func (c *gpuManagerCore) MatchPlacement(ctx context.Context, request *types.AllocationRequest, gpus []*types.GPUInfo) []*types.GPUInfo {
+	c.mu.Lock()
+	source := c.nodeLabels
+	c.mu.Unlock()
+
+	return MatchPlacement(ctx, source, request, gpus)
+}
+
+// Initialize discovers GPUs and starts monitoring them
+func (c *gpuManagerCore) Initialize(ctx context.Context) error {
+	c.mu.Lock()
+	err := c.discoverGPUs(ctx)
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to discover GPUs: %v", err)
+	}
+
+	monitorCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	c.mu.Lock()
+	c.stopMonitor, c.monitorDone = cancel, done
+	c.mu.Unlock()
+	go c.monitorGPUs(monitorCtx, done)
+
+	return nil
+}
+
+// Shutdown stops GPU monitoring, waiting for it to exit, and releases all allocations
+func (c *gpuManagerCore) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	stopMonitor, monitorDone := c.stopMonitor, c.monitorDone
+	c.mu.Unlock()
+
+	if stopMonitor != nil {
+		stopMonitor()
+		select {
+		case <-monitorDone:
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for GPU monitoring to stop: %w", ctx.Err())
+		}
+	}
+
+	for _, allocationID := range c.allocationIDs() {
+		if err := c.ReleaseGPU(ctx, allocationID); err != nil {
+			// Log error but continue
+			fmt.Printf("Error releasing allocation %s: %v\n", allocationID, err)
+		}
+	}
+
+	return nil
+}
+
+// ListGPUs lists all GPUs, ordered by device ID
+func (c *gpuManagerCore) ListGPUs(ctx context.Context) ([]*types.GPUInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.listGPUs(ctx), nil
+}
+
+// listGPUs returns all known GPUs ordered by device ID, refreshing them first
+// if they are stale. Callers must hold c.mu.
+func (c *gpuManagerCore) listGPUs(ctx context.Context) []*types.GPUInfo {
+	if time.Since(c.lastUpdate) > c.config.PollingInterval {
+		c.updateGPUInfo(ctx)
+	}
+
+	gpus := make([]*types.GPUInfo, 0, len(c.gpus))
+	for _, gpu := range c.gpus {
+		gpus = append(gpus, gpu)
+	}
+	slices.SortFunc(gpus, func(x, y *types.GPUInfo) int {
+		return strings.Compare(x.DeviceID, y.DeviceID)
+	})
+
+	return gpus
+}
+
+// GetGPUInfo gets information about a specific GPU
+func (c *gpuManagerCore) GetGPUInfo(ctx context.Context, deviceID string) (*types.GPUInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.updateSingleGPUInfo(ctx, deviceID); err != nil {
+		return nil, err
+	}
+
+	return c.gpus[deviceID], nil
+}
+
+// AllocateGPU allocates a GPU for a request
+func (c *gpuManagerCore) AllocateGPU(ctx context.Context, request *types.AllocationRequest) (result *types.AllocationResult, err error) {
+	c.recordRequested(request)
+	defer func() {
+		if err != nil {
+			c.recordFailed(request, err)
+		}
+	}()
+
+	if err := c.ValidateAllocation(ctx, request); err != nil {
+		return nil, fmt.Errorf("invalid allocation request: %v", err)
+	}
+
+	timeout := c.allocationTimeout(request)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The timeout covers waiting for other allocations to release the lock
+	if err := c.lockContext(ctx); err != nil {
+		return nil, fmt.Errorf("allocation %s timed out after %v waiting for the GPU lock: %w", request.ID, timeout, err)
+	}
+	defer c.mu.Unlock()
+
+	selectedGPU, err := c.findAvailableGPU(ctx, request)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("allocation %s timed out after %v: %w", request.ID, timeout, ctx.Err())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find available GPU: %w", err)
+	}
+	if request.Strategy == types.AllocationStrategyRoundRobin {
+		c.roundRobin.Add(1)
+	}
+
+	// Create allocation, claiming its share of the GPU
+	allocation, err := c.fractional.Allocate(selectedGPU.DeviceID, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate GPU %s: %w", selectedGPU.DeviceID, err)
+	}
+
+	c.addAllocation(allocation)
+
+	selectedGPU.ActiveAllocations++
+	selectedGPU.IsAvailable = c.isGPUAvailable(selectedGPU)
+
+	return &types.AllocationResult{
+		Success:     true,
+		Allocation:  allocation,
+		DeviceID:    selectedGPU.DeviceID,
+		NodeName:    selectedGPU.NodeName,
+		AllocatedAt: time.Now(),
+	}, nil
+}
+
+// lockContext acquires c.mu, giving up with ctx's error if ctx ends first
+func (c *gpuManagerCore) lockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.mu.TryLock() {
+		return nil
+	}
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if c.mu.TryLock() {
+				return nil
+			}
+		}
+	}
+}
+
+// ReleaseGPU releases a GPU allocation and frees its share of the GPU
+func (c *gpuManagerCore) ReleaseGPU(ctx context.Context, allocationID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	allocation, err := c.GetAllocation(ctx, allocationID)
+	if err != nil {
+		return err
+	}
+
+	// Release the fractional allocation first so a failure leaves both records in
+	// place. One the allocator no longer holds, e.g. because it expired, is
+	// already released.
+	if err := c.fractional.Release(allocationID); err != nil && !errors.Is(err, ErrAllocationNotFound) {
+		return fmt.Errorf("failed to release fractional allocation: %w", err)
+	}
+	if err := c.BaseGPUManager.ReleaseGPU(ctx, allocationID); err != nil {
+		return err
+	}
+
+	if gpu, exists := c.gpus[allocation.DeviceID]; exists {
+		if gpu.ActiveAllocations > 0 {
+			gpu.ActiveAllocations--
+		}
+		gpu.IsAvailable = c.isGPUAvailable(gpu)
+	}
+
+	return nil
+}
+
+// GetGPUStats gets GPU statistics
+func (c *gpuManagerCore) GetGPUStats(ctx context.Context) (*types.GPUStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	gpus := c.listGPUs(ctx)
+
+	stats := &types.GPUStats{
+		TotalGPUs:         len(gpus),
+		ActiveAllocations: c.activeAllocationCount(),
+	}
+
+	if len(gpus) == 0 {
+		return stats, nil
+	}
+
+	var totalUtilization, totalTemperature, totalPower float64
+
+	for _, gpu := range gpus {
+		if gpu.IsAvailable {
+			stats.AvailableGPUs++
+		}
+
+		stats.TotalMemory += gpu.TotalMemory
+		stats.AvailableMemory += gpu.AvailableMemory
+		stats.AllocatedFraction += c.fractional.GetUsedFraction(gpu.DeviceID)
+		totalUtilization += gpu.Utilization
+		totalTemperature += gpu.Temperature
+		totalPower += gpu.Power
+	}
+
+	stats.AverageUtilization = totalUtilization / float64(len(gpus))
+	stats.AverageTemperature = totalTemperature / float64(len(gpus))
+	stats.AveragePower = totalPower / float64(len(gpus))
+
+	return stats, nil
+}
+
+// GetAvailableCapacity reports the fraction and memory still free on each GPU,
+// ordered by device ID
+func (c *gpuManagerCore) GetAvailableCapacity(ctx context.Context) ([]*types.GPUCapacity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	gpus := c.listGPUs(ctx)
+	utilization := c.fractional.GetUtilizationStats()
+
+	capacity := make([]*types.GPUCapacity, 0, len(gpus))
+	for _, gpu := range gpus {
+		free := &types.GPUCapacity{
+			DeviceID:  gpu.DeviceID,
+			Available: c.isGPUAvailable(gpu),
+		}
+		if stats, exists := utilization[gpu.DeviceID]; exists {
+			free.FreeFraction = max(stats.TotalCapacity*stats.OversubscriptionFactor-stats.UsedFraction, 0)
+			free.FreeMemory = max(stats.TotalMemory-stats.UsedMemory, 0)
+		}
+		capacity = append(capacity, free)
+	}
+
+	return capacity, nil
+}
+
+// UpdateGPUInfo updates GPU information
+func (c *gpuManagerCore) UpdateGPUInfo(ctx context.Context, deviceID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.updateSingleGPUInfo(ctx, deviceID)
+}
+
+// discoverGPUs starts managing the GPUs the vendor discovers. Callers must
+// hold c.mu.
+func (c *gpuManagerCore) discoverGPUs(ctx context.Context) error {
+	discoveredGPUs, err := c.vendor.discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, gpu := range discoveredGPUs {
+		if err := c.addGPU(gpu); err != nil {
+			fmt.Printf("Skipping GPU %s: %v\n", gpu.DeviceID, err)
+		}
+	}
+
+	fmt.Printf("Discovered %d %s GPUs\n", len(discoveredGPUs), c.name)
+	return nil
+}
+
+// addGPU starts managing a GPU, registering it for fractional allocation unless
+// it already is. A GPU that cannot be registered is not managed. Callers must
+// hold c.mu.
+func (c *gpuManagerCore) addGPU(gpu *types.GPUInfo) error {
+	err := c.fractional.RegisterGPU(gpu.DeviceID, gpu.TotalMemory, false)
+	if err != nil && !errors.Is(err, ErrDeviceAlreadyRegistered) {
+		return fmt.Errorf("failed to register GPU %s: %w", gpu.DeviceID, err)
+	}
+
+	c.gpus[gpu.DeviceID] = gpu
+	return nil
+}
+
+// updateGPUInfo refreshes the metrics of all GPUs. Allocation counts are tracked
+// by the manager, not discovery, so they are carried over the refresh. Callers
+// must hold c.mu.
+func (c *gpuManagerCore) updateGPUInfo(ctx context.Context) {
+	if !c.mockGPUs {
+		activeAllocations := make(map[string]int, len(c.gpus))
+		for deviceID, gpu := range c.gpus {
+			activeAllocations[deviceID] = gpu.ActiveAllocations
+		}
+
+		c.discovery.UpdateGPUMetrics(ctx, c.gpus)
+
+		for deviceID, gpu := range c.gpus {
+			gpu.ActiveAllocations = activeAllocations[deviceID]
+		}
+	}
+
+	c.lastUpdate = time.Now()
+}
+
+// updateSingleGPUInfo updates information for a single GPU. Discovery refreshes
+// all GPUs at once, so this refreshes every GPU. Callers must hold c.mu.
+func (c *gpuManagerCore) updateSingleGPUInfo(ctx context.Context, deviceID string) error {
+	if _, exists := c.gpus[deviceID]; !exists {
+		return fmt.Errorf("GPU %s not found", deviceID)
+	}
+
+	c.updateGPUInfo(ctx)
+
+	return nil
+}
+
+// findAvailableGPU finds an available GPU for allocation. Callers must hold c.mu.
+func (c *gpuManagerCore) findAvailableGPU(ctx context.Context, request *types.AllocationRequest) (*types.GPUInfo, error) {
+	// Filter available GPUs on nodes matching the request's placement
+	placement := newPlacementFilter(ctx, c.nodeLabels, request)
+	var placed, full bool
+	var availableGPUs []*types.GPUInfo
+	var fractionErr error
+	for _, gpu := range c.listGPUs(ctx) {
+		if placement.mismatch(gpu) != "" {
+			continue
+		}
+		placed = true
+		if err := c.vendor.validateFraction(gpu, request.GPURequest.Fraction); err != nil {
+			fractionErr = err
+			continue
+		}
+		if gpu.IsAvailable && c.canGPUHandleRequest(gpu, request) {
+			availableGPUs = append(availableGPUs, gpu)
+		} else {
+			full = true
+		}
+	}
+
+	if !placed {
+		return nil, placement.noMatchError()
+	}
+	if len(availableGPUs) == 0 {
+		// Only GPUs that are busy, rather than unable to take the fraction at
+		// all, can free up for the request
+		switch {
+		case full && fractionErr != nil:
+			return nil, fmt.Errorf("no available GPUs found for request: %w; %w", ErrInsufficientCapacity, fractionErr)
+		case full:
+			return nil, fmt.Errorf("no available GPUs found for request: %w", ErrInsufficientCapacity)
+		default:
+			return nil, fmt.Errorf("no available GPUs found for request: %w", fractionErr)
+		}
+	}
+
+	return c.vendor.selectGPU(availableGPUs, request)
+}
+
+// canGPUHandleRequest checks if a GPU can handle the allocation request
+func (c *gpuManagerCore) canGPUHandleRequest(gpu *types.GPUInfo, request *types.AllocationRequest) bool {
+	if request.GPURequest.MemoryRequest > 0 {
+		if gpu.AvailableMemory < request.GPURequest.MemoryRequest*1024*1024 { // Convert MiB to bytes
+			return false
+		}
+	}
+
+	// Check the fraction and memory left after existing allocations
+	canAllocate, _ := c.fractional.CanAllocate(gpu.DeviceID, request.GPURequest)
+	return canAllocate
+}
+
+// isGPUAvailable checks if a GPU is available for allocation
+func (c *gpuManagerCore) isGPUAvailable(gpu *types.GPUInfo) bool {
+	if c.health.checkHealth(gpu) != nil {
+		return false
+	}
+
+	return !c.health.isFull(gpu.ActiveAllocations)
+}
+
+// monitorGPUs monitors GPU health and performance until ctx is cancelled, then closes done
+func (c *gpuManagerCore) monitorGPUs(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.config.PollingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			c.updateGPUInfo(ctx)
+			c.mu.Unlock()
+		}
+	}
+}
@@ -16,6 +16,7 @@ package manager
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -33,6 +34,7 @@ func TestAMDGPUManager(t *testing.T) {
 		MaxFraction:           1.0,
 		MinFraction:           0.1,
 		AllowedIsolationTypes: []types.GPUIsolationType{types.GPUIsolationTimeSlicing, types.GPUIsolationNone},
+		EnableMockGPUs:        true, // Runs without AMD hardware
 	}
 
 	// Create AMD GPU manager
@@ -95,6 +97,13 @@ func TestAMDGPUManager(t *testing.T) {
 		t.Fatalf("Failed to validate allocation: %v", err)
 	}
 
+	// A single allocation cannot span several GPUs
+	multiGPU := *request
+	multiGPU.GPURequest = &types.GPURequest{Fraction: 1.0, Count: 2, IsolationType: types.GPUIsolationNone}
+	if err := manager.ValidateAllocation(ctx, &multiGPU); err == nil {
+		t.Error("Expected validation to reject a multi-GPU request")
+	}
+
 	// Allocate GPU
 	result, err := manager.AllocateGPU(ctx, request)
 	if err != nil {
@@ -179,8 +188,8 @@ func TestFractionalAllocator(t *testing.T) {
 	allocator := NewFractionalAllocator()
 
 	// Register GPUs
-	allocator.RegisterGPU("card0", 128*1024*1024*1024) // 128GB
-	allocator.RegisterGPU("card1", 128*1024*1024*1024) // 128GB
+	allocator.RegisterGPU("card0", 128*1024*1024*1024, false) // 128GB
+	allocator.RegisterGPU("card1", 128*1024*1024*1024, false) // 128GB
 
 	// Test allocation request
 	request := &types.AllocationRequest{
@@ -262,12 +271,8 @@ func TestGPUManagerFactory(t *testing.T) {
 
 	// Test supported types
 	supportedTypes := factory.GetSupportedTypes()
-	if len(supportedTypes) != 1 {
-		t.Errorf("Expected 1 supported type, got %d", len(supportedTypes))
-	}
-
-	if supportedTypes[0] != types.GPUTypeAMD {
-		t.Errorf("Expected supported type AMD, got %s", supportedTypes[0])
+	if !slices.Equal(supportedTypes, []types.GPUType{types.GPUTypeAMD, types.GPUTypeNVIDIA}) {
+		t.Errorf("Expected supported types AMD and NVIDIA, got %v", supportedTypes)
 	}
 
 	// Test creating AMD manager
@@ -293,7 +298,7 @@ func TestGPUManagerFactory(t *testing.T) {
 
 	// Test creating unsupported manager
 	unsupportedConfig := &GPUManagerConfig{
-		GPUType: types.GPUTypeNVIDIA,
+		GPUType: types.GPUTypeUnknown,
 	}
 
 	_, err = factory.CreateManager(unsupportedConfig)
@@ -321,7 +326,7 @@ func TestGPUManagerConfigValidation(t *testing.T) {
 
 	// Test invalid GPU type
 	invalidGPUConfig := &GPUManagerConfig{
-		GPUType: types.GPUTypeNVIDIA,
+		GPUType: types.GPUTypeUnknown,
 	}
 
 	if err := ValidateGPUManagerConfig(invalidGPUConfig); err == nil {
@@ -349,4 +354,14 @@ func TestGPUManagerConfigValidation(t *testing.T) {
 	if err := ValidateGPUManagerConfig(invalidFractionConfig); err == nil {
 		t.Fatal("Expected error for invalid fraction range")
 	}
+	// SR-IOV isolation is accepted, unknown isolation types are not
+	sriovConfig := *validConfig
+	sriovConfig.AllowedIsolationTypes = []types.GPUIsolationType{types.GPUIsolationSRIOV}
+	if err := ValidateGPUManagerConfig(&sriovConfig); err != nil {
+		t.Fatalf("Config allowing SR-IOV should not return error: %v", err)
+	}
+	sriovConfig.AllowedIsolationTypes = []types.GPUIsolationType{"vfio"}
+	if err := ValidateGPUManagerConfig(&sriovConfig); err == nil {
+		t.Fatal("Expected error for unknown isolation type")
+	}
 }
@@ -1,8 +1,12 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/silogen/kaiwo/pkg/gpu/types"
@@ -16,8 +20,13 @@ const (
 	MI300XPartitionModeSPX MI300XPartitionMode = "SPX"
 	// MI300XPartitionModeCPX - Core Partitioned X-celerator: Each XCD as separate GPU
 	MI300XPartitionModeCPX MI300XPartitionMode = "CPX"
+	// MI300XPartitionModeTPX - Triple Partition X-celerator: XCDs grouped into three partitions
+	MI300XPartitionModeTPX MI300XPartitionMode = "TPX"
 )
 
+// DefaultTPXPartitionGroups is the XCD count of each TPX partition when none are configured
+var DefaultTPXPartitionGroups = []int{3, 3, 2}
+
 // MI300XMemoryMode represents the memory partitioning mode
 type MI300XMemoryMode string
 
@@ -28,11 +37,22 @@ const (
 	MI300XMemoryModeNPS4 MI300XMemoryMode = "NPS4"
 )
 
+// mi300xQuadrantCount is the number of NUMA memory quadrants in NPS4 mode
+const mi300xQuadrantCount = 4
+
+// xcdQuadrant returns the NPS4 memory quadrant an XCD belongs to
+func xcdQuadrant(xcdIndex int) int {
+	return xcdIndex / (8 / mi300xQuadrantCount)
+}
+
 // MI300XPartitionConfig represents the partitioning configuration for MI300X
 type MI300XPartitionConfig struct {
 	ComputeMode MI300XPartitionMode `json:"computeMode"`
 	MemoryMode  MI300XMemoryMode    `json:"memoryMode"`
 	XCDCount    int                 `json:"xcdCount"` // Number of XCDs (always 8 for MI300X)
+	// PartitionGroups is the number of XCDs in each TPX partition, in XCD order.
+	// Only valid in TPX mode; defaults to DefaultTPXPartitionGroups.
+	PartitionGroups []int `json:"partitionGroups,omitempty"`
 }
 
 // MI300XFractionalAllocator manages fractional GPU allocations for MI300X
@@ -51,6 +71,15 @@ type MI300XFractionalAllocator struct {
 
 	// xcdAllocations tracks XCD-level allocations for CPX mode
 	xcdAllocations map[string]map[int]*types.GPUAllocation // deviceID -> xcdIndex -> allocation
+
+	// preferContiguousXCDs places CPX allocations on a contiguous range of XCDs when one is free
+	preferContiguousXCDs bool
+
+	// events records allocation outcomes; nil records none
+	events *AllocationEventLog
+
+	// mu guards all of the fields above
+	mu sync.RWMutex
 }
 
 // NewMI300XFractionalAllocator creates a new MI300X-aware fractional allocator
@@ -64,6 +93,24 @@ func NewMI300XFractionalAllocator() *MI300XFractionalAllocator {
 	}
 }
 
+// MI300XPartitionConfigFromGPU builds the partition configuration of a discovered
+// GPU from the modes its driver reports, defaulting to SPX and NPS1 for modes
+// that were not reported. The result is validated when the GPU is registered.
+func MI300XPartitionConfigFromGPU(gpu *types.GPUInfo) *MI300XPartitionConfig {
+	config := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionMode(gpu.PartitionMode),
+		MemoryMode:  MI300XMemoryMode(gpu.MemoryMode),
+		XCDCount:    8,
+	}
+	if config.ComputeMode == "" {
+		config.ComputeMode = MI300XPartitionModeSPX
+	}
+	if config.MemoryMode == "" {
+		config.MemoryMode = MI300XMemoryModeNPS1
+	}
+	return config
+}
+
 // RegisterMI300XGPU registers an MI300X GPU with the fractional allocator
 func (f *MI300XFractionalAllocator) RegisterMI300XGPU(deviceID string, totalMemory int64, config *MI300XPartitionConfig) error {
 	if config == nil {
@@ -75,11 +122,16 @@ func (f *MI300XFractionalAllocator) RegisterMI300XGPU(deviceID string, totalMemo
 		}
 	}
 
+	config = withDefaultPartitionGroups(config)
+
 	// Validate configuration
 	if err := f.validatePartitionConfig(config); err != nil {
 		return fmt.Errorf("invalid partition config for GPU %s: %w", deviceID, err)
 	}
 
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	f.gpuCapacity[deviceID] = 1.0 // Full GPU capacity
 	f.gpuMemoryCapacity[deviceID] = totalMemory
 	f.allocations[deviceID] = make([]*types.GPUAllocation, 0)
@@ -89,6 +141,72 @@ func (f *MI300XFractionalAllocator) RegisterMI300XGPU(deviceID string, totalMemo
 	return nil
 }
 
+// Reconfigure changes the partitioning of a registered GPU. The change is refused
+// while the GPU has active allocations.
+func (f *MI300XFractionalAllocator) Reconfigure(deviceID string, newConfig *MI300XPartitionConfig) error {
+	if newConfig == nil {
+		return fmt.Errorf("partition config cannot be nil")
+	}
+
+	newConfig = withDefaultPartitionGroups(newConfig)
+
+	if err := f.validatePartitionConfig(newConfig); err != nil {
+		return fmt.Errorf("invalid partition config for GPU %s: %w", deviceID, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, exists := f.gpuCapacity[deviceID]; !exists {
+		return fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
+
+	var blocking []string
+	for _, allocation := range f.allocations[deviceID] {
+		if allocation.Status == types.GPUAllocationStatusActive {
+			blocking = append(blocking, allocation.ID)
+		}
+	}
+
+	if len(blocking) > 0 {
+		return fmt.Errorf("cannot reconfigure GPU %s with active allocations: %s",
+			deviceID, strings.Join(blocking, ", "))
+	}
+
+	f.partitionConfig[deviceID] = newConfig
+	f.xcdAllocations[deviceID] = make(map[int]*types.GPUAllocation)
+
+	return nil
+}
+
+// withDefaultPartitionGroups returns the config with DefaultTPXPartitionGroups
+// filled in for TPX mode when no groups are set
+func withDefaultPartitionGroups(config *MI300XPartitionConfig) *MI300XPartitionConfig {
+	if config.ComputeMode != MI300XPartitionModeTPX || len(config.PartitionGroups) > 0 {
+		return config
+	}
+
+	defaulted := *config
+	defaulted.PartitionGroups = append([]int{}, DefaultTPXPartitionGroups...)
+	return &defaulted
+}
+
+// SetPreferContiguousXCDs enables or disables contiguous placement in CPX mode.
+// When enabled, an allocation takes the lowest-index run of free XCDs long
+// enough to hold it, for better Infinity Fabric locality, and only falls back to
+// scattered XCDs when no such run is free.
+func (f *MI300XFractionalAllocator) SetPreferContiguousXCDs(enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.preferContiguousXCDs = enabled
+}
+
+// usesXCDAllocations reports whether allocations in this mode are pinned to XCDs
+func (c *MI300XPartitionConfig) usesXCDAllocations() bool {
+	return c.ComputeMode == MI300XPartitionModeCPX || c.ComputeMode == MI300XPartitionModeTPX
+}
+
 // validatePartitionConfig validates the MI300X partitioning configuration
 func (f *MI300XFractionalAllocator) validatePartitionConfig(config *MI300XPartitionConfig) error {
 	if config.XCDCount != 8 {
@@ -96,7 +214,7 @@ func (f *MI300XFractionalAllocator) validatePartitionConfig(config *MI300XPartit
 	}
 
 	switch config.ComputeMode {
-	case MI300XPartitionModeSPX, MI300XPartitionModeCPX:
+	case MI300XPartitionModeSPX, MI300XPartitionModeCPX, MI300XPartitionModeTPX:
 		// Valid compute modes
 	default:
 		return fmt.Errorf("invalid compute mode: %s", config.ComputeMode)
@@ -113,18 +231,66 @@ func (f *MI300XFractionalAllocator) validatePartitionConfig(config *MI300XPartit
 	if config.ComputeMode == MI300XPartitionModeSPX && config.MemoryMode == MI300XMemoryModeNPS4 {
 		return fmt.Errorf("NPS4 memory mode is not compatible with SPX compute mode")
 	}
+	if config.ComputeMode == MI300XPartitionModeTPX && config.MemoryMode == MI300XMemoryModeNPS4 {
+		return fmt.Errorf("NPS4 memory mode is not compatible with TPX compute mode")
+	}
+
+	// Validate TPX partition groups
+	if config.ComputeMode != MI300XPartitionModeTPX {
+		if len(config.PartitionGroups) > 0 {
+			return fmt.Errorf("partition groups are only supported in TPX mode, got mode %s", config.ComputeMode)
+		}
+		return nil
+	}
+
+	if len(config.PartitionGroups) != 3 {
+		return fmt.Errorf("TPX mode requires 3 partition groups, got %d", len(config.PartitionGroups))
+	}
+
+	totalXCDs := 0
+	for i, size := range config.PartitionGroups {
+		if size <= 0 {
+			return fmt.Errorf("TPX partition group %d must have at least 1 XCD, got %d", i, size)
+		}
+		totalXCDs += size
+	}
+
+	if totalXCDs != config.XCDCount {
+		return fmt.Errorf("TPX partition groups must cover all %d XCDs, got %d", config.XCDCount, totalXCDs)
+	}
 
 	return nil
 }
 
-// GetValidFractions returns the valid fractional allocations for the given GPU
-func (f *MI300XFractionalAllocator) GetValidFractions(deviceID string) []float64 {
+// GetValidFractions returns the valid fractional allocations for the given GPU.
+// A registered GPU without a partition config only supports full allocation.
+func (f *MI300XFractionalAllocator) GetValidFractions(deviceID string) ([]float64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.getValidFractions(deviceID)
+}
+
+// getValidFractions returns the valid fractions for a GPU. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) getValidFractions(deviceID string) ([]float64, error) {
+	if _, exists := f.gpuCapacity[deviceID]; !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
+
 	config, exists := f.partitionConfig[deviceID]
 	if !exists {
-		return []float64{1.0} // Default to full GPU if not configured
+		return []float64{1.0}, nil
 	}
 
-	switch config.ComputeMode {
+	return config.ValidFractions(), nil
+}
+
+// ValidFractions returns the fractions of a GPU that can be allocated in this
+// partition configuration, in ascending order
+func (c *MI300XPartitionConfig) ValidFractions() []float64 {
+	c = withDefaultPartitionGroups(c)
+
+	switch c.ComputeMode {
 	case MI300XPartitionModeSPX:
 		// SPX mode: Only full GPU allocation (1.0)
 		return []float64{1.0}
@@ -137,6 +303,18 @@ func (f *MI300XFractionalAllocator) GetValidFractions(deviceID string) []float64
 		}
 		return fractions
 
+	case MI300XPartitionModeTPX:
+		// TPX mode: Each allocation takes one whole partition group
+		fractions := make([]float64, 0, len(c.PartitionGroups))
+		for _, size := range c.PartitionGroups {
+			fraction := float64(size) / 8.0
+			if !slices.Contains(fractions, fraction) {
+				fractions = append(fractions, fraction)
+			}
+		}
+		slices.Sort(fractions)
+		return fractions
+
 	default:
 		return []float64{1.0}
 	}
@@ -144,7 +322,18 @@ func (f *MI300XFractionalAllocator) GetValidFractions(deviceID string) []float64
 
 // ValidateFraction validates if a fraction is valid for the given GPU
 func (f *MI300XFractionalAllocator) ValidateFraction(deviceID string, fraction float64) error {
-	validFractions := f.GetValidFractions(deviceID)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.validateFraction(deviceID, fraction)
+}
+
+// validateFraction validates a fraction for a GPU. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) validateFraction(deviceID string, fraction float64) error {
+	validFractions, err := f.getValidFractions(deviceID)
+	if err != nil {
+		return err
+	}
 
 	for _, valid := range validFractions {
 		if math.Abs(fraction-valid) < 0.001 { // Allow small floating point differences
@@ -158,6 +347,14 @@ func (f *MI300XFractionalAllocator) ValidateFraction(deviceID string, fraction f
 
 // CanAllocate checks if a fractional allocation is possible for MI300X
 func (f *MI300XFractionalAllocator) CanAllocate(deviceID string, request *types.GPURequest) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.canAllocate(deviceID, request)
+}
+
+// canAllocate checks if an allocation is possible. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) canAllocate(deviceID string, request *types.GPURequest) (bool, error) {
 	if request == nil {
 		return false, fmt.Errorf("GPU request cannot be nil")
 	}
@@ -168,22 +365,29 @@ func (f *MI300XFractionalAllocator) CanAllocate(deviceID string, request *types.
 
 	// Check if GPU is registered
 	if _, exists := f.gpuCapacity[deviceID]; !exists {
-		return false, fmt.Errorf("GPU %s is not registered", deviceID)
+		return false, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
 	}
 
 	// Validate fraction for MI300X partitioning
-	if err := f.ValidateFraction(deviceID, request.Fraction); err != nil {
+	if err := f.validateFraction(deviceID, request.Fraction); err != nil {
 		return false, err
 	}
 
 	config := f.partitionConfig[deviceID]
 
+	// SR-IOV exposes each XCD as a virtual function, which needs the XCDs partitioned
+	if request.IsolationType == types.GPUIsolationSRIOV && config.ComputeMode != MI300XPartitionModeCPX {
+		return false, fmt.Errorf("SR-IOV isolation requires CPX mode, GPU %s is in %s mode", deviceID, config.ComputeMode)
+	}
+
 	// Check allocation based on partitioning mode
 	switch config.ComputeMode {
 	case MI300XPartitionModeSPX:
 		return f.canAllocateSPX(deviceID, request)
 	case MI300XPartitionModeCPX:
 		return f.canAllocateCPX(deviceID, request)
+	case MI300XPartitionModeTPX:
+		return f.canAllocateTPX(deviceID, request)
 	default:
 		return false, fmt.Errorf("unknown compute mode: %s", config.ComputeMode)
 	}
@@ -237,12 +441,184 @@ func (f *MI300XFractionalAllocator) canAllocateCPX(deviceID string, request *typ
 		}
 	}
 
+	// Under NPS4 the XCDs must also fit in the memory of their quadrants
+	if _, err := f.selectXCDs(deviceID, xcdsNeeded, request.MemoryRequest); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// selectXCDs picks free XCDs for a CPX allocation. Under NPS1 the lowest-index free
+// XCDs are used. Under NPS4 the requested memory is split evenly across the chosen
+// XCDs and each quadrant only receives as many XCDs as its free memory can back.
+// With contiguous placement preferred, a free run of XCDs is used if there is one.
+// Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) selectXCDs(deviceID string, xcdsNeeded int, memoryRequest int64) ([]int, error) {
+	config := f.partitionConfig[deviceID]
+	nps4 := config.MemoryMode == MI300XMemoryModeNPS4
+	memoryPerXCD := memoryRequest * 1024 * 1024 / int64(xcdsNeeded)
+
+	if f.preferContiguousXCDs {
+		if selected, found := f.selectContiguousXCDs(deviceID, xcdsNeeded, memoryPerXCD); found {
+			return selected, nil
+		}
+	}
+
+	selected := make([]int, 0, xcdsNeeded)
+	for quadrant := 0; quadrant < mi300xQuadrantCount && len(selected) < xcdsNeeded; quadrant++ {
+		availableMemory := f.getAvailableQuadrantMemory(deviceID, quadrant)
+
+		for xcdIndex := 0; xcdIndex < 8 && len(selected) < xcdsNeeded; xcdIndex++ {
+			if xcdQuadrant(xcdIndex) != quadrant || f.xcdAllocations[deviceID][xcdIndex] != nil {
+				continue
+			}
+			if nps4 {
+				if memoryPerXCD > availableMemory {
+					break
+				}
+				availableMemory -= memoryPerXCD
+			}
+			selected = append(selected, xcdIndex)
+		}
+	}
+
+	if len(selected) < xcdsNeeded {
+		return nil, fmt.Errorf("insufficient quadrant memory: no %d free XCDs can back %d MiB under NPS4",
+			xcdsNeeded, memoryRequest)
+	}
+
+	return selected, nil
+}
+
+// selectContiguousXCDs returns the lowest-index run of free XCDs of the given
+// length whose quadrants can back memoryPerXCD bytes on each XCD under NPS4.
+// Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) selectContiguousXCDs(deviceID string, xcdsNeeded int, memoryPerXCD int64) ([]int, bool) {
+	for start := 0; start+xcdsNeeded <= 8; start++ {
+		selected := make([]int, 0, xcdsNeeded)
+		for xcdIndex := start; xcdIndex < start+xcdsNeeded; xcdIndex++ {
+			if f.xcdAllocations[deviceID][xcdIndex] != nil {
+				break
+			}
+			selected = append(selected, xcdIndex)
+		}
+
+		if len(selected) == xcdsNeeded && f.quadrantsCanBack(deviceID, selected, memoryPerXCD) {
+			return selected, true
+		}
+	}
+
+	return nil, false
+}
+
+// quadrantsCanBack reports whether the free memory of each quadrant covers
+// memoryPerXCD bytes for every one of the XCDs in it. It always holds outside
+// NPS4. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) quadrantsCanBack(deviceID string, xcds []int, memoryPerXCD int64) bool {
+	if f.partitionConfig[deviceID].MemoryMode != MI300XMemoryModeNPS4 {
+		return true
+	}
+
+	needed := make([]int64, mi300xQuadrantCount)
+	for _, xcdIndex := range xcds {
+		needed[xcdQuadrant(xcdIndex)] += memoryPerXCD
+	}
+	for quadrant, memory := range needed {
+		if memory > f.getAvailableQuadrantMemory(deviceID, quadrant) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// canAllocateTPX checks allocation for TPX mode (XCDs grouped into partitions)
+func (f *MI300XFractionalAllocator) canAllocateTPX(deviceID string, request *types.GPURequest) (bool, error) {
+	if _, found := f.findFreeTPXGroup(deviceID, request.Fraction); !found {
+		return false, fmt.Errorf("no free TPX partition of %d XCDs on GPU %s",
+			int(math.Round(request.Fraction*8.0)), deviceID)
+	}
+
+	// Check memory capacity
+	if request.MemoryRequest > 0 {
+		availableMemory := f.getAvailableMemory(deviceID)
+		if request.MemoryRequest*1024*1024 > availableMemory {
+			return false, fmt.Errorf("insufficient memory: requested %d MiB, available %d bytes",
+				request.MemoryRequest, availableMemory)
+		}
+	}
+
 	return true, nil
 }
 
+// tpxGroupXCDs returns the first XCD index and XCD count of a TPX partition group
+func tpxGroupXCDs(config *MI300XPartitionConfig, group int) (int, int) {
+	start := 0
+	for i := 0; i < group; i++ {
+		start += config.PartitionGroups[i]
+	}
+	return start, config.PartitionGroups[group]
+}
+
+// findFreeTPXGroup returns the first unallocated TPX partition group whose size
+// matches the fraction. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) findFreeTPXGroup(deviceID string, fraction float64) (int, bool) {
+	config := f.partitionConfig[deviceID]
+	xcdsNeeded := int(math.Round(fraction * 8.0))
+
+	for group := range config.PartitionGroups {
+		start, size := tpxGroupXCDs(config, group)
+		if size != xcdsNeeded {
+			continue
+		}
+
+		free := true
+		for xcdIndex := start; xcdIndex < start+size; xcdIndex++ {
+			if f.xcdAllocations[deviceID][xcdIndex] != nil {
+				free = false
+				break
+			}
+		}
+		if free {
+			return group, true
+		}
+	}
+
+	return 0, false
+}
+
 // Allocate performs a fractional allocation for MI300X
 func (f *MI300XFractionalAllocator) Allocate(deviceID string, request *types.AllocationRequest) (*types.GPUAllocation, error) {
-	canAllocate, err := f.CanAllocate(deviceID, request.GPURequest)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	allocation, err := f.allocate(deviceID, request)
+	f.recordOutcome(deviceID, request, allocation, err)
+	return allocation, err
+}
+
+// SetEventLog makes the allocator record its allocations, failed requests,
+// releases and expiries in log. A nil log stops recording.
+func (f *MI300XFractionalAllocator) SetEventLog(log *AllocationEventLog) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = log
+}
+
+// recordOutcome records an allocation or why a request failed. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) recordOutcome(deviceID string, request *types.AllocationRequest, allocation *types.GPUAllocation, err error) {
+	if err != nil {
+		f.events.Record(requestEvent(types.AllocationEventTypeFailed, request, deviceID, err.Error()))
+		return
+	}
+	f.events.Record(allocationEvent(types.AllocationEventTypeAllocated, allocation, "allocation placed"))
+}
+
+// allocate performs a fractional allocation for MI300X. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) allocate(deviceID string, request *types.AllocationRequest) (*types.GPUAllocation, error) {
+	canAllocate, err := f.canAllocate(deviceID, request.GPURequest)
 	if err != nil {
 		return nil, err
 	}
@@ -261,6 +637,9 @@ func (f *MI300XFractionalAllocator) Allocate(deviceID string, request *types.All
 		PodName:       request.PodName,
 		Namespace:     request.Namespace,
 		ContainerName: request.ContainerName,
+		TenantID:      request.GPURequest.TenantID,
+		ReservationID: request.ReservationID,
+		Priority:      request.GPURequest.Priority,
 		Status:        types.GPUAllocationStatusActive,
 		CreatedAt:     time.Now().Unix(),
 		ExpiresAt:     0, // No expiration by default
@@ -271,32 +650,93 @@ func (f *MI300XFractionalAllocator) Allocate(deviceID string, request *types.All
 		allocation.ExpiresAt = request.ExpiresAt.Unix()
 	}
 
-	// Add allocation to the GPU
-	f.allocations[deviceID] = append(f.allocations[deviceID], allocation)
-
-	// Handle XCD allocation for CPX mode
+	// Handle XCD allocation for CPX and TPX modes. Without XCDs a CPX allocation
+	// would escape NPS4 quadrant memory accounting, so it fails instead.
 	config := f.partitionConfig[deviceID]
-	if config.ComputeMode == MI300XPartitionModeCPX {
-		f.allocateXCDs(deviceID, allocation)
+	switch config.ComputeMode {
+	case MI300XPartitionModeCPX:
+		if err := f.allocateXCDs(deviceID, allocation); err != nil {
+			return nil, err
+		}
+	case MI300XPartitionModeTPX:
+		f.allocateTPXGroup(deviceID, allocation)
 	}
 
+	// Add allocation to the GPU
+	f.allocations[deviceID] = append(f.allocations[deviceID], allocation)
+
 	return allocation, nil
 }
 
-// allocateXCDs allocates XCDs for CPX mode
-func (f *MI300XFractionalAllocator) allocateXCDs(deviceID string, allocation *types.GPUAllocation) {
+// allocateXCDs allocates XCDs for CPX mode, leaving the GPU untouched if not
+// enough can be found. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) allocateXCDs(deviceID string, allocation *types.GPUAllocation) error {
 	xcdsNeeded := int(math.Ceil(allocation.Fraction * 8.0))
-	allocatedXCDs := 0
 
-	for xcdIndex := 0; xcdIndex < 8 && allocatedXCDs < xcdsNeeded; xcdIndex++ {
-		if f.xcdAllocations[deviceID][xcdIndex] == nil {
-			f.xcdAllocations[deviceID][xcdIndex] = allocation
-			allocatedXCDs++
+	xcds, err := f.selectXCDs(deviceID, xcdsNeeded, allocation.MemoryRequest)
+	if err != nil {
+		return fmt.Errorf("cannot assign XCDs on GPU %s: %w", deviceID, err)
+	}
+
+	f.assignXCDs(deviceID, allocation, xcds)
+	return nil
+}
+
+// assignXCDs gives an allocation the given XCDs. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) assignXCDs(deviceID string, allocation *types.GPUAllocation, xcds []int) {
+	for _, xcdIndex := range xcds {
+		f.xcdAllocations[deviceID][xcdIndex] = allocation
+	}
+	allocation.XCDIndices = xcds
+	f.assignVirtualFunctions(deviceID, allocation)
+}
+
+// assignVirtualFunctions gives an SR-IOV allocation one virtual function per XCD
+// it holds, each limited to an even share of the allocation's memory, or of the
+// XCD's share of the GPU if no memory was requested. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) assignVirtualFunctions(deviceID string, allocation *types.GPUAllocation) {
+	if allocation.IsolationType != types.GPUIsolationSRIOV || len(allocation.XCDIndices) == 0 {
+		return
+	}
+
+	memoryPerXCD := f.gpuMemoryCapacity[deviceID] / 8
+	if allocation.MemoryRequest > 0 {
+		memoryPerXCD = allocation.MemoryRequest * 1024 * 1024 / int64(len(allocation.XCDIndices))
+	}
+	nps4 := f.partitionConfig[deviceID].MemoryMode == MI300XMemoryModeNPS4
+
+	allocation.VirtualFunctions = make([]types.SRIOVVirtualFunction, 0, len(allocation.XCDIndices))
+	for _, xcdIndex := range allocation.XCDIndices {
+		quadrant := -1
+		if nps4 {
+			quadrant = xcdQuadrant(xcdIndex)
 		}
+		allocation.VirtualFunctions = append(allocation.VirtualFunctions, types.SRIOVVirtualFunction{
+			Index:          xcdIndex,
+			XCDIndex:       xcdIndex,
+			MemoryQuadrant: quadrant,
+			MemoryBytes:    memoryPerXCD,
+		})
+	}
+}
+
+// allocateTPXGroup assigns every XCD of a free TPX partition group to the
+// allocation. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) allocateTPXGroup(deviceID string, allocation *types.GPUAllocation) {
+	group, found := f.findFreeTPXGroup(deviceID, allocation.Fraction)
+	if !found {
+		return
+	}
+
+	start, size := tpxGroupXCDs(f.partitionConfig[deviceID], group)
+	allocation.XCDIndices = make([]int, 0, size)
+	for xcdIndex := start; xcdIndex < start+size; xcdIndex++ {
+		f.xcdAllocations[deviceID][xcdIndex] = allocation
+		allocation.XCDIndices = append(allocation.XCDIndices, xcdIndex)
 	}
 }
 
-// getAvailableXCDs returns the number of available XCDs for CPX mode
+// getAvailableXCDs returns the number of available XCDs for CPX mode. Callers must hold f.mu.
 func (f *MI300XFractionalAllocator) getAvailableXCDs(deviceID string) int {
 	allocatedXCDs := 0
 	for xcdIndex := 0; xcdIndex < 8; xcdIndex++ {
@@ -309,36 +749,185 @@ func (f *MI300XFractionalAllocator) getAvailableXCDs(deviceID string) int {
 
 // Release releases a fractional allocation for MI300X
 func (f *MI300XFractionalAllocator) Release(allocationID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	allocation, err := f.release(allocationID)
+	if err != nil {
+		return err
+	}
+
+	f.events.Record(allocationEvent(types.AllocationEventTypeReleased, allocation, "allocation released"))
+	return nil
+}
+
+// release releases a fractional allocation and its XCDs, and returns it.
+// Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) release(allocationID string) (*types.GPUAllocation, error) {
 	for deviceID, allocations := range f.allocations {
 		for i, allocation := range allocations {
 			if allocation.ID == allocationID {
 				// Remove allocation from slice
 				f.allocations[deviceID] = append(allocations[:i], allocations[i+1:]...)
 
-				// Release XCDs for CPX mode
+				// Release XCDs for CPX and TPX modes
 				config := f.partitionConfig[deviceID]
-				if config.ComputeMode == MI300XPartitionModeCPX {
+				if config.usesXCDAllocations() {
 					f.releaseXCDs(deviceID, allocation)
 				}
 
-				return nil
+				return allocation, nil
 			}
 		}
 	}
 
-	return fmt.Errorf("allocation %s not found", allocationID)
+	return nil, fmt.Errorf("%w: %s", ErrAllocationNotFound, allocationID)
 }
 
-// releaseXCDs releases XCDs for CPX mode
+// MigrateAllocation moves an active allocation to another GPU, keeping its ID and
+// metadata. The target must be able to fit the allocation under its own partition
+// mode; in CPX and TPX modes XCDs are re-assigned on the target.
+func (f *MI300XFractionalAllocator) MigrateAllocation(allocationID, targetDeviceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var sourceDeviceID string
+	var index int
+	var allocation *types.GPUAllocation
+	for deviceID, allocations := range f.allocations {
+		for i, candidate := range allocations {
+			if candidate.ID == allocationID {
+				sourceDeviceID, index, allocation = deviceID, i, candidate
+			}
+		}
+	}
+
+	if allocation == nil {
+		return fmt.Errorf("%w: %s", ErrAllocationNotFound, allocationID)
+	}
+
+	if allocation.Status != types.GPUAllocationStatusActive {
+		return fmt.Errorf("cannot migrate allocation %s in status %s", allocationID, allocation.Status)
+	}
+
+	if sourceDeviceID == targetDeviceID {
+		return fmt.Errorf("allocation %s is already on GPU %s", allocationID, targetDeviceID)
+	}
+
+	if _, err := f.canAllocate(targetDeviceID, migrationRequest(allocation)); err != nil {
+		return fmt.Errorf("cannot migrate allocation %s to GPU %s: %w", allocationID, targetDeviceID, err)
+	}
+
+	// Pick the target's XCDs before moving anything, so a failure leaves the
+	// allocation where it is
+	var targetXCDs []int
+	if f.partitionConfig[targetDeviceID].ComputeMode == MI300XPartitionModeCPX {
+		xcds, err := f.selectXCDs(targetDeviceID, int(math.Ceil(allocation.Fraction*8.0)), allocation.MemoryRequest)
+		if err != nil {
+			return fmt.Errorf("cannot migrate allocation %s to GPU %s: %w", allocationID, targetDeviceID, err)
+		}
+		targetXCDs = xcds
+	}
+
+	sourceAllocations := f.allocations[sourceDeviceID]
+	f.allocations[sourceDeviceID] = append(sourceAllocations[:index], sourceAllocations[index+1:]...)
+	if config := f.partitionConfig[sourceDeviceID]; config != nil && config.usesXCDAllocations() {
+		f.releaseXCDs(sourceDeviceID, allocation)
+	}
+
+	allocation.DeviceID = targetDeviceID
+	f.allocations[targetDeviceID] = append(f.allocations[targetDeviceID], allocation)
+
+	switch f.partitionConfig[targetDeviceID].ComputeMode {
+	case MI300XPartitionModeCPX:
+		f.assignXCDs(targetDeviceID, allocation, targetXCDs)
+	case MI300XPartitionModeTPX:
+		f.allocateTPXGroup(targetDeviceID, allocation)
+	}
+
+	return nil
+}
+
+// releaseXCDs releases XCDs for CPX mode. Callers must hold f.mu.
 func (f *MI300XFractionalAllocator) releaseXCDs(deviceID string, allocation *types.GPUAllocation) {
 	for xcdIndex := 0; xcdIndex < 8; xcdIndex++ {
-		if f.xcdAllocations[deviceID][xcdIndex] == nil {
+		if f.xcdAllocations[deviceID][xcdIndex] == allocation {
+			delete(f.xcdAllocations[deviceID], xcdIndex)
+		}
+	}
+	allocation.XCDIndices = nil
+	allocation.VirtualFunctions = nil
+}
+
+// Compact moves the CPX allocations on a GPU onto contiguous XCD ranges packed
+// from XCD 0, keeping their order and how many XCDs each holds. It returns the
+// allocations whose XCDs changed, whose workloads must be restarted with their
+// new XCDs. Compaction is refused, leaving the GPU unchanged, when the packed
+// layout would overflow the memory of an NPS4 quadrant.
+func (f *MI300XFractionalAllocator) Compact(deviceID string) ([]*types.GPUAllocation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	config, exists := f.partitionConfig[deviceID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
+	if config.ComputeMode != MI300XPartitionModeCPX {
+		return nil, fmt.Errorf("GPU %s is in %s mode, only CPX allocations can be compacted", deviceID, config.ComputeMode)
+	}
+
+	// Order allocations by their lowest XCD so that packing moves as few as possible
+	var allocations []*types.GPUAllocation
+	xcdCounts := make(map[string]int)
+	for xcdIndex := 0; xcdIndex < 8; xcdIndex++ {
+		allocation := f.xcdAllocations[deviceID][xcdIndex]
+		if allocation == nil {
 			continue
 		}
-		if f.xcdAllocations[deviceID][xcdIndex].ID == allocation.ID {
-			delete(f.xcdAllocations[deviceID], xcdIndex)
+		if xcdCounts[allocation.ID] == 0 {
+			allocations = append(allocations, allocation)
+		}
+		xcdCounts[allocation.ID]++
+	}
+
+	compacted := make(map[int]*types.GPUAllocation)
+	ranges := make([][]int, len(allocations))
+	usedQuadrantMemory := make([]int64, mi300xQuadrantCount)
+	next := 0
+	for i, allocation := range allocations {
+		count := xcdCounts[allocation.ID]
+		memoryPerXCD := allocation.MemoryRequest * 1024 * 1024 / int64(count)
+		for xcdIndex := next; xcdIndex < next+count; xcdIndex++ {
+			compacted[xcdIndex] = allocation
+			ranges[i] = append(ranges[i], xcdIndex)
+			if allocation.Status == types.GPUAllocationStatusActive {
+				usedQuadrantMemory[xcdQuadrant(xcdIndex)] += memoryPerXCD
+			}
+		}
+		next += count
+	}
+
+	if config.MemoryMode == MI300XMemoryModeNPS4 {
+		quadrantMemory := f.gpuMemoryCapacity[deviceID] / mi300xQuadrantCount
+		for quadrant, used := range usedQuadrantMemory {
+			if used > quadrantMemory {
+				return nil, fmt.Errorf("cannot compact GPU %s: quadrant %d would need %d bytes, has %d",
+					deviceID, quadrant, used, quadrantMemory)
+			}
+		}
+	}
+
+	var moved []*types.GPUAllocation
+	for i, allocation := range allocations {
+		if !slices.Equal(allocation.XCDIndices, ranges[i]) {
+			moved = append(moved, allocation)
 		}
+		allocation.XCDIndices = ranges[i]
+		f.assignVirtualFunctions(deviceID, allocation)
 	}
+	f.xcdAllocations[deviceID] = compacted
+
+	return moved, nil
 }
 
 // GetAvailableMemory returns the available memory for a GPU
@@ -354,6 +943,60 @@ func (f *MI300XFractionalAllocator) getAvailableMemory(deviceID string) int64 {
 	return available
 }
 
+// GetAvailableQuadrantMemory returns the free memory in bytes of an NPS4 memory quadrant
+func (f *MI300XFractionalAllocator) GetAvailableQuadrantMemory(deviceID string, quadrant int) (int64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	config, exists := f.partitionConfig[deviceID]
+	if !exists {
+		return 0, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
+
+	if config.MemoryMode != MI300XMemoryModeNPS4 {
+		return 0, fmt.Errorf("GPU %s is in %s memory mode, not NPS4", deviceID, config.MemoryMode)
+	}
+
+	if quadrant < 0 || quadrant >= mi300xQuadrantCount {
+		return 0, fmt.Errorf("quadrant must be between 0 and %d, got %d", mi300xQuadrantCount-1, quadrant)
+	}
+
+	return f.getAvailableQuadrantMemory(deviceID, quadrant), nil
+}
+
+// getAvailableQuadrantMemory returns the free memory of a quadrant. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) getAvailableQuadrantMemory(deviceID string, quadrant int) int64 {
+	totalMemory := f.gpuMemoryCapacity[deviceID] / mi300xQuadrantCount
+	usedMemory := f.getUsedQuadrantMemory(deviceID)[quadrant]
+
+	available := totalMemory - usedMemory
+	if available < 0 {
+		available = 0
+	}
+
+	return available
+}
+
+// getUsedQuadrantMemory returns the memory used in each quadrant, splitting each
+// allocation's memory evenly across the XCDs it owns. Callers must hold f.mu.
+func (f *MI300XFractionalAllocator) getUsedQuadrantMemory(deviceID string) []int64 {
+	used := make([]int64, mi300xQuadrantCount)
+
+	xcdCounts := make(map[string]int64)
+	for _, allocation := range f.xcdAllocations[deviceID] {
+		xcdCounts[allocation.ID]++
+	}
+
+	for xcdIndex, allocation := range f.xcdAllocations[deviceID] {
+		if allocation.Status != types.GPUAllocationStatusActive {
+			continue
+		}
+		used[xcdQuadrant(xcdIndex)] += allocation.MemoryRequest * 1024 * 1024 / xcdCounts[allocation.ID]
+	}
+
+	return used
+}
+
 // GetUsedFraction returns the used fractional capacity for a GPU
 func (f *MI300XFractionalAllocator) getUsedFraction(deviceID string) float64 {
 	allocations := f.allocations[deviceID]
@@ -383,7 +1026,14 @@ func (f *MI300XFractionalAllocator) getUsedMemory(deviceID string) int64 {
 }
 
 // GetGPUUtilization returns the utilization statistics for a GPU
-func (f *MI300XFractionalAllocator) GetGPUUtilization(deviceID string) *GPUUtilizationStats {
+func (f *MI300XFractionalAllocator) GetGPUUtilization(deviceID string) (*GPUUtilizationStats, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, exists := f.gpuCapacity[deviceID]; !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
+
 	allocations := f.allocations[deviceID]
 
 	stats := &GPUUtilizationStats{
@@ -396,6 +1046,8 @@ func (f *MI300XFractionalAllocator) GetGPUUtilization(deviceID string) *GPUUtili
 		UtilizationRate:       0.0,
 		MemoryUtilizationRate: 0.0,
 	}
+	// Partitions are carved out of the hardware and cannot be oversubscribed
+	stats.OversubscriptionFactor = 1.0
 
 	// Count active allocations
 	for _, allocation := range allocations {
@@ -413,25 +1065,63 @@ func (f *MI300XFractionalAllocator) GetGPUUtilization(deviceID string) *GPUUtili
 		stats.MemoryUtilizationRate = float64(stats.UsedMemory) / float64(stats.TotalMemory)
 	}
 
-	return stats
+	if config := f.partitionConfig[deviceID]; config != nil && config.MemoryMode == MI300XMemoryModeNPS4 {
+		stats.QuadrantMemoryUsage = f.getUsedQuadrantMemory(deviceID)
+	}
+
+	return stats, nil
 }
 
 // GetPartitionConfig returns the partitioning configuration for a GPU
-func (f *MI300XFractionalAllocator) GetPartitionConfig(deviceID string) *MI300XPartitionConfig {
-	return f.partitionConfig[deviceID]
+func (f *MI300XFractionalAllocator) GetPartitionConfig(deviceID string) (*MI300XPartitionConfig, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, exists := f.gpuCapacity[deviceID]; !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
+
+	return f.partitionConfig[deviceID], nil
 }
 
-// GetXCDAllocations returns the XCD allocations for CPX mode
-func (f *MI300XFractionalAllocator) GetXCDAllocations(deviceID string) map[int]*types.GPUAllocation {
+// GetXCDAllocations returns the XCD allocations for CPX and TPX modes
+func (f *MI300XFractionalAllocator) GetXCDAllocations(deviceID string) (map[int]*types.GPUAllocation, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, exists := f.gpuCapacity[deviceID]; !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, deviceID)
+	}
+
 	xcdAllocs := make(map[int]*types.GPUAllocation)
 	for xcdIndex, allocation := range f.xcdAllocations[deviceID] {
 		xcdAllocs[xcdIndex] = allocation
 	}
-	return xcdAllocs
+	return xcdAllocs, nil
+}
+
+// GetAllocationXCDs returns the XCD indices an allocation holds in CPX and TPX
+// modes, in ascending order. It returns nil for an allocation that holds no
+// XCDs or is not tracked.
+func (f *MI300XFractionalAllocator) GetAllocationXCDs(allocationID string) []int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, allocations := range f.allocations {
+		for _, allocation := range allocations {
+			if allocation.ID == allocationID {
+				return slices.Clone(allocation.XCDIndices)
+			}
+		}
+	}
+	return nil
 }
 
 // CleanupExpiredAllocations removes expired allocations
 func (f *MI300XFractionalAllocator) CleanupExpiredAllocations() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	now := time.Now().Unix()
 
 	for deviceID, allocations := range f.allocations {
@@ -441,10 +1131,11 @@ func (f *MI300XFractionalAllocator) CleanupExpiredAllocations() {
 			if allocation.ExpiresAt > 0 && allocation.ExpiresAt <= now {
 				// Mark as expired
 				allocation.Status = types.GPUAllocationStatusExpired
+				f.events.Record(allocationEvent(types.AllocationEventTypeExpired, allocation, "allocation expired"))
 
-				// Release XCDs for CPX mode
+				// Release XCDs for CPX and TPX modes
 				config := f.partitionConfig[deviceID]
-				if config != nil && config.ComputeMode == MI300XPartitionModeCPX {
+				if config != nil && config.usesXCDAllocations() {
 					f.releaseXCDs(deviceID, allocation)
 				}
 			} else {
@@ -455,3 +1146,86 @@ func (f *MI300XFractionalAllocator) CleanupExpiredAllocations() {
 		f.allocations[deviceID] = validAllocations
 	}
 }
+
+// StartCleanup runs CleanupExpiredAllocations every interval in the background,
+// so expired allocations free their XCDs and memory without a manual call, until
+// ctx is cancelled
+func (f *MI300XFractionalAllocator) StartCleanup(ctx context.Context, interval time.Duration) error {
+	return startCleanup(ctx, interval, f.CleanupExpiredAllocations)
+}
+
+// WorkloadProfile describes a workload's resource needs for allocation recommendations
+type WorkloadProfile struct {
+	// DeviceID is the GPU the workload is targeting
+	DeviceID string `json:"deviceId"`
+	// MemoryRequired is the workload's memory footprint in MiB
+	MemoryRequired int64 `json:"memoryRequired"`
+	// ComputeIntensity is the share of a full GPU's compute the workload needs (0-1)
+	ComputeIntensity float64 `json:"computeIntensity"`
+}
+
+// RecommendAllocation proposes the smallest GPU request valid for the target GPU's
+// partition mode that covers the profile's memory and compute needs. In CPX mode each
+// XCD contributes 1/8 of the compute and, for sizing purposes, 1/8 of the memory.
+func (f *MI300XFractionalAllocator) RecommendAllocation(profile WorkloadProfile) (*types.GPURequest, error) {
+	if profile.MemoryRequired < 0 {
+		return nil, fmt.Errorf("memory required must be non-negative, got %d", profile.MemoryRequired)
+	}
+
+	if profile.ComputeIntensity < 0 || profile.ComputeIntensity > 1.0 {
+		return nil, fmt.Errorf("compute intensity must be between 0 and 1, got %f", profile.ComputeIntensity)
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, exists := f.gpuCapacity[profile.DeviceID]; !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotRegistered, profile.DeviceID)
+	}
+
+	totalMemory := f.gpuMemoryCapacity[profile.DeviceID]
+	if profile.MemoryRequired*1024*1024 > totalMemory {
+		return nil, fmt.Errorf("workload needs %d MiB but GPU %s has %d bytes",
+			profile.MemoryRequired, profile.DeviceID, totalMemory)
+	}
+
+	request := &types.GPURequest{
+		Fraction:      1.0,
+		MemoryRequest: profile.MemoryRequired,
+	}
+
+	config := f.partitionConfig[profile.DeviceID]
+	if config == nil || !config.usesXCDAllocations() {
+		// SPX mode only supports full GPU allocation
+		return request, nil
+	}
+
+	xcdsForCompute := int(math.Ceil(profile.ComputeIntensity * 8.0))
+
+	memoryPerXCD := totalMemory / 8
+	xcdsForMemory := 0
+	if memoryPerXCD > 0 {
+		xcdsForMemory = int(math.Ceil(float64(profile.MemoryRequired*1024*1024) / float64(memoryPerXCD)))
+	}
+
+	xcds := max(xcdsForCompute, xcdsForMemory, 1)
+
+	// TPX allocations take a whole partition group, so round up to the smallest group that fits
+	if config.ComputeMode == MI300XPartitionModeTPX {
+		groupXCDs := 0
+		for _, size := range config.PartitionGroups {
+			if size >= xcds && (groupXCDs == 0 || size < groupXCDs) {
+				groupXCDs = size
+			}
+		}
+		if groupXCDs == 0 {
+			return nil, fmt.Errorf("workload needs %d XCDs but the largest TPX partition on GPU %s is smaller",
+				xcds, profile.DeviceID)
+		}
+		xcds = groupXCDs
+	}
+
+	request.Fraction = float64(xcds) / 8.0
+
+	return request, nil
+}
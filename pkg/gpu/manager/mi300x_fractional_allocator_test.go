@@ -1,6 +1,12 @@
 package manager
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,7 +42,10 @@ func TestRegisterMI300XGPU(t *testing.T) {
 		t.Fatalf("Failed to register GPU: %v", err)
 	}
 
-	config := allocator.GetPartitionConfig("card0")
+	config, err := allocator.GetPartitionConfig("card0")
+	if err != nil {
+		t.Fatalf("Failed to get partition config: %v", err)
+	}
 	if config == nil {
 		t.Fatal("Expected partition config to be set")
 	}
@@ -68,7 +77,10 @@ func TestRegisterMI300XGPUWithConfig(t *testing.T) {
 		t.Fatalf("Failed to register GPU: %v", err)
 	}
 
-	retrievedConfig := allocator.GetPartitionConfig("card0")
+	retrievedConfig, err := allocator.GetPartitionConfig("card0")
+	if err != nil {
+		t.Fatalf("Failed to get partition config: %v", err)
+	}
 	if retrievedConfig.ComputeMode != MI300XPartitionModeCPX {
 		t.Errorf("Expected CPX mode, got %s", retrievedConfig.ComputeMode)
 	}
@@ -166,7 +178,10 @@ func TestGetValidFractions(t *testing.T) {
 		t.Fatalf("Failed to register GPU: %v", err)
 	}
 
-	spxFractions := allocator.GetValidFractions("card0")
+	spxFractions, err := allocator.GetValidFractions("card0")
+	if err != nil {
+		t.Fatalf("Failed to get valid fractions: %v", err)
+	}
 	expectedSPX := []float64{1.0}
 	if len(spxFractions) != len(expectedSPX) {
 		t.Errorf("Expected %d SPX fractions, got %d", len(expectedSPX), len(spxFractions))
@@ -186,7 +201,10 @@ func TestGetValidFractions(t *testing.T) {
 		t.Fatalf("Failed to register GPU: %v", err)
 	}
 
-	cpxFractions := allocator.GetValidFractions("card1")
+	cpxFractions, err := allocator.GetValidFractions("card1")
+	if err != nil {
+		t.Fatalf("Failed to get valid fractions: %v", err)
+	}
 	expectedCPX := []float64{0.125, 0.25, 0.375, 0.5, 0.625, 0.75, 0.875, 1.0}
 	if len(cpxFractions) != len(expectedCPX) {
 		t.Errorf("Expected %d CPX fractions, got %d", len(expectedCPX), len(cpxFractions))
@@ -393,7 +411,10 @@ func TestAllocateAndRelease(t *testing.T) {
 	}
 
 	// Check XCD allocations
-	xcdAllocs := allocator.GetXCDAllocations("card0")
+	xcdAllocs, err := allocator.GetXCDAllocations("card0")
+	if err != nil {
+		t.Fatalf("Failed to get XCD allocations: %v", err)
+	}
 	if len(xcdAllocs) != 2 {
 		t.Errorf("Expected 2 XCD allocations, got %d", len(xcdAllocs))
 	}
@@ -411,7 +432,10 @@ func TestAllocateAndRelease(t *testing.T) {
 	}
 
 	// Check that XCDs are released
-	xcdAllocs = allocator.GetXCDAllocations("card0")
+	xcdAllocs, err = allocator.GetXCDAllocations("card0")
+	if err != nil {
+		t.Fatalf("Failed to get XCD allocations: %v", err)
+	}
 	if len(xcdAllocs) != 0 {
 		t.Errorf("Expected 0 XCD allocations after release, got %d", len(xcdAllocs))
 	}
@@ -544,7 +568,10 @@ func TestGetGPUUtilization(t *testing.T) {
 	}
 
 	// Get utilization stats
-	stats := allocator.GetGPUUtilization("card0")
+	stats, err := allocator.GetGPUUtilization("card0")
+	if err != nil {
+		t.Fatalf("Failed to get GPU utilization: %v", err)
+	}
 
 	if stats.DeviceID != "card0" {
 		t.Errorf("Expected device ID 'card0', got %s", stats.DeviceID)
@@ -605,7 +632,10 @@ func TestCleanupExpiredAllocations(t *testing.T) {
 	}
 
 	// Check that allocation is active initially
-	stats := allocator.GetGPUUtilization("card0")
+	stats, err := allocator.GetGPUUtilization("card0")
+	if err != nil {
+		t.Fatalf("Failed to get GPU utilization: %v", err)
+	}
 	if stats.ActiveAllocations != 1 {
 		t.Errorf("Expected 1 active allocation initially, got %d", stats.ActiveAllocations)
 	}
@@ -614,7 +644,10 @@ func TestCleanupExpiredAllocations(t *testing.T) {
 	allocator.CleanupExpiredAllocations()
 
 	// Check that allocation is now expired
-	stats = allocator.GetGPUUtilization("card0")
+	stats, err = allocator.GetGPUUtilization("card0")
+	if err != nil {
+		t.Fatalf("Failed to get GPU utilization: %v", err)
+	}
 	if stats.ActiveAllocations != 0 {
 		t.Errorf("Expected 0 active allocations after cleanup, got %d", stats.ActiveAllocations)
 	}
@@ -625,3 +658,961 @@ func TestCleanupExpiredAllocations(t *testing.T) {
 		t.Errorf("Expected 8 available XCDs after cleanup, got %d", availableXCDs)
 	}
 }
+
+func TestUnregisteredDevice(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+
+	request := &types.AllocationRequest{
+		ID: "test-allocation",
+		GPURequest: &types.GPURequest{
+			Fraction: 1.0,
+			Priority: 5,
+		},
+		PodName:       "test-pod",
+		Namespace:     "default",
+		ContainerName: "test-container",
+	}
+
+	checks := map[string]func() error{
+		"GetValidFractions": func() error {
+			_, err := allocator.GetValidFractions("unknown")
+			return err
+		},
+		"ValidateFraction": func() error {
+			return allocator.ValidateFraction("unknown", 1.0)
+		},
+		"CanAllocate": func() error {
+			_, err := allocator.CanAllocate("unknown", request.GPURequest)
+			return err
+		},
+		"Allocate": func() error {
+			_, err := allocator.Allocate("unknown", request)
+			return err
+		},
+		"GetGPUUtilization": func() error {
+			_, err := allocator.GetGPUUtilization("unknown")
+			return err
+		},
+		"GetPartitionConfig": func() error {
+			_, err := allocator.GetPartitionConfig("unknown")
+			return err
+		},
+		"GetXCDAllocations": func() error {
+			_, err := allocator.GetXCDAllocations("unknown")
+			return err
+		},
+	}
+
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			if err := check(); !errors.Is(err, ErrDeviceNotRegistered) {
+				t.Errorf("Expected ErrDeviceNotRegistered, got %v", err)
+			}
+		})
+	}
+
+	// A registered GPU without a partition config still reports full allocation only
+	if err := allocator.RegisterMI300XGPU("card0", 8*1024*1024*1024, nil); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+	delete(allocator.partitionConfig, "card0")
+
+	fractions, err := allocator.GetValidFractions("card0")
+	if err != nil {
+		t.Fatalf("Expected no error for registered GPU, got %v", err)
+	}
+
+	if len(fractions) != 1 || fractions[0] != 1.0 {
+		t.Errorf("Expected valid fractions [1.0], got %v", fractions)
+	}
+}
+
+func TestMI300XConcurrentAllocateRelease(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+
+	cpxConfig := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionModeCPX,
+		MemoryMode:  MI300XMemoryModeNPS4,
+		XCDCount:    8,
+	}
+	if err := allocator.RegisterMI300XGPU("card0", 192*1024*1024*1024, cpxConfig); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+
+	const workers = 32
+	const iterations = 50
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < iterations; i++ {
+				request := &types.AllocationRequest{
+					ID: fmt.Sprintf("alloc-%d-%d", w, i),
+					GPURequest: &types.GPURequest{
+						Fraction:      0.125,
+						MemoryRequest: 1024,
+						Priority:      5,
+					},
+					PodName:       "test-pod",
+					Namespace:     "default",
+					ContainerName: "test-container",
+				}
+
+				// Allocation fails whenever all XCDs are busy, which is expected under contention
+				if _, err := allocator.Allocate("card0", request); err == nil {
+					if _, err := allocator.GetXCDAllocations("card0"); err != nil {
+						t.Errorf("Failed to get XCD allocations: %v", err)
+					}
+					if err := allocator.Release(request.ID); err != nil {
+						t.Errorf("Failed to release %s: %v", request.ID, err)
+					}
+				}
+
+				if _, err := allocator.GetGPUUtilization("card0"); err != nil {
+					t.Errorf("Failed to get GPU utilization: %v", err)
+				}
+				allocator.CleanupExpiredAllocations()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	stats, err := allocator.GetGPUUtilization("card0")
+	if err != nil {
+		t.Fatalf("Failed to get GPU utilization: %v", err)
+	}
+
+	if stats.ActiveAllocations != 0 {
+		t.Errorf("Expected 0 active allocations after all releases, got %d", stats.ActiveAllocations)
+	}
+
+	if availableXCDs := allocator.getAvailableXCDs("card0"); availableXCDs != 8 {
+		t.Errorf("Expected 8 available XCDs after all releases, got %d", availableXCDs)
+	}
+}
+
+func TestRecommendAllocation(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+
+	if err := allocator.RegisterMI300XGPU("spx0", 192*1024*1024*1024, nil); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+
+	cpxConfig := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionModeCPX,
+		MemoryMode:  MI300XMemoryModeNPS4,
+		XCDCount:    8,
+	}
+	if err := allocator.RegisterMI300XGPU("cpx0", 192*1024*1024*1024, cpxConfig); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		profile          WorkloadProfile
+		expectedFraction float64
+		expectedMemory   int64
+		wantErr          bool
+	}{
+		{
+			name:             "small inference on SPX gets full GPU",
+			profile:          WorkloadProfile{DeviceID: "spx0", MemoryRequired: 8192, ComputeIntensity: 0.1},
+			expectedFraction: 1.0,
+			expectedMemory:   8192,
+		},
+		{
+			name:             "small inference on CPX gets one XCD",
+			profile:          WorkloadProfile{DeviceID: "cpx0", MemoryRequired: 8192, ComputeIntensity: 0.1},
+			expectedFraction: 0.125,
+			expectedMemory:   8192,
+		},
+		{
+			name:             "compute heavy workload on CPX",
+			profile:          WorkloadProfile{DeviceID: "cpx0", MemoryRequired: 8192, ComputeIntensity: 0.6},
+			expectedFraction: 0.625,
+			expectedMemory:   8192,
+		},
+		{
+			name:             "memory heavy workload on CPX",
+			profile:          WorkloadProfile{DeviceID: "cpx0", MemoryRequired: 96 * 1024, ComputeIntensity: 0.1},
+			expectedFraction: 0.5,
+			expectedMemory:   96 * 1024,
+		},
+		{
+			name:    "workload larger than the GPU",
+			profile: WorkloadProfile{DeviceID: "cpx0", MemoryRequired: 256 * 1024, ComputeIntensity: 0.1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid compute intensity",
+			profile: WorkloadProfile{DeviceID: "cpx0", MemoryRequired: 1024, ComputeIntensity: 1.5},
+			wantErr: true,
+		},
+		{
+			name:    "unregistered GPU",
+			profile: WorkloadProfile{DeviceID: "unknown", MemoryRequired: 1024, ComputeIntensity: 0.5},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := allocator.RecommendAllocation(tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RecommendAllocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if request.Fraction != tt.expectedFraction {
+				t.Errorf("Expected fraction %f, got %f", tt.expectedFraction, request.Fraction)
+			}
+
+			if request.MemoryRequest != tt.expectedMemory {
+				t.Errorf("Expected memory request %d, got %d", tt.expectedMemory, request.MemoryRequest)
+			}
+
+			// The recommendation must be accepted by the allocator as-is
+			if err := allocator.ValidateFraction(tt.profile.DeviceID, request.Fraction); err != nil {
+				t.Errorf("Recommended fraction is not valid: %v", err)
+			}
+		})
+	}
+}
+
+func TestTPXPartitionConfigValidation(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+
+	tests := []struct {
+		name    string
+		groups  []int
+		wantErr bool
+	}{
+		{name: "default groups", groups: nil, wantErr: false},
+		{name: "custom groups", groups: []int{4, 2, 2}, wantErr: false},
+		{name: "groups not summing to 8", groups: []int{3, 3, 3}, wantErr: true},
+		{name: "empty group", groups: []int{4, 4, 0}, wantErr: true},
+		{name: "wrong group count", groups: []int{4, 4}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &MI300XPartitionConfig{
+				ComputeMode:     MI300XPartitionModeTPX,
+				MemoryMode:      MI300XMemoryModeNPS1,
+				XCDCount:        8,
+				PartitionGroups: tt.groups,
+			}
+			err := allocator.RegisterMI300XGPU(fmt.Sprintf("card%d", i), 8*1024*1024*1024, config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterMI300XGPU() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Partition groups are rejected outside TPX mode
+	cpxConfig := &MI300XPartitionConfig{
+		ComputeMode:     MI300XPartitionModeCPX,
+		MemoryMode:      MI300XMemoryModeNPS1,
+		XCDCount:        8,
+		PartitionGroups: []int{3, 3, 2},
+	}
+	if err := allocator.validatePartitionConfig(cpxConfig); err == nil {
+		t.Error("Expected error for partition groups in CPX mode")
+	}
+}
+
+func TestTPXAllocation(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+
+	tpxConfig := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionModeTPX,
+		MemoryMode:  MI300XMemoryModeNPS1,
+		XCDCount:    8,
+	}
+	if err := allocator.RegisterMI300XGPU("card0", 192*1024*1024*1024, tpxConfig); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+
+	fractions, err := allocator.GetValidFractions("card0")
+	if err != nil {
+		t.Fatalf("Failed to get valid fractions: %v", err)
+	}
+
+	expectedFractions := []float64{0.25, 0.375}
+	if len(fractions) != len(expectedFractions) || fractions[0] != expectedFractions[0] || fractions[1] != expectedFractions[1] {
+		t.Errorf("Expected TPX fractions %v, got %v", expectedFractions, fractions)
+	}
+
+	newRequest := func(id string, fraction float64) *types.AllocationRequest {
+		return &types.AllocationRequest{
+			ID: id,
+			GPURequest: &types.GPURequest{
+				Fraction:      fraction,
+				MemoryRequest: 1024,
+				Priority:      5,
+			},
+			PodName:       "test-pod",
+			Namespace:     "default",
+			ContainerName: "test-container",
+		}
+	}
+
+	// Both 3-XCD partitions and the 2-XCD partition can be allocated
+	for _, request := range []*types.AllocationRequest{
+		newRequest("tpx-1", 0.375),
+		newRequest("tpx-2", 0.375),
+		newRequest("tpx-3", 0.25),
+	} {
+		if _, err := allocator.Allocate("card0", request); err != nil {
+			t.Fatalf("Failed to allocate %s: %v", request.ID, err)
+		}
+	}
+
+	xcdAllocs, err := allocator.GetXCDAllocations("card0")
+	if err != nil {
+		t.Fatalf("Failed to get XCD allocations: %v", err)
+	}
+
+	// XCDs 0-2, 3-5 and 6-7 belong to the three partition groups
+	expectedOwners := []string{"tpx-1", "tpx-1", "tpx-1", "tpx-2", "tpx-2", "tpx-2", "tpx-3", "tpx-3"}
+	for xcdIndex, owner := range expectedOwners {
+		if xcdAllocs[xcdIndex] == nil || xcdAllocs[xcdIndex].ID != owner {
+			t.Errorf("Expected XCD %d to belong to %s, got %v", xcdIndex, owner, xcdAllocs[xcdIndex])
+		}
+	}
+
+	// Every partition is now taken
+	if _, err := allocator.Allocate("card0", newRequest("tpx-4", 0.25)); err == nil {
+		t.Error("Expected allocation to fail once all TPX partitions are allocated")
+	}
+
+	// Releasing a 3-XCD partition frees it for a new 3-XCD allocation only
+	if err := allocator.Release("tpx-2"); err != nil {
+		t.Fatalf("Failed to release allocation: %v", err)
+	}
+
+	if _, err := allocator.Allocate("card0", newRequest("tpx-5", 0.25)); err == nil {
+		t.Error("Expected 2-XCD allocation to fail when only a 3-XCD partition is free")
+	}
+
+	if _, err := allocator.Allocate("card0", newRequest("tpx-6", 0.375)); err != nil {
+		t.Errorf("Expected 3-XCD allocation to reuse the released partition, got %v", err)
+	}
+}
+
+func TestNPS4QuadrantMemoryLimits(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+
+	cpxConfig := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionModeCPX,
+		MemoryMode:  MI300XMemoryModeNPS4,
+		XCDCount:    8,
+	}
+	// 192GB split into four 48GB quadrants, two XCDs per quadrant
+	if err := allocator.RegisterMI300XGPU("card0", 192*1024*1024*1024, cpxConfig); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+
+	newRequest := func(id string, memoryMiB int64) *types.AllocationRequest {
+		return &types.AllocationRequest{
+			ID: id,
+			GPURequest: &types.GPURequest{
+				Fraction:      0.125, // 1 XCD
+				MemoryRequest: memoryMiB,
+				Priority:      5,
+			},
+			PodName:       "test-pod",
+			Namespace:     "default",
+			ContainerName: "test-container",
+		}
+	}
+
+	// Fill quadrant 0 from XCD 0
+	if _, err := allocator.Allocate("card0", newRequest("fill-q0", 48*1024)); err != nil {
+		t.Fatalf("Failed to fill quadrant 0: %v", err)
+	}
+
+	available, err := allocator.GetAvailableQuadrantMemory("card0", 0)
+	if err != nil {
+		t.Fatalf("Failed to get quadrant memory: %v", err)
+	}
+	if available != 0 {
+		t.Errorf("Expected quadrant 0 to be full, got %d bytes available", available)
+	}
+
+	// XCD 1 is free but sits in the full quadrant, so it cannot back the request
+	xcds, err := allocator.selectXCDs("card0", 1, 8*1024)
+	if err != nil {
+		t.Fatalf("Expected a free XCD in another quadrant, got %v", err)
+	}
+	if xcdQuadrant(xcds[0]) == 0 {
+		t.Errorf("Expected XCD outside quadrant 0, got XCD %d", xcds[0])
+	}
+
+	// The same request lands in quadrant 1 instead
+	if _, err := allocator.Allocate("card0", newRequest("small", 8*1024)); err != nil {
+		t.Fatalf("Expected allocation in another quadrant to succeed, got %v", err)
+	}
+
+	xcdAllocs, err := allocator.GetXCDAllocations("card0")
+	if err != nil {
+		t.Fatalf("Failed to get XCD allocations: %v", err)
+	}
+	if xcdAllocs[1] != nil {
+		t.Errorf("Expected XCD 1 in the full quadrant to stay free, got %s", xcdAllocs[1].ID)
+	}
+	if xcdAllocs[2] == nil || xcdAllocs[2].ID != "small" {
+		t.Errorf("Expected XCD 2 to hold the small allocation, got %v", xcdAllocs[2])
+	}
+
+	// A request that no quadrant can back fails even though the GPU has memory overall
+	if _, err := allocator.Allocate("card0", newRequest("too-big", 49*1024)); err == nil {
+		t.Error("Expected allocation larger than any quadrant to fail")
+	}
+
+	stats, err := allocator.GetGPUUtilization("card0")
+	if err != nil {
+		t.Fatalf("Failed to get GPU utilization: %v", err)
+	}
+
+	expectedUsage := []int64{48 * 1024 * 1024 * 1024, 8 * 1024 * 1024 * 1024, 0, 0}
+	if len(stats.QuadrantMemoryUsage) != len(expectedUsage) {
+		t.Fatalf("Expected %d quadrants, got %d", len(expectedUsage), len(stats.QuadrantMemoryUsage))
+	}
+	for quadrant, expected := range expectedUsage {
+		if stats.QuadrantMemoryUsage[quadrant] != expected {
+			t.Errorf("Expected quadrant %d usage %d, got %d", quadrant, expected, stats.QuadrantMemoryUsage[quadrant])
+		}
+	}
+}
+
+func TestMI300XAllocateXCDsFailure(t *testing.T) {
+	// Four 48GiB quadrants, so no XCD can back 50GiB of its own
+	allocator := newTestCPXAllocator(t, MI300XMemoryModeNPS4, 192*1024*1024*1024)
+
+	allocation := &types.GPUAllocation{ID: "oversized", DeviceID: "card0", Fraction: 0.25, MemoryRequest: 100 * 1024}
+	if err := allocator.allocateXCDs("card0", allocation); err == nil {
+		t.Fatal("Expected XCD assignment to fail")
+	}
+	if allocation.XCDIndices != nil {
+		t.Errorf("Expected no XCDs on the failed allocation, got %v", allocation.XCDIndices)
+	}
+	if owners := xcdOwners(t, allocator, "card0"); len(owners) != 0 {
+		t.Errorf("Expected no XCDs to be assigned, got %v", owners)
+	}
+
+	// Allocate fails the request rather than committing it without XCDs
+	if _, err := allocator.Allocate("card0", newTestXCDRequest("oversized", 2, 100*1024)); err == nil {
+		t.Fatal("Expected allocation to fail")
+	}
+	if allocations := allocator.allocations["card0"]; len(allocations) != 0 {
+		t.Errorf("Expected no allocations on the GPU, got %d", len(allocations))
+	}
+}
+
+func TestReconfigure(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+
+	if err := allocator.RegisterMI300XGPU("card0", 8*1024*1024*1024, nil); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+
+	request := &types.AllocationRequest{
+		ID: "spx-allocation",
+		GPURequest: &types.GPURequest{
+			Fraction: 1.0,
+			Priority: 5,
+		},
+		PodName:       "test-pod",
+		Namespace:     "default",
+		ContainerName: "test-container",
+	}
+
+	if _, err := allocator.Allocate("card0", request); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+
+	cpxConfig := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionModeCPX,
+		MemoryMode:  MI300XMemoryModeNPS1,
+		XCDCount:    8,
+	}
+
+	// Blocked while the SPX allocation is active
+	err := allocator.Reconfigure("card0", cpxConfig)
+	if err == nil {
+		t.Fatal("Expected reconfigure to fail with an active allocation")
+	}
+	if !strings.Contains(err.Error(), "spx-allocation") {
+		t.Errorf("Expected error to name the blocking allocation, got %v", err)
+	}
+
+	config, err := allocator.GetPartitionConfig("card0")
+	if err != nil {
+		t.Fatalf("Failed to get partition config: %v", err)
+	}
+	if config.ComputeMode != MI300XPartitionModeSPX {
+		t.Errorf("Expected SPX mode to be kept, got %s", config.ComputeMode)
+	}
+
+	if err := allocator.Release("spx-allocation"); err != nil {
+		t.Fatalf("Failed to release allocation: %v", err)
+	}
+
+	// Succeeds once the GPU is idle
+	if err := allocator.Reconfigure("card0", cpxConfig); err != nil {
+		t.Fatalf("Failed to reconfigure to CPX: %v", err)
+	}
+
+	fractions, err := allocator.GetValidFractions("card0")
+	if err != nil {
+		t.Fatalf("Failed to get valid fractions: %v", err)
+	}
+	if len(fractions) != 8 || fractions[0] != 0.125 {
+		t.Errorf("Expected 8 CPX fractions starting at 0.125, got %v", fractions)
+	}
+
+	// Invalid configs and unknown GPUs are rejected
+	invalidConfig := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionModeSPX,
+		MemoryMode:  MI300XMemoryModeNPS4,
+		XCDCount:    8,
+	}
+	if err := allocator.Reconfigure("card0", invalidConfig); err == nil {
+		t.Error("Expected error for invalid partition config")
+	}
+
+	if err := allocator.Reconfigure("unknown", cpxConfig); !errors.Is(err, ErrDeviceNotRegistered) {
+		t.Errorf("Expected ErrDeviceNotRegistered, got %v", err)
+	}
+}
+
+func TestMI300XMigrateAllocationCPX(t *testing.T) {
+	allocator := NewMI300XFractionalAllocator()
+
+	cpxConfig := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionModeCPX,
+		MemoryMode:  MI300XMemoryModeNPS1,
+		XCDCount:    8,
+	}
+	for _, deviceID := range []string{"card0", "card1"} {
+		if err := allocator.RegisterMI300XGPU(deviceID, 192*1024*1024*1024, cpxConfig); err != nil {
+			t.Fatalf("Failed to register GPU %s: %v", deviceID, err)
+		}
+	}
+
+	newRequest := func(id string, fraction float64) *types.AllocationRequest {
+		return &types.AllocationRequest{
+			ID:         id,
+			GPURequest: &types.GPURequest{Fraction: fraction},
+			PodName:    "pod-" + id,
+			Namespace:  "default",
+		}
+	}
+
+	if _, err := allocator.Allocate("card0", newRequest("migrating", 0.25)); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	// Occupy the low XCDs on the target so the migrated allocation lands elsewhere
+	if _, err := allocator.Allocate("card1", newRequest("occupant", 0.5)); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+
+	if err := allocator.MigrateAllocation("migrating", "card1"); err != nil {
+		t.Fatalf("Failed to migrate allocation: %v", err)
+	}
+
+	sourceXCDs, _ := allocator.GetXCDAllocations("card0")
+	if len(sourceXCDs) != 0 {
+		t.Errorf("Expected source XCDs to be released, got %d in use", len(sourceXCDs))
+	}
+
+	targetXCDs, _ := allocator.GetXCDAllocations("card1")
+	var migratedXCDs []int
+	for xcdIndex, allocation := range targetXCDs {
+		if allocation.ID == "migrating" {
+			migratedXCDs = append(migratedXCDs, xcdIndex)
+		}
+	}
+	slices.Sort(migratedXCDs)
+	if !slices.Equal(migratedXCDs, []int{4, 5}) {
+		t.Errorf("Expected migrated allocation on XCDs [4 5], got %v", migratedXCDs)
+	}
+
+	// The target now has 2 free XCDs; a 4-XCD allocation will not fit
+	if _, err := allocator.Allocate("card0", newRequest("large", 0.5)); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	if err := allocator.MigrateAllocation("large", "card1"); err == nil {
+		t.Fatal("Expected migration to a GPU without free XCDs to fail")
+	}
+	if err := allocator.MigrateAllocation("missing", "card1"); !errors.Is(err, ErrAllocationNotFound) {
+		t.Errorf("Expected ErrAllocationNotFound, got %v", err)
+	}
+
+	sourceXCDs, _ = allocator.GetXCDAllocations("card0")
+	if len(sourceXCDs) != 4 {
+		t.Errorf("Expected rejected migration to keep 4 XCDs on card0, got %d", len(sourceXCDs))
+	}
+}
+
+// newTestCPXAllocator returns an allocator with one CPX GPU, card0, of the given memory mode and size
+func newTestCPXAllocator(t *testing.T, memoryMode MI300XMemoryMode, totalMemory int64) *MI300XFractionalAllocator {
+	t.Helper()
+
+	allocator := NewMI300XFractionalAllocator()
+	config := &MI300XPartitionConfig{
+		ComputeMode: MI300XPartitionModeCPX,
+		MemoryMode:  memoryMode,
+		XCDCount:    8,
+	}
+	if err := allocator.RegisterMI300XGPU("card0", totalMemory, config); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+	return allocator
+}
+
+// newTestXCDRequest returns a request for xcds XCDs and memoryMiB of memory
+func newTestXCDRequest(id string, xcds int, memoryMiB int64) *types.AllocationRequest {
+	return &types.AllocationRequest{
+		ID:         id,
+		GPURequest: &types.GPURequest{Fraction: float64(xcds) / 8.0, MemoryRequest: memoryMiB},
+		PodName:    "pod-" + id,
+		Namespace:  "default",
+	}
+}
+
+// xcdOwners returns the XCDs each allocation holds on a GPU, in ascending order
+func xcdOwners(t *testing.T, allocator *MI300XFractionalAllocator, deviceID string) map[string][]int {
+	t.Helper()
+
+	xcdAllocations, err := allocator.GetXCDAllocations(deviceID)
+	if err != nil {
+		t.Fatalf("GetXCDAllocations failed: %v", err)
+	}
+	owners := make(map[string][]int)
+	for xcdIndex, allocation := range xcdAllocations {
+		owners[allocation.ID] = append(owners[allocation.ID], xcdIndex)
+	}
+	for _, xcds := range owners {
+		slices.Sort(xcds)
+	}
+	return owners
+}
+
+func TestMI300XPreferContiguousXCDs(t *testing.T) {
+	for _, contiguous := range []bool{false, true} {
+		t.Run(fmt.Sprintf("contiguous=%t", contiguous), func(t *testing.T) {
+			allocator := newTestCPXAllocator(t, MI300XMemoryModeNPS1, 192*1024*1024*1024)
+			allocator.SetPreferContiguousXCDs(contiguous)
+
+			// Leave XCDs 0 and 3-7 free
+			for _, request := range []*types.AllocationRequest{
+				newTestXCDRequest("a", 1, 0),
+				newTestXCDRequest("b", 2, 0),
+				newTestXCDRequest("c", 1, 0),
+			} {
+				if _, err := allocator.Allocate("card0", request); err != nil {
+					t.Fatalf("Failed to allocate %s: %v", request.ID, err)
+				}
+			}
+			for _, id := range []string{"a", "c"} {
+				if err := allocator.Release(id); err != nil {
+					t.Fatalf("Failed to release %s: %v", id, err)
+				}
+			}
+
+			allocation, err := allocator.Allocate("card0", newTestXCDRequest("d", 2, 0))
+			if err != nil {
+				t.Fatalf("Failed to allocate: %v", err)
+			}
+
+			want := []int{0, 3}
+			if contiguous {
+				want = []int{3, 4}
+			}
+			if !slices.Equal(allocation.XCDIndices, want) {
+				t.Errorf("Expected XCDs %v, got %v", want, allocation.XCDIndices)
+			}
+			if owned := xcdOwners(t, allocator, "card0")["d"]; !slices.Equal(owned, allocation.XCDIndices) {
+				t.Errorf("Expected the allocation's XCDs %v to match the XCDs it holds, %v", allocation.XCDIndices, owned)
+			}
+		})
+	}
+}
+
+func TestMI300XCompactCPX(t *testing.T) {
+	allocator := newTestCPXAllocator(t, MI300XMemoryModeNPS1, 192*1024*1024*1024)
+
+	for _, request := range []*types.AllocationRequest{
+		newTestXCDRequest("a", 2, 0),
+		newTestXCDRequest("b", 2, 0),
+		newTestXCDRequest("c", 2, 0),
+		newTestXCDRequest("d", 1, 0),
+	} {
+		if _, err := allocator.Allocate("card0", request); err != nil {
+			t.Fatalf("Failed to allocate %s: %v", request.ID, err)
+		}
+	}
+	for _, id := range []string{"a", "c"} {
+		if err := allocator.Release(id); err != nil {
+			t.Fatalf("Failed to release %s: %v", id, err)
+		}
+	}
+
+	// The free XCDs are 0, 1, 4, 5 and 7, so e is scattered
+	e, err := allocator.Allocate("card0", newTestXCDRequest("e", 3, 0))
+	if err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	if !slices.Equal(e.XCDIndices, []int{0, 1, 4}) {
+		t.Fatalf("Expected e on XCDs [0 1 4], got %v", e.XCDIndices)
+	}
+
+	moved, err := allocator.Compact("card0")
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+
+	want := map[string][]int{
+		"e": {0, 1, 2},
+		"b": {3, 4},
+		"d": {5},
+	}
+	owners := xcdOwners(t, allocator, "card0")
+	if len(owners) != len(want) {
+		t.Errorf("Expected %d allocations to hold XCDs, got %v", len(want), owners)
+	}
+	for id, xcds := range want {
+		if !slices.Equal(owners[id], xcds) {
+			t.Errorf("Expected %s on XCDs %v, got %v", id, xcds, owners[id])
+		}
+	}
+	if len(moved) != 3 {
+		t.Errorf("Expected all 3 allocations moved, got %d", len(moved))
+	}
+	for _, allocation := range moved {
+		if !slices.Equal(allocation.XCDIndices, want[allocation.ID]) {
+			t.Errorf("Expected moved allocation %s to report XCDs %v, got %v", allocation.ID, want[allocation.ID], allocation.XCDIndices)
+		}
+	}
+
+	// A compacted GPU is left alone
+	if moved, err := allocator.Compact("card0"); err != nil || len(moved) != 0 {
+		t.Errorf("Expected compacting again to move nothing, got %d moved, error %v", len(moved), err)
+	}
+
+	// The freed XCDs are now one contiguous range
+	if _, err := allocator.Allocate("card0", newTestXCDRequest("f", 2, 0)); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	if owned := xcdOwners(t, allocator, "card0")["f"]; !slices.Equal(owned, []int{6, 7}) {
+		t.Errorf("Expected f on XCDs [6 7], got %v", owned)
+	}
+}
+
+func TestMI300XCompactRefused(t *testing.T) {
+	// 4GiB split into four 1GiB quadrants, two XCDs per quadrant
+	allocator := newTestCPXAllocator(t, MI300XMemoryModeNPS4, 4*1024*1024*1024)
+
+	// x fills quadrant 0, so y and z, which each fill a quadrant's memory,
+	// land in quadrants 1 and 2
+	for _, request := range []*types.AllocationRequest{
+		newTestXCDRequest("x", 2, 0),
+		newTestXCDRequest("y", 1, 1024),
+		newTestXCDRequest("z", 1, 1024),
+	} {
+		if _, err := allocator.Allocate("card0", request); err != nil {
+			t.Fatalf("Failed to allocate %s: %v", request.ID, err)
+		}
+	}
+	if err := allocator.Release("x"); err != nil {
+		t.Fatalf("Failed to release: %v", err)
+	}
+
+	// Packing y and z into quadrant 0 would need 2GiB there
+	before := xcdOwners(t, allocator, "card0")
+	if _, err := allocator.Compact("card0"); err == nil {
+		t.Fatal("Expected compaction overflowing a quadrant to fail")
+	}
+	if after := xcdOwners(t, allocator, "card0"); !slices.Equal(after["y"], before["y"]) || !slices.Equal(after["z"], before["z"]) {
+		t.Errorf("Expected a refused compaction to leave XCDs unchanged, had %v, got %v", before, after)
+	}
+
+	spx := NewMI300XFractionalAllocator()
+	if err := spx.RegisterMI300XGPU("card0", 192*1024*1024*1024, nil); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+	if _, err := spx.Compact("card0"); err == nil {
+		t.Error("Expected compacting an SPX GPU to fail")
+	}
+	if _, err := spx.Compact("missing"); !errors.Is(err, ErrDeviceNotRegistered) {
+		t.Errorf("Expected ErrDeviceNotRegistered, got %v", err)
+	}
+}
+
+func TestMI300XAllocationXCDIndices(t *testing.T) {
+	allocator := newTestCPXAllocator(t, MI300XMemoryModeNPS1, 192*1024*1024*1024)
+
+	if _, err := allocator.Allocate("card0", newTestXCDRequest("first", 1, 0)); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	quarter, err := allocator.Allocate("card0", newTestXCDRequest("quarter", 2, 0)) // 0.25 of the GPU
+	if err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+
+	xcds := allocator.GetAllocationXCDs("quarter")
+	if len(xcds) != 2 {
+		t.Fatalf("Expected a 0.25 allocation to hold 2 XCDs, got %v", xcds)
+	}
+	if owned := xcdOwners(t, allocator, "card0")["quarter"]; !slices.Equal(xcds, owned) {
+		t.Errorf("Expected XCDs %v to match GetXCDAllocations, got %v", owned, xcds)
+	}
+	if !slices.Equal(quarter.XCDIndices, xcds) {
+		t.Errorf("Expected the allocation to record XCDs %v, got %v", xcds, quarter.XCDIndices)
+	}
+
+	// The accessor returns a copy
+	xcds[0] = 7
+	if allocator.GetAllocationXCDs("quarter")[0] == 7 {
+		t.Error("Expected modifying the returned XCDs not to affect the allocation")
+	}
+
+	if err := allocator.Release("quarter"); err != nil {
+		t.Fatalf("Failed to release: %v", err)
+	}
+	if quarter.XCDIndices != nil || allocator.GetAllocationXCDs("quarter") != nil {
+		t.Errorf("Expected a released allocation to hold no XCDs, got %v", quarter.XCDIndices)
+	}
+
+	// Expired allocations give up their XCDs on cleanup
+	expiration := time.Now().Add(-time.Hour)
+	request := newTestXCDRequest("expiring", 2, 0)
+	request.ExpiresAt = &expiration
+	expiring, err := allocator.Allocate("card0", request)
+	if err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	if len(expiring.XCDIndices) != 2 {
+		t.Fatalf("Expected the expiring allocation to hold 2 XCDs, got %v", expiring.XCDIndices)
+	}
+	allocator.CleanupExpiredAllocations()
+	if expiring.XCDIndices != nil {
+		t.Errorf("Expected an expired allocation to hold no XCDs, got %v", expiring.XCDIndices)
+	}
+	if owners := xcdOwners(t, allocator, "card0"); len(owners) != 1 || !slices.Equal(owners["first"], []int{0}) {
+		t.Errorf("Expected only first to hold XCDs, got %v", owners)
+	}
+}
+
+func TestMI300XAllocationTenant(t *testing.T) {
+	allocator := newTestCPXAllocator(t, MI300XMemoryModeNPS1, 192*1024*1024*1024)
+	log := NewAllocationEventLog(0)
+	allocator.SetEventLog(log)
+
+	request := newTestXCDRequest("tenanted", 2, 0)
+	request.GPURequest.TenantID = "tenant-a"
+	allocation, err := allocator.Allocate("card0", request)
+	if err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	if allocation.TenantID != "tenant-a" {
+		t.Errorf("Expected allocation tenant 'tenant-a', got %q", allocation.TenantID)
+	}
+
+	events, _ := log.EventsSince(0)
+	if len(events) != 1 || events[0].Metadata["tenantId"] != "tenant-a" {
+		t.Errorf("Expected the allocated event to record tenant-a, got %+v", events)
+	}
+}
+
+func TestMI300XSRIOVAllocation(t *testing.T) {
+	const totalMemory = 192 * 1024 * 1024 * 1024
+
+	// SPX exposes the whole GPU as one device, so there are no XCDs to isolate
+	spx := NewMI300XFractionalAllocator()
+	if err := spx.RegisterMI300XGPU("card0", totalMemory, nil); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+	request := &types.AllocationRequest{
+		ID:         "sriov",
+		GPURequest: &types.GPURequest{Fraction: 1.0, IsolationType: types.GPUIsolationSRIOV},
+	}
+	if _, err := spx.Allocate("card0", request); err == nil || !strings.Contains(err.Error(), "CPX") {
+		t.Errorf("Expected an SR-IOV request on an SPX GPU to be rejected for needing CPX, got %v", err)
+	}
+
+	allocator := newTestCPXAllocator(t, MI300XMemoryModeNPS4, totalMemory)
+	if _, err := allocator.Allocate("card0", newTestXCDRequest("plain", 1, 0)); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+	request = newTestXCDRequest("sriov", 2, 8*1024)
+	request.GPURequest.IsolationType = types.GPUIsolationSRIOV
+	allocation, err := allocator.Allocate("card0", request)
+	if err != nil {
+		t.Fatalf("Failed to allocate SR-IOV request on a CPX GPU: %v", err)
+	}
+
+	// One virtual function per XCD, each with half of the requested memory
+	if len(allocation.VirtualFunctions) != len(allocation.XCDIndices) {
+		t.Fatalf("Expected a virtual function per XCD %v, got %+v", allocation.XCDIndices, allocation.VirtualFunctions)
+	}
+	for i, vf := range allocation.VirtualFunctions {
+		xcdIndex := allocation.XCDIndices[i]
+		if vf.Index != xcdIndex || vf.XCDIndex != xcdIndex || vf.MemoryQuadrant != xcdQuadrant(xcdIndex) ||
+			vf.MemoryBytes != 4*1024*1024*1024 {
+			t.Errorf("Unexpected virtual function for XCD %d: %+v", xcdIndex, vf)
+		}
+	}
+
+	// Allocations without SR-IOV get no virtual functions
+	xcdAllocations, err := allocator.GetXCDAllocations("card0")
+	if err != nil {
+		t.Fatalf("GetXCDAllocations failed: %v", err)
+	}
+	if plain := xcdAllocations[0]; plain.ID != "plain" || plain.VirtualFunctions != nil {
+		t.Errorf("Expected plain to hold XCD 0 without virtual functions, got %+v", plain)
+	}
+
+	if err := allocator.Release("sriov"); err != nil {
+		t.Fatalf("Failed to release: %v", err)
+	}
+	if allocation.VirtualFunctions != nil {
+		t.Errorf("Expected a released allocation to hold no virtual functions, got %+v", allocation.VirtualFunctions)
+	}
+}
+
+func TestMI300XStartCleanup(t *testing.T) {
+	allocator := newTestCPXAllocator(t, MI300XMemoryModeNPS1, 192*1024*1024*1024)
+
+	expired := time.Now().Add(-time.Minute)
+	request := newTestXCDRequest("expired", 8, 0)
+	request.ExpiresAt = &expired
+	if _, err := allocator.Allocate("card0", request); err != nil {
+		t.Fatalf("Failed to allocate: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := allocator.StartCleanup(ctx, 10*time.Millisecond); err != nil {
+		t.Fatalf("Failed to start cleanup: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(xcdOwners(t, allocator, "card0")) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the expired allocation's XCDs to be freed, got %v", xcdOwners(t, allocator, "card0"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := allocator.Allocate("card0", newTestXCDRequest("admitted", 8, 0)); err != nil {
+		t.Errorf("Expected the freed XCDs to be allocatable: %v", err)
+	}
+}
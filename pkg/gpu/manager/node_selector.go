@@ -0,0 +1,156 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// NodeLabelSource resolves the labels of the node a GPU is on, for matching
+// allocation requests' node selectors
+type NodeLabelSource interface {
+	// NodeLabels returns the labels of the named node
+	NodeLabels(ctx context.Context, nodeName string) (map[string]string, error)
+}
+
+// KubernetesNodeLabels reads node labels from the Kubernetes API
+type KubernetesNodeLabels struct {
+	client client.Reader
+}
+
+// NewKubernetesNodeLabels creates a node label source backed by the Kubernetes API
+func NewKubernetesNodeLabels(c client.Reader) *KubernetesNodeLabels {
+	return &KubernetesNodeLabels{client: c}
+}
+
+// NodeLabels returns the labels of the named node
+func (k *KubernetesNodeLabels) NodeLabels(ctx context.Context, nodeName string) (map[string]string, error) {
+	var node corev1.Node
+	if err := k.client.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	return node.Labels, nil
+}
+
+// hostnameNodeLabels is the node label source used when none is set. It only
+// knows each node's hostname label, so selectors can pin a request to a node
+// by name but not by any other label.
+type hostnameNodeLabels struct{}
+
+// NodeLabels returns the hostname label of the named node
+func (hostnameNodeLabels) NodeLabels(_ context.Context, nodeName string) (map[string]string, error) {
+	return map[string]string{corev1.LabelHostname: nodeName}, nil
+}
+
+// MatchPlacement returns the GPUs, of those given, whose type and node match
+// the request's GPU type and node selector. A nil source matches node
+// selectors against node hostnames only.
+func MatchPlacement(ctx context.Context, source NodeLabelSource, request *types.AllocationRequest, gpus []*types.GPUInfo) []*types.GPUInfo {
+	placement := newPlacementFilter(ctx, source, request)
+
+	var matching []*types.GPUInfo
+	for _, gpu := range gpus {
+		if placement.mismatch(gpu) == "" {
+			matching = append(matching, gpu)
+		}
+	}
+
+	return matching
+}
+
+// placementFilter matches GPUs against a request's GPU type and node
+// selector. Node labels are looked up once per node.
+type placementFilter struct {
+	ctx        context.Context
+	source     NodeLabelSource
+	request    *types.AllocationRequest
+	nodeLabels map[string]map[string]string
+	nodeErrors map[string]error
+}
+
+// newPlacementFilter creates a placement filter for a request. A nil source
+// matches node selectors against node hostnames only.
+func newPlacementFilter(ctx context.Context, source NodeLabelSource, request *types.AllocationRequest) *placementFilter {
+	if source == nil {
+		source = hostnameNodeLabels{}
+	}
+	return &placementFilter{
+		ctx:        ctx,
+		source:     source,
+		request:    request,
+		nodeLabels: make(map[string]map[string]string),
+		nodeErrors: make(map[string]error),
+	}
+}
+
+// mismatch returns why a GPU cannot host the request, or an empty string if
+// its type and node match
+func (p *placementFilter) mismatch(gpu *types.GPUInfo) string {
+	if p.request.GPUType != "" && gpu.Type != p.request.GPUType {
+		return fmt.Sprintf("GPU type %s does not match requested type %s", gpu.Type, p.request.GPUType)
+	}
+
+	if len(p.request.NodeSelector) == 0 {
+		return ""
+	}
+
+	nodeLabels, err := p.labels(gpu.NodeName)
+	if err != nil {
+		return fmt.Sprintf("labels of node %q are unknown: %v", gpu.NodeName, err)
+	}
+	if !labels.SelectorFromSet(p.request.NodeSelector).Matches(labels.Set(nodeLabels)) {
+		return fmt.Sprintf("node %q does not match node selector %s", gpu.NodeName, labels.Set(p.request.NodeSelector))
+	}
+
+	return ""
+}
+
+// labels returns the labels of a node, looking them up on first use
+func (p *placementFilter) labels(nodeName string) (map[string]string, error) {
+	if err, failed := p.nodeErrors[nodeName]; failed {
+		return nil, err
+	}
+	if nodeLabels, cached := p.nodeLabels[nodeName]; cached {
+		return nodeLabels, nil
+	}
+
+	nodeLabels, err := p.source.NodeLabels(p.ctx, nodeName)
+	if err != nil {
+		p.nodeErrors[nodeName] = err
+		return nil, err
+	}
+	p.nodeLabels[nodeName] = nodeLabels
+	return nodeLabels, nil
+}
+
+// noMatchError reports that no GPU satisfies the request's placement
+func (p *placementFilter) noMatchError() error {
+	var constraints []string
+	if p.request.GPUType != "" {
+		constraints = append(constraints, fmt.Sprintf("GPU type %s", p.request.GPUType))
+	}
+	if len(p.request.NodeSelector) > 0 {
+		constraints = append(constraints, fmt.Sprintf("node selector %s", labels.Set(p.request.NodeSelector)))
+	}
+	return fmt.Errorf("no GPU matches %s", strings.Join(constraints, " and "))
+}
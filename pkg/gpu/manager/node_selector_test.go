@@ -0,0 +1,143 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// newTestNodeGPUs returns one GPU on each of node-1, node-2 and node-3
+func newTestNodeGPUs() []*types.GPUInfo {
+	gpus := make([]*types.GPUInfo, 0, 3)
+	for i, node := range []string{"node-1", "node-2", "node-3"} {
+		gpu := newTestGPUInfo(fmt.Sprintf("card%d", i), 8*1024*1024*1024)
+		gpu.NodeName = node
+		gpus = append(gpus, gpu)
+	}
+	return gpus
+}
+
+// newTestNodeLabels returns a node label source serving zone labels for the
+// test nodes from a fake Kubernetes API
+func newTestNodeLabels() *KubernetesNodeLabels {
+	node := func(name, zone string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{corev1.LabelHostname: name, corev1.LabelTopologyZone: zone},
+		}}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		node("node-1", "zone-a"),
+		node("node-2", "zone-b"),
+		node("node-3", "zone-a"),
+	).Build()
+	return NewKubernetesNodeLabels(c)
+}
+
+func TestAMDGPUManagerNodeSelector(t *testing.T) {
+	manager := newTestAMDGPUManager(t, newTestNodeGPUs()...)
+	ctx := context.Background()
+
+	// Without a label source, selectors match node hostnames
+	request := newTestAllocationRequest("on-node-2", 0.5)
+	request.NodeSelector = map[string]string{corev1.LabelHostname: "node-2"}
+	result, err := manager.AllocateGPU(ctx, request)
+	if err != nil {
+		t.Fatalf("AllocateGPU failed: %v", err)
+	}
+	if result.DeviceID != "card1" || result.NodeName != "node-2" {
+		t.Errorf("Expected card1 on node-2, got %s on %s", result.DeviceID, result.NodeName)
+	}
+
+	// Zone labels come from the Kubernetes API; zone-b only has node-2
+	manager.SetNodeLabelSource(newTestNodeLabels())
+	request = newTestAllocationRequest("in-zone-b", 0.5)
+	request.NodeSelector = map[string]string{corev1.LabelTopologyZone: "zone-b"}
+	result, err = manager.AllocateGPU(ctx, request)
+	if err != nil {
+		t.Fatalf("AllocateGPU failed: %v", err)
+	}
+	if result.DeviceID != "card1" {
+		t.Errorf("Expected the zone-b GPU card1, got %s", result.DeviceID)
+	}
+
+	// card1 is now full, and a matching GPU without room is not a placement error
+	request = newTestAllocationRequest("zone-b-full", 0.5)
+	request.NodeSelector = map[string]string{corev1.LabelTopologyZone: "zone-b"}
+	if _, err := manager.AllocateGPU(ctx, request); err == nil {
+		t.Fatal("Expected allocation on the full zone-b GPU to fail")
+	} else if strings.Contains(err.Error(), "no GPU matches") {
+		t.Errorf("Expected a capacity error, got %v", err)
+	}
+
+	// No node is in zone-c
+	request = newTestAllocationRequest("in-zone-c", 0.5)
+	request.NodeSelector = map[string]string{corev1.LabelTopologyZone: "zone-c"}
+	if _, err := manager.AllocateGPU(ctx, request); err == nil {
+		t.Fatal("Expected allocation to fail when no node matches the selector")
+	} else if !strings.Contains(err.Error(), "no GPU matches node selector topology.kubernetes.io/zone=zone-c") {
+		t.Errorf("Expected the error to name the unmatched selector, got %v", err)
+	}
+
+	// The managed GPUs are all AMD
+	request = newTestAllocationRequest("nvidia-only", 0.5)
+	request.GPUType = types.GPUTypeNVIDIA
+	if _, err := manager.AllocateGPU(ctx, request); err == nil {
+		t.Fatal("Expected allocation to fail when no GPU has the requested type")
+	} else if !strings.Contains(err.Error(), "no GPU matches GPU type nvidia") {
+		t.Errorf("Expected the error to name the GPU type, got %v", err)
+	}
+
+	// ExplainAllocation reports GPUs outside the selector as placement mismatches
+	request = newTestAllocationRequest("explain-zone-a", 0.5)
+	request.NodeSelector = map[string]string{corev1.LabelTopologyZone: "zone-a"}
+	explanation, err := manager.ExplainAllocation(ctx, request)
+	if err != nil {
+		t.Fatalf("ExplainAllocation failed: %v", err)
+	}
+	if len(explanation.Filtered) != 1 || explanation.Filtered[0].DeviceID != "card1" ||
+		explanation.Filtered[0].Reason != CandidateFilterPlacement {
+		t.Errorf("Expected only card1 filtered for placement, got %+v", explanation.Filtered)
+	}
+	if explanation.SelectedGPU != "card0" {
+		t.Errorf("Expected card0 selected in zone-a, got %q", explanation.SelectedGPU)
+	}
+}
+
+func TestPlacementFilterUnknownNode(t *testing.T) {
+	request := newTestAllocationRequest("selector", 0.5)
+	request.NodeSelector = map[string]string{corev1.LabelTopologyZone: "zone-a"}
+	placement := newPlacementFilter(context.Background(), newTestNodeLabels(), request)
+
+	gpu := newTestGPUInfo("card9", 8*1024*1024*1024)
+	gpu.NodeName = "node-9"
+	if detail := placement.mismatch(gpu); !strings.Contains(detail, `labels of node "node-9" are unknown`) {
+		t.Errorf("Expected a GPU on an unknown node not to match, got %q", detail)
+	}
+
+	gpu.NodeName = "node-3"
+	if detail := placement.mismatch(gpu); detail != "" {
+		t.Errorf("Expected the zone-a node-3 to match, got %q", detail)
+	}
+}
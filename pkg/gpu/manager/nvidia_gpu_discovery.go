@@ -0,0 +1,195 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// nvidiaMIGPartitionMode is the PartitionMode of NVIDIA GPUs with MIG enabled
+const nvidiaMIGPartitionMode = "MIG"
+
+// nvidiaSMIQueryFields are the nvidia-smi --query-gpu fields parsed into GPUInfo, in order
+var nvidiaSMIQueryFields = []string{
+	"index",
+	"name",
+	"memory.total",
+	"memory.used",
+	"utilization.gpu",
+	"temperature.gpu",
+	"power.draw",
+	"clocks.sm",
+	"clocks.mem",
+	"fan.speed",
+	"mig.mode.current",
+}
+
+// NvidiaGPUDiscovery discovers NVIDIA GPUs and their metrics using nvidia-smi
+type NvidiaGPUDiscovery struct {
+	// nvidiaSMIPath is the path to the nvidia-smi executable
+	nvidiaSMIPath string
+
+	// timeout for commands
+	timeout time.Duration
+
+	// health sets when a discovered GPU is reported available
+	health HealthConfig
+}
+
+// NewNvidiaGPUDiscovery creates a new NVIDIA GPU discovery instance
+func NewNvidiaGPUDiscovery() *NvidiaGPUDiscovery {
+	return &NvidiaGPUDiscovery{
+		nvidiaSMIPath: findNvidiaSMI(),
+		timeout:       30 * time.Second,
+		health:        HealthConfig{}.withDefaults(),
+	}
+}
+
+// SetHealthConfig sets the thresholds used to decide whether discovered GPUs
+// are available. Unset thresholds keep their defaults.
+func (d *NvidiaGPUDiscovery) SetHealthConfig(health HealthConfig) {
+	d.health = health.withDefaults()
+}
+
+// DiscoverGPUs discovers NVIDIA GPUs using nvidia-smi
+func (d *NvidiaGPUDiscovery) DiscoverGPUs(ctx context.Context) ([]*types.GPUInfo, error) {
+	if d.nvidiaSMIPath == "" {
+		return nil, fmt.Errorf("nvidia-smi not found")
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, d.nvidiaSMIPath,
+		"--query-gpu="+strings.Join(nvidiaSMIQueryFields, ","), "--format=csv,noheader,nounits")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("nvidia-smi failed: %w", err)
+	}
+
+	return d.parseNvidiaSMIOutput(output)
+}
+
+// parseNvidiaSMIOutput converts nvidia-smi CSV output, one GPU per line, to GPU info
+func (d *NvidiaGPUDiscovery) parseNvidiaSMIOutput(output []byte) ([]*types.GPUInfo, error) {
+	reader := csv.NewReader(strings.NewReader(string(output)))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = len(nvidiaSMIQueryFields)
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nvidia-smi output: %w", err)
+	}
+
+	nodeName, _ := os.Hostname()
+
+	gpus := make([]*types.GPUInfo, 0, len(records))
+	for _, record := range records {
+		index, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid GPU index %q: %w", record[0], err)
+		}
+
+		// Memory is reported in MiB
+		totalMemory := int64(nvidiaSMIValue(record[2])) * 1024 * 1024
+		usedMemory := int64(nvidiaSMIValue(record[3])) * 1024 * 1024
+
+		gpu := &types.GPUInfo{
+			DeviceID:        fmt.Sprintf("nvidia%d", index),
+			Type:            types.GPUTypeNVIDIA,
+			Model:           record[1],
+			TotalMemory:     totalMemory,
+			AvailableMemory: max(totalMemory-usedMemory, 0),
+			Utilization:     nvidiaSMIValue(record[4]),
+			Temperature:     nvidiaSMIValue(record[5]),
+			Power:           nvidiaSMIValue(record[6]),
+			ClockMHz:        nvidiaSMIValue(record[7]),
+			MemoryClockMHz:  nvidiaSMIValue(record[8]),
+			FanSpeedPercent: nvidiaSMIValue(record[9]),
+			NodeName:        nodeName,
+			IsolationType:   types.GPUIsolationNone,
+		}
+		if strings.EqualFold(record[10], "Enabled") {
+			gpu.PartitionMode = nvidiaMIGPartitionMode
+			gpu.IsolationType = types.GPUIsolationMIG
+		}
+		gpu.IsAvailable = d.health.checkHealth(gpu) == nil
+
+		gpus = append(gpus, gpu)
+	}
+
+	return gpus, nil
+}
+
+// nvidiaSMIValue parses a numeric nvidia-smi field. Fields a GPU does not
+// support, such as "[N/A]" or "[Not Supported]", read as zero.
+func nvidiaSMIValue(field string) float64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// UpdateGPUMetrics updates metrics for existing GPUs
+func (d *NvidiaGPUDiscovery) UpdateGPUMetrics(ctx context.Context, gpus map[string]*types.GPUInfo) {
+	discoveredGPUs, err := d.DiscoverGPUs(ctx)
+	if err != nil {
+		fmt.Printf("Failed to update metrics with nvidia-smi: %v\n", err)
+		return
+	}
+
+	for _, discoveredGPU := range discoveredGPUs {
+		if existingGPU, exists := gpus[discoveredGPU.DeviceID]; exists {
+			// Update metrics while preserving allocation info
+			existingGPU.Utilization = discoveredGPU.Utilization
+			existingGPU.Temperature = discoveredGPU.Temperature
+			existingGPU.Power = discoveredGPU.Power
+			existingGPU.AvailableMemory = discoveredGPU.AvailableMemory
+			existingGPU.ClockMHz = discoveredGPU.ClockMHz
+			existingGPU.MemoryClockMHz = discoveredGPU.MemoryClockMHz
+			existingGPU.FanSpeedPercent = discoveredGPU.FanSpeedPercent
+			existingGPU.PartitionMode = discoveredGPU.PartitionMode
+			existingGPU.IsAvailable = d.health.checkHealth(existingGPU) == nil &&
+				!d.health.isFull(existingGPU.ActiveAllocations)
+		}
+	}
+}
+
+// findNvidiaSMI finds the nvidia-smi executable
+func findNvidiaSMI() string {
+	// Check PATH first
+	if path, err := exec.LookPath("nvidia-smi"); err == nil {
+		return path
+	}
+
+	// Check common paths
+	for _, path := range []string{"/usr/bin/nvidia-smi", "/usr/local/nvidia/bin/nvidia-smi"} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
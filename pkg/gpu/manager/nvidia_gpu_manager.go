@@ -0,0 +1,141 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// migSlices is the number of compute slices a MIG-enabled GPU is divided into
+const migSlices = 7
+
+// migSliceCounts are the slice counts of the MIG GPU instance profiles
+// (1g, 2g, 3g, 4g and 7g)
+var migSliceCounts = []int{1, 2, 3, 4, 7}
+
+// migFractionTolerance is how far a requested fraction may be from a MIG
+// profile's share of the GPU and still select it
+const migFractionTolerance = 1e-3
+
+// NvidiaGPUManager manages NVIDIA GPUs
+type NvidiaGPUManager struct {
+	*gpuManagerCore
+}
+
+// NewNvidiaGPUManager creates a new NVIDIA GPU manager
+func NewNvidiaGPUManager(config *GPUManagerConfig) (*NvidiaGPUManager, error) {
+	if config.GPUType != types.GPUTypeNVIDIA {
+		return nil, fmt.Errorf("expected NVIDIA GPU type, got %s", config.GPUType)
+	}
+
+	if err := ValidateGPUManagerConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %v", err)
+	}
+
+	health := config.Health.withDefaults()
+
+	discovery := NewNvidiaGPUDiscovery()
+	discovery.SetHealthConfig(health)
+
+	n := &NvidiaGPUManager{gpuManagerCore: newGPUManagerCore(config, "NVIDIA", discovery, health)}
+	n.vendor = n
+	return n, nil
+}
+
+// discover discovers NVIDIA GPUs in the system. Callers must hold n.mu.
+func (n *NvidiaGPUManager) discover(ctx context.Context) ([]*types.GPUInfo, error) {
+	discoveredGPUs, err := n.discovery.DiscoverGPUs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover NVIDIA GPUs: %w", err)
+	}
+	if len(discoveredGPUs) == 0 {
+		return nil, fmt.Errorf("no NVIDIA GPUs found")
+	}
+
+	return discoveredGPUs, nil
+}
+
+// validateFraction checks the fraction against the GPU's MIG profiles
+func (n *NvidiaGPUManager) validateFraction(gpu *types.GPUInfo, fraction float64) error {
+	return validateMIGFraction(gpu, fraction)
+}
+
+// selectGPU applies the request's allocation strategy to GPUs, ordered by
+// device ID, that can all handle it
+func (n *NvidiaGPUManager) selectGPU(availableGPUs []*types.GPUInfo, request *types.AllocationRequest) (*types.GPUInfo, error) {
+	freeFraction := func(gpu *types.GPUInfo) float64 {
+		return n.fractional.GetAvailableFraction(gpu.DeviceID)
+	}
+
+	var selected *types.GPUInfo
+	switch request.Strategy {
+	case types.AllocationStrategyBestFit:
+		// The GPU left with the least free capacity, packing allocations tightly
+		selected = slices.MinFunc(availableGPUs, func(x, y *types.GPUInfo) int {
+			return compareFloat(freeFraction(x), freeFraction(y))
+		})
+	case types.AllocationStrategyWorstFit:
+		// The GPU left with the most free capacity, spreading allocations out
+		selected = slices.MaxFunc(availableGPUs, func(x, y *types.GPUInfo) int {
+			return compareFloat(freeFraction(x), freeFraction(y))
+		})
+	case types.AllocationStrategyRoundRobin:
+		selected = availableGPUs[n.roundRobin.Load()%uint64(len(availableGPUs))]
+	case types.AllocationStrategyLoadBalanced:
+		selected = slices.MinFunc(availableGPUs, func(x, y *types.GPUInfo) int {
+			return x.ActiveAllocations - y.ActiveAllocations
+		})
+	default:
+		selected = availableGPUs[0]
+	}
+
+	return selected, nil
+}
+
+// compareFloat orders two floats for slices.MinFunc and slices.MaxFunc
+func compareFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// validateMIGFraction checks that a fraction of a MIG-enabled GPU matches one
+// of its GPU instance profiles. A MIG GPU is split into seven compute slices
+// and an instance takes 1, 2, 3, 4 or all 7 of them, so only those shares can
+// be allocated. GPUs without MIG accept any fraction.
+func validateMIGFraction(gpu *types.GPUInfo, fraction float64) error {
+	if gpu.PartitionMode != nvidiaMIGPartitionMode {
+		return nil
+	}
+
+	for _, sliceCount := range migSliceCounts {
+		if math.Abs(fraction-float64(sliceCount)/migSlices) <= migFractionTolerance {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("GPU %s has MIG enabled and cannot allocate fraction %.3f; fractions must be 1/7, 2/7, 3/7, 4/7 or 1",
+		gpu.DeviceID, fraction)
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// nvidiaSMIOutput is nvidia-smi CSV output for an H100 and an A100 with MIG enabled
+const nvidiaSMIOutput = `0, NVIDIA H100 80GB HBM3, 81559, 1024, 35, 45, 120.50, 1980, 2619, [N/A], Disabled
+1, NVIDIA A100-SXM4-40GB, 40960, 0, 0, 38, 55.10, 1410, 1215, [N/A], Enabled`
+
+// writeFakeNvidiaSMI writes an nvidia-smi script that prints output for any query
+func writeFakeNvidiaSMI(t *testing.T, output string) string {
+	t.Helper()
+
+	nvidiaSMI := filepath.Join(t.TempDir(), "nvidia-smi")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + output + "\nEOF\n"
+	if err := os.WriteFile(nvidiaSMI, []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to write fake nvidia-smi: %v", err)
+	}
+	return nvidiaSMI
+}
+
+// newTestNvidiaGPUManager creates an initialized NVIDIA GPU manager whose
+// discovery runs a fake nvidia-smi
+func newTestNvidiaGPUManager(t *testing.T) *NvidiaGPUManager {
+	t.Helper()
+
+	config := newTestGPUManagerConfig()
+	config.GPUType = types.GPUTypeNVIDIA
+	config.AllowedIsolationTypes = append(config.AllowedIsolationTypes, types.GPUIsolationMIG)
+
+	manager, err := NewNvidiaGPUManager(config)
+	if err != nil {
+		t.Fatalf("Failed to create NVIDIA GPU manager: %v", err)
+	}
+
+	discovery := NewNvidiaGPUDiscovery()
+	discovery.nvidiaSMIPath = writeFakeNvidiaSMI(t, nvidiaSMIOutput)
+	manager.SetDiscovery(discovery)
+
+	if err := manager.Initialize(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize NVIDIA GPU manager: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := manager.Shutdown(ctx); err != nil {
+			t.Errorf("Failed to shut down NVIDIA GPU manager: %v", err)
+		}
+	})
+
+	return manager
+}
+
+func TestNvidiaGPUManagerListsGPUs(t *testing.T) {
+	manager := newTestNvidiaGPUManager(t)
+
+	gpus, err := manager.ListGPUs(context.Background())
+	if err != nil {
+		t.Fatalf("ListGPUs failed: %v", err)
+	}
+	if len(gpus) != 2 {
+		t.Fatalf("Expected 2 GPUs, got %d", len(gpus))
+	}
+
+	h100 := gpus[0]
+	if h100.DeviceID != "nvidia0" || h100.Type != types.GPUTypeNVIDIA || h100.Model != "NVIDIA H100 80GB HBM3" {
+		t.Errorf("Unexpected GPU: %+v", h100)
+	}
+	if h100.TotalMemory != 81559*1024*1024 || h100.AvailableMemory != (81559-1024)*1024*1024 {
+		t.Errorf("Unexpected memory: total %d, available %d", h100.TotalMemory, h100.AvailableMemory)
+	}
+	if h100.Utilization != 35 || h100.Temperature != 45 || h100.Power != 120.5 || h100.ClockMHz != 1980 {
+		t.Errorf("Unexpected metrics: %+v", h100)
+	}
+	if h100.FanSpeedPercent != 0 {
+		t.Errorf("Expected an unsupported fan speed to read as 0, got %.1f", h100.FanSpeedPercent)
+	}
+	if h100.PartitionMode != "" || !h100.IsAvailable {
+		t.Errorf("Expected an available GPU without MIG, got %+v", h100)
+	}
+
+	if a100 := gpus[1]; a100.DeviceID != "nvidia1" || a100.PartitionMode != "MIG" || a100.IsolationType != types.GPUIsolationMIG {
+		t.Errorf("Expected nvidia1 with MIG enabled, got %+v", a100)
+	}
+
+	factory := NewDefaultGPUManagerFactory()
+	created, err := factory.CreateManager(manager.GetConfig())
+	if err != nil {
+		t.Fatalf("Failed to create NVIDIA manager from factory: %v", err)
+	}
+	if created.GetGPUType() != types.GPUTypeNVIDIA {
+		t.Errorf("Expected GPU type NVIDIA, got %s", created.GetGPUType())
+	}
+}
+
+func TestNvidiaGPUManagerAllocatesGPUs(t *testing.T) {
+	manager := newTestNvidiaGPUManager(t)
+	ctx := context.Background()
+
+	// Half of a GPU only fits the H100; MIG has no half-GPU profile
+	result, err := manager.AllocateGPU(ctx, newTestAllocationRequest("half", 0.5))
+	if err != nil {
+		t.Fatalf("AllocateGPU failed: %v", err)
+	}
+	if result.DeviceID != "nvidia0" {
+		t.Errorf("Expected the half GPU on nvidia0, got %s", result.DeviceID)
+	}
+
+	// 0.6 no longer fits on nvidia0 and matches no MIG profile on nvidia1
+	if _, err := manager.AllocateGPU(ctx, newTestAllocationRequest("too-large", 0.6)); err == nil {
+		t.Fatal("Expected a fraction matching no MIG profile to fail")
+	} else if !strings.Contains(err.Error(), "MIG") {
+		t.Errorf("Expected the error to explain the MIG profiles, got %v", err)
+	}
+
+	// Three sevenths is a MIG profile, but first fit still prefers nvidia0
+	migResult, err := manager.AllocateGPU(ctx, newTestAllocationRequest("three-sevenths", 3.0/7))
+	if err != nil {
+		t.Fatalf("AllocateGPU for a MIG profile failed: %v", err)
+	}
+	if migResult.DeviceID != "nvidia0" {
+		t.Errorf("Expected first fit to place 3/7 on nvidia0, got %s", migResult.DeviceID)
+	}
+
+	// nvidia0 is now nearly full, so four sevenths goes to the MIG GPU
+	migResult, err = manager.AllocateGPU(ctx, newTestAllocationRequest("four-sevenths", 4.0/7))
+	if err != nil {
+		t.Fatalf("AllocateGPU for a MIG profile failed: %v", err)
+	}
+	if migResult.DeviceID != "nvidia1" {
+		t.Errorf("Expected 4/7 on the MIG GPU nvidia1, got %s", migResult.DeviceID)
+	}
+
+	stats, err := manager.GetGPUStats(ctx)
+	if err != nil {
+		t.Fatalf("GetGPUStats failed: %v", err)
+	}
+	if stats.TotalGPUs != 2 || stats.ActiveAllocations != 3 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	if err := manager.ReleaseGPU(ctx, result.Allocation.ID); err != nil {
+		t.Fatalf("ReleaseGPU failed: %v", err)
+	}
+	capacity, err := manager.GetAvailableCapacity(ctx)
+	if err != nil {
+		t.Fatalf("GetAvailableCapacity failed: %v", err)
+	}
+	if len(capacity) != 2 || capacity[0].DeviceID != "nvidia0" {
+		t.Fatalf("Unexpected capacity: %+v", capacity)
+	}
+	if got, want := capacity[0].FreeFraction, 4.0/7; got < want-1e-9 || got > want+1e-9 {
+		t.Errorf("Expected %.3f of nvidia0 free after release, got %.3f", want, got)
+	}
+}
+
+func TestValidateMIGFraction(t *testing.T) {
+	mig := &types.GPUInfo{DeviceID: "nvidia0", PartitionMode: "MIG"}
+	for _, fraction := range []float64{1.0 / 7, 2.0 / 7, 3.0 / 7, 4.0 / 7, 1} {
+		if err := validateMIGFraction(mig, fraction); err != nil {
+			t.Errorf("Expected fraction %.3f to match a MIG profile: %v", fraction, err)
+		}
+	}
+	for _, fraction := range []float64{0.25, 0.5, 5.0 / 7, 6.0 / 7} {
+		if err := validateMIGFraction(mig, fraction); err == nil {
+			t.Errorf("Expected fraction %.3f to be rejected on a MIG GPU", fraction)
+		}
+	}
+
+	if err := validateMIGFraction(&types.GPUInfo{DeviceID: "nvidia1"}, 0.5); err != nil {
+		t.Errorf("Expected any fraction on a GPU without MIG, got %v", err)
+	}
+}
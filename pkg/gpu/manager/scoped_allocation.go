@@ -0,0 +1,102 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// scopedAllocationLogf reports allocations reclaimed by the garbage collector
+var scopedAllocationLogf = func(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
+// ScopedAllocation is a handle to a GPU allocation that is released either
+// explicitly via Release or, as a safety net, when the handle is garbage collected
+type ScopedAllocation struct {
+	*scopedAllocationState
+}
+
+// scopedAllocationState holds everything needed to release an allocation.
+// It is kept separate from ScopedAllocation so the finalizer does not keep the
+// handle itself reachable.
+type scopedAllocationState struct {
+	manager GPUManager
+	result  *types.AllocationResult
+	once    sync.Once
+	err     error
+}
+
+// NewScopedAllocation allocates a GPU through the manager and wraps the result in a ScopedAllocation
+func NewScopedAllocation(ctx context.Context, manager GPUManager, request *types.AllocationRequest) (*ScopedAllocation, error) {
+	result, err := manager.AllocateGPU(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	handle := &ScopedAllocation{
+		scopedAllocationState: &scopedAllocationState{
+			manager: manager,
+			result:  result,
+		},
+	}
+
+	runtime.SetFinalizer(handle, func(h *ScopedAllocation) {
+		h.releaseLeaked()
+	})
+
+	return handle, nil
+}
+
+// Allocation returns the underlying GPU allocation
+func (s *ScopedAllocation) Allocation() *types.GPUAllocation {
+	return s.result.Allocation
+}
+
+// Result returns the allocation result returned by the manager
+func (s *ScopedAllocation) Result() *types.AllocationResult {
+	return s.result
+}
+
+// Release releases the allocation. It is safe to call more than once; only the
+// first call releases and its error is returned on every call.
+func (s *ScopedAllocation) Release(ctx context.Context) error {
+	runtime.SetFinalizer(s, nil)
+	s.release(ctx)
+	return s.err
+}
+
+// release releases the allocation exactly once
+func (s *scopedAllocationState) release(ctx context.Context) {
+	s.once.Do(func() {
+		s.err = s.manager.ReleaseGPU(ctx, s.result.Allocation.ID)
+	})
+}
+
+// releaseLeaked releases an allocation whose handle was garbage collected without Release
+func (s *scopedAllocationState) releaseLeaked() {
+	scopedAllocationLogf("WARNING: allocation %s on GPU %s was garbage collected without being released; reclaiming it\n",
+		s.result.Allocation.ID, s.result.Allocation.DeviceID)
+
+	s.release(context.Background())
+	if s.err != nil {
+		scopedAllocationLogf("Error reclaiming leaked allocation %s: %v\n", s.result.Allocation.ID, s.err)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Advanced Micro Devices, Inc.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScopedAllocationRelease(t *testing.T) {
+	manager := newTestAMDGPUManager(t, newTestGPUInfo("card0", 8*1024*1024*1024))
+	ctx := context.Background()
+
+	handle, err := NewScopedAllocation(ctx, manager, newTestAllocationRequest("scoped-1", 0.5))
+	if err != nil {
+		t.Fatalf("Failed to create scoped allocation: %v", err)
+	}
+
+	if handle.Allocation().DeviceID != "card0" {
+		t.Errorf("Expected allocation on card0, got %s", handle.Allocation().DeviceID)
+	}
+
+	if err := handle.Release(ctx); err != nil {
+		t.Fatalf("Failed to release scoped allocation: %v", err)
+	}
+
+	// Releasing twice is a no-op
+	if err := handle.Release(ctx); err != nil {
+		t.Errorf("Expected second release to succeed, got %v", err)
+	}
+
+	allocations, _ := manager.ListAllocations(ctx)
+	if len(allocations) != 0 {
+		t.Errorf("Expected 0 allocations after release, got %d", len(allocations))
+	}
+}
+
+func TestScopedAllocationReclaimedOnGC(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		logs []string
+	)
+	originalLogf := scopedAllocationLogf
+	scopedAllocationLogf = func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, fmt.Sprintf(format, args...))
+	}
+	defer func() { scopedAllocationLogf = originalLogf }()
+
+	manager := newTestAMDGPUManager(t, newTestGPUInfo("card0", 8*1024*1024*1024))
+	ctx := context.Background()
+
+	// Leak a handle by dropping it without calling Release
+	func() {
+		if _, err := NewScopedAllocation(ctx, manager, newTestAllocationRequest("leaked-1", 0.5)); err != nil {
+			t.Fatalf("Failed to create scoped allocation: %v", err)
+		}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		runtime.GC()
+
+		allocations, _ := manager.ListAllocations(ctx)
+		if len(allocations) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("Expected leaked allocation to be reclaimed after GC")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(logs) == 0 || !strings.Contains(logs[0], "leaked-1") || !strings.Contains(logs[0], "WARNING") {
+		t.Errorf("Expected a warning naming the leaked allocation, got %v", logs)
+	}
+}
@@ -0,0 +1,85 @@
+package reservation
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// BlackoutWindow is a recurring period during which no reservation may run,
+// such as a weekly maintenance window
+type BlackoutWindow struct {
+	Name     string
+	Days     []time.Weekday // Days the window starts on; empty means every day
+	Start    time.Duration  // Offset from midnight at which the window starts, e.g. 2*time.Hour
+	Duration time.Duration
+	GPUIDs   []string       // GPUs the window applies to; empty means all GPUs
+	Location *time.Location // Time zone Start is in; nil means UTC
+}
+
+// validateBlackoutWindow validates a blackout window configuration
+func validateBlackoutWindow(window BlackoutWindow) error {
+	if window.Name == "" {
+		return fmt.Errorf("blackout window name is required")
+	}
+	if window.Start < 0 || window.Start >= 24*time.Hour {
+		return fmt.Errorf("blackout window %q must start within the day, got %v", window.Name, window.Start)
+	}
+	if window.Duration <= 0 {
+		return fmt.Errorf("blackout window %q duration must be positive, got %v", window.Name, window.Duration)
+	}
+	if window.Duration > 7*24*time.Hour {
+		return fmt.Errorf("blackout window %q must last at most a week, got %v", window.Name, window.Duration)
+	}
+	for _, day := range window.Days {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("blackout window %q has invalid day of week: %d", window.Name, day)
+		}
+	}
+	return nil
+}
+
+// appliesTo reports whether the window covers a GPU
+func (w BlackoutWindow) appliesTo(gpuID string) bool {
+	return len(w.GPUIDs) == 0 || slices.Contains(w.GPUIDs, gpuID)
+}
+
+// overlap returns the start of the first occurrence of the window that
+// overlaps [start, end), if any
+func (w BlackoutWindow) overlap(start, end time.Time) (time.Time, bool) {
+	location := w.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	// An occurrence starting up to Duration before start can still be in progress
+	from := start.Add(-w.Duration).In(location)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if len(w.Days) > 0 && !slices.Contains(w.Days, day.Weekday()) {
+			continue
+		}
+
+		windowStart := day.Add(w.Start)
+		if windowStart.Before(end) && start.Before(windowStart.Add(w.Duration)) {
+			return windowStart, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// checkBlackouts returns an error naming the first configured blackout window
+// a reservation on gpuID from start for duration would overlap
+func (r *GPUReservationManager) checkBlackouts(gpuID string, start time.Time, duration time.Duration) error {
+	end := start.Add(duration)
+	for _, window := range r.config.Blackouts {
+		if !window.appliesTo(gpuID) {
+			continue
+		}
+		if windowStart, overlaps := window.overlap(start, end); overlaps {
+			return fmt.Errorf("reservation overlaps blackout window %q from %v to %v",
+				window.Name, windowStart, windowStart.Add(window.Duration))
+		}
+	}
+	return nil
+}
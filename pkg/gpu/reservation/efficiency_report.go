@@ -0,0 +1,135 @@
+package reservation
+
+import (
+	"fmt"
+	"time"
+)
+
+// UtilizationSample is a single utilization measurement for the GPU share held by a reservation
+type UtilizationSample struct {
+	Timestamp time.Time
+	// Utilization is the fraction (0-1) of the reserved share that was in use
+	Utilization float64
+}
+
+// UtilizationSource provides utilization history for reservations, typically by
+// correlating a reservation with the allocations bound to it and their metrics
+type UtilizationSource interface {
+	// GetUtilizationHistory returns the samples recorded for a reservation between from and to
+	GetUtilizationHistory(reservation *GPUReservation, from, to time.Time) ([]UtilizationSample, error)
+}
+
+// ReservationEfficiency reports reserved versus utilized GPU time for a single reservation
+type ReservationEfficiency struct {
+	ReservationID    string
+	GPUID            string
+	ReservedGPUHours float64
+	UtilizedGPUHours float64
+	Efficiency       float64
+	Samples          int
+}
+
+// EfficiencyReport reports reserved versus utilized GPU time for a user over a window
+type EfficiencyReport struct {
+	UserID           string
+	WindowStart      time.Time
+	WindowEnd        time.Time
+	Reservations     []*ReservationEfficiency
+	ReservedGPUHours float64
+	UtilizedGPUHours float64
+	Efficiency       float64
+}
+
+// SetUtilizationSource sets the source used to build efficiency reports
+func (r *GPUReservationManager) SetUtilizationSource(source UtilizationSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.utilizationSource = source
+}
+
+// GetUserEfficiencyReport returns the reserved and utilized GPU-hours of a user's
+// reservations over the trailing window, per reservation and in aggregate
+func (r *GPUReservationManager) GetUserEfficiencyReport(userID string, window time.Duration) (*EfficiencyReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.utilizationSource == nil {
+		return nil, fmt.Errorf("no utilization source configured")
+	}
+
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive, got %v", window)
+	}
+
+	windowEnd := r.now()
+	report := &EfficiencyReport{
+		UserID:      userID,
+		WindowStart: windowEnd.Add(-window),
+		WindowEnd:   windowEnd,
+	}
+
+	for _, reservation := range r.reservations {
+		if reservation.UserID != userID || reservation.Status == ReservationStatusCancelled {
+			continue
+		}
+
+		// Only count the part of the reservation that falls inside the window
+		from := maxTime(reservation.StartTime, report.WindowStart)
+		to := minTime(reservation.EndTime, report.WindowEnd)
+		if !from.Before(to) {
+			continue
+		}
+
+		samples, err := r.utilizationSource.GetUtilizationHistory(reservation, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get utilization for reservation %s: %w", reservation.ID, err)
+		}
+
+		entry := &ReservationEfficiency{
+			ReservationID:    reservation.ID,
+			GPUID:            reservation.GPUID,
+			ReservedGPUHours: reservation.Fraction * to.Sub(from).Hours(),
+			Samples:          len(samples),
+		}
+
+		// Utilized time is the reserved time scaled by the mean sampled utilization
+		if len(samples) > 0 {
+			var total float64
+			for _, sample := range samples {
+				total += sample.Utilization
+			}
+			entry.UtilizedGPUHours = entry.ReservedGPUHours * total / float64(len(samples))
+		}
+
+		if entry.ReservedGPUHours > 0 {
+			entry.Efficiency = entry.UtilizedGPUHours / entry.ReservedGPUHours
+		}
+
+		report.Reservations = append(report.Reservations, entry)
+		report.ReservedGPUHours += entry.ReservedGPUHours
+		report.UtilizedGPUHours += entry.UtilizedGPUHours
+	}
+
+	if report.ReservedGPUHours > 0 {
+		report.Efficiency = report.UtilizedGPUHours / report.ReservedGPUHours
+	}
+
+	return report, nil
+}
+
+// maxTime returns the later of two times
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// minTime returns the earlier of two times
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
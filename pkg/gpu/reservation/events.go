@@ -0,0 +1,116 @@
+package reservation
+
+// EventHandler is notified of reservation lifecycle transitions, including those
+// made by the background activation and expiry loop. Handlers are called in
+// transition order after the manager lock has been released, so they may call
+// back into the manager. They receive a snapshot of the reservation taken at the
+// transition.
+type EventHandler interface {
+	OnCreated(reservation *GPUReservation)
+	OnActivated(reservation *GPUReservation)
+	OnCompleted(reservation *GPUReservation)
+	OnCancelled(reservation *GPUReservation)
+	OnExpired(reservation *GPUReservation)
+}
+
+// QueuePositionHandler may be implemented by an EventHandler to be told when a
+// queued reservation moves up its GPU's waitlist
+type QueuePositionHandler interface {
+	OnQueuePositionChanged(reservation *GPUReservation, position int)
+}
+
+// eventType identifies a reservation lifecycle transition
+type eventType int
+
+const (
+	eventCreated eventType = iota
+	eventActivated
+	eventCompleted
+	eventCancelled
+	eventExpired
+	eventQueuePositionChanged
+)
+
+// reservationEvent is a transition waiting to be dispatched to the event handler
+type reservationEvent struct {
+	eventType   eventType
+	reservation GPUReservation
+	position    int // New waitlist position for eventQueuePositionChanged
+}
+
+// emit records a transition for dispatch once r.mu is released. Callers must hold r.mu.
+func (r *GPUReservationManager) emit(eventType eventType, reservation *GPUReservation) {
+	if r.config.EventHandler == nil {
+		return
+	}
+
+	r.events = append(r.events, reservationEvent{eventType: eventType, reservation: *reservation})
+}
+
+// emitPositionChanges records a position change for every reservation queued on
+// a GPU whose waitlist position improved since before was taken with
+// waitlistPositions. Callers must hold r.mu.
+func (r *GPUReservationManager) emitPositionChanges(gpuID string, before map[string]int) {
+	if r.config.EventHandler == nil {
+		return
+	}
+
+	for position, reservation := range r.orderedWaitlist(gpuID) {
+		if previous, queued := before[reservation.ID]; queued && position+1 < previous {
+			r.events = append(r.events, reservationEvent{
+				eventType:   eventQueuePositionChanged,
+				reservation: *reservation,
+				position:    position + 1,
+			})
+		}
+	}
+}
+
+// emitStatus records the transition into a reservation's current status, if that
+// status has an event. Callers must hold r.mu.
+func (r *GPUReservationManager) emitStatus(reservation *GPUReservation) {
+	switch reservation.Status {
+	case ReservationStatusActive:
+		r.emit(eventActivated, reservation)
+	case ReservationStatusCompleted:
+		r.emit(eventCompleted, reservation)
+	case ReservationStatusCancelled:
+		r.emit(eventCancelled, reservation)
+	case ReservationStatusExpired:
+		r.emit(eventExpired, reservation)
+	}
+}
+
+// dispatchEvents delivers recorded transitions to the event handler. It must be
+// called without r.mu held, typically deferred before the lock is taken.
+func (r *GPUReservationManager) dispatchEvents() {
+	handler := r.config.EventHandler
+	if handler == nil {
+		return
+	}
+
+	r.mu.Lock()
+	events := r.events
+	r.events = nil
+	r.mu.Unlock()
+
+	for _, event := range events {
+		reservation := event.reservation
+		switch event.eventType {
+		case eventCreated:
+			handler.OnCreated(&reservation)
+		case eventActivated:
+			handler.OnActivated(&reservation)
+		case eventCompleted:
+			handler.OnCompleted(&reservation)
+		case eventCancelled:
+			handler.OnCancelled(&reservation)
+		case eventExpired:
+			handler.OnExpired(&reservation)
+		case eventQueuePositionChanged:
+			if positionHandler, ok := handler.(QueuePositionHandler); ok {
+				positionHandler.OnQueuePositionChanged(&reservation, event.position)
+			}
+		}
+	}
+}
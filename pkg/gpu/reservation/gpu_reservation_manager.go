@@ -2,10 +2,18 @@ package reservation
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/silogen/kaiwo/pkg/gpu/types"
 )
 
@@ -18,12 +26,56 @@ const (
 	ReservationStatusCompleted ReservationStatus = "completed"
 	ReservationStatusCancelled ReservationStatus = "cancelled"
 	ReservationStatusExpired   ReservationStatus = "expired"
+	// ReservationStatusQueued is a reservation waiting on the waitlist for a conflicting slot to free up
+	ReservationStatusQueued ReservationStatus = "queued"
+	// ReservationStatusHeld is a suspended reservation that frees its slot until HeldUntil
+	ReservationStatusHeld ReservationStatus = "held"
 )
 
 const (
 	ConflictResolutionPolicyStrict   = "strict"
 	ConflictResolutionPolicyFlexible = "flexible"
 	ConflictResolutionPolicyOverlap  = "overlap"
+	// ConflictResolutionPolicyPriority admits a request that only conflicts with
+	// reservations of lower priority, letting it overlap them, and otherwise
+	// behaves like ConflictResolutionPolicyStrict
+	ConflictResolutionPolicyPriority = "priority"
+)
+
+// Placeholders supported in ReservationManagerConfig.ReservationIDTemplate
+const (
+	ReservationIDPlaceholderTenant    = "{tenant}"
+	ReservationIDPlaceholderUser      = "{user}"
+	ReservationIDPlaceholderGPU       = "{gpu}"
+	ReservationIDPlaceholderWorkload  = "{workload}"
+	ReservationIDPlaceholderUUID      = "{uuid}"
+	ReservationIDPlaceholderTimestamp = "{timestamp}"
+)
+
+// fractionTolerance absorbs floating point error when summing GPU fractions
+const fractionTolerance = 1e-9
+
+// DefaultReservationIDTemplate is the ID template used when none is configured
+const DefaultReservationIDTemplate = "res-{user}-{gpu}-{timestamp}"
+
+var reservationIDPlaceholderRegex = regexp.MustCompile(`\{[^{}]*\}`)
+
+// Errors wrapped by reservation manager methods, so that callers such as an API
+// server can tell failures apart with errors.Is
+var (
+	// ErrReservationNotFound means no reservation has the requested ID
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrInvalidReservation means a reservation request failed validation
+	ErrInvalidReservation = errors.New("invalid reservation request")
+	// ErrReservationConflict means a request conflicts with existing reservations
+	// under the strict conflict policy
+	ErrReservationConflict = errors.New("reservation conflicts detected")
+	// ErrLimitExceeded means a reservation would exceed a per-user or per-GPU
+	// reservation limit or a capacity partition
+	ErrLimitExceeded = errors.New("reservation limit exceeded")
+	// ErrInvalidTransition means a reservation cannot be moved to the requested
+	// status from the one it is in
+	ErrInvalidTransition = errors.New("invalid reservation status transition")
 )
 
 // ReservationPriority represents the priority of a reservation
@@ -39,6 +91,8 @@ const (
 // GPUReservation represents a GPU reservation
 type GPUReservation struct {
 	ID             string
+	SeriesID       string // Shared by the occurrences of a recurring reservation
+	TenantID       string
 	UserID         string
 	WorkloadID     string
 	GPUID          string
@@ -53,10 +107,16 @@ type GPUReservation struct {
 	Annotations    map[string]string
 	IsolationType  string // "time-slicing", "none"
 	SharingEnabled bool
+	AllocationIDs  []string // Allocations created when the reservation was activated
+	DependsOn      []string // Reservations that must complete before this one activates
+	SLA            *ReservationSLA
+	ActivatedAt    time.Time
+	HeldUntil      time.Time // When a held reservation returns to pending
 }
 
 // ReservationRequest represents a request to create a GPU reservation
 type ReservationRequest struct {
+	TenantID       string // Used to fill {tenant} in the reservation ID template
 	UserID         string
 	WorkloadID     string
 	GPUID          string
@@ -68,21 +128,68 @@ type ReservationRequest struct {
 	Annotations    map[string]string
 	IsolationType  string
 	SharingEnabled bool
+	DependsOn      []string // Reservation IDs that must complete before this one starts
+	SLA            *ReservationSLA
+	Recurrence     *RecurrenceRule // Repeats the reservation; StartTime is the first occurrence
 }
 
+// ConflictSeverity classifies how serious a reservation conflict is
+type ConflictSeverity string
+
+const (
+	// ConflictSeverityHard means the overlapping reservations cannot share the GPU
+	ConflictSeverityHard ConflictSeverity = "hard"
+	// ConflictSeveritySoft means the overlap still fits on the GPU under sharing
+	ConflictSeveritySoft ConflictSeverity = "soft"
+)
+
 // ReservationConflict represents a conflict between reservations
 type ReservationConflict struct {
 	ReservationID           string
 	ConflictType            string
+	Severity                ConflictSeverity
 	Message                 string
 	ConflictingReservations []string
+	CombinedFraction        float64             // Fraction in use during the overlap if the request is admitted
+	CombinedMemoryRequest   int64               // Memory in MiB in use during the overlap if the request is admitted
+	RequestPriority         ReservationPriority // Priority of the request being checked
+	ConflictingPriority     ReservationPriority // Priority of the reservation it conflicts with
+}
+
+// Displaceable reports whether the conflicting reservation has lower priority
+// than the request, so that the priority policy lets the request overlap it
+func (c *ReservationConflict) Displaceable() bool {
+	return c.ConflictingPriority < c.RequestPriority
 }
 
 // GPUReservationManager manages GPU reservations
 type GPUReservationManager struct {
-	reservations map[string]*GPUReservation
-	config       ReservationManagerConfig
-	mu           sync.RWMutex
+	reservations      map[string]*GPUReservation
+	waitlists         map[string][]string // GPU ID -> queued reservation IDs in arrival order
+	config            ReservationManagerConfig
+	utilizationSource UtilizationSource
+	allocator         ReservationAllocator
+	fractionValidator map[string]FractionValidator // GPU ID -> partition-aware fraction check
+	slaBreaches       []*SLABreach
+	slaBreachHandler  func(*SLABreach)
+	now               func() time.Time
+	mu                sync.RWMutex
+	done              chan struct{}
+	stopped           chan struct{} // Closed once the cleanup goroutine has exited
+	stopOnce          sync.Once
+	events            []reservationEvent // Transitions waiting to be dispatched to config.EventHandler
+}
+
+// ReservationAllocator places and releases the GPU allocations backing reservations
+type ReservationAllocator interface {
+	Allocate(deviceID string, request *types.AllocationRequest) (*types.GPUAllocation, error)
+	Release(allocationID string) error
+}
+
+// FractionValidator checks that a fraction is valid for a GPU's partitioning,
+// e.g. MI300XFractionalAllocator
+type FractionValidator interface {
+	ValidateFraction(deviceID string, fraction float64) error
 }
 
 // ReservationManagerConfig contains configuration for the reservation manager
@@ -90,14 +197,25 @@ type ReservationManagerConfig struct {
 	MaxReservationsPerGPU    int
 	MaxReservationsPerUser   int
 	DefaultReservationWindow time.Duration
-	ConflictResolutionPolicy string // "strict", "flexible", "overlap"
+	ConflictResolutionPolicy string // "strict", "flexible", "overlap", "priority"
 	EnablePreemption         bool
 	MaxReservationDuration   time.Duration
 	CleanupInterval          time.Duration
+	ActivationInterval       time.Duration    // How often pending reservations are checked for their start time
+	ReservationIDTemplate    string           // e.g. "{tenant}-res-{uuid}"
+	EnableWaitlist           bool             // Queue conflicting requests under the strict policy instead of rejecting them
+	Store                    ReservationStore // Optional; reservations are kept in memory only when nil
+	// CapacityPartitions reserves a share of each GPU for reservations at or above
+	// a priority, e.g. {ReservationPriorityUrgent: 0.25}
+	CapacityPartitions types.CapacityPartitions
+	EventHandler       EventHandler // Optional; notified of reservation lifecycle transitions
+	// Blackouts are recurring windows, such as maintenance, that no reservation may overlap
+	Blackouts []BlackoutWindow
 }
 
-// NewGPUReservationManager creates a new GPU reservation manager
-func NewGPUReservationManager(config ReservationManagerConfig) *GPUReservationManager {
+// SetReservationManagerConfigDefaults fills unset fields of a reservation manager
+// configuration with their defaults
+func SetReservationManagerConfigDefaults(config *ReservationManagerConfig) {
 	if config.MaxReservationsPerGPU == 0 {
 		config.MaxReservationsPerGPU = 10
 	}
@@ -116,32 +234,118 @@ func NewGPUReservationManager(config ReservationManagerConfig) *GPUReservationMa
 	if config.CleanupInterval == 0 {
 		config.CleanupInterval = 1 * time.Hour
 	}
+	if config.ActivationInterval == 0 {
+		config.ActivationInterval = 1 * time.Minute
+	}
+	if config.ReservationIDTemplate == "" {
+		config.ReservationIDTemplate = DefaultReservationIDTemplate
+	}
+}
+
+// ValidateReservationManagerConfig validates a reservation manager configuration
+// after defaults have been applied
+func ValidateReservationManagerConfig(config *ReservationManagerConfig) error {
+	if config.MaxReservationsPerGPU < 0 {
+		return fmt.Errorf("max reservations per GPU must be non-negative, got %d", config.MaxReservationsPerGPU)
+	}
+	if config.MaxReservationsPerUser < 0 {
+		return fmt.Errorf("max reservations per user must be non-negative, got %d", config.MaxReservationsPerUser)
+	}
+
+	switch config.ConflictResolutionPolicy {
+	case ConflictResolutionPolicyStrict, ConflictResolutionPolicyFlexible, ConflictResolutionPolicyOverlap,
+		ConflictResolutionPolicyPriority:
+		// Valid policy
+	default:
+		return fmt.Errorf("unknown conflict resolution policy: %s", config.ConflictResolutionPolicy)
+	}
+
+	if config.MaxReservationDuration <= 0 {
+		return fmt.Errorf("max reservation duration must be positive, got %v", config.MaxReservationDuration)
+	}
+	if config.CleanupInterval <= 0 {
+		return fmt.Errorf("cleanup interval must be positive, got %v", config.CleanupInterval)
+	}
+	if config.ActivationInterval <= 0 {
+		return fmt.Errorf("activation interval must be positive, got %v", config.ActivationInterval)
+	}
+
+	if err := validateReservationIDTemplate(config.ReservationIDTemplate); err != nil {
+		return fmt.Errorf("invalid reservation ID template: %w", err)
+	}
+	if err := types.ValidateCapacityPartitions(config.CapacityPartitions); err != nil {
+		return fmt.Errorf("invalid capacity partitions: %w", err)
+	}
+	for _, window := range config.Blackouts {
+		if err := validateBlackoutWindow(window); err != nil {
+			return fmt.Errorf("invalid blackout: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// NewGPUReservationManager creates a new GPU reservation manager
+func NewGPUReservationManager(config ReservationManagerConfig) (*GPUReservationManager, error) {
+	SetReservationManagerConfigDefaults(&config)
+
+	if err := ValidateReservationManagerConfig(&config); err != nil {
+		return nil, err
+	}
 
 	manager := &GPUReservationManager{
-		reservations: make(map[string]*GPUReservation),
-		config:       config,
+		reservations:      make(map[string]*GPUReservation),
+		waitlists:         make(map[string][]string),
+		fractionValidator: make(map[string]FractionValidator),
+		config:            config,
+		now:               time.Now,
+		done:              make(chan struct{}),
+		stopped:           make(chan struct{}),
 	}
 
+	if err := manager.loadFromStore(); err != nil {
+		return nil, err
+	}
+	manager.dispatchEvents()
+
 	// Start cleanup goroutine
 	go manager.cleanupExpiredReservations()
 
-	return manager
+	return manager, nil
 }
 
-// CreateReservation creates a new GPU reservation
+// CreateReservation creates a new GPU reservation. A request with a recurrence
+// rule creates one reservation per occurrence, linked by a shared SeriesID, and
+// returns the first of them.
 func (r *GPUReservationManager) CreateReservation(ctx context.Context, request *ReservationRequest) (*GPUReservation, error) {
+	defer r.dispatchEvents()
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Validate request
 	if err := r.validateReservationRequest(request); err != nil {
-		return nil, fmt.Errorf("invalid reservation request: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidReservation, err)
+	}
+
+	occurrences, err := r.expandRecurrence(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid recurrence: %w", ErrInvalidReservation, err)
 	}
 
-	// Check for conflicts
-	conflicts := r.checkConflicts(request)
-	if len(conflicts) > 0 && r.config.ConflictResolutionPolicy == ConflictResolutionPolicyStrict {
-		return nil, fmt.Errorf("reservation conflicts detected: %v", conflicts)
+	// Check for conflicts against every occurrence
+	var conflicts []*ReservationConflict
+	for _, occurrence := range occurrences {
+		if err := r.checkCapacityPartitions(occurrence); err != nil {
+			return nil, fmt.Errorf("%w: capacity partition exceeded: %w", ErrLimitExceeded, err)
+		}
+		conflicts = append(conflicts, r.checkConflicts(occurrence)...)
+	}
+	queued := false
+	if len(conflicts) > 0 && r.conflictsBlock(conflicts) {
+		if !r.config.EnableWaitlist || request.Recurrence != nil {
+			return nil, fmt.Errorf("%w: %v", ErrReservationConflict, conflicts)
+		}
+		queued = true
 	}
 
 	// Check user limits
@@ -154,44 +358,83 @@ func (r *GPUReservationManager) CreateReservation(ctx context.Context, request *
 		return nil, fmt.Errorf("GPU limits exceeded: %w", err)
 	}
 
-	// Calculate end time
-	endTime := request.StartTime.Add(request.Duration)
+	id := r.generateReservationID(request)
+	var seriesID string
+	if request.Recurrence != nil {
+		seriesID = "series-" + id
+	}
+
+	reservations := make([]*GPUReservation, 0, len(occurrences))
+	for i, occurrence := range occurrences {
+		// Create reservation
+		reservation := &GPUReservation{
+			ID:             id,
+			SeriesID:       seriesID,
+			TenantID:       occurrence.TenantID,
+			UserID:         occurrence.UserID,
+			WorkloadID:     occurrence.WorkloadID,
+			GPUID:          occurrence.GPUID,
+			Fraction:       occurrence.Fraction,
+			MemoryRequest:  occurrence.MemoryRequest,
+			StartTime:      occurrence.StartTime,
+			EndTime:        occurrence.StartTime.Add(occurrence.Duration),
+			Priority:       occurrence.Priority,
+			Status:         ReservationStatusPending,
+			CreatedAt:      r.now(),
+			UpdatedAt:      r.now(),
+			Annotations:    occurrence.Annotations,
+			IsolationType:  occurrence.IsolationType,
+			SharingEnabled: occurrence.SharingEnabled,
+			DependsOn:      occurrence.DependsOn,
+			SLA:            occurrence.SLA,
+		}
+		if seriesID != "" {
+			reservation.ID = fmt.Sprintf("%s-%d", id, i)
+		}
+
+		if err := r.validateDependencies(reservation.ID, reservation.DependsOn); err != nil {
+			return nil, fmt.Errorf("%w: invalid dependencies: %w", ErrInvalidReservation, err)
+		}
+
+		// Handle conflicts based on policy
+		if len(conflicts) > 0 && !queued {
+			if err := r.resolveConflicts(reservation, conflicts); err != nil {
+				return nil, fmt.Errorf("failed to resolve conflicts: %w", err)
+			}
+		}
 
-	// Create reservation
-	reservation := &GPUReservation{
-		ID:             r.generateReservationID(request),
-		UserID:         request.UserID,
-		WorkloadID:     request.WorkloadID,
-		GPUID:          request.GPUID,
-		Fraction:       request.Fraction,
-		MemoryRequest:  request.MemoryRequest,
-		StartTime:      request.StartTime,
-		EndTime:        endTime,
-		Priority:       request.Priority,
-		Status:         ReservationStatusPending,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-		Annotations:    request.Annotations,
-		IsolationType:  request.IsolationType,
-		SharingEnabled: request.SharingEnabled,
+		reservations = append(reservations, reservation)
 	}
 
-	// Handle conflicts based on policy
-	if len(conflicts) > 0 {
-		if err := r.resolveConflicts(reservation, conflicts); err != nil {
-			return nil, fmt.Errorf("failed to resolve conflicts: %w", err)
+	eventCount := len(r.events)
+	for _, reservation := range reservations {
+		// Add reservation
+		r.reservations[reservation.ID] = reservation
+		r.emit(eventCreated, reservation)
+
+		// Update status if reservation starts immediately
+		if queued {
+			reservation.Status = ReservationStatusQueued
+		} else if !r.now().Before(reservation.StartTime) && r.dependenciesComplete(reservation) {
+			r.markActive(reservation)
 		}
 	}
 
-	// Add reservation
-	r.reservations[reservation.ID] = reservation
+	for _, reservation := range reservations {
+		if err := r.persist(reservation); err != nil {
+			for _, added := range reservations {
+				delete(r.reservations, added.ID)
+			}
+			r.events = r.events[:eventCount]
+			return nil, err
+		}
+	}
 
-	// Update status if reservation starts immediately
-	if time.Now().After(request.StartTime) || time.Now().Equal(request.StartTime) {
-		reservation.Status = ReservationStatusActive
+	if queued {
+		r.enqueue(reservations[0])
 	}
 
-	return reservation, nil
+	return reservations[0], nil
 }
 
 // GetReservation returns a reservation by ID
@@ -203,8 +446,10 @@ func (r *GPUReservationManager) GetReservation(id string) (*GPUReservation, bool
 	return reservation, exists
 }
 
-// ListReservations returns all reservations with optional filters
-func (r *GPUReservationManager) ListReservations(filters *ReservationFilters) []*GPUReservation {
+// ListReservations returns the requested page of reservations matching the
+// optional filters, in a deterministic order, along with the total number of
+// matching reservations before paging
+func (r *GPUReservationManager) ListReservations(filters *ReservationFilters) ([]*GPUReservation, int) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -216,20 +461,127 @@ func (r *GPUReservationManager) ListReservations(filters *ReservationFilters) []
 		}
 	}
 
+	sortReservations(reservations, filters)
+	total := len(reservations)
+
+	if filters == nil {
+		return reservations, total
+	}
+
+	offset := min(max(filters.Offset, 0), total)
+	reservations = reservations[offset:]
+	if filters.Limit > 0 && filters.Limit < len(reservations) {
+		reservations = reservations[:filters.Limit]
+	}
+
+	return reservations, total
+}
+
+// sortReservations orders reservations by the filters' sort field, breaking ties
+// by ID so that pages are stable. Without a sort field reservations are ordered
+// by ascending start time.
+func sortReservations(reservations []*GPUReservation, filters *ReservationFilters) {
+	sortBy := ReservationSortByStartTime
+	ascending := true
+	if filters != nil && filters.SortBy != "" {
+		sortBy = filters.SortBy
+		ascending = filters.Ascending
+	}
+
+	sort.SliceStable(reservations, func(i, j int) bool {
+		a, b := reservations[i], reservations[j]
+
+		var cmp int
+		switch sortBy {
+		case ReservationSortByCreatedAt:
+			cmp = a.CreatedAt.Compare(b.CreatedAt)
+		case ReservationSortByPriority:
+			cmp = int(a.Priority) - int(b.Priority)
+		default:
+			cmp = a.StartTime.Compare(b.StartTime)
+		}
+
+		if cmp == 0 {
+			return a.ID < b.ID
+		}
+		if ascending {
+			return cmp < 0
+		}
+		return cmp > 0
+	})
+}
+
+// GetActiveReservationsAt returns the reservations on a GPU whose
+// [StartTime, EndTime) window contains the given instant and whose status
+// occupies capacity, highest priority first
+func (r *GPUReservationManager) GetActiveReservationsAt(gpuID string, at time.Time) []*GPUReservation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var reservations []*GPUReservation
+	for _, reservation := range r.reservations {
+		if reservation.GPUID == gpuID && occupiesAt(reservation, at) {
+			reservations = append(reservations, reservation)
+		}
+	}
+
+	sortByPriority(reservations)
 	return reservations
 }
 
+// GetOccupancyAt returns, for every GPU with at least one reservation occupying
+// it at the given instant, those reservations highest priority first
+func (r *GPUReservationManager) GetOccupancyAt(at time.Time) map[string][]*GPUReservation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	occupancy := make(map[string][]*GPUReservation)
+	for _, reservation := range r.reservations {
+		if occupiesAt(reservation, at) {
+			occupancy[reservation.GPUID] = append(occupancy[reservation.GPUID], reservation)
+		}
+	}
+
+	for _, reservations := range occupancy {
+		sortByPriority(reservations)
+	}
+	return occupancy
+}
+
+// occupiesAt reports whether a reservation holds GPU capacity at the given instant
+func occupiesAt(reservation *GPUReservation, at time.Time) bool {
+	if reservation.Status != ReservationStatusPending && reservation.Status != ReservationStatusActive {
+		return false
+	}
+	return !at.Before(reservation.StartTime) && at.Before(reservation.EndTime)
+}
+
+// sortByPriority orders reservations by descending priority, then by start time
+func sortByPriority(reservations []*GPUReservation) {
+	sort.Slice(reservations, func(i, j int) bool {
+		if reservations[i].Priority != reservations[j].Priority {
+			return reservations[i].Priority > reservations[j].Priority
+		}
+		if !reservations[i].StartTime.Equal(reservations[j].StartTime) {
+			return reservations[i].StartTime.Before(reservations[j].StartTime)
+		}
+		return reservations[i].ID < reservations[j].ID
+	})
+}
+
 // UpdateReservation updates an existing reservation
 func (r *GPUReservationManager) UpdateReservation(id string, updates map[string]interface{}) (*GPUReservation, error) {
+	defer r.dispatchEvents()
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	reservation, exists := r.reservations[id]
 	if !exists {
-		return nil, fmt.Errorf("reservation %s not found", id)
+		return nil, fmt.Errorf("%w: %s", ErrReservationNotFound, id)
 	}
 
 	// Apply updates
+	statusChanged := false
 	for key, value := range updates {
 		switch key {
 		case "fraction":
@@ -253,56 +605,578 @@ func (r *GPUReservationManager) UpdateReservation(id string, updates map[string]
 				reservation.Priority = priority
 			}
 		case "status":
-			if status, ok := value.(ReservationStatus); ok {
-				reservation.Status = status
+			if status, ok := value.(ReservationStatus); ok && status != reservation.Status {
+				if err := r.setStatus(reservation, status); err != nil {
+					return nil, err
+				}
+				statusChanged = true
 			}
 		case "annotations":
 			if annotations, ok := value.(map[string]string); ok {
 				reservation.Annotations = annotations
 			}
+		case "depends_on":
+			if dependsOn, ok := value.([]string); ok {
+				if err := r.validateDependencies(id, dependsOn); err != nil {
+					return nil, fmt.Errorf("%w: invalid dependencies: %w", ErrInvalidReservation, err)
+				}
+				reservation.DependsOn = dependsOn
+			}
+		}
+	}
+
+	reservation.UpdatedAt = r.now()
+
+	if err := r.persist(reservation); err != nil {
+		return nil, err
+	}
+
+	// A reservation leaving its slot hands it on, as through CompleteReservation
+	// and CancelReservation
+	if statusChanged {
+		if reservation.Status == ReservationStatusCompleted {
+			r.activateDependents(id)
 		}
+		r.promoteWaitlist(reservation.GPUID)
 	}
 
-	reservation.UpdatedAt = time.Now()
 	return reservation, nil
 }
 
-// CancelReservation cancels a reservation
-func (r *GPUReservationManager) CancelReservation(id string) error {
+// setStatus moves a reservation to a status set directly through
+// UpdateReservation. Completing or cancelling it goes through complete and
+// cancel, any other status that stops it holding its slot releases its
+// allocations, and the GPU's waitlist is kept in step with queued status.
+// Callers must hold r.mu.
+func (r *GPUReservationManager) setStatus(reservation *GPUReservation, status ReservationStatus) error {
+	switch status {
+	case ReservationStatusCompleted:
+		return r.complete(reservation)
+	case ReservationStatusCancelled:
+		return r.cancel(reservation)
+	case ReservationStatusPending, ReservationStatusActive:
+	default:
+		if err := r.releaseAllocations(reservation); err != nil {
+			return err
+		}
+	}
+
+	before := r.waitlistPositions(reservation.GPUID)
+	if reservation.Status == ReservationStatusQueued {
+		r.dequeue(reservation)
+	}
+	if status == ReservationStatusQueued {
+		r.enqueue(reservation)
+	}
+
+	reservation.Status = status
+	r.emitStatus(reservation)
+	r.emitPositionChanges(reservation.GPUID, before)
+
+	return nil
+}
+
+// CancelReservation cancels a reservation. With cancelSeries set, every
+// occurrence of the reservation's series that has not yet completed or been
+// cancelled is cancelled as well.
+func (r *GPUReservationManager) CancelReservation(id string, cancelSeries bool) error {
+	defer r.dispatchEvents()
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	reservation, exists := r.reservations[id]
 	if !exists {
-		return fmt.Errorf("reservation %s not found", id)
+		return fmt.Errorf("%w: %s", ErrReservationNotFound, id)
 	}
 
+	if !cancelSeries || reservation.SeriesID == "" {
+		if err := r.cancel(reservation); err != nil {
+			return err
+		}
+		r.promoteWaitlist(reservation.GPUID)
+		return nil
+	}
+
+	for _, occurrence := range r.seriesReservations(reservation.SeriesID) {
+		if occurrence.Status == ReservationStatusCompleted || occurrence.Status == ReservationStatusCancelled {
+			continue
+		}
+		if err := r.cancel(occurrence); err != nil {
+			return err
+		}
+	}
+	r.promoteWaitlist(reservation.GPUID)
+
+	return nil
+}
+
+// cancel releases a reservation's allocations and marks it cancelled. Callers
+// must hold r.mu.
+func (r *GPUReservationManager) cancel(reservation *GPUReservation) error {
 	if reservation.Status == ReservationStatusCompleted || reservation.Status == ReservationStatusCancelled {
-		return fmt.Errorf("cannot cancel reservation in status %s", reservation.Status)
+		return fmt.Errorf("%w: cannot cancel reservation in status %s", ErrInvalidTransition, reservation.Status)
+	}
+
+	// Release the allocations backing the reservation
+	if err := r.releaseAllocations(reservation); err != nil {
+		return err
+	}
+
+	wasQueued := reservation.Status == ReservationStatusQueued
+	before := r.waitlistPositions(reservation.GPUID)
+	if wasQueued {
+		r.dequeue(reservation)
 	}
 
 	reservation.Status = ReservationStatusCancelled
-	reservation.UpdatedAt = time.Now()
+	reservation.UpdatedAt = r.now()
+	r.emit(eventCancelled, reservation)
+
+	// Leaving the queue moves everyone behind the reservation up
+	if wasQueued {
+		r.emitPositionChanges(reservation.GPUID, before)
+	}
+
+	return r.persist(reservation)
+}
+
+// releaseAllocations releases the allocations backing a reservation and unlinks
+// them. Callers must hold r.mu.
+func (r *GPUReservationManager) releaseAllocations(reservation *GPUReservation) error {
+	if r.allocator == nil {
+		return nil
+	}
+
+	for _, allocationID := range reservation.AllocationIDs {
+		if err := r.allocator.Release(allocationID); err != nil {
+			return fmt.Errorf("failed to release allocation %s for reservation %s: %w", allocationID, reservation.ID, err)
+		}
+	}
+	reservation.AllocationIDs = nil
+
+	return nil
+}
+
+// DeleteReservation removes a reservation entirely, including from the store.
+// Active reservations are rejected unless force is set, in which case their
+// allocations are released first.
+func (r *GPUReservationManager) DeleteReservation(id string, force bool) error {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, exists := r.reservations[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrReservationNotFound, id)
+	}
+
+	if reservation.Status == ReservationStatusActive && !force {
+		return fmt.Errorf("%w: cannot delete active reservation %s without force", ErrInvalidTransition, id)
+	}
+
+	if dependent := r.waitingDependent(id); dependent != "" {
+		return fmt.Errorf("cannot delete reservation %s, reservation %s depends on it", id, dependent)
+	}
+
+	if err := r.releaseAllocations(reservation); err != nil {
+		return err
+	}
+
+	if err := r.remove(reservation); err != nil {
+		return err
+	}
+
+	r.promoteWaitlist(reservation.GPUID)
+
+	return nil
+}
+
+// PurgeTerminated deletes completed, cancelled and expired reservations that
+// reached that state more than olderThan ago, and returns how many were deleted
+func (r *GPUReservationManager) PurgeTerminated(olderThan time.Duration) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoffTime := r.now().Add(-olderThan)
+	purged := 0
+	for _, reservation := range r.reservations {
+		switch reservation.Status {
+		case ReservationStatusCompleted, ReservationStatusCancelled, ReservationStatusExpired:
+		default:
+			continue
+		}
+
+		// Keep reservations that pending dependents still need to see completed
+		if !reservation.UpdatedAt.Before(cutoffTime) || r.waitingDependent(reservation.ID) != "" {
+			continue
+		}
+
+		if err := r.remove(reservation); err != nil {
+			fmt.Printf("Error purging reservation %s: %v\n", reservation.ID, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged
+}
+
+// remove drops a reservation from the manager, its waitlist and the store.
+// Callers must hold r.mu.
+func (r *GPUReservationManager) remove(reservation *GPUReservation) error {
+	if r.config.Store != nil {
+		if err := r.config.Store.Delete(reservation.ID); err != nil {
+			return fmt.Errorf("failed to delete reservation %s from store: %w", reservation.ID, err)
+		}
+	}
+
+	if reservation.Status == ReservationStatusQueued {
+		before := r.waitlistPositions(reservation.GPUID)
+		r.dequeue(reservation)
+		r.emitPositionChanges(reservation.GPUID, before)
+	}
+	delete(r.reservations, reservation.ID)
 
 	return nil
 }
 
-// CompleteReservation marks a reservation as completed
+// Stop stops the background cleanup goroutine. It is safe to call more than once.
+func (r *GPUReservationManager) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+	})
+}
+
+// Shutdown stops the background cleanup goroutine and waits for it to exit
+func (r *GPUReservationManager) Shutdown(ctx context.Context) error {
+	r.Stop()
+
+	select {
+	case <-r.stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for reservation cleanup to stop: %w", ctx.Err())
+	}
+}
+
+// SetFractionValidator registers a validator that reservations on the GPU must
+// satisfy in addition to the generic fraction range check
+func (r *GPUReservationManager) SetFractionValidator(gpuID string, validator FractionValidator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fractionValidator[gpuID] = validator
+}
+
+// SetAllocator sets the allocator used to back activated reservations with GPU allocations
+func (r *GPUReservationManager) SetAllocator(allocator ReservationAllocator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.allocator = allocator
+}
+
+// ActivateReservation hands a reservation off to the allocator, creating an
+// allocation stamped with the reservation ID, and marks the reservation active
+func (r *GPUReservationManager) ActivateReservation(ctx context.Context, id string) (*types.GPUAllocation, error) {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.allocator == nil {
+		return nil, fmt.Errorf("no allocator configured")
+	}
+
+	reservation, exists := r.reservations[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrReservationNotFound, id)
+	}
+
+	return r.activate(reservation)
+}
+
+// Fulfill hands a reservation whose window has opened off to the allocator
+// set with SetAllocator, like ActivateReservation. The allocation is made on
+// the reserved GPU for the reserved fraction, memory and isolation, expires
+// with the reservation and is linked onto its AllocationIDs. Completing or
+// cancelling the reservation releases it.
+func (r *GPUReservationManager) Fulfill(reservationID string) (*types.GPUAllocation, error) {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.allocator == nil {
+		return nil, fmt.Errorf("no allocator configured")
+	}
+
+	reservation, exists := r.reservations[reservationID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrReservationNotFound, reservationID)
+	}
+
+	now := r.now()
+	if now.Before(reservation.StartTime) {
+		return nil, fmt.Errorf("reservation %s does not start until %s", reservationID, reservation.StartTime.Format(time.RFC3339))
+	}
+	if !now.Before(reservation.EndTime) {
+		return nil, fmt.Errorf("reservation %s ended at %s", reservationID, reservation.EndTime.Format(time.RFC3339))
+	}
+
+	return r.activate(reservation)
+}
+
+// activate allocates the GPU share of a pending or active reservation that is
+// not yet backed by an allocation and marks it active. Callers must hold r.mu
+// and have checked that an allocator is set.
+func (r *GPUReservationManager) activate(reservation *GPUReservation) (*types.GPUAllocation, error) {
+	id := reservation.ID
+
+	if reservation.Status != ReservationStatusPending && reservation.Status != ReservationStatusActive {
+		return nil, fmt.Errorf("%w: cannot activate reservation in status %s", ErrInvalidTransition, reservation.Status)
+	}
+
+	if len(reservation.AllocationIDs) > 0 {
+		return nil, fmt.Errorf("reservation %s is already backed by allocation %s", id, reservation.AllocationIDs[0])
+	}
+
+	expiresAt := reservation.EndTime
+	request := &types.AllocationRequest{
+		ID: fmt.Sprintf("%s-alloc", reservation.ID),
+		GPURequest: &types.GPURequest{
+			Fraction:       reservation.Fraction,
+			MemoryRequest:  reservation.MemoryRequest,
+			IsolationType:  types.GPUIsolationType(reservation.IsolationType),
+			SharingEnabled: reservation.SharingEnabled,
+			Priority:       int(reservation.Priority),
+			TenantID:       reservation.TenantID,
+		},
+		Priority:      int(reservation.Priority),
+		CreatedAt:     r.now(),
+		ExpiresAt:     &expiresAt,
+		ReservationID: reservation.ID,
+	}
+
+	allocation, err := r.allocator.Allocate(reservation.GPUID, request)
+	if err != nil {
+		r.recordActivationFailure(reservation, err)
+		return nil, fmt.Errorf("failed to allocate GPU %s for reservation %s: %w", reservation.GPUID, id, err)
+	}
+
+	reservation.AllocationIDs = append(reservation.AllocationIDs, allocation.ID)
+	if reservation.Status != ReservationStatusActive {
+		r.markActive(reservation)
+	}
+
+	if err := r.persist(reservation); err != nil {
+		return nil, err
+	}
+
+	return allocation, nil
+}
+
+// CompleteReservation marks a reservation as completed, releasing the
+// allocations backing it
 func (r *GPUReservationManager) CompleteReservation(id string) error {
+	defer r.dispatchEvents()
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	reservation, exists := r.reservations[id]
 	if !exists {
-		return fmt.Errorf("reservation %s not found", id)
+		return fmt.Errorf("%w: %s", ErrReservationNotFound, id)
+	}
+
+	if err := r.complete(reservation); err != nil {
+		return err
+	}
+
+	r.activateDependents(id)
+	r.promoteWaitlist(reservation.GPUID)
+
+	return nil
+}
+
+// complete releases a reservation's allocations and marks it completed. A queued
+// reservation leaves its GPU's waitlist first. Callers must hold r.mu.
+func (r *GPUReservationManager) complete(reservation *GPUReservation) error {
+	if reservation.Status != ReservationStatusActive && reservation.Status != ReservationStatusPending &&
+		reservation.Status != ReservationStatusQueued {
+		return fmt.Errorf("%w: cannot complete reservation in status %s", ErrInvalidTransition, reservation.Status)
+	}
+
+	if err := r.releaseAllocations(reservation); err != nil {
+		return err
+	}
+
+	wasQueued := reservation.Status == ReservationStatusQueued
+	before := r.waitlistPositions(reservation.GPUID)
+	if wasQueued {
+		r.dequeue(reservation)
 	}
 
 	reservation.Status = ReservationStatusCompleted
-	reservation.UpdatedAt = time.Now()
+	reservation.UpdatedAt = r.now()
+	r.emit(eventCompleted, reservation)
+
+	if wasQueued {
+		r.emitPositionChanges(reservation.GPUID, before)
+	}
+
+	return r.persist(reservation)
+}
+
+// loadFromStore rehydrates reservations from the configured store, expiring those
+// that ended while the manager was not running
+func (r *GPUReservationManager) loadFromStore() error {
+	if r.config.Store == nil {
+		return nil
+	}
+
+	reservations, err := r.config.Store.LoadAll()
+	if err != nil {
+		return fmt.Errorf("failed to load reservations: %w", err)
+	}
+
+	// Rebuild waitlists in arrival order
+	slices.SortStableFunc(reservations, func(a, b *GPUReservation) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	now := r.now()
+	for _, reservation := range reservations {
+		r.reservations[reservation.ID] = reservation
+		if reservation.Status == ReservationStatusQueued {
+			r.enqueue(reservation)
+		}
+
+		if reservation.EndTime.Before(now) && expires(reservation.Status) {
+			r.expire(reservation, now)
+			if err := r.persist(reservation); err != nil {
+				return err
+			}
+		}
+	}
 
 	return nil
 }
 
+// persist writes a reservation through to the configured store, if any.
+// Callers must hold r.mu.
+func (r *GPUReservationManager) persist(reservation *GPUReservation) error {
+	if r.config.Store == nil {
+		return nil
+	}
+
+	if err := r.config.Store.Save(reservation); err != nil {
+		return fmt.Errorf("failed to persist reservation %s: %w", reservation.ID, err)
+	}
+
+	return nil
+}
+
+// persistOrLog persists a reservation changed by a background transition, logging
+// failures since there is no caller to return them to. Callers must hold r.mu.
+func (r *GPUReservationManager) persistOrLog(reservation *GPUReservation) {
+	if r.config.Store == nil {
+		return
+	}
+
+	if err := r.config.Store.Save(reservation); err != nil {
+		fmt.Printf("Error persisting reservation %s: %v\n", reservation.ID, err)
+	}
+}
+
+// validateDependencies checks that every dependency exists and that depending on
+// them would not create a cycle back to the reservation. Callers must hold r.mu.
+func (r *GPUReservationManager) validateDependencies(id string, dependsOn []string) error {
+	for _, dependencyID := range dependsOn {
+		if dependencyID == id {
+			return fmt.Errorf("reservation %s cannot depend on itself", id)
+		}
+		if _, exists := r.reservations[dependencyID]; !exists {
+			return fmt.Errorf("dependency %s not found", dependencyID)
+		}
+	}
+
+	// Walk the dependency graph looking for a path back to the reservation
+	visited := make(map[string]bool)
+	stack := append([]string{}, dependsOn...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if current == id {
+			return fmt.Errorf("dependency cycle detected through reservation %s", id)
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		if reservation, exists := r.reservations[current]; exists {
+			stack = append(stack, reservation.DependsOn...)
+		}
+	}
+
+	return nil
+}
+
+// dependenciesComplete reports whether every dependency of a reservation has
+// completed. Callers must hold r.mu.
+func (r *GPUReservationManager) dependenciesComplete(reservation *GPUReservation) bool {
+	for _, dependencyID := range reservation.DependsOn {
+		dependency, exists := r.reservations[dependencyID]
+		if !exists || dependency.Status != ReservationStatusCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// hasActiveCapacity reports whether the reservation fits on its GPU alongside the
+// reservations already active there. Callers must hold r.mu.
+func (r *GPUReservationManager) hasActiveCapacity(reservation *GPUReservation) bool {
+	fraction := reservation.Fraction
+	for _, other := range r.reservations {
+		if other.ID != reservation.ID && other.GPUID == reservation.GPUID && other.Status == ReservationStatusActive {
+			fraction += other.Fraction
+		}
+	}
+	return fraction <= 1.0+fractionTolerance
+}
+
+// activateDependents activates pending reservations waiting on the given
+// reservation once all their dependencies have completed, their start time has
+// been reached and their GPU has capacity. Callers must hold r.mu.
+func (r *GPUReservationManager) activateDependents(id string) {
+	now := r.now()
+
+	for _, reservation := range r.reservations {
+		if reservation.Status != ReservationStatusPending || !slices.Contains(reservation.DependsOn, id) {
+			continue
+		}
+
+		if now.Before(reservation.StartTime) || !r.dependenciesComplete(reservation) || !r.hasActiveCapacity(reservation) {
+			continue
+		}
+
+		r.markActive(reservation)
+		r.persistOrLog(reservation)
+	}
+}
+
+// waitingDependent returns the ID of a pending or queued reservation that depends
+// on the given reservation, or "" if there is none. Callers must hold r.mu.
+func (r *GPUReservationManager) waitingDependent(id string) string {
+	for _, reservation := range r.reservations {
+		if (reservation.Status == ReservationStatusPending || reservation.Status == ReservationStatusQueued) &&
+			slices.Contains(reservation.DependsOn, id) {
+			return reservation.ID
+		}
+	}
+	return ""
+}
+
 // GetReservationConflicts returns conflicts for a reservation request
 func (r *GPUReservationManager) GetReservationConflicts(request *ReservationRequest) []*ReservationConflict {
 	r.mu.RLock()
@@ -356,6 +1230,65 @@ func (r *GPUReservationManager) GetReservationStats() *types.ReservationStats {
 	return stats
 }
 
+// GetReservationUtilization returns the reserved GPU-hours of the reservations
+// matching the user, GPU and status filters, in total, per status and per user
+// and GPU by status, for chargeback. The filters' StartTime and EndTime bound
+// the window: reservations overlapping it only count their hours inside it.
+// Sorting and paging filters are ignored.
+func (r *GPUReservationManager) GetReservationUtilization(filters *ReservationFilters) *types.ReservationUtilization {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matching *ReservationFilters
+	utilization := &types.ReservationUtilization{
+		GPUHoursByStatus: make(map[string]float64),
+		GPUHoursByUser:   make(map[string]*types.ReservedGPUHours),
+		GPUHoursByGPU:    make(map[string]*types.ReservedGPUHours),
+	}
+	if filters != nil {
+		matching = &ReservationFilters{UserID: filters.UserID, GPUID: filters.GPUID, Status: filters.Status}
+		utilization.WindowStart = filters.StartTime
+		utilization.WindowEnd = filters.EndTime
+	}
+
+	add := func(breakdown map[string]*types.ReservedGPUHours, key, status string, hours float64) {
+		entry, exists := breakdown[key]
+		if !exists {
+			entry = &types.ReservedGPUHours{ByStatus: make(map[string]float64)}
+			breakdown[key] = entry
+		}
+		entry.Total += hours
+		entry.ByStatus[status] += hours
+	}
+
+	for _, reservation := range r.reservations {
+		if !r.matchesFilters(reservation, matching) {
+			continue
+		}
+
+		from, to := reservation.StartTime, reservation.EndTime
+		if !utilization.WindowStart.IsZero() {
+			from = maxTime(from, utilization.WindowStart)
+		}
+		if !utilization.WindowEnd.IsZero() {
+			to = minTime(to, utilization.WindowEnd)
+		}
+		if !from.Before(to) {
+			continue
+		}
+
+		hours := reservation.Fraction * to.Sub(from).Hours()
+		status := string(reservation.Status)
+
+		utilization.TotalGPUHours += hours
+		utilization.GPUHoursByStatus[status] += hours
+		add(utilization.GPUHoursByUser, reservation.UserID, status, hours)
+		add(utilization.GPUHoursByGPU, reservation.GPUID, status, hours)
+	}
+
+	return utilization
+}
+
 // validateReservationRequest validates a reservation request
 func (r *GPUReservationManager) validateReservationRequest(request *ReservationRequest) error {
 	if request.UserID == "" {
@@ -370,8 +1303,12 @@ func (r *GPUReservationManager) validateReservationRequest(request *ReservationR
 		return fmt.Errorf("GPU ID is required")
 	}
 
-	if request.Fraction < 0.1 || request.Fraction > 1.0 {
-		return fmt.Errorf("GPU fraction must be between 0.1 and 1.0, got %f", request.Fraction)
+	if request.TenantID == "" && strings.Contains(r.config.ReservationIDTemplate, ReservationIDPlaceholderTenant) {
+		return fmt.Errorf("tenant ID is required by reservation ID template %q", r.config.ReservationIDTemplate)
+	}
+
+	if err := r.validateFraction(request.GPUID, request.Fraction); err != nil {
+		return err
 	}
 
 	if request.MemoryRequest < 0 {
@@ -386,39 +1323,112 @@ func (r *GPUReservationManager) validateReservationRequest(request *ReservationR
 		return fmt.Errorf("duration exceeds maximum allowed duration of %v", r.config.MaxReservationDuration)
 	}
 
-	if request.StartTime.Before(time.Now()) {
+	if request.StartTime.Before(r.now()) {
 		return fmt.Errorf("start time cannot be in the past")
 	}
 
+	// Occurrences of a recurring reservation that fall in a blackout are
+	// skipped during expansion instead
+	if request.Recurrence == nil {
+		if err := r.checkBlackouts(request.GPUID, request.StartTime, request.Duration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateFraction checks a fraction is within bounds and accepted by the GPU's
+// fraction validator, if it has one
+func (r *GPUReservationManager) validateFraction(gpuID string, fraction float64) error {
+	if fraction < 0.1 || fraction > 1.0 {
+		return fmt.Errorf("GPU fraction must be between 0.1 and 1.0, got %f", fraction)
+	}
+
+	if validator, exists := r.fractionValidator[gpuID]; exists {
+		if err := validator.ValidateFraction(gpuID, fraction); err != nil {
+			return fmt.Errorf("invalid fraction for GPU %s: %w", gpuID, err)
+		}
+	}
+
 	return nil
 }
 
 // checkConflicts checks for conflicts with existing reservations
 func (r *GPUReservationManager) checkConflicts(request *ReservationRequest) []*ReservationConflict {
-	var conflicts []*ReservationConflict
+	overlapping := r.overlappingReservations(request)
+	if len(overlapping) == 0 {
+		return nil
+	}
+
+	// Capacity is judged conservatively against every overlapping reservation at once
+	combinedFraction := request.Fraction
+	combinedMemory := request.MemoryRequest
+	allShared := request.SharingEnabled
+	for _, reservation := range overlapping {
+		combinedFraction += reservation.Fraction
+		combinedMemory += reservation.MemoryRequest
+		allShared = allShared && reservation.SharingEnabled
+	}
+
+	severity := ConflictSeverityHard
+	if allShared && combinedFraction <= 1.0+fractionTolerance {
+		severity = ConflictSeveritySoft
+	}
+
+	conflicts := make([]*ReservationConflict, 0, len(overlapping))
+	for _, reservation := range overlapping {
+		conflicts = append(conflicts, &ReservationConflict{
+			ReservationID:           reservation.ID,
+			ConflictType:            "time_overlap",
+			Severity:                severity,
+			Message:                 fmt.Sprintf("Time overlap with reservation %s (%s)", reservation.ID, severity),
+			ConflictingReservations: []string{reservation.ID},
+			CombinedFraction:        combinedFraction,
+			CombinedMemoryRequest:   combinedMemory,
+			RequestPriority:         request.Priority,
+			ConflictingPriority:     reservation.Priority,
+		})
+	}
+
+	return conflicts
+}
+
+// overlappingReservations returns the reservations holding capacity on the
+// request's GPU at some point during the request's window
+func (r *GPUReservationManager) overlappingReservations(request *ReservationRequest) []*GPUReservation {
+	var overlapping []*GPUReservation
 
 	for _, reservation := range r.reservations {
-		// Skip completed and cancelled reservations
-		if reservation.Status == ReservationStatusCompleted || reservation.Status == ReservationStatusCancelled {
+		// Skip completed, cancelled, queued and held reservations
+		if reservation.Status == ReservationStatusCompleted || reservation.Status == ReservationStatusCancelled ||
+			reservation.Status == ReservationStatusQueued || reservation.Status == ReservationStatusHeld {
 			continue
 		}
 
-		// Check if reservations overlap in time
-		if r.timeOverlaps(request, reservation) {
-			// Check if they use the same GPU
-			if request.GPUID == reservation.GPUID {
-				conflict := &ReservationConflict{
-					ReservationID:           reservation.ID,
-					ConflictType:            "time_overlap",
-					Message:                 fmt.Sprintf("Time overlap with reservation %s", reservation.ID),
-					ConflictingReservations: []string{reservation.ID},
-				}
-				conflicts = append(conflicts, conflict)
-			}
+		// Check if reservations overlap in time on the same GPU
+		if request.GPUID == reservation.GPUID && r.timeOverlaps(request, reservation) {
+			overlapping = append(overlapping, reservation)
 		}
 	}
 
-	return conflicts
+	return overlapping
+}
+
+// checkCapacityPartitions checks that a request stays out of the capacity
+// partitions reserved for higher priorities, whatever the conflict policy.
+// Callers must hold r.mu.
+func (r *GPUReservationManager) checkCapacityPartitions(request *ReservationRequest) error {
+	if len(r.config.CapacityPartitions) == 0 {
+		return nil
+	}
+
+	used := make(map[int]float64)
+	for _, reservation := range r.overlappingReservations(request) {
+		used[int(reservation.Priority)] += reservation.Fraction
+	}
+
+	return r.config.CapacityPartitions.Check(int(request.Priority), request.Fraction, used)
 }
 
 // timeOverlaps checks if two reservations overlap in time
@@ -430,6 +1440,22 @@ func (r *GPUReservationManager) timeOverlaps(request *ReservationRequest, reserv
 	return !(requestEnd.Before(reservation.StartTime) || request.StartTime.After(reservationEnd))
 }
 
+// conflictsBlock reports whether conflicts keep a request out under the strict
+// and priority policies, which queue it on the waitlist if enabled and reject it
+// otherwise
+func (r *GPUReservationManager) conflictsBlock(conflicts []*ReservationConflict) bool {
+	switch r.config.ConflictResolutionPolicy {
+	case ConflictResolutionPolicyStrict:
+		return true
+	case ConflictResolutionPolicyPriority:
+		return slices.ContainsFunc(conflicts, func(conflict *ReservationConflict) bool {
+			return !conflict.Displaceable()
+		})
+	default:
+		return false
+	}
+}
+
 // resolveConflicts resolves conflicts based on the configured policy
 func (r *GPUReservationManager) resolveConflicts(newReservation *GPUReservation, conflicts []*ReservationConflict) error {
 	switch r.config.ConflictResolutionPolicy {
@@ -448,75 +1474,211 @@ func (r *GPUReservationManager) resolveConflicts(newReservation *GPUReservation,
 		// No conflicts allowed
 		return fmt.Errorf("conflicts not allowed with strict policy")
 
+	case ConflictResolutionPolicyPriority:
+		// Only reservations of lower priority may be overlapped
+		if r.conflictsBlock(conflicts) {
+			return fmt.Errorf("conflicts with reservations of equal or higher priority not allowed with priority policy")
+		}
+		return nil
+
 	default:
 		return fmt.Errorf("unknown conflict resolution policy: %s", r.config.ConflictResolutionPolicy)
 	}
 }
 
-// checkUserLimits checks if user has exceeded reservation limits
+// checkUserLimits checks if user has exceeded reservation limits. A recurring
+// series counts as a single reservation.
 func (r *GPUReservationManager) checkUserLimits(userID string) error {
-	count := 0
+	counted := make(map[string]bool)
 	for _, reservation := range r.reservations {
 		if reservation.UserID == userID &&
 			(reservation.Status == ReservationStatusPending || reservation.Status == ReservationStatusActive) {
-			count++
+			counted[limitKey(reservation)] = true
 		}
 	}
+	count := len(counted)
 
 	if count >= r.config.MaxReservationsPerUser {
-		return fmt.Errorf("user %s has exceeded maximum reservations limit of %d", userID, r.config.MaxReservationsPerUser)
+		return fmt.Errorf("%w: user %s has exceeded maximum reservations limit of %d", ErrLimitExceeded, userID, r.config.MaxReservationsPerUser)
 	}
 
 	return nil
 }
 
-// checkGPULimits checks if GPU has exceeded reservation limits
+// checkGPULimits checks if GPU has exceeded reservation limits. A recurring
+// series counts as a single reservation.
 func (r *GPUReservationManager) checkGPULimits(gpuID string) error {
-	count := 0
+	counted := make(map[string]bool)
 	for _, reservation := range r.reservations {
 		if reservation.GPUID == gpuID &&
 			(reservation.Status == ReservationStatusPending || reservation.Status == ReservationStatusActive) {
-			count++
+			counted[limitKey(reservation)] = true
 		}
 	}
+	count := len(counted)
 
 	if count >= r.config.MaxReservationsPerGPU {
-		return fmt.Errorf("GPU %s has exceeded maximum reservations limit of %d", gpuID, r.config.MaxReservationsPerGPU)
+		return fmt.Errorf("%w: GPU %s has exceeded maximum reservations limit of %d", ErrLimitExceeded, gpuID, r.config.MaxReservationsPerGPU)
 	}
 
 	return nil
 }
 
-// generateReservationID generates a unique reservation ID
+// generateReservationID generates a unique reservation ID from the configured template
 func (r *GPUReservationManager) generateReservationID(request *ReservationRequest) string {
-	return fmt.Sprintf("res-%s-%s-%d", request.UserID, request.GPUID, time.Now().Unix())
+	replacer := strings.NewReplacer(
+		ReservationIDPlaceholderTenant, request.TenantID,
+		ReservationIDPlaceholderUser, request.UserID,
+		ReservationIDPlaceholderGPU, request.GPUID,
+		ReservationIDPlaceholderWorkload, request.WorkloadID,
+		ReservationIDPlaceholderUUID, uuid.NewString(),
+		ReservationIDPlaceholderTimestamp, strconv.FormatInt(r.now().Unix(), 10),
+	)
+	return replacer.Replace(r.config.ReservationIDTemplate)
+}
+
+// validateReservationIDTemplate checks that a reservation ID template only uses
+// known placeholders and contains a placeholder that varies between reservations
+func validateReservationIDTemplate(template string) error {
+	hasUniquePart := false
+	for _, placeholder := range reservationIDPlaceholderRegex.FindAllString(template, -1) {
+		switch placeholder {
+		case ReservationIDPlaceholderUUID, ReservationIDPlaceholderTimestamp:
+			hasUniquePart = true
+		case ReservationIDPlaceholderTenant, ReservationIDPlaceholderUser,
+			ReservationIDPlaceholderGPU, ReservationIDPlaceholderWorkload:
+			// Valid placeholder
+		default:
+			return fmt.Errorf("unknown placeholder %s in template %q", placeholder, template)
+		}
+	}
+
+	// Any brace left after removing placeholders is unbalanced
+	if strings.ContainsAny(reservationIDPlaceholderRegex.ReplaceAllString(template, ""), "{}") {
+		return fmt.Errorf("unbalanced braces in template %q", template)
+	}
+
+	if !hasUniquePart {
+		return fmt.Errorf("template %q must contain %s or %s", template,
+			ReservationIDPlaceholderUUID, ReservationIDPlaceholderTimestamp)
+	}
+
+	return nil
 }
 
-// cleanupExpiredReservations periodically cleans up expired reservations
+// cleanupExpiredReservations periodically expires reservations past their end
+// time and activates pending reservations whose start time has arrived
 func (r *GPUReservationManager) cleanupExpiredReservations() {
-	ticker := time.NewTicker(r.config.CleanupInterval)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		r.mu.Lock()
-		now := time.Now()
-		for _, reservation := range r.reservations {
-			if reservation.EndTime.Before(now) && reservation.Status == ReservationStatusActive {
-				reservation.Status = ReservationStatusExpired
-				reservation.UpdatedAt = now
-			}
+	defer close(r.stopped)
+
+	cleanupTicker := time.NewTicker(r.config.CleanupInterval)
+	defer cleanupTicker.Stop()
+
+	activationTicker := time.NewTicker(r.config.ActivationInterval)
+	defer activationTicker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-cleanupTicker.C:
+			r.expireReservations()
+		case <-activationTicker.C:
+			r.resumeDueHolds()
+			r.activateDueReservations()
+		}
+	}
+}
+
+// expireReservations marks reservations past their end time as expired. This
+// covers pending and queued reservations that never activated as well as
+// active ones.
+func (r *GPUReservationManager) expireReservations() {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, reservation := range r.reservations {
+		if reservation.EndTime.Before(now) && expires(reservation.Status) {
+			r.expire(reservation, now)
+			r.persistOrLog(reservation)
+		}
+	}
+}
+
+// expires reports whether a reservation in the given status expires once its
+// end time passes
+func expires(status ReservationStatus) bool {
+	switch status {
+	case ReservationStatusPending, ReservationStatusQueued, ReservationStatusActive:
+		return true
+	default:
+		return false
+	}
+}
+
+// expire marks a reservation as expired, taking it off its GPU's waitlist if it
+// is queued. Callers must hold r.mu.
+func (r *GPUReservationManager) expire(reservation *GPUReservation, now time.Time) {
+	if reservation.Status == ReservationStatusQueued {
+		before := r.waitlistPositions(reservation.GPUID)
+		r.dequeue(reservation)
+		r.emitPositionChanges(reservation.GPUID, before)
+	}
+
+	reservation.Status = ReservationStatusExpired
+	reservation.UpdatedAt = now
+	r.emit(eventExpired, reservation)
+}
+
+// activateDueReservations activates pending reservations whose start time has
+// been reached, whose dependencies have completed and whose GPU has capacity for
+// them. Reservations without capacity stay pending until it frees up, and those
+// left waiting past their SLA are flagged. Cancelled reservations are never
+// pending, so they are never reactivated.
+func (r *GPUReservationManager) activateDueReservations() {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, reservation := range r.reservations {
+		if reservation.Status != ReservationStatusPending && reservation.Status != ReservationStatusQueued {
+			continue
+		}
+
+		if reservation.Status == ReservationStatusPending && !now.Before(reservation.StartTime) &&
+			now.Before(reservation.EndTime) && r.dependenciesComplete(reservation) && r.hasActiveCapacity(reservation) {
+			r.markActive(reservation)
+			r.persistOrLog(reservation)
+			continue
 		}
-		r.mu.Unlock()
+
+		r.checkActivationDeadline(reservation)
 	}
 }
 
-// ReservationFilters contains filters for listing reservations
+// ReservationSortField is a field ListReservations can order reservations by
+type ReservationSortField string
+
+const (
+	ReservationSortByStartTime ReservationSortField = "start_time"
+	ReservationSortByCreatedAt ReservationSortField = "created_at"
+	ReservationSortByPriority  ReservationSortField = "priority"
+)
+
+// ReservationFilters contains filters, ordering and paging for listing reservations
 type ReservationFilters struct {
 	UserID    string
 	GPUID     string
 	Status    ReservationStatus
 	StartTime time.Time
 	EndTime   time.Time
+	SortBy    ReservationSortField // Unknown fields sort by start time
+	Ascending bool                 // Only applies when SortBy is set
+	Limit     int                  // Maximum reservations returned; 0 means no limit
+	Offset    int                  // Matching reservations skipped before the page starts
 }
 
 // matchesFilters checks if a reservation matches the given filters
@@ -2,11 +2,33 @@ package reservation
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	gpumanager "github.com/silogen/kaiwo/pkg/gpu/manager"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
 )
 
+func newTestManager(t *testing.T, config ReservationManagerConfig) *GPUReservationManager {
+	t.Helper()
+
+	manager, err := NewGPUReservationManager(config)
+	if err != nil {
+		t.Fatalf("Failed to create reservation manager: %v", err)
+	}
+	t.Cleanup(manager.Stop)
+	return manager
+}
+
 func TestNewGPUReservationManager(t *testing.T) {
 	config := ReservationManagerConfig{
 		MaxReservationsPerGPU:    5,
@@ -18,7 +40,7 @@ func TestNewGPUReservationManager(t *testing.T) {
 		CleanupInterval:          30 * time.Minute,
 	}
 
-	manager := NewGPUReservationManager(config)
+	manager := newTestManager(t, config)
 
 	if manager == nil {
 		t.Fatal("Expected non-nil manager")
@@ -38,7 +60,7 @@ func TestNewGPUReservationManager(t *testing.T) {
 }
 
 func TestGPUReservationManagerDefaultConfig(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	if manager.config.MaxReservationsPerGPU != 10 {
 		t.Errorf("Expected default max reservations per GPU 10, got %d", manager.config.MaxReservationsPerGPU)
@@ -66,7 +88,7 @@ func TestGPUReservationManagerDefaultConfig(t *testing.T) {
 }
 
 func TestCreateReservation(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	request := &ReservationRequest{
 		UserID:         "user1",
@@ -129,7 +151,7 @@ func TestCreateReservation(t *testing.T) {
 }
 
 func TestCreateReservationValidation(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	tests := []struct {
 		name    string
@@ -243,7 +265,7 @@ func TestCreateReservationValidation(t *testing.T) {
 }
 
 func TestGetReservation(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	// Create a reservation
 	request := &ReservationRequest{
@@ -278,7 +300,7 @@ func TestGetReservation(t *testing.T) {
 }
 
 func TestListReservations(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	// Create multiple reservations
 	requests := []*ReservationRequest{
@@ -316,32 +338,107 @@ func TestListReservations(t *testing.T) {
 	}
 
 	// Test listing all reservations
-	allReservations := manager.ListReservations(nil)
+	allReservations, _ := manager.ListReservations(nil)
 	if len(allReservations) != 3 {
 		t.Errorf("Expected 3 reservations, got %d", len(allReservations))
 	}
 
 	// Test filtering by user
-	user1Reservations := manager.ListReservations(&ReservationFilters{UserID: "user1"})
+	user1Reservations, _ := manager.ListReservations(&ReservationFilters{UserID: "user1"})
 	if len(user1Reservations) != 2 {
 		t.Errorf("Expected 2 reservations for user1, got %d", len(user1Reservations))
 	}
 
 	// Test filtering by GPU
-	card0Reservations := manager.ListReservations(&ReservationFilters{GPUID: "card0"})
+	card0Reservations, _ := manager.ListReservations(&ReservationFilters{GPUID: "card0"})
 	if len(card0Reservations) != 2 {
 		t.Errorf("Expected 2 reservations for card0, got %d", len(card0Reservations))
 	}
 
 	// Test filtering by status
-	pendingReservations := manager.ListReservations(&ReservationFilters{Status: ReservationStatusPending})
+	pendingReservations, _ := manager.ListReservations(&ReservationFilters{Status: ReservationStatusPending})
 	if len(pendingReservations) != 3 {
 		t.Errorf("Expected 3 pending reservations, got %d", len(pendingReservations))
 	}
 }
 
+func TestListReservationsOrderingAndPaging(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		MaxReservationsPerUser: 10,
+		ReservationIDTemplate:  "res-{workload}-{uuid}",
+	})
+
+	// Create reservations out of start time order, on separate GPUs so they
+	// never conflict
+	base := time.Now().Add(time.Hour)
+	offsets := []int{3, 0, 4, 1, 2}
+	for i, offset := range offsets {
+		_, err := manager.CreateReservation(context.Background(), &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: fmt.Sprintf("workload%d", offset),
+			GPUID:      fmt.Sprintf("card%d", i),
+			Fraction:   0.5,
+			StartTime:  base.Add(time.Duration(offset) * time.Hour),
+			Duration:   time.Hour,
+			Priority:   ReservationPriority(offset),
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+	}
+
+	workloads := func(reservations []*GPUReservation) []string {
+		var ids []string
+		for _, reservation := range reservations {
+			ids = append(ids, reservation.WorkloadID)
+		}
+		return ids
+	}
+
+	// Default ordering is by ascending start time and stable across calls
+	all, total := manager.ListReservations(nil)
+	if total != 5 {
+		t.Fatalf("Expected total of 5, got %d", total)
+	}
+	expected := []string{"workload0", "workload1", "workload2", "workload3", "workload4"}
+	for i := 0; i < 3; i++ {
+		if got := workloads(all); fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Fatalf("Expected start time order %v, got %v", expected, got)
+		}
+		all, _ = manager.ListReservations(nil)
+	}
+
+	page, total := manager.ListReservations(&ReservationFilters{
+		SortBy:    ReservationSortByStartTime,
+		Ascending: true,
+		Limit:     2,
+		Offset:    1,
+	})
+	if total != 5 {
+		t.Errorf("Expected total of 5 regardless of paging, got %d", total)
+	}
+	if got := workloads(page); fmt.Sprint(got) != "[workload1 workload2]" {
+		t.Errorf("Expected page [workload1 workload2], got %v", got)
+	}
+
+	page, _ = manager.ListReservations(&ReservationFilters{SortBy: ReservationSortByPriority, Limit: 2})
+	if got := workloads(page); fmt.Sprint(got) != "[workload4 workload3]" {
+		t.Errorf("Expected highest priority page [workload4 workload3], got %v", got)
+	}
+
+	page, _ = manager.ListReservations(&ReservationFilters{Limit: 10, Offset: 4})
+	if got := workloads(page); fmt.Sprint(got) != "[workload4]" {
+		t.Errorf("Expected last page [workload4], got %v", got)
+	}
+
+	page, total = manager.ListReservations(&ReservationFilters{Offset: 10})
+	if len(page) != 0 || total != 5 {
+		t.Errorf("Expected empty page past the end with total 5, got %d reservations and total %d", len(page), total)
+	}
+}
+
 func TestUpdateReservation(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	// Create a reservation
 	request := &ReservationRequest{
@@ -407,12 +504,12 @@ func createTestReservation(t *testing.T, manager *GPUReservationManager) *GPURes
 }
 
 func TestCancelReservation(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	reservation := createTestReservation(t, manager)
 
 	// Cancel the reservation
-	err := manager.CancelReservation(reservation.ID)
+	err := manager.CancelReservation(reservation.ID, false)
 	if err != nil {
 		t.Fatalf("Failed to cancel reservation: %v", err)
 	}
@@ -428,14 +525,14 @@ func TestCancelReservation(t *testing.T) {
 	}
 
 	// Test cancelling non-existent reservation
-	err = manager.CancelReservation("non-existent")
+	err = manager.CancelReservation("non-existent", false)
 	if err == nil {
 		t.Error("Expected error when cancelling non-existent reservation")
 	}
 }
 
 func TestCompleteReservation(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	reservation := createTestReservation(t, manager)
 
@@ -462,8 +559,100 @@ func TestCompleteReservation(t *testing.T) {
 	}
 }
 
+func TestDeleteReservation(t *testing.T) {
+	store := NewFileReservationStore(filepath.Join(t.TempDir(), "reservations.json"))
+	manager := newTestManager(t, ReservationManagerConfig{Store: store})
+
+	reservation := createTestReservation(t, manager)
+	if _, err := manager.UpdateReservation(reservation.ID, map[string]interface{}{
+		"status": ReservationStatusActive,
+	}); err != nil {
+		t.Fatalf("Failed to activate reservation: %v", err)
+	}
+
+	// Active reservations need force
+	if err := manager.DeleteReservation(reservation.ID, false); err == nil {
+		t.Fatal("Expected error when deleting an active reservation without force")
+	}
+	if _, exists := manager.GetReservation(reservation.ID); !exists {
+		t.Fatal("Expected rejected delete to keep the reservation")
+	}
+
+	if err := manager.DeleteReservation(reservation.ID, true); err != nil {
+		t.Fatalf("Failed to force delete reservation: %v", err)
+	}
+	if _, exists := manager.GetReservation(reservation.ID); exists {
+		t.Error("Expected reservation to be removed")
+	}
+
+	stored, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("Expected reservation to be removed from the store, got %d stored", len(stored))
+	}
+
+	if err := manager.DeleteReservation(reservation.ID, false); err == nil {
+		t.Error("Expected error when deleting non-existent reservation")
+	}
+}
+
+func TestPurgeTerminated(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		ConflictResolutionPolicy: ConflictResolutionPolicyOverlap,
+		ReservationIDTemplate:    "res-{workload}-{uuid}",
+	})
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	create := func(workloadID string) *GPUReservation {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   0.2,
+			StartTime:  clock.Add(time.Hour),
+			Duration:   time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+		return reservation
+	}
+
+	old := create("old")
+	if err := manager.CancelReservation(old.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+
+	clock = clock.Add(2 * time.Hour)
+
+	recent := create("recent")
+	if err := manager.CompleteReservation(recent.ID); err != nil {
+		t.Fatalf("Failed to complete reservation: %v", err)
+	}
+	pending := create("pending")
+
+	if purged := manager.PurgeTerminated(time.Hour); purged != 1 {
+		t.Errorf("Expected 1 reservation purged, got %d", purged)
+	}
+
+	if _, exists := manager.GetReservation(old.ID); exists {
+		t.Error("Expected reservation cancelled before the retention window to be purged")
+	}
+	if _, exists := manager.GetReservation(recent.ID); !exists {
+		t.Error("Expected reservation completed within the retention window to be kept")
+	}
+	if _, exists := manager.GetReservation(pending.ID); !exists {
+		t.Error("Expected pending reservation to be kept")
+	}
+}
+
 func TestGetReservationConflicts(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	// Create an existing reservation
 	existingRequest := &ReservationRequest{
@@ -512,7 +701,7 @@ func TestGetReservationConflicts(t *testing.T) {
 }
 
 func TestGetReservationStats(t *testing.T) {
-	manager := NewGPUReservationManager(ReservationManagerConfig{})
+	manager := newTestManager(t, ReservationManagerConfig{})
 
 	// Create reservations with different statuses
 	requests := []*ReservationRequest{
@@ -573,7 +762,7 @@ func TestUserLimits(t *testing.T) {
 	config := ReservationManagerConfig{
 		MaxReservationsPerUser: 2,
 	}
-	manager := NewGPUReservationManager(config)
+	manager := newTestManager(t, config)
 
 	// Create maximum allowed reservations
 	for i := 0; i < 2; i++ {
@@ -612,7 +801,7 @@ func TestGPULimits(t *testing.T) {
 	config := ReservationManagerConfig{
 		MaxReservationsPerGPU: 2,
 	}
-	manager := NewGPUReservationManager(config)
+	manager := newTestManager(t, config)
 
 	// Create maximum allowed reservations for the same GPU
 	for i := 0; i < 2; i++ {
@@ -646,3 +835,2280 @@ func TestGPULimits(t *testing.T) {
 		t.Error("Expected error when exceeding GPU limits")
 	}
 }
+
+func TestReservationIDTemplate(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		ReservationIDTemplate:    "{tenant}-res-{uuid}",
+		ConflictResolutionPolicy: ConflictResolutionPolicyOverlap,
+	})
+
+	idPattern := regexp.MustCompile(`^team-a-res-[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	seen := make(map[string]bool)
+
+	for i := 0; i < 3; i++ {
+		request := &ReservationRequest{
+			TenantID:   "team-a",
+			UserID:     fmt.Sprintf("user%d", i),
+			WorkloadID: fmt.Sprintf("workload%d", i),
+			GPUID:      "card0",
+			Fraction:   0.5,
+			StartTime:  time.Now().Add(1 * time.Hour),
+			Duration:   1 * time.Hour,
+		}
+
+		reservation, err := manager.CreateReservation(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+
+		if !idPattern.MatchString(reservation.ID) {
+			t.Errorf("Expected ID to follow template, got %s", reservation.ID)
+		}
+
+		if seen[reservation.ID] {
+			t.Errorf("Expected unique reservation IDs, got duplicate %s", reservation.ID)
+		}
+		seen[reservation.ID] = true
+
+		if reservation.TenantID != "team-a" {
+			t.Errorf("Expected tenant ID 'team-a', got %s", reservation.TenantID)
+		}
+	}
+
+	// A tenant-scoped template requires a tenant on the request
+	_, err := manager.CreateReservation(context.Background(), &ReservationRequest{
+		UserID:     "user1",
+		WorkloadID: "workload1",
+		GPUID:      "card1",
+		Fraction:   0.5,
+		StartTime:  time.Now().Add(1 * time.Hour),
+		Duration:   1 * time.Hour,
+	})
+	if err == nil {
+		t.Error("Expected error when tenant ID is missing")
+	}
+}
+
+func TestReservationIDTemplateValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "default", template: "", wantErr: false},
+		{name: "tenant and uuid", template: "{tenant}-res-{uuid}", wantErr: false},
+		{name: "timestamp", template: "{user}-{gpu}-{timestamp}", wantErr: false},
+		{name: "unknown placeholder", template: "{tenant}-{nope}-{uuid}", wantErr: true},
+		{name: "unbalanced braces", template: "{tenant-res-{uuid}", wantErr: true},
+		{name: "no unique part", template: "{tenant}-res", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGPUReservationManager(ReservationManagerConfig{ReservationIDTemplate: tt.template})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewGPUReservationManager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReservationConflictSeverity(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ConflictResolutionPolicy: ConflictResolutionPolicyOverlap})
+
+	existing := &ReservationRequest{
+		UserID:         "user1",
+		WorkloadID:     "workload1",
+		GPUID:          "card0",
+		Fraction:       0.5,
+		MemoryRequest:  1024,
+		StartTime:      time.Now().Add(1 * time.Hour),
+		Duration:       2 * time.Hour,
+		SharingEnabled: true,
+	}
+	if _, err := manager.CreateReservation(context.Background(), existing); err != nil {
+		t.Fatalf("Failed to create existing reservation: %v", err)
+	}
+
+	// Still fits on the GPU under sharing
+	softRequest := &ReservationRequest{
+		UserID:         "user2",
+		WorkloadID:     "workload2",
+		GPUID:          "card0",
+		Fraction:       0.25,
+		MemoryRequest:  512,
+		StartTime:      time.Now().Add(2 * time.Hour),
+		Duration:       1 * time.Hour,
+		SharingEnabled: true,
+	}
+
+	conflicts := manager.GetReservationConflicts(softRequest)
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d", len(conflicts))
+	}
+
+	if conflicts[0].Severity != ConflictSeveritySoft {
+		t.Errorf("Expected soft conflict, got %s", conflicts[0].Severity)
+	}
+
+	if conflicts[0].CombinedFraction != 0.75 {
+		t.Errorf("Expected combined fraction 0.75, got %f", conflicts[0].CombinedFraction)
+	}
+
+	if conflicts[0].CombinedMemoryRequest != 1536 {
+		t.Errorf("Expected combined memory 1536, got %d", conflicts[0].CombinedMemoryRequest)
+	}
+
+	// Exceeds the GPU's capacity
+	hardRequest := &ReservationRequest{
+		UserID:         "user2",
+		WorkloadID:     "workload3",
+		GPUID:          "card0",
+		Fraction:       0.75,
+		StartTime:      time.Now().Add(2 * time.Hour),
+		Duration:       1 * time.Hour,
+		SharingEnabled: true,
+	}
+
+	conflicts = manager.GetReservationConflicts(hardRequest)
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d", len(conflicts))
+	}
+
+	if conflicts[0].Severity != ConflictSeverityHard {
+		t.Errorf("Expected hard conflict, got %s", conflicts[0].Severity)
+	}
+
+	if conflicts[0].CombinedFraction != 1.25 {
+		t.Errorf("Expected combined fraction 1.25, got %f", conflicts[0].CombinedFraction)
+	}
+
+	// Fits by capacity but the request does not allow sharing
+	exclusiveRequest := &ReservationRequest{
+		UserID:     "user2",
+		WorkloadID: "workload4",
+		GPUID:      "card0",
+		Fraction:   0.25,
+		StartTime:  time.Now().Add(2 * time.Hour),
+		Duration:   1 * time.Hour,
+	}
+
+	conflicts = manager.GetReservationConflicts(exclusiveRequest)
+	if len(conflicts) != 1 || conflicts[0].Severity != ConflictSeverityHard {
+		t.Errorf("Expected a single hard conflict for an exclusive request, got %v", conflicts)
+	}
+}
+
+// fakeUtilizationSource returns fixed utilization samples per reservation
+type fakeUtilizationSource struct {
+	samples map[string][]float64
+}
+
+func (f *fakeUtilizationSource) GetUtilizationHistory(reservation *GPUReservation, from, to time.Time) ([]UtilizationSample, error) {
+	var samples []UtilizationSample
+	for i, utilization := range f.samples[reservation.ID] {
+		samples = append(samples, UtilizationSample{
+			Timestamp:   from.Add(time.Duration(i) * time.Minute),
+			Utilization: utilization,
+		})
+	}
+	return samples, nil
+}
+
+func TestGetUserEfficiencyReport(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+	now := time.Now()
+
+	// Reservations that already ran are inserted directly, since CreateReservation rejects past start times
+	manager.reservations["res-past"] = &GPUReservation{
+		ID:        "res-past",
+		UserID:    "user1",
+		GPUID:     "card0",
+		Fraction:  0.5,
+		StartTime: now.Add(-3 * time.Hour),
+		EndTime:   now.Add(-1 * time.Hour),
+		Status:    ReservationStatusCompleted,
+	}
+	manager.reservations["res-cancelled"] = &GPUReservation{
+		ID:        "res-cancelled",
+		UserID:    "user1",
+		GPUID:     "card1",
+		Fraction:  1.0,
+		StartTime: now.Add(-3 * time.Hour),
+		EndTime:   now.Add(-1 * time.Hour),
+		Status:    ReservationStatusCancelled,
+	}
+	manager.reservations["res-other-user"] = &GPUReservation{
+		ID:        "res-other-user",
+		UserID:    "user2",
+		GPUID:     "card0",
+		Fraction:  0.5,
+		StartTime: now.Add(-3 * time.Hour),
+		EndTime:   now.Add(-1 * time.Hour),
+		Status:    ReservationStatusCompleted,
+	}
+
+	if _, err := manager.GetUserEfficiencyReport("user1", 24*time.Hour); err == nil {
+		t.Error("Expected error without a utilization source")
+	}
+
+	manager.SetUtilizationSource(&fakeUtilizationSource{
+		samples: map[string][]float64{"res-past": {0.5, 0.25}},
+	})
+
+	report, err := manager.GetUserEfficiencyReport("user1", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to get efficiency report: %v", err)
+	}
+
+	if len(report.Reservations) != 1 {
+		t.Fatalf("Expected 1 reservation in report, got %d", len(report.Reservations))
+	}
+
+	// 0.5 GPU for 2 hours at a mean utilization of 0.375
+	const epsilon = 1e-6
+	if math.Abs(report.ReservedGPUHours-1.0) > epsilon {
+		t.Errorf("Expected 1.0 reserved GPU-hours, got %f", report.ReservedGPUHours)
+	}
+
+	if math.Abs(report.UtilizedGPUHours-0.375) > epsilon {
+		t.Errorf("Expected 0.375 utilized GPU-hours, got %f", report.UtilizedGPUHours)
+	}
+
+	if math.Abs(report.Efficiency-0.375) > epsilon {
+		t.Errorf("Expected efficiency 0.375, got %f", report.Efficiency)
+	}
+
+	if report.Reservations[0].Samples != 2 {
+		t.Errorf("Expected 2 samples, got %d", report.Reservations[0].Samples)
+	}
+
+	// A window that only covers the last hour of the reservation halves the reserved time
+	report, err = manager.GetUserEfficiencyReport("user1", 2*time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to get efficiency report: %v", err)
+	}
+
+	if math.Abs(report.ReservedGPUHours-0.5) > 1e-3 {
+		t.Errorf("Expected 0.5 reserved GPU-hours in a 2h window, got %f", report.ReservedGPUHours)
+	}
+}
+
+func TestActivateReservationLinksAllocation(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+
+	allocator := gpumanager.NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 8*1024*1024*1024, false)
+	manager.SetAllocator(allocator)
+
+	reservation, err := manager.CreateReservation(context.Background(), &ReservationRequest{
+		UserID:         "user1",
+		WorkloadID:     "workload1",
+		GPUID:          "card0",
+		Fraction:       0.5,
+		MemoryRequest:  2048,
+		StartTime:      time.Now().Add(1 * time.Hour),
+		Duration:       2 * time.Hour,
+		Priority:       ReservationPriorityNormal,
+		IsolationType:  "time-slicing",
+		SharingEnabled: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	allocation, err := manager.ActivateReservation(context.Background(), reservation.ID)
+	if err != nil {
+		t.Fatalf("Failed to activate reservation: %v", err)
+	}
+
+	if reservation.Status != ReservationStatusActive {
+		t.Errorf("Expected status active, got %s", reservation.Status)
+	}
+
+	// Allocation -> reservation
+	if allocation.ReservationID != reservation.ID {
+		t.Errorf("Expected allocation reservation ID %s, got %s", reservation.ID, allocation.ReservationID)
+	}
+
+	allocations := allocator.GetAllocationsForReservation(reservation.ID)
+	if len(allocations) != 1 || allocations[0].ID != allocation.ID {
+		t.Fatalf("Expected allocation %s for reservation, got %v", allocation.ID, allocations)
+	}
+
+	// Reservation -> allocation
+	if len(reservation.AllocationIDs) != 1 || reservation.AllocationIDs[0] != allocation.ID {
+		t.Errorf("Expected reservation allocation IDs [%s], got %v", allocation.ID, reservation.AllocationIDs)
+	}
+
+	if _, err := manager.ActivateReservation(context.Background(), reservation.ID); err == nil {
+		t.Error("Expected error activating an already backed reservation")
+	}
+
+	// Cancelling the reservation releases its allocation
+	if err := manager.CancelReservation(reservation.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+
+	if allocations := allocator.GetAllocationsForReservation(reservation.ID); len(allocations) != 0 {
+		t.Errorf("Expected 0 allocations after cancel, got %d", len(allocations))
+	}
+}
+
+func TestFulfillReservation(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	allocator := gpumanager.NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 8*1024*1024*1024, false)
+
+	reservation, err := manager.CreateReservation(context.Background(), &ReservationRequest{
+		UserID:         "user1",
+		WorkloadID:     "workload1",
+		GPUID:          "card0",
+		Fraction:       0.5,
+		MemoryRequest:  2048,
+		StartTime:      clock.Add(time.Hour),
+		Duration:       2 * time.Hour,
+		IsolationType:  "time-slicing",
+		SharingEnabled: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	if _, err := manager.Fulfill(reservation.ID); err == nil {
+		t.Fatal("Expected fulfilling without an allocator to fail")
+	}
+	manager.SetAllocator(allocator)
+
+	if _, err := manager.Fulfill(reservation.ID); err == nil {
+		t.Fatal("Expected fulfilling before the reservation window to fail")
+	}
+	if allocations := allocator.GetAllocationsForReservation(reservation.ID); len(allocations) != 0 {
+		t.Fatalf("Expected no allocation before the window, got %d", len(allocations))
+	}
+
+	clock = reservation.StartTime.Add(time.Minute)
+	allocation, err := manager.Fulfill(reservation.ID)
+	if err != nil {
+		t.Fatalf("Failed to fulfill reservation: %v", err)
+	}
+
+	if allocation.DeviceID != "card0" || allocation.Fraction != 0.5 || allocation.MemoryRequest != 2048 ||
+		allocation.IsolationType != types.GPUIsolationTimeSlicing {
+		t.Errorf("Expected a 0.5 time-sliced allocation of 2048 MiB on card0, got %+v", allocation)
+	}
+	if allocation.ReservationID != reservation.ID || allocation.ExpiresAt != reservation.EndTime.Unix() {
+		t.Errorf("Expected the allocation tied to the reservation and its end, got %+v", allocation)
+	}
+	if len(reservation.AllocationIDs) != 1 || reservation.AllocationIDs[0] != allocation.ID {
+		t.Errorf("Expected reservation allocation IDs [%s], got %v", allocation.ID, reservation.AllocationIDs)
+	}
+	if reservation.Status != ReservationStatusActive {
+		t.Errorf("Expected status active, got %s", reservation.Status)
+	}
+
+	if _, err := manager.Fulfill(reservation.ID); err == nil {
+		t.Error("Expected fulfilling an already backed reservation to fail")
+	}
+
+	// Completing the reservation releases its allocation
+	if err := manager.CompleteReservation(reservation.ID); err != nil {
+		t.Fatalf("Failed to complete reservation: %v", err)
+	}
+	if allocations := allocator.GetGPUAllocations("card0"); len(allocations) != 0 {
+		t.Errorf("Expected 0 allocations after completion, got %d", len(allocations))
+	}
+	if len(reservation.AllocationIDs) != 0 {
+		t.Errorf("Expected the completed reservation to be unlinked, got %v", reservation.AllocationIDs)
+	}
+	if reservation.Status != ReservationStatusCompleted {
+		t.Errorf("Expected status completed, got %s", reservation.Status)
+	}
+
+	// A reservation whose window has closed cannot be fulfilled
+	clock = reservation.EndTime
+	if _, err := manager.Fulfill(reservation.ID); err == nil || !strings.Contains(err.Error(), "ended") {
+		t.Errorf("Expected fulfilling after the reservation window to fail, got %v", err)
+	}
+}
+
+func TestReservationDependencies(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+	ctx := context.Background()
+
+	newRequest := func(gpuID string, dependsOn ...string) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: "pipeline",
+			GPUID:      gpuID,
+			Fraction:   1.0,
+			StartTime:  time.Now().Add(1 * time.Hour),
+			Duration:   2 * time.Hour,
+			Priority:   ReservationPriorityNormal,
+			DependsOn:  dependsOn,
+		}
+	}
+
+	stageA, err := manager.CreateReservation(ctx, newRequest("card0"))
+	if err != nil {
+		t.Fatalf("Failed to create stage A reservation: %v", err)
+	}
+
+	stageB, err := manager.CreateReservation(ctx, newRequest("card1", stageA.ID))
+	if err != nil {
+		t.Fatalf("Failed to create stage B reservation: %v", err)
+	}
+
+	// Stage B's nominal start passes while stage A is still running
+	stageB.StartTime = time.Now().Add(-1 * time.Minute)
+	stageA.Status = ReservationStatusActive
+
+	if stageB.Status != ReservationStatusPending {
+		t.Errorf("Expected stage B to stay pending, got %s", stageB.Status)
+	}
+
+	if err := manager.CompleteReservation(stageA.ID); err != nil {
+		t.Fatalf("Failed to complete stage A: %v", err)
+	}
+
+	if stageB.Status != ReservationStatusActive {
+		t.Errorf("Expected stage B to activate after stage A completes, got %s", stageB.Status)
+	}
+
+	if _, err := manager.CreateReservation(ctx, newRequest("card2", "missing")); err == nil {
+		t.Error("Expected error for unknown dependency")
+	}
+}
+
+func TestReservationDependencyCycleRejected(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+	ctx := context.Background()
+
+	first, err := manager.CreateReservation(ctx, &ReservationRequest{
+		UserID:     "user1",
+		WorkloadID: "pipeline",
+		GPUID:      "card0",
+		Fraction:   0.5,
+		StartTime:  time.Now().Add(1 * time.Hour),
+		Duration:   time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create first reservation: %v", err)
+	}
+
+	second, err := manager.CreateReservation(ctx, &ReservationRequest{
+		UserID:     "user1",
+		WorkloadID: "pipeline",
+		GPUID:      "card1",
+		Fraction:   0.5,
+		StartTime:  time.Now().Add(2 * time.Hour),
+		Duration:   time.Hour,
+		DependsOn:  []string{first.ID},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create second reservation: %v", err)
+	}
+
+	if _, err := manager.UpdateReservation(first.ID, map[string]interface{}{"depends_on": []string{second.ID}}); err == nil {
+		t.Error("Expected error for cyclic dependency")
+	}
+
+	if _, err := manager.UpdateReservation(first.ID, map[string]interface{}{"depends_on": []string{first.ID}}); err == nil {
+		t.Error("Expected error for self dependency")
+	}
+
+	if len(first.DependsOn) != 0 {
+		t.Errorf("Expected rejected dependencies to leave reservation unchanged, got %v", first.DependsOn)
+	}
+}
+
+func TestReservationSLABreach(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		ConflictResolutionPolicy: ConflictResolutionPolicyOverlap,
+		ReservationIDTemplate:    "res-{workload}-{timestamp}",
+	})
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	var alerted []*SLABreach
+	manager.SetSLABreachHandler(func(breach *SLABreach) {
+		alerted = append(alerted, breach)
+	})
+
+	allocator := gpumanager.NewFractionalAllocator()
+	allocator.RegisterGPU("card0", 8*1024*1024*1024, false)
+	manager.SetAllocator(allocator)
+
+	newRequest := func(workloadID string, fraction float64) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   fraction,
+			StartTime:  clock.Add(10 * time.Minute),
+			Duration:   time.Hour,
+			SLA:        &ReservationSLA{MaxActivationDelay: time.Minute},
+		}
+	}
+
+	onTime, err := manager.CreateReservation(ctx, newRequest("on-time", 0.5))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	// Activating 30s after the start time is within the SLA
+	clock = onTime.StartTime.Add(30 * time.Second)
+	if _, err := manager.ActivateReservation(ctx, onTime.ID); err != nil {
+		t.Fatalf("Failed to activate reservation: %v", err)
+	}
+
+	if breaches := manager.GetSLABreaches(time.Hour); len(breaches) != 0 {
+		t.Fatalf("Expected no SLA breaches, got %d", len(breaches))
+	}
+
+	clock = time.Now()
+	late, err := manager.CreateReservation(ctx, newRequest("late", 0.25))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	// Activating 5 minutes after the start time breaches the 1 minute SLA
+	clock = late.StartTime.Add(5 * time.Minute)
+	if _, err := manager.ActivateReservation(ctx, late.ID); err != nil {
+		t.Fatalf("Failed to activate reservation: %v", err)
+	}
+
+	breaches := manager.GetSLABreaches(time.Hour)
+	if len(breaches) != 1 {
+		t.Fatalf("Expected 1 SLA breach, got %d", len(breaches))
+	}
+
+	if breaches[0].ReservationID != late.ID || breaches[0].Reason != SLABreachReasonLateActivation {
+		t.Errorf("Expected late activation breach for %s, got %+v", late.ID, breaches[0])
+	}
+
+	if breaches[0].Delay != 5*time.Minute {
+		t.Errorf("Expected delay 5m, got %v", breaches[0].Delay)
+	}
+
+	// A failed activation is also a breach
+	clock = time.Now()
+	failed, err := manager.CreateReservation(ctx, newRequest("failed", 0.5))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	clock = failed.StartTime
+	if _, err := manager.ActivateReservation(ctx, failed.ID); err == nil {
+		t.Fatal("Expected activation to fail on a full GPU")
+	}
+
+	breaches = manager.GetSLABreaches(time.Hour)
+	if len(breaches) != 2 || breaches[1].Reason != SLABreachReasonActivationFailed {
+		t.Errorf("Expected an activation failure breach, got %+v", breaches)
+	}
+
+	if len(alerted) != 2 {
+		t.Errorf("Expected 2 breach alerts, got %d", len(alerted))
+	}
+
+	// Breaches outside the window are not reported
+	clock = clock.Add(2 * time.Hour)
+	if breaches := manager.GetSLABreaches(time.Hour); len(breaches) != 0 {
+		t.Errorf("Expected no breaches in the last hour, got %d", len(breaches))
+	}
+}
+
+func TestReservationSLABreachWhenNeverActivated(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ReservationIDTemplate: "res-{workload}-{uuid}"})
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	create := func(workloadID, gpuID string, dependsOn []string, sla *ReservationSLA) *GPUReservation {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      gpuID,
+			Fraction:   0.5,
+			StartTime:  clock.Add(time.Minute),
+			Duration:   time.Hour,
+			DependsOn:  dependsOn,
+			SLA:        sla,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation %s: %v", workloadID, err)
+		}
+		return reservation
+	}
+
+	// The dependent reservation cannot activate until its dependency completes
+	dependency := create("dependency", "card0", nil, nil)
+	waiting := create("waiting", "card1", []string{dependency.ID}, &ReservationSLA{MaxActivationDelay: time.Minute})
+
+	clock = waiting.StartTime.Add(30 * time.Second)
+	manager.activateDueReservations()
+	if breaches := manager.GetSLABreaches(time.Hour); len(breaches) != 0 {
+		t.Fatalf("Expected no breach within the SLA, got %+v", breaches)
+	}
+
+	// Still pending past the SLA is flagged, once
+	clock = waiting.StartTime.Add(2 * time.Minute)
+	manager.activateDueReservations()
+	manager.activateDueReservations()
+	breaches := manager.GetSLABreaches(time.Hour)
+	if len(breaches) != 1 || breaches[0].ReservationID != waiting.ID || breaches[0].Reason != SLABreachReasonNotActivated {
+		t.Fatalf("Expected one not activated breach for %s, got %+v", waiting.ID, breaches)
+	}
+	if breaches[0].Delay != 2*time.Minute {
+		t.Errorf("Expected delay 2m, got %v", breaches[0].Delay)
+	}
+
+	// Activating late afterwards does not flag the same reservation again
+	if err := manager.CompleteReservation(dependency.ID); err != nil {
+		t.Fatalf("Failed to complete dependency: %v", err)
+	}
+	if waiting.Status != ReservationStatusActive {
+		t.Fatalf("Expected waiting reservation to activate, got %s", waiting.Status)
+	}
+	if breaches := manager.GetSLABreaches(time.Hour); len(breaches) != 1 {
+		t.Errorf("Expected still one breach, got %+v", breaches)
+	}
+
+	// Breaches older than the retention period are dropped as new ones are recorded
+	clock = clock.Add(slaBreachRetention + time.Hour)
+	laterDependency := create("later-dependency", "card2", nil, nil)
+	later := create("later", "card3", []string{laterDependency.ID}, &ReservationSLA{MaxActivationDelay: time.Minute})
+	clock = later.StartTime.Add(2 * time.Minute)
+	manager.activateDueReservations()
+
+	manager.mu.RLock()
+	retained := len(manager.slaBreaches)
+	manager.mu.RUnlock()
+	if retained != 1 {
+		t.Errorf("Expected only the recent breach to be retained, got %d", retained)
+	}
+}
+
+func TestReservationsSurviveRestart(t *testing.T) {
+	store := NewFileReservationStore(filepath.Join(t.TempDir(), "reservations.json"))
+	ctx := context.Background()
+
+	manager := newTestManager(t, ReservationManagerConfig{
+		Store:                 store,
+		ReservationIDTemplate: "res-{workload}-{uuid}",
+	})
+
+	newRequest := func(workloadID, gpuID string) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      gpuID,
+			Fraction:   0.5,
+			StartTime:  time.Now().Add(1 * time.Hour),
+			Duration:   2 * time.Hour,
+			Priority:   ReservationPriorityHigh,
+		}
+	}
+
+	pending, err := manager.CreateReservation(ctx, newRequest("pending", "card0"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	cancelled, err := manager.CreateReservation(ctx, newRequest("cancelled", "card1"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	if err := manager.CancelReservation(cancelled.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+
+	updated, err := manager.CreateReservation(ctx, newRequest("updated", "card2"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	if _, err := manager.UpdateReservation(updated.ID, map[string]interface{}{"fraction": 0.75}); err != nil {
+		t.Fatalf("Failed to update reservation: %v", err)
+	}
+
+	// An active reservation whose end time passes while the process is down
+	ended, err := manager.CreateReservation(ctx, newRequest("ended", "card3"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	if _, err := manager.UpdateReservation(ended.ID, map[string]interface{}{
+		"status":   ReservationStatusActive,
+		"end_time": time.Now().Add(-1 * time.Minute),
+	}); err != nil {
+		t.Fatalf("Failed to update reservation: %v", err)
+	}
+
+	restarted := newTestManager(t, ReservationManagerConfig{Store: store})
+
+	if got, _ := restarted.ListReservations(nil); len(got) != 4 {
+		t.Fatalf("Expected 4 reservations after restart, got %d", len(got))
+	}
+
+	restored, exists := restarted.GetReservation(pending.ID)
+	if !exists {
+		t.Fatalf("Expected reservation %s to survive restart", pending.ID)
+	}
+	if restored.Status != ReservationStatusPending || restored.Priority != ReservationPriorityHigh || restored.GPUID != "card0" {
+		t.Errorf("Expected pending high priority reservation on card0, got %+v", restored)
+	}
+	if !restored.StartTime.Equal(pending.StartTime) {
+		t.Errorf("Expected start time %v, got %v", pending.StartTime, restored.StartTime)
+	}
+
+	if restored, _ := restarted.GetReservation(cancelled.ID); restored.Status != ReservationStatusCancelled {
+		t.Errorf("Expected cancelled status to survive restart, got %s", restored.Status)
+	}
+
+	if restored, _ := restarted.GetReservation(updated.ID); restored.Fraction != 0.75 {
+		t.Errorf("Expected updated fraction 0.75 to survive restart, got %f", restored.Fraction)
+	}
+
+	if restored, _ := restarted.GetReservation(ended.ID); restored.Status != ReservationStatusExpired {
+		t.Errorf("Expected reservation that ended during downtime to be expired, got %s", restored.Status)
+	}
+
+	// The expiry is written back so later restarts see it too
+	stored, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	for _, reservation := range stored {
+		if reservation.ID == ended.ID && reservation.Status != ReservationStatusExpired {
+			t.Errorf("Expected stored status expired, got %s", reservation.Status)
+		}
+	}
+}
+
+func TestStopEndsCleanupGoroutine(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	manager, err := NewGPUReservationManager(ReservationManagerConfig{CleanupInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create reservation manager: %v", err)
+	}
+
+	if runtime.NumGoroutine() <= baseline {
+		t.Fatalf("Expected cleanup goroutine to be running")
+	}
+
+	manager.Stop()
+	manager.Stop() // Stopping twice must not panic
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected goroutine count to return to %d after Stop, got %d", baseline, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGetActiveReservationsAt(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		MaxReservationsPerUser:   10,
+		ConflictResolutionPolicy: ConflictResolutionPolicyOverlap,
+		ReservationIDTemplate:    "res-{workload}-{uuid}",
+	})
+	ctx := context.Background()
+	base := time.Now().Add(1 * time.Hour)
+
+	create := func(workloadID, gpuID string, start time.Time, duration time.Duration, priority ReservationPriority) *GPUReservation {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      gpuID,
+			Fraction:   0.3,
+			StartTime:  start,
+			Duration:   duration,
+			Priority:   priority,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation %s: %v", workloadID, err)
+		}
+		return reservation
+	}
+
+	low := create("low", "card0", base, 3*time.Hour, ReservationPriorityLow)
+	urgent := create("urgent", "card0", base.Add(30*time.Minute), 2*time.Hour, ReservationPriorityUrgent)
+	normal := create("normal", "card0", base.Add(1*time.Hour), 1*time.Hour, ReservationPriorityNormal)
+	create("later", "card0", base.Add(2*time.Hour), 1*time.Hour, ReservationPriorityHigh)
+	cancelled := create("cancelled", "card0", base, 3*time.Hour, ReservationPriorityHigh)
+	other := create("other", "card1", base, 3*time.Hour, ReservationPriorityNormal)
+
+	if err := manager.CancelReservation(cancelled.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+
+	at := base.Add(90 * time.Minute)
+	active := manager.GetActiveReservationsAt("card0", at)
+	if len(active) != 3 {
+		t.Fatalf("Expected 3 reservations on card0 at instant, got %d", len(active))
+	}
+	for i, expected := range []*GPUReservation{urgent, normal, low} {
+		if active[i].ID != expected.ID {
+			t.Errorf("Expected reservation %d to be %s, got %s", i, expected.ID, active[i].ID)
+		}
+	}
+
+	// EndTime is exclusive
+	if reservations := manager.GetActiveReservationsAt("card0", normal.EndTime); len(reservations) != 3 {
+		t.Errorf("Expected reservation ending at the instant to be excluded, got %d reservations", len(reservations))
+	}
+
+	occupancy := manager.GetOccupancyAt(at)
+	if len(occupancy) != 2 {
+		t.Fatalf("Expected occupancy for 2 GPUs, got %d", len(occupancy))
+	}
+	if len(occupancy["card0"]) != 3 || occupancy["card0"][0].ID != urgent.ID {
+		t.Errorf("Expected card0 occupancy to match per-GPU query")
+	}
+	if len(occupancy["card1"]) != 1 || occupancy["card1"][0].ID != other.ID {
+		t.Errorf("Expected card1 occupied by %s", other.ID)
+	}
+
+	if reservations := manager.GetActiveReservationsAt("card0", base.Add(-time.Minute)); len(reservations) != 0 {
+		t.Errorf("Expected no reservations before any start, got %d", len(reservations))
+	}
+}
+
+func TestPendingReservationActivatesAtStartTime(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		ActivationInterval:    20 * time.Millisecond,
+		ReservationIDTemplate: "res-{workload}-{uuid}",
+	})
+	ctx := context.Background()
+
+	newRequest := func(workloadID, gpuID string) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      gpuID,
+			Fraction:   0.3,
+			StartTime:  time.Now().Add(300 * time.Millisecond),
+			Duration:   1 * time.Hour,
+		}
+	}
+
+	pending, err := manager.CreateReservation(ctx, newRequest("pending", "card0"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	cancelled, err := manager.CreateReservation(ctx, newRequest("cancelled", "card1"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	if err := manager.CancelReservation(cancelled.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+
+	if active, _ := manager.ListReservations(&ReservationFilters{Status: ReservationStatusActive}); len(active) != 0 {
+		t.Fatalf("Expected no active reservations before start time, got %d", len(active))
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		active, _ := manager.ListReservations(&ReservationFilters{Status: ReservationStatusActive})
+		if len(active) == 1 {
+			if active[0].ID != pending.ID {
+				t.Fatalf("Expected %s to activate, got %s", pending.ID, active[0].ID)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected pending reservation to activate at its start time")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if cancelledReservations, _ := manager.ListReservations(&ReservationFilters{Status: ReservationStatusCancelled}); len(cancelledReservations) != 1 {
+		t.Errorf("Expected cancelled reservation to stay cancelled, got %d cancelled", len(cancelledReservations))
+	}
+}
+
+func TestDueReservationWaitsForActiveCapacity(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		ConflictResolutionPolicy: "overlap",
+		ReservationIDTemplate:    "res-{workload}-{uuid}",
+	})
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	var reservations []*GPUReservation
+	for _, workloadID := range []string{"first", "second"} {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   0.75,
+			StartTime:  clock.Add(time.Minute),
+			Duration:   time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation %s: %v", workloadID, err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	// Both are due, but only one fits on the GPU at a time
+	clock = clock.Add(2 * time.Minute)
+	manager.activateDueReservations()
+
+	active, _ := manager.ListReservations(&ReservationFilters{Status: ReservationStatusActive})
+	pending, _ := manager.ListReservations(&ReservationFilters{Status: ReservationStatusPending})
+	if len(active) != 1 || len(pending) != 1 {
+		t.Fatalf("Expected one active and one pending reservation, got %d active and %d pending", len(active), len(pending))
+	}
+
+	// The other activates once the first completes
+	if err := manager.CompleteReservation(active[0].ID); err != nil {
+		t.Fatalf("Failed to complete reservation: %v", err)
+	}
+	manager.activateDueReservations()
+	if pending[0].Status != ReservationStatusActive {
+		t.Errorf("Expected %s to activate once capacity freed up, got %s", pending[0].WorkloadID, pending[0].Status)
+	}
+}
+
+// nextWeekday returns 22:00 on the first given weekday strictly after now
+func nextWeekday(day time.Weekday) time.Time {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 22, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	for start.Weekday() != day {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start
+}
+
+func newNightlyRequest(start time.Time, rule *RecurrenceRule) *ReservationRequest {
+	return &ReservationRequest{
+		UserID:     "user1",
+		WorkloadID: "nightly",
+		GPUID:      "card0",
+		Fraction:   1.0,
+		StartTime:  start,
+		Duration:   8 * time.Hour,
+		Recurrence: rule,
+	}
+}
+
+func TestRecurringReservationDailyExpansion(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+	start := nextWeekday(time.Monday)
+
+	first, err := manager.CreateReservation(context.Background(), newNightlyRequest(start, &RecurrenceRule{
+		Frequency: RecurrenceFrequencyDaily,
+		Until:     start.AddDate(0, 0, 6),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create recurring reservation: %v", err)
+	}
+
+	if first.SeriesID == "" {
+		t.Fatal("Expected recurring reservation to have a series ID")
+	}
+
+	series := manager.GetSeries(first.SeriesID)
+	if len(series) != 7 {
+		t.Fatalf("Expected 7 daily occurrences, got %d", len(series))
+	}
+
+	for i, occurrence := range series {
+		expectedStart := start.AddDate(0, 0, i)
+		if !occurrence.StartTime.Equal(expectedStart) {
+			t.Errorf("Expected occurrence %d to start at %v, got %v", i, expectedStart, occurrence.StartTime)
+		}
+		if occurrence.EndTime.Sub(occurrence.StartTime) != 8*time.Hour {
+			t.Errorf("Expected occurrence %d to last 8h, got %v", i, occurrence.EndTime.Sub(occurrence.StartTime))
+		}
+	}
+
+	// The series counts once towards the per-user limit
+	if stats := manager.GetReservationStats(); stats.PendingReservations != 7 {
+		t.Errorf("Expected 7 pending reservations, got %d", stats.PendingReservations)
+	}
+
+	// Every occurrence is considered when checking conflicts
+	conflicting := &ReservationRequest{
+		UserID:     "user2",
+		WorkloadID: "clash",
+		GPUID:      "card0",
+		Fraction:   0.5,
+		StartTime:  start.AddDate(0, 0, 5).Add(time.Hour),
+		Duration:   time.Hour,
+	}
+	if _, err := manager.CreateReservation(context.Background(), conflicting); err == nil {
+		t.Error("Expected conflict with a later occurrence of the series")
+	}
+}
+
+func TestRecurringReservationWeekdayFiltering(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+	start := nextWeekday(time.Monday)
+
+	first, err := manager.CreateReservation(context.Background(), newNightlyRequest(start, &RecurrenceRule{
+		Frequency: RecurrenceFrequencyDaily,
+		DaysOfWeek: []time.Weekday{
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+		},
+		Until: start.AddDate(0, 0, 13),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create recurring reservation: %v", err)
+	}
+
+	series := manager.GetSeries(first.SeriesID)
+	if len(series) != 10 {
+		t.Fatalf("Expected 10 weekday occurrences over two weeks, got %d", len(series))
+	}
+	for _, occurrence := range series {
+		if day := occurrence.StartTime.Weekday(); day == time.Saturday || day == time.Sunday {
+			t.Errorf("Expected no weekend occurrences, got one on %s", day)
+		}
+	}
+
+	weekly, err := manager.CreateReservation(context.Background(), &ReservationRequest{
+		UserID:     "user1",
+		WorkloadID: "weekly",
+		GPUID:      "card1",
+		Fraction:   1.0,
+		StartTime:  start,
+		Duration:   time.Hour,
+		Recurrence: &RecurrenceRule{Frequency: RecurrenceFrequencyWeekly, Until: start.AddDate(0, 0, 27)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create weekly reservation: %v", err)
+	}
+	if series := manager.GetSeries(weekly.SeriesID); len(series) != 4 {
+		t.Errorf("Expected 4 weekly occurrences, got %d", len(series))
+	}
+
+	invalid := newNightlyRequest(start, &RecurrenceRule{Frequency: RecurrenceFrequencyDaily, Until: start.Add(-time.Hour)})
+	invalid.GPUID = "card2"
+	if _, err := manager.CreateReservation(context.Background(), invalid); err == nil {
+		t.Error("Expected recurrence ending before its first occurrence to be rejected")
+	}
+}
+
+func TestCancelReservationSeries(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+	start := nextWeekday(time.Monday)
+
+	first, err := manager.CreateReservation(context.Background(), newNightlyRequest(start, &RecurrenceRule{
+		Frequency: RecurrenceFrequencyDaily,
+		Until:     start.AddDate(0, 0, 4),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create recurring reservation: %v", err)
+	}
+
+	series := manager.GetSeries(first.SeriesID)
+
+	// Cancelling a single occurrence leaves the rest of the series alone
+	if err := manager.CancelReservation(series[1].ID, false); err != nil {
+		t.Fatalf("Failed to cancel occurrence: %v", err)
+	}
+	if series[0].Status != ReservationStatusPending || series[2].Status != ReservationStatusPending {
+		t.Error("Expected other occurrences to stay pending")
+	}
+
+	if err := manager.CancelReservation(series[0].ID, true); err != nil {
+		t.Fatalf("Failed to cancel series: %v", err)
+	}
+	for i, occurrence := range series {
+		if occurrence.Status != ReservationStatusCancelled {
+			t.Errorf("Expected occurrence %d to be cancelled, got %s", i, occurrence.Status)
+		}
+	}
+}
+
+func TestReservationWaitlist(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		EnableWaitlist:        true,
+		ReservationIDTemplate: "res-{workload}-{uuid}",
+	})
+	ctx := context.Background()
+	start := time.Now().Add(1 * time.Hour)
+
+	create := func(userID, workloadID string, priority ReservationPriority) *GPUReservation {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     userID,
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   1.0,
+			StartTime:  start,
+			Duration:   2 * time.Hour,
+			Priority:   priority,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation %s: %v", workloadID, err)
+		}
+		return reservation
+	}
+
+	assertPositions := func(expected ...*GPUReservation) {
+		t.Helper()
+		for i, reservation := range expected {
+			position, err := manager.GetWaitlistPosition(reservation.ID)
+			if err != nil {
+				t.Fatalf("Failed to get waitlist position of %s: %v", reservation.WorkloadID, err)
+			}
+			if position != i+1 {
+				t.Errorf("Expected %s at position %d, got %d", reservation.WorkloadID, i+1, position)
+			}
+		}
+	}
+
+	blocker := create("user1", "blocker", ReservationPriorityNormal)
+	firstNormal := create("user2", "first-normal", ReservationPriorityNormal)
+	high := create("user3", "high", ReservationPriorityHigh)
+	secondNormal := create("user4", "second-normal", ReservationPriorityNormal)
+
+	for _, reservation := range []*GPUReservation{firstNormal, high, secondNormal} {
+		if reservation.Status != ReservationStatusQueued {
+			t.Errorf("Expected %s to be queued, got %s", reservation.WorkloadID, reservation.Status)
+		}
+	}
+	assertPositions(high, firstNormal, secondNormal)
+
+	if _, err := manager.GetWaitlistPosition(blocker.ID); err == nil {
+		t.Error("Expected error for the position of a reservation that is not queued")
+	}
+
+	// Completing the blocker promotes the highest priority request only
+	if err := manager.CompleteReservation(blocker.ID); err != nil {
+		t.Fatalf("Failed to complete blocker: %v", err)
+	}
+	if high.Status != ReservationStatusPending {
+		t.Errorf("Expected high priority request to be promoted to pending, got %s", high.Status)
+	}
+	assertPositions(firstNormal, secondNormal)
+
+	// Cancelling the promoted reservation hands the slot over first come first served
+	if err := manager.CancelReservation(high.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+	if firstNormal.Status != ReservationStatusPending {
+		t.Errorf("Expected first normal request to be promoted, got %s", firstNormal.Status)
+	}
+	if secondNormal.Status != ReservationStatusQueued {
+		t.Errorf("Expected second normal request to stay queued, got %s", secondNormal.Status)
+	}
+	assertPositions(secondNormal)
+}
+
+func TestCompleteQueuedReservation(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		EnableWaitlist:        true,
+		ReservationIDTemplate: "res-{workload}-{uuid}",
+	})
+	ctx := context.Background()
+	start := time.Now().Add(1 * time.Hour)
+
+	create := func(userID, workloadID string) *GPUReservation {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     userID,
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   1.0,
+			StartTime:  start,
+			Duration:   2 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation %s: %v", workloadID, err)
+		}
+		return reservation
+	}
+
+	blocker := create("user1", "blocker")
+	completed := create("user2", "completed")
+	updated := create("user3", "updated")
+	waiting := create("user4", "waiting")
+
+	// Completing a queued reservation, directly or through a status update, takes
+	// it off the waitlist
+	if err := manager.CompleteReservation(completed.ID); err != nil {
+		t.Fatalf("Failed to complete queued reservation: %v", err)
+	}
+	if _, err := manager.UpdateReservation(updated.ID, map[string]interface{}{
+		"status": ReservationStatusCompleted,
+	}); err != nil {
+		t.Fatalf("Failed to update queued reservation: %v", err)
+	}
+	if position, err := manager.GetWaitlistPosition(waiting.ID); err != nil || position != 1 {
+		t.Errorf("Expected waiting reservation at position 1, got %d (%v)", position, err)
+	}
+	if err := manager.CompleteReservation(completed.ID); err == nil {
+		t.Error("Expected error when completing a completed reservation")
+	}
+
+	// Freeing the slot promotes the reservation still waiting, not the completed ones
+	if err := manager.CompleteReservation(blocker.ID); err != nil {
+		t.Fatalf("Failed to complete blocker: %v", err)
+	}
+	if waiting.Status != ReservationStatusPending {
+		t.Errorf("Expected waiting reservation to be promoted, got %s", waiting.Status)
+	}
+	for _, reservation := range []*GPUReservation{completed, updated} {
+		if reservation.Status != ReservationStatusCompleted {
+			t.Errorf("Expected %s to stay completed, got %s", reservation.WorkloadID, reservation.Status)
+		}
+	}
+}
+
+func TestWaitlistPromotionAdmission(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		EnableWaitlist:           true,
+		ConflictResolutionPolicy: ConflictResolutionPolicyPriority,
+		MaxReservationsPerUser:   1,
+		ReservationIDTemplate:    "res-{workload}-{uuid}",
+	})
+	ctx := context.Background()
+	start := time.Now().Add(1 * time.Hour)
+
+	create := func(userID, workloadID, gpuID string, offset, duration time.Duration, priority ReservationPriority) *GPUReservation {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     userID,
+			WorkloadID: workloadID,
+			GPUID:      gpuID,
+			Fraction:   1.0,
+			StartTime:  start.Add(offset),
+			Duration:   duration,
+			Priority:   priority,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation %s: %v", workloadID, err)
+		}
+		return reservation
+	}
+
+	// The queued request conflicts with a blocker it cannot displace and with a
+	// lower priority reservation it can
+	blocker := create("user1", "blocker", "card0", 0, time.Hour, ReservationPriorityHigh)
+	create("user2", "displaceable", "card0", 2*time.Hour, time.Hour, ReservationPriorityNormal)
+	queued := create("user3", "queued", "card0", 0, 3*time.Hour, ReservationPriorityHigh)
+	if queued.Status != ReservationStatusQueued {
+		t.Fatalf("Expected request to be queued, got %s", queued.Status)
+	}
+
+	// Freeing the slot lets the queued request overlap the lower priority
+	// reservation, as it could have had it been created now
+	if err := manager.CompleteReservation(blocker.ID); err != nil {
+		t.Fatalf("Failed to complete blocker: %v", err)
+	}
+	if queued.Status != ReservationStatusPending {
+		t.Errorf("Expected queued request to be promoted, got %s", queued.Status)
+	}
+
+	// A request whose user has since reached their limit stays queued
+	otherBlocker := create("user4", "other-blocker", "card1", 0, time.Hour, ReservationPriorityHigh)
+	limited := create("user5", "limited", "card1", 0, time.Hour, ReservationPriorityHigh)
+	create("user5", "elsewhere", "card2", 0, time.Hour, ReservationPriorityHigh)
+	if err := manager.CompleteReservation(otherBlocker.ID); err != nil {
+		t.Fatalf("Failed to complete blocker: %v", err)
+	}
+	if limited.Status != ReservationStatusQueued {
+		t.Errorf("Expected request over its user's limit to stay queued, got %s", limited.Status)
+	}
+}
+
+func TestReservationWaitlistDisabled(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ReservationIDTemplate: "res-{workload}-{uuid}"})
+	ctx := context.Background()
+
+	request := &ReservationRequest{
+		UserID:     "user1",
+		WorkloadID: "blocker",
+		GPUID:      "card0",
+		Fraction:   1.0,
+		StartTime:  time.Now().Add(1 * time.Hour),
+		Duration:   time.Hour,
+	}
+	if _, err := manager.CreateReservation(ctx, request); err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	request.WorkloadID = "conflicting"
+	if _, err := manager.CreateReservation(ctx, request); err == nil {
+		t.Error("Expected conflicting request to be rejected without a waitlist")
+	}
+}
+
+func TestReservationFractionValidatedAgainstPartitionMode(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ReservationIDTemplate: "res-{workload}-{uuid}"})
+
+	allocator := gpumanager.NewMI300XFractionalAllocator()
+	if err := allocator.RegisterMI300XGPU("card0", 192*1024*1024*1024, &gpumanager.MI300XPartitionConfig{
+		ComputeMode: gpumanager.MI300XPartitionModeCPX,
+		MemoryMode:  gpumanager.MI300XMemoryModeNPS1,
+		XCDCount:    8,
+	}); err != nil {
+		t.Fatalf("Failed to register GPU: %v", err)
+	}
+	manager.SetFractionValidator("card0", allocator)
+
+	newRequest := func(gpuID string, fraction float64) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:         "user1",
+			WorkloadID:     fmt.Sprintf("workload-%s-%v", gpuID, fraction),
+			GPUID:          gpuID,
+			Fraction:       fraction,
+			StartTime:      time.Now().Add(1 * time.Hour),
+			Duration:       1 * time.Hour,
+			SharingEnabled: true,
+		}
+	}
+
+	if _, err := manager.CreateReservation(context.Background(), newRequest("card0", 0.3)); err == nil {
+		t.Error("Expected fraction 0.3 to be rejected on a CPX GPU")
+	}
+
+	if _, err := manager.CreateReservation(context.Background(), newRequest("card0", 0.375)); err != nil {
+		t.Errorf("Expected fraction 0.375 to be accepted on a CPX GPU, got %v", err)
+	}
+
+	// GPUs without partition info keep the generic range check
+	if _, err := manager.CreateReservation(context.Background(), newRequest("card1", 0.3)); err != nil {
+		t.Errorf("Expected fraction 0.3 to be accepted on an unpartitioned GPU, got %v", err)
+	}
+}
+
+func TestHoldReservationAutoResumes(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ReservationIDTemplate: "res-{workload}-{uuid}"})
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+		UserID:     "user1",
+		WorkloadID: "training",
+		GPUID:      "card0",
+		Fraction:   0.5,
+		StartTime:  clock.Add(48 * time.Hour),
+		Duration:   time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	if err := manager.HoldReservation(reservation.ID, clock.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Failed to hold reservation: %v", err)
+	}
+	if reservation.Status != ReservationStatusHeld {
+		t.Fatalf("Expected status 'held', got %s", reservation.Status)
+	}
+
+	// A held reservation frees its slot
+	if conflicts := manager.GetReservationConflicts(&ReservationRequest{
+		GPUID:     "card0",
+		Fraction:  0.8,
+		StartTime: reservation.StartTime,
+		Duration:  time.Hour,
+	}); len(conflicts) != 0 {
+		t.Errorf("Expected held reservation not to conflict, got %d conflicts", len(conflicts))
+	}
+
+	clock = clock.Add(12 * time.Hour)
+	manager.resumeDueHolds()
+	if reservation.Status != ReservationStatusHeld {
+		t.Fatalf("Expected reservation to stay held before the hold ends, got %s", reservation.Status)
+	}
+
+	clock = clock.Add(12 * time.Hour)
+	manager.resumeDueHolds()
+	if reservation.Status != ReservationStatusPending {
+		t.Fatalf("Expected reservation to return to pending when the hold ends, got %s", reservation.Status)
+	}
+	if !reservation.HeldUntil.IsZero() {
+		t.Errorf("Expected HeldUntil to be cleared, got %v", reservation.HeldUntil)
+	}
+}
+
+func TestResumeReservationEarly(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ReservationIDTemplate: "res-{workload}-{uuid}"})
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	newRequest := func(workloadID string) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   0.6,
+			StartTime:  clock.Add(48 * time.Hour),
+			Duration:   time.Hour,
+		}
+	}
+
+	reservation, err := manager.CreateReservation(ctx, newRequest("training"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	if err := manager.ResumeReservation(reservation.ID); err == nil {
+		t.Error("Expected error when resuming a reservation that is not held")
+	}
+
+	if err := manager.HoldReservation(reservation.ID, clock.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Failed to hold reservation: %v", err)
+	}
+
+	clock = clock.Add(time.Hour)
+	if err := manager.ResumeReservation(reservation.ID); err != nil {
+		t.Fatalf("Failed to resume reservation: %v", err)
+	}
+	if reservation.Status != ReservationStatusPending {
+		t.Fatalf("Expected status 'pending' after resume, got %s", reservation.Status)
+	}
+
+	// A slot taken while the reservation was held blocks resuming it
+	if err := manager.HoldReservation(reservation.ID, clock.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Failed to hold reservation: %v", err)
+	}
+	if _, err := manager.CreateReservation(ctx, newRequest("inference")); err != nil {
+		t.Fatalf("Failed to take the held slot: %v", err)
+	}
+
+	if err := manager.ResumeReservation(reservation.ID); err == nil {
+		t.Error("Expected error when resuming into a taken slot")
+	}
+	if reservation.Status != ReservationStatusHeld {
+		t.Errorf("Expected reservation to stay held after a failed resume, got %s", reservation.Status)
+	}
+}
+
+func TestResumeReservationChecksLimits(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		ReservationIDTemplate:  "res-{workload}-{uuid}",
+		MaxReservationsPerUser: 1,
+	})
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	newRequest := func(workloadID, gpuID string) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      gpuID,
+			Fraction:   0.5,
+			StartTime:  clock.Add(48 * time.Hour),
+			Duration:   time.Hour,
+		}
+	}
+
+	reservation, err := manager.CreateReservation(ctx, newRequest("training", "card0"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	if err := manager.HoldReservation(reservation.ID, clock.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Failed to hold reservation: %v", err)
+	}
+
+	// The held reservation frees the user's only reservation for another
+	if _, err := manager.CreateReservation(ctx, newRequest("inference", "card1")); err != nil {
+		t.Fatalf("Failed to create a reservation while the first is held: %v", err)
+	}
+
+	err = manager.ResumeReservation(reservation.ID)
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded when resuming over the user limit, got %v", err)
+	}
+	if reservation.Status != ReservationStatusHeld {
+		t.Errorf("Expected reservation to stay held after a failed resume, got %s", reservation.Status)
+	}
+}
+
+func TestReservationCapacityPartitions(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		ConflictResolutionPolicy: ConflictResolutionPolicyOverlap,
+		ReservationIDTemplate:    "res-{workload}-{uuid}",
+		CapacityPartitions:       types.CapacityPartitions{int(ReservationPriorityUrgent): 0.25},
+	})
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour)
+	newRequest := func(workloadID string, fraction float64, priority ReservationPriority) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   fraction,
+			StartTime:  start,
+			Duration:   time.Hour,
+			Priority:   priority,
+		}
+	}
+
+	if _, err := manager.CreateReservation(ctx, newRequest("normal-large", 0.8, ReservationPriorityNormal)); err == nil {
+		t.Error("Expected a normal reservation larger than the unpartitioned share to be rejected")
+	}
+
+	if _, err := manager.CreateReservation(ctx, newRequest("normal", 0.5, ReservationPriorityNormal)); err != nil {
+		t.Fatalf("Failed to create normal reservation: %v", err)
+	}
+
+	// Only 0.25 of the unpartitioned share is left for normal priority
+	if _, err := manager.CreateReservation(ctx, newRequest("normal-encroaching", 0.3, ReservationPriorityNormal)); err == nil {
+		t.Error("Expected a normal reservation encroaching on the urgent partition to be rejected")
+	}
+
+	// Urgent reservations may use the whole GPU, including the urgent partition
+	if _, err := manager.CreateReservation(ctx, newRequest("urgent", 0.5, ReservationPriorityUrgent)); err != nil {
+		t.Errorf("Expected urgent reservation to use the urgent partition, got %v", err)
+	}
+}
+
+func TestInvalidCapacityPartitions(t *testing.T) {
+	_, err := NewGPUReservationManager(ReservationManagerConfig{
+		CapacityPartitions: types.CapacityPartitions{
+			int(ReservationPriorityHigh):   0.6,
+			int(ReservationPriorityUrgent): 0.6,
+		},
+	})
+	if err == nil {
+		t.Error("Expected partitions reserving more than the whole GPU to be rejected")
+	}
+}
+
+// recordingEventHandler records lifecycle events as "<event>:<workload>"
+type recordingEventHandler struct {
+	manager *GPUReservationManager
+	events  []string
+}
+
+func (h *recordingEventHandler) record(event string, reservation *GPUReservation) {
+	// Calling back into the manager must not deadlock
+	if _, exists := h.manager.GetReservation(reservation.ID); !exists {
+		event += "(missing)"
+	}
+	h.events = append(h.events, event+":"+reservation.WorkloadID)
+}
+
+func (h *recordingEventHandler) OnCreated(reservation *GPUReservation) {
+	h.record("created", reservation)
+}
+
+func (h *recordingEventHandler) OnActivated(reservation *GPUReservation) {
+	h.record("activated", reservation)
+}
+
+func (h *recordingEventHandler) OnCompleted(reservation *GPUReservation) {
+	h.record("completed", reservation)
+}
+
+func (h *recordingEventHandler) OnCancelled(reservation *GPUReservation) {
+	h.record("cancelled", reservation)
+}
+
+func (h *recordingEventHandler) OnExpired(reservation *GPUReservation) {
+	h.record("expired", reservation)
+}
+
+func (h *recordingEventHandler) OnQueuePositionChanged(reservation *GPUReservation, position int) {
+	h.record(fmt.Sprintf("position%d", position), reservation)
+}
+
+func TestReservationLifecycleEvents(t *testing.T) {
+	handler := &recordingEventHandler{}
+	manager := newTestManager(t, ReservationManagerConfig{
+		ReservationIDTemplate: "res-{workload}-{uuid}",
+		EventHandler:          handler,
+	})
+	handler.manager = manager
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	newRequest := func(workloadID, gpuID string) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workloadID,
+			GPUID:      gpuID,
+			Fraction:   0.5,
+			StartTime:  clock.Add(time.Minute),
+			Duration:   5 * time.Minute,
+		}
+	}
+
+	short, err := manager.CreateReservation(ctx, newRequest("short", "card0"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	completed, err := manager.CreateReservation(ctx, newRequest("completed", "card1"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	cancelled, err := manager.CreateReservation(ctx, newRequest("cancelled", "card2"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	if err := manager.CancelReservation(cancelled.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+
+	// The background loop activates the reservations at their start time...
+	clock = clock.Add(2 * time.Minute)
+	manager.activateDueReservations()
+
+	if err := manager.CompleteReservation(completed.ID); err != nil {
+		t.Fatalf("Failed to complete reservation: %v", err)
+	}
+
+	// ...and expires the one still running past its end time
+	clock = clock.Add(10 * time.Minute)
+	manager.expireReservations()
+
+	if status := short.Status; status != ReservationStatusExpired {
+		t.Fatalf("Expected short reservation to expire, got %s", status)
+	}
+
+	// The two activations happen in one pass in map order
+	if len(handler.events) > 6 {
+		slices.Sort(handler.events[4:6])
+	}
+
+	expected := []string{
+		"created:short",
+		"created:completed",
+		"created:cancelled",
+		"cancelled:cancelled",
+		"activated:completed",
+		"activated:short",
+		"completed:completed",
+		"expired:short",
+	}
+	if !slices.Equal(handler.events, expected) {
+		t.Errorf("Expected events %v, got %v", expected, handler.events)
+	}
+}
+
+func TestExpireReservationsThatNeverActivated(t *testing.T) {
+	handler := &recordingEventHandler{}
+	store := NewFileReservationStore(filepath.Join(t.TempDir(), "reservations.json"))
+	manager := newTestManager(t, ReservationManagerConfig{
+		EnableWaitlist:        true,
+		ReservationIDTemplate: "res-{workload}-{uuid}",
+		EventHandler:          handler,
+		Store:                 store,
+	})
+	handler.manager = manager
+	ctx := context.Background()
+
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+
+	create := func(userID, workloadID string) *GPUReservation {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     userID,
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   1.0,
+			StartTime:  clock.Add(time.Minute),
+			Duration:   5 * time.Minute,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation %s: %v", workloadID, err)
+		}
+		return reservation
+	}
+
+	pending := create("user1", "pending")
+	queued := create("user2", "queued")
+	if pending.Status != ReservationStatusPending || queued.Status != ReservationStatusQueued {
+		t.Fatalf("Expected pending and queued reservations, got %s and %s", pending.Status, queued.Status)
+	}
+	handler.events = nil
+
+	// The activation pass never runs before both reservations' end time
+	clock = clock.Add(10 * time.Minute)
+	manager.expireReservations()
+
+	for _, reservation := range []*GPUReservation{pending, queued} {
+		if reservation.Status != ReservationStatusExpired {
+			t.Errorf("Expected %s reservation to expire, got %s", reservation.WorkloadID, reservation.Status)
+		}
+	}
+	if _, err := manager.GetWaitlistPosition(queued.ID); err == nil {
+		t.Error("Expected the expired reservation to leave the waitlist")
+	}
+
+	slices.Sort(handler.events)
+	expected := []string{"expired:pending", "expired:queued"}
+	if !slices.Equal(handler.events, expected) {
+		t.Errorf("Expected events %v, got %v", expected, handler.events)
+	}
+
+	stored, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	for _, reservation := range stored {
+		if reservation.Status != ReservationStatusExpired {
+			t.Errorf("Expected stored %s reservation to be expired, got %s", reservation.WorkloadID, reservation.Status)
+		}
+	}
+}
+
+func TestCancelQueuedReservation(t *testing.T) {
+	handler := &recordingEventHandler{}
+	manager := newTestManager(t, ReservationManagerConfig{
+		EnableWaitlist:        true,
+		ReservationIDTemplate: "res-{workload}-{uuid}",
+		EventHandler:          handler,
+	})
+	handler.manager = manager
+	ctx := context.Background()
+	start := time.Now().Add(1 * time.Hour)
+
+	create := func(userID, workloadID string) *GPUReservation {
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     userID,
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   1.0,
+			StartTime:  start,
+			Duration:   2 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation %s: %v", workloadID, err)
+		}
+		return reservation
+	}
+
+	blocker := create("user1", "blocker")
+	first := create("user2", "first")
+	middle := create("user3", "middle")
+	last := create("user4", "last")
+	handler.events = nil
+
+	if err := manager.CancelQueuedReservation(blocker.ID); err == nil {
+		t.Error("Expected error when queue-cancelling a reservation that is not queued")
+	}
+	if blocker.Status != ReservationStatusPending {
+		t.Errorf("Expected rejected queue-cancel to leave the reservation pending, got %s", blocker.Status)
+	}
+
+	if err := manager.CancelQueuedReservation(middle.ID); err != nil {
+		t.Fatalf("Failed to cancel queued reservation: %v", err)
+	}
+	if middle.Status != ReservationStatusCancelled {
+		t.Errorf("Expected cancelled status, got %s", middle.Status)
+	}
+	if _, err := manager.GetWaitlistPosition(middle.ID); err == nil {
+		t.Error("Expected cancelled reservation to leave the waitlist")
+	}
+
+	for expected, reservation := range []*GPUReservation{first, last} {
+		position, err := manager.GetWaitlistPosition(reservation.ID)
+		if err != nil {
+			t.Fatalf("Failed to get waitlist position of %s: %v", reservation.WorkloadID, err)
+		}
+		if position != expected+1 {
+			t.Errorf("Expected %s at position %d, got %d", reservation.WorkloadID, expected+1, position)
+		}
+	}
+
+	// Only the reservation behind the cancelled one moved up
+	expectedEvents := []string{"cancelled:middle", "position2:last"}
+	if !slices.Equal(handler.events, expectedEvents) {
+		t.Errorf("Expected events %v, got %v", expectedEvents, handler.events)
+	}
+}
+
+func TestReservationBlackoutWindows(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{
+		ReservationIDTemplate: "res-{workload}-{uuid}",
+		Blackouts: []BlackoutWindow{{
+			Name:     "sunday-maintenance",
+			Days:     []time.Weekday{time.Sunday},
+			Start:    2 * time.Hour,
+			Duration: 2 * time.Hour,
+			GPUIDs:   []string{"card0"},
+		}},
+	})
+
+	now := time.Now().UTC()
+	sunday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	for sunday.Weekday() != time.Sunday {
+		sunday = sunday.AddDate(0, 0, 1)
+	}
+
+	request := func(workload, gpuID string, start time.Time, duration time.Duration) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user1",
+			WorkloadID: workload,
+			GPUID:      gpuID,
+			Fraction:   0.5,
+			StartTime:  start,
+			Duration:   duration,
+		}
+	}
+
+	// Saturday 22:00 to Sunday 06:00 spans the window
+	_, err := manager.CreateReservation(context.Background(), request("overnight", "card0", sunday.Add(-2*time.Hour), 8*time.Hour))
+	if err == nil {
+		t.Fatal("Expected reservation overlapping the blackout to be rejected")
+	}
+	if !strings.Contains(err.Error(), "sunday-maintenance") {
+		t.Errorf("Expected error to name the blackout window, got %v", err)
+	}
+
+	// Ending as the window opens and starting as it closes both fit around it
+	if _, err := manager.CreateReservation(context.Background(), request("before", "card0", sunday.Add(-2*time.Hour), 4*time.Hour)); err != nil {
+		t.Errorf("Expected reservation ending at the blackout start to be accepted: %v", err)
+	}
+	if _, err := manager.CreateReservation(context.Background(), request("after", "card0", sunday.Add(4*time.Hour), 2*time.Hour)); err != nil {
+		t.Errorf("Expected reservation starting at the blackout end to be accepted: %v", err)
+	}
+
+	// The window only applies to card0
+	if _, err := manager.CreateReservation(context.Background(), request("other-gpu", "card1", sunday.Add(-2*time.Hour), 8*time.Hour)); err != nil {
+		t.Errorf("Expected reservation on an unaffected GPU to be accepted: %v", err)
+	}
+
+	// Nightly occurrences starting Wednesday through the following Tuesday skip
+	// the Saturday night that runs into the window
+	first, err := manager.CreateReservation(context.Background(), &ReservationRequest{
+		UserID:     "user2",
+		WorkloadID: "nightly",
+		GPUID:      "card0",
+		Fraction:   0.5,
+		StartTime:  sunday.AddDate(0, 0, 3).Add(22 * time.Hour),
+		Duration:   6 * time.Hour,
+		Recurrence: &RecurrenceRule{
+			Frequency: RecurrenceFrequencyDaily,
+			Until:     sunday.AddDate(0, 0, 9).Add(22 * time.Hour),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create recurring reservation: %v", err)
+	}
+
+	series := manager.GetSeries(first.SeriesID)
+	if len(series) != 6 {
+		t.Fatalf("Expected 6 occurrences with the blacked out night skipped, got %d", len(series))
+	}
+	for _, occurrence := range series {
+		if occurrence.StartTime.Weekday() == time.Saturday {
+			t.Errorf("Expected no occurrence on Saturday night, got one at %v", occurrence.StartTime)
+		}
+	}
+}
+
+func TestInvalidBlackoutWindow(t *testing.T) {
+	_, err := NewGPUReservationManager(ReservationManagerConfig{
+		Blackouts: []BlackoutWindow{{Name: "maintenance", Start: 25 * time.Hour, Duration: time.Hour}},
+	})
+	if err == nil {
+		t.Error("Expected blackout window starting after the end of the day to be rejected")
+	}
+}
+
+func TestGetReservationUtilization(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{})
+	clock := time.Now().Truncate(time.Hour)
+	manager.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	create := func(userID, gpuID string, fraction float64, start, duration time.Duration) *GPUReservation {
+		t.Helper()
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     userID,
+			WorkloadID: "train",
+			GPUID:      gpuID,
+			Fraction:   fraction,
+			StartTime:  clock.Add(start),
+			Duration:   duration,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+		return reservation
+	}
+
+	create("alice", "card0", 0.5, time.Hour, 2*time.Hour)               // 1 GPU-hour
+	completed := create("alice", "card1", 0.25, time.Hour, 4*time.Hour) // 1 GPU-hour
+	create("bob", "card0", 1.0, 4*time.Hour, 3*time.Hour)               // 3 GPU-hours
+	cancelled := create("bob", "card2", 0.5, time.Hour, 2*time.Hour)    // 1 GPU-hour
+	if err := manager.CompleteReservation(completed.ID); err != nil {
+		t.Fatalf("Failed to complete reservation: %v", err)
+	}
+	if err := manager.CancelReservation(cancelled.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+
+	assertHours := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("Expected %s to be %.2f GPU-hours, got %f", name, want, got)
+		}
+	}
+
+	utilization := manager.GetReservationUtilization(nil)
+	assertHours("total", utilization.TotalGPUHours, 6)
+	assertHours("pending", utilization.GPUHoursByStatus["pending"], 4)
+	assertHours("completed", utilization.GPUHoursByStatus["completed"], 1)
+	assertHours("cancelled", utilization.GPUHoursByStatus["cancelled"], 1)
+	assertHours("alice", utilization.GPUHoursByUser["alice"].Total, 2)
+	assertHours("alice completed", utilization.GPUHoursByUser["alice"].ByStatus["completed"], 1)
+	assertHours("bob", utilization.GPUHoursByUser["bob"].Total, 4)
+	assertHours("bob cancelled", utilization.GPUHoursByUser["bob"].ByStatus["cancelled"], 1)
+	assertHours("card0", utilization.GPUHoursByGPU["card0"].Total, 4)
+	assertHours("card1", utilization.GPUHoursByGPU["card1"].Total, 1)
+	assertHours("card2", utilization.GPUHoursByGPU["card2"].Total, 1)
+
+	// Between hours 2 and 5 only the overlapping part of each reservation counts
+	window := &ReservationFilters{StartTime: clock.Add(2 * time.Hour), EndTime: clock.Add(5 * time.Hour)}
+	utilization = manager.GetReservationUtilization(window)
+	assertHours("windowed total", utilization.TotalGPUHours, 0.5+0.75+1+0.5)
+	assertHours("windowed card1", utilization.GPUHoursByGPU["card1"].Total, 0.75)
+
+	window.UserID = "bob"
+	window.Status = ReservationStatusPending
+	utilization = manager.GetReservationUtilization(window)
+	assertHours("bob's windowed pending", utilization.TotalGPUHours, 1)
+	if len(utilization.GPUHoursByUser) != 1 || len(utilization.GPUHoursByGPU) != 1 {
+		t.Errorf("Expected only bob's card0 reservation, got users %v and GPUs %v",
+			utilization.GPUHoursByUser, utilization.GPUHoursByGPU)
+	}
+
+	var csv strings.Builder
+	if err := manager.GetReservationUtilization(&ReservationFilters{UserID: "alice"}).WriteCSV(&csv); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	want := "scope,id,status,gpu_hours\n" +
+		"user,alice,completed,1.0000\n" +
+		"user,alice,pending,1.0000\n" +
+		"gpu,card0,pending,1.0000\n" +
+		"gpu,card1,completed,1.0000\n"
+	if csv.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", csv.String(), want)
+	}
+}
+
+func TestTransferReservation(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{MaxReservationsPerUser: 1})
+	clock := time.Now()
+	manager.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	newRequest := func(userID, gpuID string) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:      userID,
+			WorkloadID:  "train",
+			GPUID:       gpuID,
+			Fraction:    0.5,
+			StartTime:   clock.Add(time.Hour),
+			Duration:    2 * time.Hour,
+			Annotations: map[string]string{"team": "vision"},
+		}
+	}
+
+	reservation, err := manager.CreateReservation(ctx, newRequest("alice", "card0"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	bobs, err := manager.CreateReservation(ctx, newRequest("bob", "card1"))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	// bob already holds the one reservation allowed per user
+	if err := manager.TransferReservation(reservation.ID, "bob"); err == nil || !strings.Contains(err.Error(), "user limits exceeded") {
+		t.Fatalf("Expected the transfer to be blocked by bob's limit, got %v", err)
+	}
+	if reservation.UserID != "alice" || reservation.Annotations[AnnotationOriginalOwner] != "" {
+		t.Errorf("Expected a blocked transfer to leave the reservation unchanged, got %+v", reservation)
+	}
+
+	clock = clock.Add(time.Minute)
+	if err := manager.TransferReservation(reservation.ID, "carol"); err != nil {
+		t.Fatalf("Failed to transfer reservation: %v", err)
+	}
+	if reservation.UserID != "carol" || !reservation.UpdatedAt.Equal(clock) {
+		t.Errorf("Expected carol to own the reservation as of the transfer, got %s at %v", reservation.UserID, reservation.UpdatedAt)
+	}
+	if owned, _ := manager.ListReservations(&ReservationFilters{UserID: "carol"}); len(owned) != 1 {
+		t.Errorf("Expected carol to own 1 reservation, got %d", len(owned))
+	}
+
+	// Transferring frees a slot for the previous owner
+	if _, err := manager.CreateReservation(ctx, newRequest("alice", "card2")); err != nil {
+		t.Errorf("Expected alice to be able to reserve again after the transfer, got %v", err)
+	}
+
+	clock = clock.Add(time.Minute)
+	if err := manager.TransferReservation(reservation.ID, "dave"); err != nil {
+		t.Fatalf("Failed to transfer reservation: %v", err)
+	}
+	want := map[string]string{
+		"team":                    "vision",
+		AnnotationOriginalOwner:   "alice",
+		AnnotationTransferredFrom: "carol",
+	}
+	if !maps.Equal(reservation.Annotations, want) {
+		t.Errorf("Expected annotations %v, got %v", want, reservation.Annotations)
+	}
+
+	if err := manager.TransferReservation(reservation.ID, "dave"); !errors.Is(err, ErrInvalidReservation) {
+		t.Errorf("Expected transferring to the current owner to be invalid, got %v", err)
+	}
+	if err := manager.TransferReservation("missing", "dave"); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Expected ErrReservationNotFound, got %v", err)
+	}
+
+	if err := manager.CancelReservation(bobs.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+	if err := manager.TransferReservation(bobs.ID, "erin"); err == nil {
+		t.Error("Expected transferring a cancelled reservation to fail")
+	}
+}
+
+func TestPriorityConflictResolution(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ConflictResolutionPolicy: ConflictResolutionPolicyPriority})
+	ctx := context.Background()
+	start := time.Now().Add(time.Hour)
+
+	newRequest := func(workloadID string, priority ReservationPriority, offset time.Duration) *ReservationRequest {
+		return &ReservationRequest{
+			UserID:     "user-" + workloadID,
+			WorkloadID: workloadID,
+			GPUID:      "card0",
+			Fraction:   1.0,
+			StartTime:  start.Add(offset),
+			Duration:   2 * time.Hour,
+			Priority:   priority,
+		}
+	}
+
+	low, err := manager.CreateReservation(ctx, newRequest("low", ReservationPriorityLow, 0))
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	// A request of equal priority is held to the strict rules
+	if _, err := manager.CreateReservation(ctx, newRequest("also-low", ReservationPriorityLow, 30*time.Minute)); !errors.Is(err, ErrReservationConflict) {
+		t.Fatalf("Expected an equal-priority overlap to conflict, got %v", err)
+	}
+
+	// The conflicts report both priorities so callers can decide what to displace
+	urgentRequest := newRequest("urgent", ReservationPriorityUrgent, 30*time.Minute)
+	conflicts := manager.GetReservationConflicts(urgentRequest)
+	if len(conflicts) != 1 || conflicts[0].ReservationID != low.ID {
+		t.Fatalf("Expected one conflict with %s, got %v", low.ID, conflicts)
+	}
+	if conflicts[0].RequestPriority != ReservationPriorityUrgent || conflicts[0].ConflictingPriority != ReservationPriorityLow ||
+		!conflicts[0].Displaceable() {
+		t.Errorf("Expected a displaceable low-priority conflict for an urgent request, got %+v", conflicts[0])
+	}
+
+	// A higher-priority request overlaps the lower-priority reservation
+	urgent, err := manager.CreateReservation(ctx, urgentRequest)
+	if err != nil {
+		t.Fatalf("Expected the urgent reservation to be admitted, got %v", err)
+	}
+	if urgent.Status != ReservationStatusPending || low.Status != ReservationStatusPending {
+		t.Errorf("Expected both reservations pending, got %s and %s", urgent.Status, low.Status)
+	}
+
+	// A high-priority request outranks low but not urgent, so it is rejected
+	if _, err := manager.CreateReservation(ctx, newRequest("high", ReservationPriorityHigh, time.Hour)); !errors.Is(err, ErrReservationConflict) {
+		t.Errorf("Expected an overlap with a higher-priority reservation to conflict, got %v", err)
+	}
+	conflicts = manager.GetReservationConflicts(newRequest("high", ReservationPriorityHigh, time.Hour))
+	displaceable := 0
+	for _, conflict := range conflicts {
+		if conflict.Displaceable() {
+			displaceable++
+		}
+	}
+	if len(conflicts) != 2 || displaceable != 1 {
+		t.Errorf("Expected 2 conflicts of which 1 is displaceable, got %d and %d", len(conflicts), displaceable)
+	}
+}
+
+func TestGetGPUSchedule(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ConflictResolutionPolicy: ConflictResolutionPolicyOverlap})
+	ctx := context.Background()
+	base := time.Now().Add(time.Hour).Truncate(time.Hour)
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	create := func(workloadID, gpuID string, fraction float64, start, end int) *GPUReservation {
+		t.Helper()
+		reservation, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:         "user-" + workloadID,
+			WorkloadID:     workloadID,
+			GPUID:          gpuID,
+			Fraction:       fraction,
+			StartTime:      at(start),
+			Duration:       at(end).Sub(at(start)),
+			SharingEnabled: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+		return reservation
+	}
+
+	a := create("a", "card0", 0.5, 1, 3)
+	b := create("b", "card0", 0.25, 2, 4) // Overlaps a
+	c := create("c", "card0", 0.5, 4, 5)  // Adjacent to b
+	d := create("d", "card0", 1.0, 7, 12) // Runs past the window
+	cancelled := create("cancelled", "card0", 1.0, 5, 6)
+	create("other-gpu", "card1", 1.0, 5, 6)
+	if err := manager.CancelReservation(cancelled.ID, false); err != nil {
+		t.Fatalf("Failed to cancel reservation: %v", err)
+	}
+
+	schedule, err := manager.GetGPUSchedule("card0", at(0), at(10))
+	if err != nil {
+		t.Fatalf("GetGPUSchedule failed: %v", err)
+	}
+
+	wantBusy := []BusyInterval{
+		{Start: at(1), End: at(2), Fraction: 0.5, ReservationIDs: []string{a.ID}},
+		{Start: at(2), End: at(3), Fraction: 0.75, ReservationIDs: []string{a.ID, b.ID}},
+		{Start: at(3), End: at(4), Fraction: 0.25, ReservationIDs: []string{b.ID}},
+		{Start: at(4), End: at(5), Fraction: 0.5, ReservationIDs: []string{c.ID}},
+		{Start: at(7), End: at(10), Fraction: 1.0, ReservationIDs: []string{d.ID}},
+	}
+	if len(schedule.Busy) != len(wantBusy) {
+		t.Fatalf("Expected %d busy intervals, got %+v", len(wantBusy), schedule.Busy)
+	}
+	for i, want := range wantBusy {
+		got := schedule.Busy[i]
+		if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) || math.Abs(got.Fraction-want.Fraction) > 1e-9 ||
+			!slices.Equal(got.ReservationIDs, want.ReservationIDs) {
+			t.Errorf("Busy interval %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	// The cancelled reservation and the other GPU's leave hours 5 to 7 free
+	wantFree := []FreeInterval{{Start: at(0), End: at(1)}, {Start: at(5), End: at(7)}}
+	if len(schedule.Free) != len(wantFree) {
+		t.Fatalf("Expected %d free intervals, got %+v", len(wantFree), schedule.Free)
+	}
+	for i, want := range wantFree {
+		if got := schedule.Free[i]; !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+			t.Errorf("Free interval %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	// A window with no reservations is entirely free
+	schedule, err = manager.GetGPUSchedule("card2", at(0), at(10))
+	if err != nil {
+		t.Fatalf("GetGPUSchedule failed: %v", err)
+	}
+	if len(schedule.Busy) != 0 || len(schedule.Free) != 1 || !schedule.Free[0].Start.Equal(at(0)) || !schedule.Free[0].End.Equal(at(10)) {
+		t.Errorf("Expected card2 free for the whole window, got %+v", schedule)
+	}
+
+	if _, err := manager.GetGPUSchedule("card0", at(10), at(0)); err == nil {
+		t.Error("Expected an inverted window to fail")
+	}
+}
+
+func TestSuggestAlternatives(t *testing.T) {
+	manager := newTestManager(t, ReservationManagerConfig{ConflictResolutionPolicy: ConflictResolutionPolicyStrict})
+	ctx := context.Background()
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	create := func(workloadID, gpuID string, start, end int) {
+		t.Helper()
+		if _, err := manager.CreateReservation(ctx, &ReservationRequest{
+			UserID:     "user-" + workloadID,
+			WorkloadID: workloadID,
+			GPUID:      gpuID,
+			Fraction:   1.0,
+			StartTime:  at(start),
+			Duration:   at(end).Sub(at(start)),
+		}); err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+	}
+
+	// card0 is busy from hour 2 to 5 and 6 to 8, leaving a gap too short for two hours
+	create("a", "card0", 2, 5)
+	create("b", "card0", 6, 8)
+	// card1 is busy from hour 1 to 4
+	create("c", "card1", 1, 4)
+
+	request := &ReservationRequest{
+		UserID:     "requester",
+		WorkloadID: "wanted",
+		GPUID:      "card0",
+		Fraction:   1.0,
+		StartTime:  at(4),
+		Duration:   2 * time.Hour,
+	}
+	if _, err := manager.CreateReservation(ctx, request); !errors.Is(err, ErrReservationConflict) {
+		t.Fatalf("Expected the request to conflict, got %v", err)
+	}
+
+	suggestions := manager.SuggestAlternatives(request, 5)
+	// Two hours before card0's first reservation, then after its last
+	wantStarts := []time.Time{at(0).Add(-suggestionGap), at(8).Add(suggestionGap)}
+	if len(suggestions) != len(wantStarts) {
+		t.Fatalf("Expected %d suggestions, got %d", len(wantStarts), len(suggestions))
+	}
+	for i, suggestion := range suggestions {
+		if suggestion.GPUID != "card0" || !suggestion.StartTime.Equal(wantStarts[i]) {
+			t.Errorf("Suggestion %d: expected card0 at %v, got %s at %v", i, wantStarts[i], suggestion.GPUID, suggestion.StartTime)
+		}
+		if suggestion.Duration != request.Duration || suggestion.Fraction != request.Fraction {
+			t.Errorf("Suggestion %d changed the duration or fraction: %+v", i, suggestion)
+		}
+	}
+
+	// Allowing card1 adds a slot just after its reservation, and all are ordered by how far they move the request
+	suggestions = manager.SuggestAlternatives(request, 3, "card1", "card0")
+	if len(suggestions) != 3 {
+		t.Fatalf("Expected 3 suggestions, got %d", len(suggestions))
+	}
+	if want := at(4).Add(suggestionGap); suggestions[0].GPUID != "card1" || !suggestions[0].StartTime.Equal(want) {
+		t.Errorf("Expected card1 at %v first, got %s at %v", want, suggestions[0].GPUID, suggestions[0].StartTime)
+	}
+	previous := time.Duration(0)
+	for i, suggestion := range suggestions {
+		distance := suggestion.StartTime.Sub(request.StartTime).Abs()
+		if distance < previous {
+			t.Errorf("Suggestion %d at %v is nearer than the one before it", i, suggestion.StartTime)
+		}
+		previous = distance
+
+		if conflicts := manager.GetReservationConflicts(suggestion); len(conflicts) > 0 {
+			t.Errorf("Suggestion %d on %s at %v overlaps %v", i, suggestion.GPUID, suggestion.StartTime, conflicts)
+		}
+	}
+
+	// Every suggestion can be booked
+	if _, err := manager.CreateReservation(ctx, suggestions[1]); err != nil {
+		t.Errorf("Failed to book suggestion: %v", err)
+	}
+
+	if suggestions := manager.SuggestAlternatives(request, 0); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions when none are asked for, got %d", len(suggestions))
+	}
+}
@@ -0,0 +1,156 @@
+package reservation
+
+import (
+	"fmt"
+	"time"
+)
+
+// HoldReservation suspends a pending reservation until the given time. A held
+// reservation does not occupy its slot and never activates; once until is
+// reached it returns to pending. Holding an occurrence of a recurring series
+// also holds every later pending occurrence that starts before until.
+func (r *GPUReservationManager) HoldReservation(id string, until time.Time) error {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, exists := r.reservations[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrReservationNotFound, id)
+	}
+
+	if reservation.Status != ReservationStatusPending && reservation.Status != ReservationStatusHeld {
+		return fmt.Errorf("%w: cannot hold reservation in status %s", ErrInvalidTransition, reservation.Status)
+	}
+
+	if !until.After(r.now()) {
+		return fmt.Errorf("%w: hold must end in the future, got %v", ErrInvalidReservation, until)
+	}
+
+	held := []*GPUReservation{reservation}
+	if reservation.SeriesID != "" {
+		for _, occurrence := range r.seriesReservations(reservation.SeriesID) {
+			if occurrence.ID != reservation.ID && occurrence.StartTime.After(reservation.StartTime) &&
+				occurrence.StartTime.Before(until) &&
+				(occurrence.Status == ReservationStatusPending || occurrence.Status == ReservationStatusHeld) {
+				held = append(held, occurrence)
+			}
+		}
+	}
+
+	for _, occurrence := range held {
+		occurrence.Status = ReservationStatusHeld
+		occurrence.HeldUntil = until
+		occurrence.UpdatedAt = r.now()
+		if err := r.persist(occurrence); err != nil {
+			return err
+		}
+	}
+
+	// The held slot is free for queued reservations in the meantime
+	r.promoteWaitlist(reservation.GPUID)
+
+	return nil
+}
+
+// ResumeReservation ends a hold early and returns the reservation to pending. It
+// fails, leaving the reservation held, if its slot has since been taken in a way
+// the conflict resolution policy does not allow, or if its user or GPU has
+// since reached its reservation limit.
+func (r *GPUReservationManager) ResumeReservation(id string) error {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, exists := r.reservations[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrReservationNotFound, id)
+	}
+
+	if reservation.Status != ReservationStatusHeld {
+		return fmt.Errorf("%w: reservation %s is not held, status is %s", ErrInvalidTransition, id, reservation.Status)
+	}
+
+	return r.resume(reservation)
+}
+
+// resume returns a held reservation to pending, or expires it if its window has
+// already ended. Callers must hold r.mu.
+func (r *GPUReservationManager) resume(reservation *GPUReservation) error {
+	now := r.now()
+
+	if !now.Before(reservation.EndTime) {
+		reservation.Status = ReservationStatusExpired
+		reservation.HeldUntil = time.Time{}
+		reservation.UpdatedAt = now
+		r.emit(eventExpired, reservation)
+		return r.persist(reservation)
+	}
+
+	request := &ReservationRequest{
+		GPUID:          reservation.GPUID,
+		Fraction:       reservation.Fraction,
+		MemoryRequest:  reservation.MemoryRequest,
+		StartTime:      reservation.StartTime,
+		Duration:       reservation.EndTime.Sub(reservation.StartTime),
+		SharingEnabled: reservation.SharingEnabled,
+		Priority:       reservation.Priority,
+	}
+	if err := r.checkCapacityPartitions(request); err != nil {
+		return fmt.Errorf("cannot resume reservation %s: %w", reservation.ID, err)
+	}
+	// Held reservations count toward no limit, unless another occurrence of
+	// their series does
+	if !r.countsTowardLimits(reservation) {
+		if err := r.checkUserLimits(reservation.UserID); err != nil {
+			return fmt.Errorf("cannot resume reservation %s: %w", reservation.ID, err)
+		}
+		if err := r.checkGPULimits(reservation.GPUID); err != nil {
+			return fmt.Errorf("cannot resume reservation %s: %w", reservation.ID, err)
+		}
+	}
+	if conflicts := r.checkConflicts(request); len(conflicts) > 0 {
+		if err := r.resolveConflicts(reservation, conflicts); err != nil {
+			return fmt.Errorf("cannot resume reservation %s: %w", reservation.ID, err)
+		}
+	}
+
+	reservation.Status = ReservationStatusPending
+	reservation.HeldUntil = time.Time{}
+	reservation.UpdatedAt = now
+
+	return r.persist(reservation)
+}
+
+// countsTowardLimits reports whether a pending or active reservation other than
+// the given one shares its limit key. Callers must hold r.mu.
+func (r *GPUReservationManager) countsTowardLimits(reservation *GPUReservation) bool {
+	key := limitKey(reservation)
+	for _, other := range r.reservations {
+		if other.ID != reservation.ID && limitKey(other) == key &&
+			(other.Status == ReservationStatusPending || other.Status == ReservationStatusActive) {
+			return true
+		}
+	}
+	return false
+}
+
+// resumeDueHolds returns held reservations whose hold has ended to pending.
+// Reservations whose slot has been taken stay held and are retried on the next
+// pass until their window ends and they expire.
+func (r *GPUReservationManager) resumeDueHolds() {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, reservation := range r.reservations {
+		if reservation.Status != ReservationStatusHeld || now.Before(reservation.HeldUntil) {
+			continue
+		}
+
+		if err := r.resume(reservation); err != nil {
+			fmt.Printf("Error resuming reservation %s: %v\n", reservation.ID, err)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// Package httpapi serves a GPUReservationManager over HTTP with JSON bodies,
+// keeping the reservation package itself free of any transport
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/reservation"
+)
+
+// CreateReservationRequest is the body of a create request. Recurring
+// reservations and SLAs are not exposed over HTTP.
+type CreateReservationRequest struct {
+	TenantID       string            `json:"tenantId,omitempty"`
+	UserID         string            `json:"userId"`
+	WorkloadID     string            `json:"workloadId"`
+	GPUID          string            `json:"gpuId"`
+	Fraction       float64           `json:"fraction"`
+	MemoryRequest  int64             `json:"memoryRequest,omitempty"` // in MiB
+	StartTime      time.Time         `json:"startTime"`
+	Duration       string            `json:"duration"`           // Go duration, e.g. "2h30m"
+	Priority       int               `json:"priority,omitempty"` // Defaults to normal priority
+	Annotations    map[string]string `json:"annotations,omitempty"`
+	IsolationType  string            `json:"isolationType,omitempty"`
+	SharingEnabled bool              `json:"sharingEnabled,omitempty"`
+	DependsOn      []string          `json:"dependsOn,omitempty"`
+}
+
+// Reservation is a reservation as returned by the API
+type Reservation struct {
+	ID             string            `json:"id"`
+	SeriesID       string            `json:"seriesId,omitempty"`
+	TenantID       string            `json:"tenantId,omitempty"`
+	UserID         string            `json:"userId"`
+	WorkloadID     string            `json:"workloadId"`
+	GPUID          string            `json:"gpuId"`
+	Fraction       float64           `json:"fraction"`
+	MemoryRequest  int64             `json:"memoryRequest,omitempty"`
+	StartTime      time.Time         `json:"startTime"`
+	EndTime        time.Time         `json:"endTime"`
+	Priority       int               `json:"priority"`
+	Status         string            `json:"status"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
+	IsolationType  string            `json:"isolationType,omitempty"`
+	SharingEnabled bool              `json:"sharingEnabled"`
+	AllocationIDs  []string          `json:"allocationIds,omitempty"`
+	DependsOn      []string          `json:"dependsOn,omitempty"`
+}
+
+// ReservationList is a page of reservations with the total number matching the filters
+type ReservationList struct {
+	Reservations []Reservation `json:"reservations"`
+	Total        int           `json:"total"`
+}
+
+// errorResponse is the body of every error response
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server exposes a reservation manager over HTTP:
+//
+//	POST /reservations                 create a reservation
+//	GET  /reservations                 list reservations, filtered by query parameters
+//	GET  /reservations/stats           reservation statistics
+//	GET  /reservations/{id}            get a reservation
+//	POST /reservations/{id}/cancel     cancel a reservation; ?series=true cancels its series
+//	POST /reservations/{id}/complete   complete a reservation
+type Server struct {
+	manager *reservation.GPUReservationManager
+	mux     *http.ServeMux
+}
+
+// NewServer creates an HTTP server for a reservation manager
+func NewServer(manager *reservation.GPUReservationManager) *Server {
+	s := &Server{
+		manager: manager,
+		mux:     http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("POST /reservations", s.createReservation)
+	s.mux.HandleFunc("GET /reservations", s.listReservations)
+	s.mux.HandleFunc("GET /reservations/stats", s.getReservationStats)
+	s.mux.HandleFunc("GET /reservations/{id}", s.getReservation)
+	s.mux.HandleFunc("POST /reservations/{id}/cancel", s.cancelReservation)
+	s.mux.HandleFunc("POST /reservations/{id}/complete", s.completeReservation)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// createReservation handles POST /reservations
+func (s *Server) createReservation(w http.ResponseWriter, r *http.Request) {
+	var body CreateReservationRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	duration, err := time.ParseDuration(body.Duration)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid duration %q: %w", body.Duration, err))
+		return
+	}
+
+	priority := reservation.ReservationPriority(body.Priority)
+	if priority == 0 {
+		priority = reservation.ReservationPriorityNormal
+	}
+
+	created, err := s.manager.CreateReservation(r.Context(), &reservation.ReservationRequest{
+		TenantID:       body.TenantID,
+		UserID:         body.UserID,
+		WorkloadID:     body.WorkloadID,
+		GPUID:          body.GPUID,
+		Fraction:       body.Fraction,
+		MemoryRequest:  body.MemoryRequest,
+		StartTime:      body.StartTime,
+		Duration:       duration,
+		Priority:       priority,
+		Annotations:    body.Annotations,
+		IsolationType:  body.IsolationType,
+		SharingEnabled: body.SharingEnabled,
+		DependsOn:      body.DependsOn,
+	})
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toReservation(created))
+}
+
+// listReservations handles GET /reservations
+func (s *Server) listReservations(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseFilters(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reservations, total := s.manager.ListReservations(filters)
+
+	list := ReservationList{
+		Reservations: make([]Reservation, 0, len(reservations)),
+		Total:        total,
+	}
+	for _, res := range reservations {
+		list.Reservations = append(list.Reservations, toReservation(res))
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// parseFilters reads ReservationFilters from the query parameters userId,
+// gpuId, status, startTime and endTime (RFC 3339), sortBy, ascending, limit
+// and offset
+func parseFilters(r *http.Request) (*reservation.ReservationFilters, error) {
+	query := r.URL.Query()
+
+	filters := &reservation.ReservationFilters{
+		UserID: query.Get("userId"),
+		GPUID:  query.Get("gpuId"),
+		Status: reservation.ReservationStatus(query.Get("status")),
+		SortBy: reservation.ReservationSortField(query.Get("sortBy")),
+	}
+
+	var err error
+	if filters.StartTime, err = parseTimeParam(query.Get("startTime")); err != nil {
+		return nil, fmt.Errorf("invalid startTime: %w", err)
+	}
+	if filters.EndTime, err = parseTimeParam(query.Get("endTime")); err != nil {
+		return nil, fmt.Errorf("invalid endTime: %w", err)
+	}
+	if value := query.Get("ascending"); value != "" {
+		if filters.Ascending, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid ascending: %w", err)
+		}
+	}
+	if filters.Limit, err = parseCountParam(query.Get("limit")); err != nil {
+		return nil, fmt.Errorf("invalid limit: %w", err)
+	}
+	if filters.Offset, err = parseCountParam(query.Get("offset")); err != nil {
+		return nil, fmt.Errorf("invalid offset: %w", err)
+	}
+
+	return filters, nil
+}
+
+// parseTimeParam parses an optional RFC 3339 time, returning the zero time if unset
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseCountParam parses an optional non-negative integer, returning 0 if unset
+func parseCountParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("must be non-negative, got %d", count)
+	}
+	return count, nil
+}
+
+// getReservation handles GET /reservations/{id}
+func (s *Server) getReservation(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	res, exists := s.manager.GetReservation(id)
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", reservation.ErrReservationNotFound, id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toReservation(res))
+}
+
+// cancelReservation handles POST /reservations/{id}/cancel
+func (s *Server) cancelReservation(w http.ResponseWriter, r *http.Request) {
+	cancelSeries := false
+	if value := r.URL.Query().Get("series"); value != "" {
+		var err error
+		if cancelSeries, err = strconv.ParseBool(value); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid series: %w", err))
+			return
+		}
+	}
+
+	s.transition(w, r.PathValue("id"), func(id string) error {
+		return s.manager.CancelReservation(id, cancelSeries)
+	})
+}
+
+// completeReservation handles POST /reservations/{id}/complete
+func (s *Server) completeReservation(w http.ResponseWriter, r *http.Request) {
+	s.transition(w, r.PathValue("id"), s.manager.CompleteReservation)
+}
+
+// transition applies a status change to a reservation and responds with the
+// updated reservation
+func (s *Server) transition(w http.ResponseWriter, id string, apply func(id string) error) {
+	if err := apply(id); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+
+	res, exists := s.manager.GetReservation(id)
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", reservation.ErrReservationNotFound, id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toReservation(res))
+}
+
+// getReservationStats handles GET /reservations/stats
+func (s *Server) getReservationStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.manager.GetReservationStats())
+}
+
+// toReservation converts a managed reservation to its API form
+func toReservation(res *reservation.GPUReservation) Reservation {
+	return Reservation{
+		ID:             res.ID,
+		SeriesID:       res.SeriesID,
+		TenantID:       res.TenantID,
+		UserID:         res.UserID,
+		WorkloadID:     res.WorkloadID,
+		GPUID:          res.GPUID,
+		Fraction:       res.Fraction,
+		MemoryRequest:  res.MemoryRequest,
+		StartTime:      res.StartTime,
+		EndTime:        res.EndTime,
+		Priority:       int(res.Priority),
+		Status:         string(res.Status),
+		CreatedAt:      res.CreatedAt,
+		UpdatedAt:      res.UpdatedAt,
+		Annotations:    res.Annotations,
+		IsolationType:  res.IsolationType,
+		SharingEnabled: res.SharingEnabled,
+		AllocationIDs:  res.AllocationIDs,
+		DependsOn:      res.DependsOn,
+	}
+}
+
+// writeManagerError responds with the status code matching a reservation
+// manager error: 404 for a missing reservation, 400 for an invalid request,
+// 409 for a conflict, an exceeded limit or a transition the reservation's
+// status does not allow, and 500 otherwise
+func writeManagerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, reservation.ErrReservationNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, reservation.ErrInvalidReservation):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, reservation.ErrReservationConflict),
+		errors.Is(err, reservation.ErrLimitExceeded),
+		errors.Is(err, reservation.ErrInvalidTransition):
+		writeError(w, http.StatusConflict, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// writeError responds with an error message
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeJSON responds with a JSON body
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Printf("Failed to write response: %v\n", err)
+	}
+}
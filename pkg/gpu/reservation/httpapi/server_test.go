@@ -0,0 +1,175 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/silogen/kaiwo/pkg/gpu/reservation"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	manager, err := reservation.NewGPUReservationManager(reservation.ReservationManagerConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create reservation manager: %v", err)
+	}
+	t.Cleanup(manager.Stop)
+
+	server := httptest.NewServer(NewServer(manager))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestCreateRequest(userID, gpuID string, start time.Time) CreateReservationRequest {
+	return CreateReservationRequest{
+		UserID:     userID,
+		WorkloadID: "train",
+		GPUID:      gpuID,
+		Fraction:   0.5,
+		StartTime:  start,
+		Duration:   "2h",
+	}
+}
+
+// do sends a request with an optional JSON body, checks the status code and
+// decodes the response into out unless it is nil
+func do(t *testing.T, server *httptest.Server, method, path string, body interface{}, wantStatus int, out interface{}) {
+	t.Helper()
+
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+	request, err := http.NewRequest(method, server.URL+path, &reader)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+
+	response, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != wantStatus {
+		var errBody errorResponse
+		_ = json.NewDecoder(response.Body).Decode(&errBody)
+		t.Fatalf("%s %s: expected status %d, got %d (%s)", method, path, wantStatus, response.StatusCode, errBody.Error)
+	}
+	if out != nil {
+		if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+}
+
+func TestServerReservationLifecycle(t *testing.T) {
+	server := newTestServer(t)
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	var first, second Reservation
+	do(t, server, http.MethodPost, "/reservations", newTestCreateRequest("alice", "gpu-0", start), http.StatusCreated, &first)
+	if first.ID == "" || first.UserID != "alice" || first.Status != string(reservation.ReservationStatusPending) {
+		t.Fatalf("Unexpected reservation: %+v", first)
+	}
+	if !first.EndTime.Equal(start.Add(2*time.Hour)) || first.Priority != int(reservation.ReservationPriorityNormal) {
+		t.Errorf("Expected a 2h reservation at normal priority, got %+v", first)
+	}
+	do(t, server, http.MethodPost, "/reservations", newTestCreateRequest("bob", "gpu-1", start), http.StatusCreated, &second)
+
+	var fetched Reservation
+	do(t, server, http.MethodGet, "/reservations/"+first.ID, nil, http.StatusOK, &fetched)
+	if fetched.ID != first.ID || fetched.GPUID != "gpu-0" {
+		t.Errorf("Expected reservation %s, got %+v", first.ID, fetched)
+	}
+
+	var list ReservationList
+	do(t, server, http.MethodGet, "/reservations?userId=bob", nil, http.StatusOK, &list)
+	if list.Total != 1 || len(list.Reservations) != 1 || list.Reservations[0].ID != second.ID {
+		t.Errorf("Expected only bob's reservation, got %+v", list)
+	}
+	do(t, server, http.MethodGet, "/reservations?sortBy=created_at&limit=1&offset=1", nil, http.StatusOK, &list)
+	if list.Total != 2 || len(list.Reservations) != 1 {
+		t.Errorf("Expected a page of 1 of 2 reservations, got %+v", list)
+	}
+
+	var cancelled, completed Reservation
+	do(t, server, http.MethodPost, "/reservations/"+first.ID+"/cancel", nil, http.StatusOK, &cancelled)
+	if cancelled.Status != string(reservation.ReservationStatusCancelled) {
+		t.Errorf("Expected a cancelled reservation, got %s", cancelled.Status)
+	}
+	do(t, server, http.MethodPost, "/reservations/"+second.ID+"/complete", nil, http.StatusOK, &completed)
+	if completed.Status != string(reservation.ReservationStatusCompleted) {
+		t.Errorf("Expected a completed reservation, got %s", completed.Status)
+	}
+
+	var stats types.ReservationStats
+	do(t, server, http.MethodGet, "/reservations/stats", nil, http.StatusOK, &stats)
+	if stats.TotalReservations != 2 || stats.CancelledReservations != 1 || stats.CompletedReservations != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestServerErrorStatusCodes(t *testing.T) {
+	server := newTestServer(t)
+	start := time.Now().Add(time.Hour)
+
+	var created Reservation
+	do(t, server, http.MethodPost, "/reservations", newTestCreateRequest("alice", "gpu-0", start), http.StatusCreated, &created)
+
+	invalid := newTestCreateRequest("alice", "gpu-1", start)
+	invalid.Fraction = 2
+	badDuration := newTestCreateRequest("alice", "gpu-1", start)
+	badDuration.Duration = "soon"
+
+	var cancelled, completed Reservation
+	do(t, server, http.MethodPost, "/reservations", newTestCreateRequest("dave", "gpu-2", start), http.StatusCreated, &cancelled)
+	do(t, server, http.MethodPost, "/reservations/"+cancelled.ID+"/cancel", nil, http.StatusOK, nil)
+	do(t, server, http.MethodPost, "/reservations", newTestCreateRequest("dave", "gpu-3", start), http.StatusCreated, &completed)
+	do(t, server, http.MethodPost, "/reservations/"+completed.ID+"/complete", nil, http.StatusOK, nil)
+
+	// The default manager config allows each user five reservations
+	for i := 0; i < 5; i++ {
+		do(t, server, http.MethodPost, "/reservations", newTestCreateRequest("carol", fmt.Sprintf("gpu-%d", 10+i), start), http.StatusCreated, nil)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		want   int
+	}{
+		{"validation error", http.MethodPost, "/reservations", invalid, http.StatusBadRequest},
+		{"malformed duration", http.MethodPost, "/reservations", badDuration, http.StatusBadRequest},
+		{"malformed body", http.MethodPost, "/reservations", "not an object", http.StatusBadRequest},
+		{"strict conflict", http.MethodPost, "/reservations", newTestCreateRequest("bob", "gpu-0", start), http.StatusConflict},
+		{"malformed filter", http.MethodGet, "/reservations?limit=-1", nil, http.StatusBadRequest},
+		{"missing reservation", http.MethodGet, "/reservations/missing", nil, http.StatusNotFound},
+		{"cancel missing reservation", http.MethodPost, "/reservations/missing/cancel", nil, http.StatusNotFound},
+		{"complete missing reservation", http.MethodPost, "/reservations/missing/complete", nil, http.StatusNotFound},
+		{"user limit exceeded", http.MethodPost, "/reservations", newTestCreateRequest("carol", "gpu-15", start), http.StatusConflict},
+		{"cancel cancelled reservation", http.MethodPost, "/reservations/" + cancelled.ID + "/cancel", nil, http.StatusConflict},
+		{"complete completed reservation", http.MethodPost, "/reservations/" + completed.ID + "/complete", nil, http.StatusConflict},
+		{"malformed series flag", http.MethodPost, "/reservations/" + created.ID + "/cancel?series=maybe", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errBody errorResponse
+			do(t, server, tt.method, tt.path, tt.body, tt.want, &errBody)
+			if errBody.Error == "" {
+				t.Error("Expected an error message in the response")
+			}
+		})
+	}
+}
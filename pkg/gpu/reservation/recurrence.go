@@ -0,0 +1,132 @@
+package reservation
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// RecurrenceFrequency is how often a recurring reservation repeats
+type RecurrenceFrequency string
+
+const (
+	// RecurrenceFrequencyDaily repeats every day, or on DaysOfWeek when set
+	RecurrenceFrequencyDaily RecurrenceFrequency = "daily"
+	// RecurrenceFrequencyWeekly repeats once a week on the start time's weekday,
+	// or on every day in DaysOfWeek when set
+	RecurrenceFrequencyWeekly RecurrenceFrequency = "weekly"
+)
+
+// maxRecurrenceOccurrences bounds how many reservations a single series may expand into
+const maxRecurrenceOccurrences = 366
+
+// RecurrenceRule describes how a reservation repeats
+type RecurrenceRule struct {
+	Frequency  RecurrenceFrequency
+	DaysOfWeek []time.Weekday // Restricts occurrences to these weekdays; empty means no restriction
+	Until      time.Time      // No occurrence starts after this instant
+}
+
+// seriesReservations returns every reservation in a series ordered by start time.
+// Callers must hold r.mu.
+func (r *GPUReservationManager) seriesReservations(seriesID string) []*GPUReservation {
+	var reservations []*GPUReservation
+	for _, reservation := range r.reservations {
+		if reservation.SeriesID == seriesID {
+			reservations = append(reservations, reservation)
+		}
+	}
+
+	slices.SortFunc(reservations, func(a, b *GPUReservation) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	return reservations
+}
+
+// GetSeries returns the reservations of a recurring series ordered by start time
+func (r *GPUReservationManager) GetSeries(seriesID string) []*GPUReservation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.seriesReservations(seriesID)
+}
+
+// limitKey identifies a reservation for per-user and per-GPU limits, so that a
+// series counts once
+func limitKey(reservation *GPUReservation) string {
+	if reservation.SeriesID != "" {
+		return reservation.SeriesID
+	}
+	return reservation.ID
+}
+
+// expandRecurrence returns one request per occurrence of a reservation request,
+// or the request itself if it does not recur. Occurrences overlapping a
+// blackout window are skipped.
+func (r *GPUReservationManager) expandRecurrence(request *ReservationRequest) ([]*ReservationRequest, error) {
+	rule := request.Recurrence
+	if rule == nil {
+		return []*ReservationRequest{request}, nil
+	}
+
+	if err := validateRecurrenceRule(rule, request); err != nil {
+		return nil, err
+	}
+
+	days := rule.DaysOfWeek
+	if len(days) == 0 && rule.Frequency == RecurrenceFrequencyWeekly {
+		days = []time.Weekday{request.StartTime.Weekday()}
+	}
+
+	var occurrences []*ReservationRequest
+	for start := request.StartTime; !start.After(rule.Until); start = start.AddDate(0, 0, 1) {
+		if len(days) > 0 && !slices.Contains(days, start.Weekday()) {
+			continue
+		}
+		if r.checkBlackouts(request.GPUID, start, request.Duration) != nil {
+			continue
+		}
+
+		if len(occurrences) == maxRecurrenceOccurrences {
+			return nil, fmt.Errorf("recurrence expands to more than %d occurrences", maxRecurrenceOccurrences)
+		}
+
+		occurrence := *request
+		occurrence.StartTime = start
+		occurrence.Recurrence = nil
+		occurrences = append(occurrences, &occurrence)
+	}
+
+	if len(occurrences) == 0 {
+		return nil, fmt.Errorf("recurrence has no occurrences before %v", rule.Until)
+	}
+
+	return occurrences, nil
+}
+
+// validateRecurrenceRule validates a recurrence rule against the request it repeats
+func validateRecurrenceRule(rule *RecurrenceRule, request *ReservationRequest) error {
+	switch rule.Frequency {
+	case RecurrenceFrequencyDaily, RecurrenceFrequencyWeekly:
+		// Valid frequency
+	default:
+		return fmt.Errorf("unknown recurrence frequency: %q", rule.Frequency)
+	}
+
+	for _, day := range rule.DaysOfWeek {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid day of week: %d", day)
+		}
+	}
+
+	if rule.Until.IsZero() || rule.Until.Before(request.StartTime) {
+		return fmt.Errorf("recurrence must end after the first occurrence")
+	}
+
+	// Occurrences are at least a day apart, so shorter reservations never overlap each other
+	if request.Duration >= 24*time.Hour {
+		return fmt.Errorf("recurring reservations must last less than 24h, got %v", request.Duration)
+	}
+
+	return nil
+}
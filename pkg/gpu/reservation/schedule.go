@@ -0,0 +1,94 @@
+package reservation
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// BusyInterval is a stretch of a GPU's timeline held by the same set of reservations
+type BusyInterval struct {
+	Start          time.Time
+	End            time.Time
+	Fraction       float64  // Sum of the fractions reserved throughout the interval
+	ReservationIDs []string // Reservations holding the GPU throughout the interval, sorted
+}
+
+// FreeInterval is a stretch of a GPU's timeline that no reservation holds
+type FreeInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// GPUSchedule is the timeline of a GPU over a window, split into busy intervals
+// and the free gaps between them, both in time order
+type GPUSchedule struct {
+	GPUID string
+	From  time.Time
+	To    time.Time
+	Busy  []BusyInterval
+	Free  []FreeInterval
+}
+
+// GetGPUSchedule returns a GPU's reserved and free time between from and to,
+// for planning reservations. Only pending and active reservations hold the
+// GPU, and reservations reaching outside the window are clipped to it.
+// Overlapping reservations split the timeline wherever one starts or ends, so
+// each busy interval reports the combined fraction reserved during it, and a
+// GPU that is only partly reserved is busy, not free.
+func (r *GPUReservationManager) GetGPUSchedule(gpuID string, from, to time.Time) (*GPUSchedule, error) {
+	if gpuID == "" {
+		return nil, fmt.Errorf("GPU ID cannot be empty")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("schedule window must end after it starts, got %v to %v", from, to)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var reservations []*GPUReservation
+	boundaries := []time.Time{from, to}
+	for _, reservation := range r.reservations {
+		if reservation.GPUID != gpuID ||
+			(reservation.Status != ReservationStatusPending && reservation.Status != ReservationStatusActive) ||
+			!reservation.StartTime.Before(to) || !reservation.EndTime.After(from) {
+			continue
+		}
+
+		reservations = append(reservations, reservation)
+		boundaries = append(boundaries, maxTime(reservation.StartTime, from), minTime(reservation.EndTime, to))
+	}
+
+	slices.SortFunc(boundaries, time.Time.Compare)
+	boundaries = slices.CompactFunc(boundaries, time.Time.Equal)
+
+	schedule := &GPUSchedule{GPUID: gpuID, From: from, To: to}
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+
+		// No reservation starts or ends inside the interval, so those covering
+		// its start cover all of it
+		interval := BusyInterval{Start: start, End: end}
+		for _, reservation := range reservations {
+			if !reservation.StartTime.After(start) && reservation.EndTime.After(start) {
+				interval.Fraction += reservation.Fraction
+				interval.ReservationIDs = append(interval.ReservationIDs, reservation.ID)
+			}
+		}
+
+		if len(interval.ReservationIDs) == 0 {
+			if last := len(schedule.Free) - 1; last >= 0 && schedule.Free[last].End.Equal(start) {
+				schedule.Free[last].End = end
+			} else {
+				schedule.Free = append(schedule.Free, FreeInterval{Start: start, End: end})
+			}
+			continue
+		}
+
+		slices.Sort(interval.ReservationIDs)
+		schedule.Busy = append(schedule.Busy, interval)
+	}
+
+	return schedule, nil
+}
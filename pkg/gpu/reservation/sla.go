@@ -0,0 +1,145 @@
+package reservation
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SLA breach reasons
+const (
+	SLABreachReasonLateActivation   = "late_activation"
+	SLABreachReasonActivationFailed = "activation_failed"
+	SLABreachReasonNotActivated     = "not_activated"
+)
+
+// slaBreachRetention is how long breaches are kept, and so the longest window
+// GetSLABreaches reports on
+const slaBreachRetention = 7 * 24 * time.Hour
+
+// ReservationSLA describes the service level promised for a reservation
+type ReservationSLA struct {
+	// MaxActivationDelay is how long after its start time the reservation may take to activate
+	MaxActivationDelay time.Duration
+}
+
+// SLABreach records a reservation that missed its SLA
+type SLABreach struct {
+	ReservationID string
+	UserID        string
+	Reason        string
+	Message       string
+	StartTime     time.Time
+	Delay         time.Duration // How late the activation was, or had become when it failed or was flagged
+	DetectedAt    time.Time
+}
+
+// SetSLABreachHandler sets a function called whenever an SLA breach is recorded,
+// e.g. to raise an alert. The handler is called with the manager lock held and must
+// not call back into the manager.
+func (r *GPUReservationManager) SetSLABreachHandler(handler func(*SLABreach)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.slaBreachHandler = handler
+}
+
+// GetSLABreaches returns the SLA breaches detected within the trailing window.
+// Breaches are kept for a week, so longer windows report no more than that.
+func (r *GPUReservationManager) GetSLABreaches(window time.Duration) []*SLABreach {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cutoff := r.now().Add(-window)
+
+	var breaches []*SLABreach
+	for _, breach := range r.slaBreaches {
+		if !breach.DetectedAt.Before(cutoff) {
+			breaches = append(breaches, breach)
+		}
+	}
+
+	return breaches
+}
+
+// markActive activates a reservation and checks its activation against its SLA.
+// Callers must hold r.mu.
+func (r *GPUReservationManager) markActive(reservation *GPUReservation) {
+	now := r.now()
+
+	reservation.Status = ReservationStatusActive
+	reservation.ActivatedAt = now
+	reservation.UpdatedAt = now
+	r.emit(eventActivated, reservation)
+
+	if reservation.SLA == nil {
+		return
+	}
+
+	// A reservation already flagged for not activating in time is not flagged again
+	delay := now.Sub(reservation.StartTime)
+	if delay > reservation.SLA.MaxActivationDelay && !r.hasSLABreach(reservation.ID, SLABreachReasonNotActivated) {
+		r.recordSLABreach(reservation, SLABreachReasonLateActivation, delay,
+			fmt.Sprintf("activated %v after start time, SLA allows %v", delay, reservation.SLA.MaxActivationDelay))
+	}
+}
+
+// recordActivationFailure records an SLA breach for a reservation whose activation
+// failed. Callers must hold r.mu.
+func (r *GPUReservationManager) recordActivationFailure(reservation *GPUReservation, err error) {
+	if reservation.SLA == nil {
+		return
+	}
+
+	delay := max(r.now().Sub(reservation.StartTime), 0)
+	r.recordSLABreach(reservation, SLABreachReasonActivationFailed, delay,
+		fmt.Sprintf("activation failed: %v", err))
+}
+
+// checkActivationDeadline records a breach, once, for a reservation still
+// waiting to activate after its SLA's activation delay has passed. Callers must
+// hold r.mu.
+func (r *GPUReservationManager) checkActivationDeadline(reservation *GPUReservation) {
+	if reservation.SLA == nil || r.hasSLABreach(reservation.ID, SLABreachReasonNotActivated) {
+		return
+	}
+
+	delay := r.now().Sub(reservation.StartTime)
+	if delay > reservation.SLA.MaxActivationDelay {
+		r.recordSLABreach(reservation, SLABreachReasonNotActivated, delay,
+			fmt.Sprintf("not activated %v after start time, SLA allows %v", delay, reservation.SLA.MaxActivationDelay))
+	}
+}
+
+// hasSLABreach reports whether a breach with the given reason is recorded for a
+// reservation. Callers must hold r.mu.
+func (r *GPUReservationManager) hasSLABreach(reservationID, reason string) bool {
+	return slices.ContainsFunc(r.slaBreaches, func(breach *SLABreach) bool {
+		return breach.ReservationID == reservationID && breach.Reason == reason
+	})
+}
+
+// recordSLABreach stores a breach and notifies the breach handler. Callers must hold r.mu.
+func (r *GPUReservationManager) recordSLABreach(reservation *GPUReservation, reason string, delay time.Duration, message string) {
+	breach := &SLABreach{
+		ReservationID: reservation.ID,
+		UserID:        reservation.UserID,
+		Reason:        reason,
+		Message:       message,
+		StartTime:     reservation.StartTime,
+		Delay:         delay,
+		DetectedAt:    r.now(),
+	}
+
+	// Breaches are recorded in detection order, so expired ones are at the front
+	cutoff := breach.DetectedAt.Add(-slaBreachRetention)
+	expired := 0
+	for expired < len(r.slaBreaches) && r.slaBreaches[expired].DetectedAt.Before(cutoff) {
+		expired++
+	}
+	r.slaBreaches = append(r.slaBreaches[expired:], breach)
+
+	if r.slaBreachHandler != nil {
+		r.slaBreachHandler(breach)
+	}
+}
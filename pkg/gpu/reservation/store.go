@@ -0,0 +1,138 @@
+package reservation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ReservationStore persists reservations so they survive restarts
+type ReservationStore interface {
+	// Save creates or replaces a reservation
+	Save(reservation *GPUReservation) error
+
+	// LoadAll returns every stored reservation
+	LoadAll() ([]*GPUReservation, error)
+
+	// Delete removes a reservation; deleting an unknown reservation is not an error
+	Delete(id string) error
+}
+
+// FileReservationStore is a ReservationStore backed by a single JSON file
+type FileReservationStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileReservationStore creates a store that keeps reservations in the JSON file at path
+func NewFileReservationStore(path string) *FileReservationStore {
+	return &FileReservationStore{path: path}
+}
+
+// Save creates or replaces a reservation in the file
+func (s *FileReservationStore) Save(reservation *GPUReservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	reservations[reservation.ID] = reservation
+
+	return s.write(reservations)
+}
+
+// Delete removes a reservation from the file
+func (s *FileReservationStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, exists := reservations[id]; !exists {
+		return nil
+	}
+	delete(reservations, id)
+
+	return s.write(reservations)
+}
+
+// LoadAll returns every reservation in the file, ordered by ID
+func (s *FileReservationStore) LoadAll() ([]*GPUReservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*GPUReservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		result = append(result, reservation)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+// read loads the reservations from the file. A missing file holds no reservations.
+func (s *FileReservationStore) read() (map[string]*GPUReservation, error) {
+	reservations := make(map[string]*GPUReservation)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return reservations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reservation store %s: %w", s.path, err)
+	}
+
+	if err := json.Unmarshal(data, &reservations); err != nil {
+		return nil, fmt.Errorf("failed to decode reservation store %s: %w", s.path, err)
+	}
+
+	return reservations, nil
+}
+
+// write replaces the file contents, going through a temporary file so a crash
+// never leaves a partially written store
+func (s *FileReservationStore) write(reservations map[string]*GPUReservation) error {
+	data, err := json.MarshalIndent(reservations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode reservations: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary reservation store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write reservation store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write reservation store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace reservation store %s: %w", s.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package reservation
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// suggestionGap separates a suggested slot from the reservations either side
+// of it, since reservations that merely touch still conflict
+const suggestionGap = time.Minute
+
+// SuggestAlternatives returns up to maxSuggestions copies of a request moved
+// to start times, on its own GPU or any of otherGPUs, at which it would be
+// accepted without blocking conflicts. Start times are tried just after and
+// just before each reservation on those GPUs, and suggestions are ordered by
+// how far they move the request, nearest first, preferring earlier starts and
+// the request's own GPU on ties. A recurring request is moved as a whole.
+// Nothing is reserved, so a suggestion can still be taken by someone else
+// before it is requested.
+func (r *GPUReservationManager) SuggestAlternatives(request *ReservationRequest, maxSuggestions int, otherGPUs ...string) []*ReservationRequest {
+	if request == nil || maxSuggestions <= 0 {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	gpuIDs := []string{request.GPUID}
+	for _, gpuID := range otherGPUs {
+		if !slices.Contains(gpuIDs, gpuID) {
+			gpuIDs = append(gpuIDs, gpuID)
+		}
+	}
+
+	var candidates []*ReservationRequest
+	for _, gpuID := range gpuIDs {
+		starts := []time.Time{request.StartTime}
+		for _, reservation := range r.reservations {
+			if reservation.GPUID != gpuID || reservation.Status == ReservationStatusCompleted ||
+				reservation.Status == ReservationStatusCancelled || reservation.Status == ReservationStatusQueued ||
+				reservation.Status == ReservationStatusHeld {
+				continue
+			}
+			starts = append(starts,
+				reservation.EndTime.Add(suggestionGap),
+				reservation.StartTime.Add(-request.Duration-suggestionGap))
+		}
+
+		slices.SortFunc(starts, time.Time.Compare)
+		for _, start := range slices.CompactFunc(starts, time.Time.Equal) {
+			candidate := *request
+			candidate.GPUID = gpuID
+			candidate.StartTime = start
+			if r.accepts(&candidate) {
+				candidates = append(candidates, &candidate)
+			}
+		}
+	}
+
+	// Stable, so ties between GPUs keep the request's own GPU first
+	slices.SortStableFunc(candidates, func(a, b *ReservationRequest) int {
+		distanceA := a.StartTime.Sub(request.StartTime).Abs()
+		distanceB := b.StartTime.Sub(request.StartTime).Abs()
+		if c := cmp.Compare(distanceA, distanceB); c != 0 {
+			return c
+		}
+		return a.StartTime.Compare(b.StartTime)
+	})
+
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
+	return candidates
+}
+
+// accepts reports whether CreateReservation would accept a request without
+// queueing it, the requesting user's limits aside. Callers must hold r.mu.
+func (r *GPUReservationManager) accepts(request *ReservationRequest) bool {
+	if r.validateReservationRequest(request) != nil || r.checkGPULimits(request.GPUID) != nil {
+		return false
+	}
+
+	occurrences, err := r.expandRecurrence(request)
+	if err != nil {
+		return false
+	}
+
+	for _, occurrence := range occurrences {
+		if r.checkCapacityPartitions(occurrence) != nil {
+			return false
+		}
+		if conflicts := r.checkConflicts(occurrence); len(conflicts) > 0 && r.conflictsBlock(conflicts) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package reservation
+
+import (
+	"fmt"
+	"maps"
+)
+
+const (
+	// AnnotationOriginalOwner records the user a transferred reservation was
+	// created by. It is set on the first transfer and kept on later ones.
+	AnnotationOriginalOwner = "kaiwo.ai/original-owner"
+	// AnnotationTransferredFrom records the user a reservation was most
+	// recently transferred from
+	AnnotationTransferredFrom = "kaiwo.ai/transferred-from"
+)
+
+// TransferReservation reassigns a reservation to another user, keeping its slot,
+// for example when its owner leaves a team. The new owner must be within their
+// reservation limit. The previous and original owners are recorded in the
+// reservation's annotations. Completed, cancelled and expired reservations
+// cannot be transferred. Other occurrences of a recurring series stay with
+// their owner.
+func (r *GPUReservationManager) TransferReservation(id, newUserID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, exists := r.reservations[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrReservationNotFound, id)
+	}
+
+	switch reservation.Status {
+	case ReservationStatusCompleted, ReservationStatusCancelled, ReservationStatusExpired:
+		return fmt.Errorf("%w: cannot transfer reservation in status %s", ErrInvalidTransition, reservation.Status)
+	}
+
+	if newUserID == "" {
+		return fmt.Errorf("%w: new user ID cannot be empty", ErrInvalidReservation)
+	}
+	if newUserID == reservation.UserID {
+		return fmt.Errorf("%w: reservation %s already belongs to user %s", ErrInvalidReservation, id, newUserID)
+	}
+
+	if err := r.checkUserLimits(newUserID); err != nil {
+		return fmt.Errorf("user limits exceeded: %w", err)
+	}
+
+	// Occurrences of a series share their annotations, so copy before writing
+	annotations := maps.Clone(reservation.Annotations)
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if _, transferred := annotations[AnnotationOriginalOwner]; !transferred {
+		annotations[AnnotationOriginalOwner] = reservation.UserID
+	}
+	annotations[AnnotationTransferredFrom] = reservation.UserID
+
+	reservation.Annotations = annotations
+	reservation.UserID = newUserID
+	reservation.UpdatedAt = r.now()
+
+	return r.persist(reservation)
+}
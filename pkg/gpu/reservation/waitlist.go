@@ -0,0 +1,153 @@
+package reservation
+
+import (
+	"fmt"
+	"slices"
+)
+
+// enqueue adds a queued reservation to the end of its GPU's waitlist. Callers
+// must hold r.mu.
+func (r *GPUReservationManager) enqueue(reservation *GPUReservation) {
+	r.waitlists[reservation.GPUID] = append(r.waitlists[reservation.GPUID], reservation.ID)
+}
+
+// dequeue removes a reservation from its GPU's waitlist. Callers must hold r.mu.
+func (r *GPUReservationManager) dequeue(reservation *GPUReservation) {
+	r.waitlists[reservation.GPUID] = slices.DeleteFunc(r.waitlists[reservation.GPUID], func(id string) bool {
+		return id == reservation.ID
+	})
+	if len(r.waitlists[reservation.GPUID]) == 0 {
+		delete(r.waitlists, reservation.GPUID)
+	}
+}
+
+// orderedWaitlist returns a GPU's queued reservations in promotion order: highest
+// priority first, then first come first served. Callers must hold r.mu.
+func (r *GPUReservationManager) orderedWaitlist(gpuID string) []*GPUReservation {
+	queue := make([]*GPUReservation, 0, len(r.waitlists[gpuID]))
+	for _, id := range r.waitlists[gpuID] {
+		queue = append(queue, r.reservations[id])
+	}
+
+	slices.SortStableFunc(queue, func(a, b *GPUReservation) int {
+		return int(b.Priority) - int(a.Priority)
+	})
+	return queue
+}
+
+// waitlistPositions returns the 1-based waitlist position of every reservation
+// queued on a GPU. Callers must hold r.mu.
+func (r *GPUReservationManager) waitlistPositions(gpuID string) map[string]int {
+	positions := make(map[string]int, len(r.waitlists[gpuID]))
+	for i, reservation := range r.orderedWaitlist(gpuID) {
+		positions[reservation.ID] = i + 1
+	}
+	return positions
+}
+
+// CancelQueuedReservation removes a reservation from its GPU's waitlist and
+// cancels it. Unlike CancelReservation it refuses reservations that are no longer
+// queued, e.g. because they were promoted in the meantime.
+func (r *GPUReservationManager) CancelQueuedReservation(id string) error {
+	defer r.dispatchEvents()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, exists := r.reservations[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrReservationNotFound, id)
+	}
+
+	if reservation.Status != ReservationStatusQueued {
+		return fmt.Errorf("%w: reservation %s is not queued, status is %s", ErrInvalidTransition, id, reservation.Status)
+	}
+
+	return r.cancel(reservation)
+}
+
+// GetWaitlistPosition returns the 1-based position of a queued reservation in its
+// GPU's waitlist
+func (r *GPUReservationManager) GetWaitlistPosition(id string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reservation, exists := r.reservations[id]
+	if !exists {
+		return 0, fmt.Errorf("%w: %s", ErrReservationNotFound, id)
+	}
+
+	if reservation.Status != ReservationStatusQueued {
+		return 0, fmt.Errorf("%w: reservation %s is not queued, status is %s", ErrInvalidTransition, id, reservation.Status)
+	}
+
+	for i, queued := range r.orderedWaitlist(reservation.GPUID) {
+		if queued.ID == id {
+			return i + 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("reservation %s is missing from the waitlist of GPU %s", id, reservation.GPUID)
+}
+
+// promoteWaitlist promotes queued reservations on a GPU, in waitlist order, that
+// CreateReservation would now accept without queueing. Queued reservations whose
+// window has already ended expire instead. Callers must hold r.mu.
+func (r *GPUReservationManager) promoteWaitlist(gpuID string) {
+	now := r.now()
+	before := r.waitlistPositions(gpuID)
+	defer r.emitPositionChanges(gpuID, before)
+
+	for _, reservation := range r.orderedWaitlist(gpuID) {
+		if !now.Before(reservation.EndTime) {
+			r.dequeue(reservation)
+			reservation.Status = ReservationStatusExpired
+			reservation.UpdatedAt = now
+			r.emit(eventExpired, reservation)
+			r.persistOrLog(reservation)
+			continue
+		}
+
+		if !r.admitsQueued(reservation) {
+			continue
+		}
+
+		r.dequeue(reservation)
+		reservation.Status = ReservationStatusPending
+		reservation.UpdatedAt = now
+		if !now.Before(reservation.StartTime) && r.dependenciesComplete(reservation) {
+			r.markActive(reservation)
+		}
+		r.persistOrLog(reservation)
+	}
+}
+
+// admitsQueued reports whether a queued reservation now passes the checks
+// CreateReservation admits requests with, other than the start time, which may
+// have passed while it waited. Callers must hold r.mu.
+func (r *GPUReservationManager) admitsQueued(reservation *GPUReservation) bool {
+	request := &ReservationRequest{
+		TenantID:       reservation.TenantID,
+		UserID:         reservation.UserID,
+		WorkloadID:     reservation.WorkloadID,
+		GPUID:          reservation.GPUID,
+		Fraction:       reservation.Fraction,
+		MemoryRequest:  reservation.MemoryRequest,
+		StartTime:      reservation.StartTime,
+		Duration:       reservation.EndTime.Sub(reservation.StartTime),
+		Priority:       reservation.Priority,
+		SharingEnabled: reservation.SharingEnabled,
+	}
+
+	if r.validateFraction(request.GPUID, request.Fraction) != nil ||
+		r.checkCapacityPartitions(request) != nil ||
+		r.checkUserLimits(request.UserID) != nil ||
+		r.checkGPULimits(request.GPUID) != nil {
+		return false
+	}
+
+	conflicts := r.checkConflicts(request)
+	if len(conflicts) == 0 {
+		return true
+	}
+	return !r.conflictsBlock(conflicts) && r.resolveConflicts(reservation, conflicts) == nil
+}
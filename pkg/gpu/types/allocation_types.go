@@ -73,6 +73,13 @@ type AllocationRequest struct {
 
 	// GPUType is the preferred GPU type
 	GPUType GPUType `json:"gpuType,omitempty"`
+
+	// ReservationID is the reservation being activated by this request (empty if none)
+	ReservationID string `json:"reservationId,omitempty"`
+
+	// Timeout bounds how long the allocation may take, overriding the manager's
+	// AllocationTimeout (zero uses the manager default)
+	Timeout time.Duration `json:"timeout,omitempty"`
 }
 
 // AllocationResult represents the result of a GPU allocation
@@ -268,6 +275,10 @@ func ValidateAllocationRequest(request *AllocationRequest) error {
 		return fmt.Errorf("invalid GPU request: %v", err)
 	}
 
+	if request.Timeout < 0 {
+		return fmt.Errorf("allocation timeout must be positive, got %v", request.Timeout)
+	}
+
 	switch request.Strategy {
 	case AllocationStrategyFirstFit, AllocationStrategyBestFit, AllocationStrategyWorstFit,
 		AllocationStrategyRoundRobin, AllocationStrategyLoadBalanced:
@@ -318,10 +329,7 @@ func ValidateAllocationPolicy(policy *AllocationPolicy) error {
 	}
 
 	for _, isolationType := range policy.AllowedIsolationTypes {
-		switch isolationType {
-		case GPUIsolationTimeSlicing, GPUIsolationMIG, GPUIsolationNone:
-			// Valid isolation type
-		default:
+		if !isolationType.IsValid() {
 			return fmt.Errorf("invalid isolation type: %s", isolationType)
 		}
 	}
@@ -368,3 +376,74 @@ func (pool *AllocationPool) CanAllocate(request *GPURequest) bool {
 
 	return true
 }
+
+// fractionTolerance absorbs floating point error when summing GPU fractions
+const fractionTolerance = 1e-9
+
+// CapacityPartitions reserves a fraction of each GPU's capacity for requests at or
+// above a priority tier. Keys are the minimum priority of each tier; requests in a
+// tier may use its slice and every slice below it, so the highest tier can use the
+// whole GPU.
+type CapacityPartitions map[int]float64
+
+// ValidateCapacityPartitions validates capacity partitions
+func ValidateCapacityPartitions(partitions CapacityPartitions) error {
+	var total float64
+	for priority, fraction := range partitions {
+		if priority < 0 {
+			return fmt.Errorf("partition priority must be non-negative, got %d", priority)
+		}
+		if fraction <= 0 || fraction > 1.0 {
+			return fmt.Errorf("partition fraction for priority %d must be in (0, 1], got %f", priority, fraction)
+		}
+		total += fraction
+	}
+
+	if total > 1.0+fractionTolerance {
+		return fmt.Errorf("partition fractions must not exceed 1.0 in total, got %f", total)
+	}
+
+	return nil
+}
+
+// Limit returns the share of a GPU usable by requests at the given priority: the
+// whole GPU minus the slices reserved for higher tiers
+func (p CapacityPartitions) Limit(priority int) float64 {
+	limit := 1.0
+	for tier, fraction := range p {
+		if tier > priority {
+			limit -= fraction
+		}
+	}
+	return limit
+}
+
+// Check returns an error if adding fraction at priority to a GPU already using
+// used (priority -> fraction in use) would eat into a slice reserved for a
+// higher tier than the one using it
+func (p CapacityPartitions) Check(priority int, fraction float64, used map[int]float64) error {
+	// The request counts against its own limit and against the limit of every
+	// higher tier, since all of them share the capacity below their slices
+	tiers := []int{priority}
+	for tier := range p {
+		if tier > priority {
+			tiers = append(tiers, tier)
+		}
+	}
+
+	for _, tier := range tiers {
+		inUse := fraction
+		for usedPriority, usedFraction := range used {
+			if usedPriority <= tier {
+				inUse += usedFraction
+			}
+		}
+
+		if limit := p.Limit(tier); inUse > limit+fractionTolerance {
+			return fmt.Errorf("priority %d request would use %f of GPU capacity, above the %f left by partitions reserved for higher priorities",
+				priority, inUse, limit)
+		}
+	}
+
+	return nil
+}
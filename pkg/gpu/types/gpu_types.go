@@ -15,9 +15,14 @@
 package types
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -38,9 +43,20 @@ type GPUIsolationType string
 const (
 	GPUIsolationTimeSlicing GPUIsolationType = "time-slicing" // Time-slicing for AMD GPUs
 	GPUIsolationMIG         GPUIsolationType = "mig"          // Multi-Instance GPU (NVIDIA)
+	GPUIsolationSRIOV       GPUIsolationType = "sr-iov"       // SR-IOV virtual functions (AMD MI300X in CPX mode)
 	GPUIsolationNone        GPUIsolationType = "none"         // No isolation
 )
 
+// IsValid reports whether the isolation type is one of the known mechanisms
+func (t GPUIsolationType) IsValid() bool {
+	switch t {
+	case GPUIsolationTimeSlicing, GPUIsolationMIG, GPUIsolationSRIOV, GPUIsolationNone:
+		return true
+	default:
+		return false
+	}
+}
+
 // GPUInfo represents information about a GPU device
 type GPUInfo struct {
 	// DeviceID is the unique identifier for the GPU
@@ -67,6 +83,21 @@ type GPUInfo struct {
 	// Power is the current GPU power consumption in watts
 	Power float64 `json:"power"`
 
+	// ClockMHz is the current graphics (sclk) clock frequency in MHz
+	ClockMHz float64 `json:"clockMhz,omitempty"`
+
+	// MemoryClockMHz is the current memory (mclk) clock frequency in MHz
+	MemoryClockMHz float64 `json:"memoryClockMhz,omitempty"`
+
+	// FanSpeedPercent is the current fan speed (0-100), 0 for passively cooled GPUs
+	FanSpeedPercent float64 `json:"fanSpeedPercent,omitempty"`
+
+	// ECCErrorsCorrectable is the number of correctable ECC errors since the driver loaded
+	ECCErrorsCorrectable int64 `json:"eccErrorsCorrectable,omitempty"`
+
+	// ECCErrorsUncorrectable is the number of uncorrectable ECC errors since the driver loaded
+	ECCErrorsUncorrectable int64 `json:"eccErrorsUncorrectable,omitempty"`
+
 	// NodeName is the Kubernetes node where this GPU is located
 	NodeName string `json:"nodeName"`
 
@@ -78,6 +109,14 @@ type GPUInfo struct {
 
 	// ActiveAllocations is the number of active allocations on this GPU
 	ActiveAllocations int `json:"activeAllocations"`
+
+	// PartitionMode is the compute partition mode reported by the driver (e.g. SPX, CPX),
+	// empty if the GPU does not support partitioning
+	PartitionMode string `json:"partitionMode,omitempty"`
+
+	// MemoryMode is the memory partition mode reported by the driver (e.g. NPS1, NPS4),
+	// empty if the GPU does not support partitioning
+	MemoryMode string `json:"memoryMode,omitempty"`
 }
 
 // GPUAllocation represents a GPU allocation request
@@ -106,6 +145,23 @@ type GPUAllocation struct {
 	// ContainerName is the container requesting the allocation
 	ContainerName string `json:"containerName"`
 
+	// TenantID is the tenant owning the allocation (empty if untenanted)
+	TenantID string `json:"tenantId,omitempty"`
+
+	// ReservationID is the reservation this allocation was created for (empty if none)
+	ReservationID string `json:"reservationId,omitempty"`
+
+	// Priority is the priority the allocation was requested at
+	Priority int `json:"priority,omitempty"`
+
+	// XCDIndices are the MI300X XCD indices the allocation is pinned to in CPX and TPX
+	// modes, in ascending order, for setting HIP_VISIBLE_DEVICES
+	XCDIndices []int `json:"xcdIndices,omitempty"`
+
+	// VirtualFunctions are the SR-IOV virtual functions isolating an SR-IOV allocation,
+	// one per XCD in the order of XCDIndices
+	VirtualFunctions []SRIOVVirtualFunction `json:"virtualFunctions,omitempty"`
+
 	// Status is the current status of the allocation
 	Status GPUAllocationStatus `json:"status"`
 
@@ -116,6 +172,23 @@ type GPUAllocation struct {
 	ExpiresAt int64 `json:"expiresAt"`
 }
 
+// SRIOVVirtualFunction is an SR-IOV virtual function backing part of an allocation.
+// On an MI300X in CPX mode each XCD is exposed as its own virtual function.
+type SRIOVVirtualFunction struct {
+	// Index is the virtual function index on the GPU's physical function
+	Index int `json:"index"`
+
+	// XCDIndex is the XCD the virtual function exposes
+	XCDIndex int `json:"xcdIndex"`
+
+	// MemoryQuadrant is the NPS4 memory quadrant backing the virtual function,
+	// -1 if the GPU's memory is not partitioned
+	MemoryQuadrant int `json:"memoryQuadrant"`
+
+	// MemoryBytes is the memory the virtual function is limited to
+	MemoryBytes int64 `json:"memoryBytes"`
+}
+
 // GPUAllocationStatus represents the status of a GPU allocation
 type GPUAllocationStatus string
 
@@ -125,6 +198,8 @@ const (
 	GPUAllocationStatusCompleted GPUAllocationStatus = "completed"
 	GPUAllocationStatusFailed    GPUAllocationStatus = "failed"
 	GPUAllocationStatusExpired   GPUAllocationStatus = "expired"
+	// GPUAllocationStatusPreempted is an allocation evicted for a higher-priority request
+	GPUAllocationStatusPreempted GPUAllocationStatus = "preempted"
 )
 
 // GPURequest represents a GPU allocation request from a pod
@@ -132,6 +207,10 @@ type GPURequest struct {
 	// Fraction is the fractional GPU allocation (0.1 to 1.0)
 	Fraction float64 `json:"fraction"`
 
+	// Count is the number of whole GPUs requested; zero means one. Fractions
+	// below 1.0 are only valid for a single GPU.
+	Count int64 `json:"count,omitempty"`
+
 	// MemoryRequest is the requested GPU memory in MiB
 	MemoryRequest int64 `json:"memoryRequest"`
 
@@ -143,6 +222,9 @@ type GPURequest struct {
 
 	// Priority is the allocation priority (higher values = higher priority)
 	Priority int `json:"priority"`
+
+	// TenantID is the tenant requesting the allocation (empty if untenanted)
+	TenantID string `json:"tenantId,omitempty"`
 }
 
 // GPUAnnotations represents GPU-related annotations that can be applied to pods
@@ -210,12 +292,10 @@ func ParseGPUAnnotations(pod *corev1.Pod, containerName string) (*GPUAnnotations
 	// Parse GPU isolation annotation
 	if isolationStr, exists := pod.Annotations["kaiwo.ai/gpu-isolation"]; exists {
 		isolation := GPUIsolationType(strings.ToLower(isolationStr))
-		switch isolation {
-		case GPUIsolationTimeSlicing, GPUIsolationMIG, GPUIsolationNone:
-			annotations.IsolationType = &isolation
-		default:
+		if !isolation.IsValid() {
 			return nil, fmt.Errorf("invalid gpu-isolation annotation: %s", isolationStr)
 		}
+		annotations.IsolationType = &isolation
 	}
 
 	return annotations, nil
@@ -266,21 +346,16 @@ func CreateGPURequest(pod *corev1.Pod, containerName string) (*GPURequest, error
 		request.SharingEnabled = *annotations.SharingEnabled
 	}
 
-	// Check for GPU resource requests in container spec
-	if gpuResource, exists := container.Resources.Requests["nvidia.com/gpu"]; exists {
-		// If GPU resource is requested, use it to determine fraction
-		gpuQuantity := gpuResource.Value()
-		if gpuQuantity > 0 {
-			request.Fraction = float64(gpuQuantity) / 1000.0 // Convert millicores to fraction
+	// GPU resources count whole devices; sub-GPU sharing is requested with
+	// the fraction annotation
+	for _, resourceName := range []corev1.ResourceName{"amd.com/gpu", "nvidia.com/gpu"} {
+		if gpuResource, exists := container.Resources.Requests[resourceName]; exists {
+			request.Count = gpuResource.Value()
+			break
 		}
 	}
-
-	// Check for AMD GPU resource requests
-	if gpuResource, exists := container.Resources.Requests["amd.com/gpu"]; exists {
-		gpuQuantity := gpuResource.Value()
-		if gpuQuantity > 0 {
-			request.Fraction = float64(gpuQuantity) / 1000.0
-		}
+	if request.Count > 1 && request.Fraction < 1.0 {
+		return nil, fmt.Errorf("gpu-fraction %.2f cannot be combined with a request for %d GPUs", request.Fraction, request.Count)
 	}
 
 	return request, nil
@@ -292,6 +367,14 @@ func ValidateGPURequest(request *GPURequest) error {
 		return fmt.Errorf("GPU fraction must be between 0.1 and 1.0, got %f", request.Fraction)
 	}
 
+	if request.Count < 0 {
+		return fmt.Errorf("GPU count must be non-negative, got %d", request.Count)
+	}
+
+	if request.Count > 1 && request.Fraction < 1.0 {
+		return fmt.Errorf("GPU fraction must be 1.0 when requesting %d GPUs, got %f", request.Count, request.Fraction)
+	}
+
 	if request.MemoryRequest < 0 {
 		return fmt.Errorf("GPU memory request must be non-negative, got %d", request.MemoryRequest)
 	}
@@ -300,6 +383,10 @@ func ValidateGPURequest(request *GPURequest) error {
 		return fmt.Errorf("GPU priority must be non-negative, got %d", request.Priority)
 	}
 
+	if request.IsolationType != "" && !request.IsolationType.IsValid() {
+		return fmt.Errorf("invalid GPU isolation type: %s", request.IsolationType)
+	}
+
 	return nil
 }
 
@@ -324,6 +411,86 @@ type GPUResourceList struct {
 	Fraction resource.Quantity `json:"fraction"`
 }
 
+// GPUCapacity is the capacity still free on a GPU
+type GPUCapacity struct {
+	// DeviceID is the unique identifier for the GPU
+	DeviceID string `json:"deviceId"`
+
+	// FreeFraction is the fraction of the GPU not yet allocated
+	FreeFraction float64 `json:"freeFraction"`
+
+	// FreeMemory is the GPU memory not yet allocated, in bytes
+	FreeMemory int64 `json:"freeMemory"`
+
+	// Available indicates if the GPU currently accepts new allocations
+	Available bool `json:"available"`
+}
+
+// GPULinkType is the kind of interconnect between two GPUs
+type GPULinkType string
+
+const (
+	// GPULinkTypeXGMI is a direct XGMI (Infinity Fabric) link between two AMD GPUs
+	GPULinkTypeXGMI GPULinkType = "XGMI"
+	// GPULinkTypePCIe is a path through the PCIe fabric, not a direct link
+	GPULinkTypePCIe GPULinkType = "PCIE"
+)
+
+// GPULink describes the connection from a GPU to one of its peers
+type GPULink struct {
+	// PeerID is the device ID of the GPU at the other end of the link
+	PeerID string `json:"peerId"`
+
+	// Type is the interconnect the link uses
+	Type GPULinkType `json:"type"`
+
+	// Hops is the number of hops between the two GPUs, 0 if unknown
+	Hops int `json:"hops,omitempty"`
+
+	// Weight is the relative cost of the link reported by the driver, lower is closer
+	Weight int `json:"weight,omitempty"`
+
+	// MinBandwidth is the minimum link bandwidth in MB/s, 0 if unknown
+	MinBandwidth int64 `json:"minBandwidth,omitempty"`
+
+	// MaxBandwidth is the maximum link bandwidth in MB/s, 0 if unknown
+	MaxBandwidth int64 `json:"maxBandwidth,omitempty"`
+}
+
+// GPUTopology is the interconnect between the GPUs on a node
+type GPUTopology struct {
+	// Links holds the links from each GPU to its peers, keyed by device ID
+	// and sorted by peer ID
+	Links map[string][]GPULink `json:"links"`
+}
+
+// Link returns the link from one GPU to another, if the topology has one
+func (t *GPUTopology) Link(from, to string) (GPULink, bool) {
+	for _, link := range t.Links[from] {
+		if link.PeerID == to {
+			return link, true
+		}
+	}
+	return GPULink{}, false
+}
+
+// DirectlyLinked reports whether two GPUs share a direct XGMI link
+func (t *GPUTopology) DirectlyLinked(a, b string) bool {
+	link, ok := t.Link(a, b)
+	return ok && link.Type == GPULinkTypeXGMI
+}
+
+// XGMIPeers returns the GPUs directly linked to a GPU over XGMI, sorted
+func (t *GPUTopology) XGMIPeers(deviceID string) []string {
+	var peers []string
+	for _, link := range t.Links[deviceID] {
+		if link.Type == GPULinkTypeXGMI {
+			peers = append(peers, link.PeerID)
+		}
+	}
+	return peers
+}
+
 // GPUStats represents GPU statistics for a node or cluster
 type GPUStats struct {
 	// TotalGPUs is the total number of GPUs
@@ -349,6 +516,9 @@ type GPUStats struct {
 
 	// ActiveAllocations is the number of active GPU allocations
 	ActiveAllocations int `json:"activeAllocations"`
+
+	// AllocatedFraction is the fractional GPU capacity allocated, summed over all GPUs
+	AllocatedFraction float64 `json:"allocatedFraction"`
 }
 
 // ReservationStats contains statistics about GPU reservations
@@ -363,3 +533,52 @@ type ReservationStats struct {
 	ReservationsByUser    map[string]int `json:"reservations_by_user"`
 	ReservationsByStatus  map[string]int `json:"reservations_by_status"`
 }
+
+// ReservationUtilization reports reserved GPU-hours, each reservation's fraction
+// times the part of its duration inside the window, broken down like
+// ReservationStats. A zero WindowStart or WindowEnd leaves that side unbounded.
+type ReservationUtilization struct {
+	WindowStart      time.Time                    `json:"window_start"`
+	WindowEnd        time.Time                    `json:"window_end"`
+	TotalGPUHours    float64                      `json:"total_gpu_hours"`
+	GPUHoursByStatus map[string]float64           `json:"gpu_hours_by_status"`
+	GPUHoursByUser   map[string]*ReservedGPUHours `json:"gpu_hours_by_user"`
+	GPUHoursByGPU    map[string]*ReservedGPUHours `json:"gpu_hours_by_gpu"`
+}
+
+// ReservedGPUHours is the reserved GPU-hours of a single user or GPU
+type ReservedGPUHours struct {
+	Total    float64            `json:"total"`
+	ByStatus map[string]float64 `json:"by_status"`
+}
+
+// WriteCSV writes the per-user and per-GPU GPU-hours as CSV for chargeback,
+// with the columns scope ("user" or "gpu"), id, status and gpu_hours. Users
+// come before GPUs, and rows are sorted by id and status.
+func (u *ReservationUtilization) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"scope", "id", "status", "gpu_hours"}); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, scope := range []struct {
+		name  string
+		hours map[string]*ReservedGPUHours
+	}{
+		{"user", u.GPUHoursByUser},
+		{"gpu", u.GPUHoursByGPU},
+	} {
+		for _, id := range slices.Sorted(maps.Keys(scope.hours)) {
+			byStatus := scope.hours[id].ByStatus
+			for _, status := range slices.Sorted(maps.Keys(byStatus)) {
+				row := []string{scope.name, id, status, strconv.FormatFloat(byStatus[status], 'f', 4, 64)}
+				if err := writer.Write(row); err != nil {
+					return fmt.Errorf("failed to write CSV row: %w", err)
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newGPUPod(resourceName corev1.ResourceName, gpus int64, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default", Annotations: annotations},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{resourceName: *resource.NewQuantity(gpus, resource.DecimalSI)},
+				},
+			}},
+		},
+	}
+}
+
+func TestCreateGPURequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		pod          *corev1.Pod
+		wantFraction float64
+		wantCount    int64
+		wantErr      bool
+	}{
+		{
+			name:         "one AMD GPU is a whole GPU",
+			pod:          newGPUPod("amd.com/gpu", 1, nil),
+			wantFraction: 1.0,
+			wantCount:    1,
+		},
+		{
+			name:         "one NVIDIA GPU is a whole GPU",
+			pod:          newGPUPod("nvidia.com/gpu", 1, nil),
+			wantFraction: 1.0,
+			wantCount:    1,
+		},
+		{
+			name:         "fraction annotation shares a single GPU",
+			pod:          newGPUPod("amd.com/gpu", 1, map[string]string{"kaiwo.ai/gpu-fraction": "0.5"}),
+			wantFraction: 0.5,
+			wantCount:    1,
+		},
+		{
+			name:         "several GPUs are counted",
+			pod:          newGPUPod("amd.com/gpu", 4, nil),
+			wantFraction: 1.0,
+			wantCount:    4,
+		},
+		{
+			name:    "fraction cannot be combined with several GPUs",
+			pod:     newGPUPod("amd.com/gpu", 4, map[string]string{"kaiwo.ai/gpu-fraction": "0.5"}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := CreateGPURequest(tt.pod, "main")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", request)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateGPURequest failed: %v", err)
+			}
+			if request.Fraction != tt.wantFraction || request.Count != tt.wantCount {
+				t.Errorf("expected fraction %.2f and count %d, got %.2f and %d",
+					tt.wantFraction, tt.wantCount, request.Fraction, request.Count)
+			}
+			if err := ValidateGPURequest(request); err != nil {
+				t.Errorf("expected a valid request: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateGPURequestRejectsFractionalMultiGPU(t *testing.T) {
+	if err := ValidateGPURequest(&GPURequest{Fraction: 0.5, Count: 2}); err == nil {
+		t.Error("expected an error for a fraction of several GPUs")
+	}
+	if err := ValidateGPURequest(&GPURequest{Fraction: 1.0, Count: -1}); err == nil {
+		t.Error("expected an error for a negative count")
+	}
+}
+
+func TestSRIOVIsolationAccepted(t *testing.T) {
+	if err := ValidateGPURequest(&GPURequest{Fraction: 0.25, IsolationType: GPUIsolationSRIOV}); err != nil {
+		t.Errorf("expected an SR-IOV request to be valid: %v", err)
+	}
+	if err := ValidateGPURequest(&GPURequest{Fraction: 0.25, IsolationType: "vfio"}); err == nil {
+		t.Error("expected an error for an unknown isolation type")
+	}
+
+	pod := newGPUPod("amd.com/gpu", 1, map[string]string{"kaiwo.ai/gpu-isolation": "SR-IOV"})
+	request, err := CreateGPURequest(pod, "main")
+	if err != nil {
+		t.Fatalf("CreateGPURequest failed: %v", err)
+	}
+	if request.IsolationType != GPUIsolationSRIOV {
+		t.Errorf("expected isolation type %s, got %s", GPUIsolationSRIOV, request.IsolationType)
+	}
+
+	policy := &AllocationPolicy{
+		Name:                  "sriov",
+		Strategy:              AllocationStrategyFirstFit,
+		MinFraction:           0.125,
+		MaxFraction:           1.0,
+		AllowedIsolationTypes: []GPUIsolationType{GPUIsolationSRIOV},
+	}
+	if err := ValidateAllocationPolicy(policy); err != nil {
+		t.Errorf("expected a policy allowing SR-IOV to be valid: %v", err)
+	}
+	policy.AllowedIsolationTypes = append(policy.AllowedIsolationTypes, "vfio")
+	if err := ValidateAllocationPolicy(policy); err == nil {
+		t.Error("expected an error for a policy allowing an unknown isolation type")
+	}
+}
@@ -3,12 +3,15 @@ package alerting
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
+	"golang.org/x/time/rate"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
 )
 
 // AlertManager implements intelligent alerting for KaiwoJobs
@@ -18,20 +21,122 @@ type AlertManager struct {
 	alerts  map[string]*Alert
 	metrics *AlertManagerMetrics
 	rules   []AlertRule
+	config  AlertManagerConfig
+	now     func() time.Time
+
+	// breachStarted records, per alert key, when a rule's condition was first
+	// seen over threshold in the current unbroken breach
+	breachStarted map[string]time.Time
+
+	// silences maps silenced alert types to when their silence ends
+	silences map[AlertType]time.Time
+
+	// lastNotified records, per alert key, when it was last notified as firing
+	lastNotified map[string]time.Time
+	// limiter caps the rate of outbound notifications
+	limiter *rate.Limiter
+
+	// store persists active alerts and breach timers; dirty is set when they
+	// change and cleared once saved
+	store AlertStore
+	dirty bool
+
+	notifiers     []*notifierQueue
+	notifyRetries int
+	notifyBackoff time.Duration
+	notifyWG      sync.WaitGroup
+
+	// closing is closed by Close to stop new notifications and cut retry
+	// backoffs short
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+const (
+	// defaultNotifyRetries is how many times a failed notification is retried
+	defaultNotifyRetries = 3
+	// defaultNotifyBackoff is the delay before the first retry; it doubles per retry
+	defaultNotifyBackoff = time.Second
+)
+
+// AlertManagerConfig configures how often an AlertManager notifies
+type AlertManagerConfig struct {
+	// MinRenotifyInterval is the shortest time between two firing notifications
+	// for the same alert key. An alert that resolves and fires again within it
+	// is not notified again, and neither is its resolution.
+	MinRenotifyInterval time.Duration `json:"minRenotifyInterval"`
+
+	// NotificationRate is the sustained number of alert notifications sent per
+	// second, across all notifiers. Notifications over the limit are dropped.
+	NotificationRate float64 `json:"notificationRate"`
+
+	// NotificationBurst is how many notifications may be sent at once before
+	// NotificationRate applies
+	NotificationBurst int `json:"notificationBurst"`
+
+	// Store persists active alerts across restarts. Nil keeps them in memory only.
+	Store AlertStore `json:"-"`
+}
+
+const (
+	// DefaultMinRenotifyInterval is the default minimum time between notifications of an alert
+	DefaultMinRenotifyInterval = 15 * time.Minute
+	// DefaultNotificationRate is the default sustained notification rate per second
+	DefaultNotificationRate = 1.0
+	// DefaultNotificationBurst is the default notification burst size
+	DefaultNotificationBurst = 10
+)
+
+// DefaultAlertManagerConfig returns the default alert manager configuration
+func DefaultAlertManagerConfig() *AlertManagerConfig {
+	return &AlertManagerConfig{
+		MinRenotifyInterval: DefaultMinRenotifyInterval,
+		NotificationRate:    DefaultNotificationRate,
+		NotificationBurst:   DefaultNotificationBurst,
+	}
+}
+
+// ValidateAlertManagerConfig validates an alert manager configuration
+func ValidateAlertManagerConfig(config *AlertManagerConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	if config.MinRenotifyInterval < 0 {
+		return fmt.Errorf("min renotify interval cannot be negative, got %v", config.MinRenotifyInterval)
+	}
+	if config.NotificationRate <= 0 {
+		return fmt.Errorf("notification rate must be positive, got %.3f", config.NotificationRate)
+	}
+	if config.NotificationBurst < 1 {
+		return fmt.Errorf("notification burst must be at least 1, got %d", config.NotificationBurst)
+	}
+	return nil
 }
 
 // Alert represents an alert condition
 type Alert struct {
-	ID         string
-	JobName    string
-	Namespace  string
-	Type       AlertType
-	Severity   AlertSeverity
-	Message    string
-	Timestamp  time.Time
-	Resolved   bool
-	ResolvedAt *time.Time
-	Metrics    map[string]interface{}
+	ID         string                 `json:"id"`
+	JobName    string                 `json:"jobName"`
+	Namespace  string                 `json:"namespace"`
+	Type       AlertType              `json:"type"`
+	Severity   AlertSeverity          `json:"severity"`
+	Message    string                 `json:"message"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Resolved   bool                   `json:"resolved"`
+	ResolvedAt *time.Time             `json:"resolvedAt,omitempty"`
+	Metrics    map[string]interface{} `json:"metrics,omitempty"`
+
+	// DeviceID is the GPU an alert raised by CheckGPUAlerts is about
+	DeviceID string `json:"deviceId,omitempty"`
+
+	// Acknowledged alerts send no further notifications
+	Acknowledged bool       `json:"acknowledged"`
+	AckedBy      string     `json:"ackedBy,omitempty"`
+	AckedAt      *time.Time `json:"ackedAt,omitempty"`
+
+	// notified records whether the alert's firing notification was sent, so
+	// its resolution is only sent if it was
+	notified bool
 }
 
 // AlertType represents the type of alert
@@ -45,8 +150,15 @@ const (
 	AlertTypePodFailure             AlertType = "PodFailure"
 	AlertTypeResourceExhaustion     AlertType = "ResourceExhaustion"
 	AlertTypePerformanceDegradation AlertType = "PerformanceDegradation"
+	AlertTypeHighGPUTemperature     AlertType = "HighGPUTemperature"
+	AlertTypeHighGPUPower           AlertType = "HighGPUPower"
 )
 
+// gpuResolveRatio is the fraction of a GPU rule's threshold a reading must
+// drop below before its alert resolves, so a card hovering at the threshold
+// does not flap
+const gpuResolveRatio = 0.95
+
 // AlertSeverity represents the severity level of an alert
 type AlertSeverity string
 
@@ -63,6 +175,18 @@ type AlertRule struct {
 	Threshold   float64
 	Duration    time.Duration
 	Description string
+
+	// DeviceThresholds overrides Threshold for individual GPUs, keyed by
+	// device ID. Only used by GPU rules.
+	DeviceThresholds map[string]float64
+}
+
+// thresholdFor returns the rule's threshold for a GPU
+func (r AlertRule) thresholdFor(deviceID string) float64 {
+	if threshold, ok := r.DeviceThresholds[deviceID]; ok {
+		return threshold
+	}
+	return r.Threshold
 }
 
 // AlertManagerMetrics tracks alert manager performance metrics
@@ -71,11 +195,24 @@ type AlertManagerMetrics struct {
 	ActiveAlerts     int64
 	ResolvedAlerts   int64
 	AverageAlertTime time.Duration
-	mu               sync.RWMutex
+	// DeduplicatedNotifications counts notifications skipped by MinRenotifyInterval
+	DeduplicatedNotifications int64
+	// DroppedNotifications counts notifications dropped by the rate limiter
+	DroppedNotifications int64
+	mu                   sync.RWMutex
 }
 
-// NewAlertManager creates a new alert manager instance
-func NewAlertManager(client client.Client) *AlertManager {
+// NewAlertManager creates a new alert manager instance. A nil config uses
+// DefaultAlertManagerConfig. If the config has a Store, the active alerts and
+// breach timers saved in it are restored.
+func NewAlertManager(ctx context.Context, client client.Client, config *AlertManagerConfig) (*AlertManager, error) {
+	if config == nil {
+		config = DefaultAlertManagerConfig()
+	}
+	if err := ValidateAlertManagerConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid alert manager config: %w", err)
+	}
+
 	am := &AlertManager{
 		client: client,
 		alerts: make(map[string]*Alert),
@@ -84,13 +221,79 @@ func NewAlertManager(client client.Client) *AlertManager {
 			ActiveAlerts:   0,
 			ResolvedAlerts: 0,
 		},
-		rules: make([]AlertRule, 0),
+		rules:         make([]AlertRule, 0),
+		config:        *config,
+		now:           time.Now,
+		breachStarted: make(map[string]time.Time),
+		silences:      make(map[AlertType]time.Time),
+		lastNotified:  make(map[string]time.Time),
+		limiter:       rate.NewLimiter(rate.Limit(config.NotificationRate), config.NotificationBurst),
+		store:         config.Store,
+		notifyRetries: defaultNotifyRetries,
+		notifyBackoff: defaultNotifyBackoff,
+		closing:       make(chan struct{}),
 	}
 
 	// Initialize default alert rules
 	am.initializeDefaultRules()
 
-	return am
+	if am.store != nil {
+		if err := am.restore(ctx); err != nil {
+			return nil, fmt.Errorf("failed to restore alert state: %w", err)
+		}
+	}
+
+	return am, nil
+}
+
+// restore loads the active alerts and breach timers saved in the store
+func (am *AlertManager) restore(ctx context.Context) error {
+	state, err := am.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	maps.Copy(am.breachStarted, state.BreachStarted)
+	maps.Copy(am.lastNotified, state.LastNotified)
+	for _, alert := range state.Alerts {
+		if alert == nil || alert.Resolved {
+			continue
+		}
+		// The alert was notified if it was notified since it fired
+		if last, exists := am.lastNotified[alert.ID]; exists && !last.Before(alert.Timestamp) {
+			alert.notified = true
+		}
+		am.alerts[alert.ID] = alert
+		am.metrics.TotalAlerts++
+		am.metrics.ActiveAlerts++
+	}
+
+	return nil
+}
+
+// saveState writes the active alerts and breach timers to the store if they
+// changed. A failed save is logged and retried on the next check. Callers must
+// hold am.mu.
+func (am *AlertManager) saveState(ctx context.Context) {
+	if am.store == nil || !am.dirty {
+		return
+	}
+
+	state := &AlertState{
+		BreachStarted: maps.Clone(am.breachStarted),
+		LastNotified:  maps.Clone(am.lastNotified),
+	}
+	for _, alert := range am.alerts {
+		if !alert.Resolved {
+			state.Alerts = append(state.Alerts, alert)
+		}
+	}
+
+	if err := am.store.Save(ctx, state); err != nil {
+		fmt.Printf("Error saving alert state: %v\n", err)
+		return
+	}
+	am.dirty = false
 }
 
 // initializeDefaultRules sets up default alert rules
@@ -138,6 +341,20 @@ func (am *AlertManager) initializeDefaultRules() {
 			Duration:    10 * time.Minute,
 			Description: "Performance degradation detected",
 		},
+		{
+			Type:        AlertTypeHighGPUTemperature,
+			Severity:    AlertSeverityCritical,
+			Threshold:   85.0, // 85°C
+			Duration:    1 * time.Minute,
+			Description: "High GPU temperature detected",
+		},
+		{
+			Type:        AlertTypeHighGPUPower,
+			Severity:    AlertSeverityWarning,
+			Threshold:   700.0, // 700W
+			Duration:    2 * time.Minute,
+			Description: "High GPU power draw detected",
+		},
 	}
 }
 
@@ -156,20 +373,69 @@ func (am *AlertManager) CheckAlerts(ctx context.Context, job *v1alpha1.KaiwoJob,
 	}
 
 	// Check for resolved alerts
-	am.checkResolvedAlerts(job, metrics)
+	am.checkResolvedAlerts(ctx, job, metrics)
 
+	am.saveState(ctx)
 	return nil
 }
 
-// shouldTriggerAlert determines if an alert should be triggered
+// shouldTriggerAlert determines if an alert should be triggered. A rule's
+// condition must hold continuously for its Duration before the alert fires.
 func (am *AlertManager) shouldTriggerAlert(job *v1alpha1.KaiwoJob, rule AlertRule, metrics map[string]interface{}) bool {
 	alertKey := fmt.Sprintf("%s-%s-%s", job.Namespace, job.Name, rule.Type)
+	return am.isBreachSustained(alertKey, rule, am.isThresholdBreached(job, rule, metrics))
+}
+
+// isBreachSustained tracks the breach state of an alert key and reports
+// whether a new alert should fire for it. Callers must hold am.mu.
+func (am *AlertManager) isBreachSustained(alertKey string, rule AlertRule, breached bool) bool {
+	if !breached {
+		// The breach is broken; a later one starts its timer afresh
+		if _, breaching := am.breachStarted[alertKey]; breaching {
+			delete(am.breachStarted, alertKey)
+			am.dirty = true
+		}
+		return false
+	}
 
 	// Check if alert already exists and is active
 	if existingAlert, exists := am.alerts[alertKey]; exists && !existingAlert.Resolved {
 		return false
 	}
 
+	now := am.now()
+	started, breaching := am.breachStarted[alertKey]
+	if !breaching {
+		started = now
+		am.breachStarted[alertKey] = started
+		am.dirty = true
+	}
+
+	// The breach is still timed while silenced, so a sustained breach fires
+	// as soon as the silence ends
+	if am.isSilenced(rule.Type, now) {
+		return false
+	}
+
+	return now.Sub(started) >= rule.Duration
+}
+
+// isSilenced reports whether an alert type is silenced, dropping the silence
+// once it has expired. Callers must hold am.mu.
+func (am *AlertManager) isSilenced(alertType AlertType, now time.Time) bool {
+	until, exists := am.silences[alertType]
+	if !exists {
+		return false
+	}
+	if !now.Before(until) {
+		delete(am.silences, alertType)
+		return false
+	}
+	return true
+}
+
+// isThresholdBreached reports whether a rule's condition currently holds
+func (am *AlertManager) isThresholdBreached(job *v1alpha1.KaiwoJob, rule AlertRule, metrics map[string]interface{}) bool {
 	// Check threshold based on alert type
 	switch rule.Type {
 	case AlertTypeHighCPUUsage:
@@ -212,12 +478,20 @@ func (am *AlertManager) createAlert(ctx context.Context, job *v1alpha1.KaiwoJob,
 		Type:      rule.Type,
 		Severity:  rule.Severity,
 		Message:   rule.Description,
-		Timestamp: time.Now(),
+		Timestamp: am.now(),
 		Resolved:  false,
 		Metrics:   metrics,
 	}
 
-	am.alerts[alertKey] = alert
+	am.raiseAlert(ctx, alert)
+	return nil
+}
+
+// raiseAlert records a new alert and notifies sinks. Callers must hold am.mu.
+func (am *AlertManager) raiseAlert(ctx context.Context, alert *Alert) {
+	am.alerts[alert.ID] = alert
+	delete(am.breachStarted, alert.ID)
+	am.dirty = true
 
 	// Update metrics
 	am.metrics.mu.Lock()
@@ -225,21 +499,83 @@ func (am *AlertManager) createAlert(ctx context.Context, job *v1alpha1.KaiwoJob,
 	am.metrics.ActiveAlerts++
 	am.metrics.mu.Unlock()
 
-	// Log alert (in a real implementation, this would send notifications)
-	fmt.Printf("ALERT: %s - %s - %s: %s\n", alert.Severity, alert.Type, alert.JobName, alert.Message)
+	// Log alert and notify sinks
+	fmt.Printf("ALERT: %s - %s - %s: %s\n", alert.Severity, alert.Type, alert.subject(), alert.Message)
+	am.notify(ctx, alert)
+}
 
-	return nil
+// subject names what an alert is about: its job, or its GPU for GPU alerts
+func (a *Alert) subject() string {
+	if a.DeviceID != "" {
+		return "GPU " + a.DeviceID
+	}
+	return a.JobName
 }
 
 // checkResolvedAlerts checks if existing alerts should be resolved
-func (am *AlertManager) checkResolvedAlerts(job *v1alpha1.KaiwoJob, metrics map[string]interface{}) {
+func (am *AlertManager) checkResolvedAlerts(ctx context.Context, job *v1alpha1.KaiwoJob, metrics map[string]interface{}) {
 	for _, alert := range am.alerts {
 		if alert.JobName == job.Name && alert.Namespace == job.Namespace && !alert.Resolved {
 			if am.isAlertResolved(alert, metrics) {
-				am.resolveAlert(alert)
+				am.resolveAlert(ctx, alert)
+			}
+		}
+	}
+}
+
+// CheckGPUAlerts evaluates each GPU's temperature and power draw against the
+// GPU alert rules, raising and resolving alerts keyed by device ID
+func (am *AlertManager) CheckGPUAlerts(ctx context.Context, gpus []*types.GPUInfo) error {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	for _, gpu := range gpus {
+		if gpu == nil || gpu.DeviceID == "" {
+			continue
+		}
+		for _, rule := range am.rules {
+			reading, ok := gpuReading(gpu, rule.Type)
+			if !ok {
+				continue
+			}
+			alertKey := fmt.Sprintf("gpu-%s-%s", gpu.DeviceID, rule.Type)
+			threshold := rule.thresholdFor(gpu.DeviceID)
+
+			if existing, exists := am.alerts[alertKey]; exists && !existing.Resolved && reading < threshold*gpuResolveRatio {
+				am.resolveAlert(ctx, existing)
+			}
+
+			if am.isBreachSustained(alertKey, rule, reading > threshold) {
+				am.raiseAlert(ctx, &Alert{
+					ID:        alertKey,
+					DeviceID:  gpu.DeviceID,
+					Type:      rule.Type,
+					Severity:  rule.Severity,
+					Message:   fmt.Sprintf("%s on %s: %.1f exceeds %.1f", rule.Description, gpu.NodeName, reading, threshold),
+					Timestamp: am.now(),
+					Metrics: map[string]interface{}{
+						"value":     reading,
+						"threshold": threshold,
+					},
+				})
 			}
 		}
 	}
+
+	am.saveState(ctx)
+	return nil
+}
+
+// gpuReading returns the GPU reading a rule type is evaluated against. It
+// reports false for rules that are not about GPU hardware readings.
+func gpuReading(gpu *types.GPUInfo, alertType AlertType) (float64, bool) {
+	switch alertType {
+	case AlertTypeHighGPUTemperature:
+		return gpu.Temperature, true
+	case AlertTypeHighGPUPower:
+		return gpu.Power, true
+	}
+	return 0, false
 }
 
 // isAlertResolved determines if an alert should be resolved
@@ -274,10 +610,11 @@ func (am *AlertManager) isAlertResolved(alert *Alert, metrics map[string]interfa
 }
 
 // resolveAlert marks an alert as resolved
-func (am *AlertManager) resolveAlert(alert *Alert) {
+func (am *AlertManager) resolveAlert(ctx context.Context, alert *Alert) {
 	alert.Resolved = true
-	now := time.Now()
+	now := am.now()
 	alert.ResolvedAt = &now
+	am.dirty = true
 
 	// Update metrics
 	am.metrics.mu.Lock()
@@ -285,8 +622,194 @@ func (am *AlertManager) resolveAlert(alert *Alert) {
 	am.metrics.ResolvedAlerts++
 	am.metrics.mu.Unlock()
 
-	// Log resolution
-	fmt.Printf("RESOLVED: %s - %s - %s\n", alert.Severity, alert.Type, alert.JobName)
+	// Log resolution and notify sinks
+	fmt.Printf("RESOLVED: %s - %s - %s\n", alert.Severity, alert.Type, alert.subject())
+	am.notify(ctx, alert)
+}
+
+// AcknowledgeAlert marks an active alert as acknowledged by user, which stops
+// any further notifications about it
+func (am *AlertManager) AcknowledgeAlert(id, user string) error {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	alert, exists := am.alerts[id]
+	if !exists {
+		return fmt.Errorf("alert %s not found", id)
+	}
+	if alert.Resolved {
+		return fmt.Errorf("alert %s is already resolved", id)
+	}
+
+	now := am.now()
+	alert.Acknowledged = true
+	alert.AckedBy = user
+	alert.AckedAt = &now
+	// Saved with the next check
+	am.dirty = true
+
+	return nil
+}
+
+// Silence suppresses new alerts of a type until the given time, for example
+// during maintenance. Silencing a type again replaces its previous silence.
+func (am *AlertManager) Silence(alertType AlertType, until time.Time) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	am.silences[alertType] = until
+}
+
+// AddNotifier registers a notifier to be told of alert creation and resolution
+func (am *AlertManager) AddNotifier(notifier Notifier) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	am.notifiers = append(am.notifiers, &notifierQueue{notifier: notifier})
+}
+
+// notifierQueue holds the notifications waiting for one notifier so they are
+// delivered in the order the alerts changed
+type notifierQueue struct {
+	notifier Notifier
+	mu       sync.Mutex
+	pending  []pendingNotification
+	draining bool
+}
+
+// pendingNotification is an alert snapshot waiting to be delivered
+type pendingNotification struct {
+	ctx   context.Context
+	alert Alert
+}
+
+// notify queues a snapshot of the alert for every notifier. Queues are drained
+// in the background so slow or failing sinks never hold up CheckAlerts.
+// Callers must hold am.mu.
+func (am *AlertManager) notify(ctx context.Context, alert *Alert) {
+	if len(am.notifiers) == 0 || alert.Acknowledged || am.isClosing() {
+		return
+	}
+
+	now := am.now()
+	if alert.Resolved {
+		// Receivers never heard the alert fire
+		if !alert.notified {
+			return
+		}
+	} else if last, exists := am.lastNotified[alert.ID]; exists && now.Sub(last) < am.config.MinRenotifyInterval {
+		am.metrics.mu.Lock()
+		am.metrics.DeduplicatedNotifications++
+		am.metrics.mu.Unlock()
+		return
+	}
+
+	if !am.limiter.AllowN(now, 1) {
+		am.metrics.mu.Lock()
+		am.metrics.DroppedNotifications++
+		am.metrics.mu.Unlock()
+		fmt.Printf("Dropping notification for alert %s: notification rate limit exceeded\n", alert.ID)
+		return
+	}
+	if !alert.Resolved {
+		alert.notified = true
+		am.lastNotified[alert.ID] = now
+	}
+
+	snapshot := *alert
+	snapshot.Metrics = maps.Clone(alert.Metrics)
+	// Deliveries outlive the CheckAlerts call that triggered them
+	ctx = context.WithoutCancel(ctx)
+
+	for _, queue := range am.notifiers {
+		queue.mu.Lock()
+		queue.pending = append(queue.pending, pendingNotification{ctx: ctx, alert: snapshot})
+		if !queue.draining {
+			queue.draining = true
+			am.notifyWG.Add(1)
+			go am.drain(queue)
+		}
+		queue.mu.Unlock()
+	}
+}
+
+// drain delivers a notifier's queued notifications in order until none are left
+func (am *AlertManager) drain(queue *notifierQueue) {
+	defer am.notifyWG.Done()
+
+	for {
+		queue.mu.Lock()
+		if len(queue.pending) == 0 {
+			queue.draining = false
+			queue.mu.Unlock()
+			return
+		}
+		next := queue.pending[0]
+		queue.pending = queue.pending[1:]
+		queue.mu.Unlock()
+
+		am.deliver(next.ctx, queue.notifier, next.alert)
+	}
+}
+
+// deliver sends an alert to a notifier, retrying with exponential backoff
+func (am *AlertManager) deliver(ctx context.Context, notifier Notifier, alert Alert) {
+	backoff := am.notifyBackoff
+	for attempt := 0; ; attempt++ {
+		err := notifier.Notify(ctx, &alert)
+		if err == nil {
+			return
+		}
+		if attempt >= am.notifyRetries {
+			fmt.Printf("Error notifying alert %s after %d attempts: %v\n", alert.ID, attempt+1, err)
+			return
+		}
+		fmt.Printf("Error notifying alert %s (attempt %d), retrying in %v: %v\n", alert.ID, attempt+1, backoff, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-am.closing:
+			timer.Stop()
+			fmt.Printf("Giving up on alert %s notification: alert manager is closing\n", alert.ID)
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// isClosing reports whether Close has been called
+func (am *AlertManager) isClosing() bool {
+	select {
+	case <-am.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops queuing new notifications and waits for the queued ones to be
+// delivered. Failed deliveries are no longer retried. It is safe to call more
+// than once.
+func (am *AlertManager) Close(ctx context.Context) error {
+	am.closeOnce.Do(func() {
+		am.mu.Lock()
+		close(am.closing)
+		am.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		am.notifyWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for alert notifications to be delivered: %w", ctx.Err())
+	}
 }
 
 // GetAlerts returns all alerts for a job
@@ -379,10 +902,19 @@ func (am *AlertManager) ClearResolvedAlerts(olderThan time.Duration) {
 	am.mu.Lock()
 	defer am.mu.Unlock()
 
-	cutoffTime := time.Now().Add(-olderThan)
+	now := am.now()
+	cutoffTime := now.Add(-olderThan)
 	for alertKey, alert := range am.alerts {
 		if alert.Resolved && alert.ResolvedAt != nil && alert.ResolvedAt.Before(cutoffTime) {
 			delete(am.alerts, alertKey)
 		}
 	}
+
+	// Notification times only matter within the renotify interval
+	for alertKey, notified := range am.lastNotified {
+		if now.Sub(notified) >= am.config.MinRenotifyInterval {
+			delete(am.lastNotified, alertKey)
+			am.dirty = true
+		}
+	}
 }
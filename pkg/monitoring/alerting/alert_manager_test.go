@@ -0,0 +1,673 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// fakeNotifier records the alerts it is sent and fails the first failures calls
+type fakeNotifier struct {
+	mu       sync.Mutex
+	alerts   []Alert
+	failures int
+	calls    int
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, alert *Alert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.calls <= f.failures {
+		return fmt.Errorf("sink unavailable")
+	}
+	f.alerts = append(f.alerts, *alert)
+	return nil
+}
+
+func (f *fakeNotifier) received() []Alert {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Alert(nil), f.alerts...)
+}
+
+// testClock is a manually advanced clock
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTestAlertManager(t *testing.T, notifiers ...Notifier) (*AlertManager, *testClock) {
+	t.Helper()
+	return newTestAlertManagerWithConfig(t, nil, notifiers...)
+}
+
+func newTestAlertManagerWithConfig(t *testing.T, config *AlertManagerConfig, notifiers ...Notifier) (*AlertManager, *testClock) {
+	t.Helper()
+	clock := &testClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	am, err := NewAlertManager(context.Background(), nil, config)
+	if err != nil {
+		t.Fatalf("NewAlertManager failed: %v", err)
+	}
+	am.now = clock.Now
+	am.notifyBackoff = time.Millisecond
+	for _, notifier := range notifiers {
+		am.AddNotifier(notifier)
+	}
+	return am, clock
+}
+
+// sustainAlerts reports the same metrics before and after every default rule's
+// Duration has passed, so any breached rule fires
+func sustainAlerts(am *AlertManager, clock *testClock, job *v1alpha1.KaiwoJob, metrics map[string]interface{}) error {
+	if err := am.CheckAlerts(context.Background(), job, metrics); err != nil {
+		return err
+	}
+	clock.Advance(time.Hour)
+	return am.CheckAlerts(context.Background(), job, metrics)
+}
+
+func newTestJob() *v1alpha1.KaiwoJob {
+	return &v1alpha1.KaiwoJob{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"}}
+}
+
+func TestAlertManagerNotifiesOnCreateAndResolve(t *testing.T) {
+	first, second := &fakeNotifier{}, &fakeNotifier{}
+	am, clock := newTestAlertManager(t, first, second)
+	job := newTestJob()
+
+	ctx := context.Background()
+	if err := sustainAlerts(am, clock, job, map[string]interface{}{"cpu_usage": 0.95}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+
+	for _, notifier := range []*fakeNotifier{first, second} {
+		alerts := notifier.received()
+		if len(alerts) != 1 {
+			t.Fatalf("expected 1 notification after creation, got %d", len(alerts))
+		}
+		alert := alerts[0]
+		if alert.ID != "default-train-HighCPUUsage" || alert.Type != AlertTypeHighCPUUsage ||
+			alert.Severity != AlertSeverityWarning || alert.JobName != "train" || alert.Namespace != "default" ||
+			alert.Message != "High CPU usage detected" || alert.Resolved {
+			t.Errorf("unexpected creation payload: %+v", alert)
+		}
+		if alert.Metrics["cpu_usage"] != 0.95 {
+			t.Errorf("expected metrics in payload, got %v", alert.Metrics)
+		}
+	}
+
+	if err := am.CheckAlerts(ctx, job, map[string]interface{}{"cpu_usage": 0.5}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+
+	alerts := first.received()
+	if len(alerts) != 2 {
+		t.Fatalf("expected a second notification after resolution, got %d", len(alerts))
+	}
+	resolved := alerts[1]
+	if resolved.ID != "default-train-HighCPUUsage" || !resolved.Resolved || resolved.ResolvedAt == nil {
+		t.Errorf("unexpected resolution payload: %+v", resolved)
+	}
+	// The creation notification is a snapshot and is not changed by resolution
+	if alerts[0].Resolved {
+		t.Error("creation notification was modified by resolution")
+	}
+}
+
+func TestAlertManagerRetriesFailedNotifications(t *testing.T) {
+	flaky := &fakeNotifier{failures: 2}
+	broken := &fakeNotifier{failures: 100}
+	am, clock := newTestAlertManager(t, flaky, broken)
+
+	if err := sustainAlerts(am, clock, newTestJob(), map[string]interface{}{"gpu_usage": 0.99}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+
+	if got := len(flaky.received()); got != 1 {
+		t.Errorf("expected flaky notifier to succeed after retries, got %d notifications", got)
+	}
+	broken.mu.Lock()
+	defer broken.mu.Unlock()
+	if broken.calls != defaultNotifyRetries+1 {
+		t.Errorf("broken notifier called %d times, want %d", broken.calls, defaultNotifyRetries+1)
+	}
+}
+
+func TestAlertManagerCloseStopsRetriesAndNewNotifications(t *testing.T) {
+	broken := &fakeNotifier{failures: 100}
+	am, clock := newTestAlertManager(t, broken)
+	am.notifyBackoff = time.Hour
+
+	if err := sustainAlerts(am, clock, newTestJob(), map[string]interface{}{"gpu_usage": 0.99}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := am.Close(ctx); err != nil {
+		t.Fatalf("Close did not cut the retry backoff short: %v", err)
+	}
+	if err := am.Close(ctx); err != nil {
+		t.Fatalf("second Close failed: %v", err)
+	}
+
+	if err := sustainAlerts(am, clock, newTestJob(), map[string]interface{}{"cpu_usage": 0.95}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+
+	broken.mu.Lock()
+	defer broken.mu.Unlock()
+	if broken.calls != 1 {
+		t.Errorf("notifier called %d times after Close, want 1", broken.calls)
+	}
+}
+
+func TestAlertManagerDoesNotBlockOnNotifiers(t *testing.T) {
+	release := make(chan struct{})
+	blocking := notifierFunc(func(ctx context.Context, alert *Alert) error {
+		<-release
+		return nil
+	})
+	am, clock := newTestAlertManager(t, blocking)
+	defer func() {
+		close(release)
+		am.notifyWG.Wait()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sustainAlerts(am, clock, newTestJob(), map[string]interface{}{"memory_usage": 0.95})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("CheckAlerts failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("CheckAlerts blocked on a notifier")
+	}
+}
+
+// notifierFunc adapts a function to the Notifier interface
+type notifierFunc func(ctx context.Context, alert *Alert) error
+
+func (f notifierFunc) Notify(ctx context.Context, alert *Alert) error {
+	return f(ctx, alert)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []Alert
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("failed to decode webhook payload: %v", err)
+		}
+		mu.Lock()
+		received = append(received, alert)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	am, clock := newTestAlertManager(t, NewWebhookNotifier(server.URL))
+	job := newTestJob()
+	job.Status.Status = v1alpha1.WorkloadStatusFailed
+
+	if err := sustainAlerts(am, clock, job, map[string]interface{}{"performance": 0.3}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if err := am.CheckAlerts(context.Background(), job, map[string]interface{}{"performance": 0.9}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	byType := make(map[AlertType][]Alert)
+	for _, alert := range received {
+		byType[alert.Type] = append(byType[alert.Type], alert)
+	}
+
+	failures := byType[AlertTypeJobFailure]
+	if len(failures) != 1 || failures[0].Severity != AlertSeverityCritical || failures[0].Namespace != "default" {
+		t.Errorf("unexpected job failure notifications: %+v", failures)
+	}
+	degradation := byType[AlertTypePerformanceDegradation]
+	if len(degradation) != 2 || degradation[0].Resolved || !degradation[1].Resolved {
+		t.Fatalf("expected creation and resolution of the degradation alert, got %+v", degradation)
+	}
+	if degradation[0].Metrics["performance"] != 0.3 {
+		t.Errorf("expected metrics in webhook payload, got %v", degradation[0].Metrics)
+	}
+}
+
+func TestWebhookNotifierRejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL).Notify(context.Background(), &Alert{ID: "alert"})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected a 500 error, got %v", err)
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		texts = append(texts, message.Text)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL)
+	alert := &Alert{
+		Type:      AlertTypeHighGPUUsage,
+		Severity:  AlertSeverityWarning,
+		JobName:   "train",
+		Namespace: "default",
+		Message:   "High GPU usage detected",
+	}
+	if err := notifier.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	alert.Resolved = true
+	if err := notifier.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if len(texts) != 2 {
+		t.Fatalf("expected 2 slack messages, got %d", len(texts))
+	}
+	if !strings.Contains(texts[0], "[Warning] HighGPUUsage for default/train: High GPU usage detected") ||
+		strings.Contains(texts[0], "RESOLVED") {
+		t.Errorf("unexpected alert message: %q", texts[0])
+	}
+	if !strings.Contains(texts[1], "RESOLVED") {
+		t.Errorf("expected resolution message, got %q", texts[1])
+	}
+}
+
+func TestAlertManagerRequiresSustainedBreach(t *testing.T) {
+	notifier := &fakeNotifier{}
+	am, clock := newTestAlertManager(t, notifier)
+	job := newTestJob()
+	ctx := context.Background()
+	high := map[string]interface{}{"cpu_usage": 0.95}
+
+	check := func(metrics map[string]interface{}) {
+		t.Helper()
+		if err := am.CheckAlerts(ctx, job, metrics); err != nil {
+			t.Fatalf("CheckAlerts failed: %v", err)
+		}
+	}
+	activeCPUAlerts := func() int {
+		alerts, _ := am.GetActiveAlerts()
+		count := 0
+		for _, alert := range alerts {
+			if alert.Type == AlertTypeHighCPUUsage {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The CPU rule needs five minutes of continuous breach
+	check(high)
+	if activeCPUAlerts() != 0 {
+		t.Fatal("alert fired on the first sample over threshold")
+	}
+
+	clock.Advance(4 * time.Minute)
+	check(high)
+	if activeCPUAlerts() != 0 {
+		t.Fatal("alert fired before the rule duration elapsed")
+	}
+
+	// A dip below threshold resets the timer
+	clock.Advance(30 * time.Second)
+	check(map[string]interface{}{"cpu_usage": 0.85})
+	clock.Advance(30 * time.Second)
+	check(high)
+	clock.Advance(4 * time.Minute)
+	check(high)
+	if activeCPUAlerts() != 0 {
+		t.Fatal("alert fired although the breach was interrupted")
+	}
+
+	clock.Advance(time.Minute)
+	check(high)
+	if activeCPUAlerts() != 1 {
+		t.Fatalf("expected the alert after a sustained breach, got %d", activeCPUAlerts())
+	}
+	alerts, _ := am.GetAlerts("train", "default")
+	if len(alerts) != 1 || !alerts[0].Timestamp.Equal(clock.Now()) {
+		t.Errorf("unexpected alerts: %+v", alerts)
+	}
+
+	am.notifyWG.Wait()
+	if got := len(notifier.received()); got != 1 {
+		t.Errorf("expected 1 notification, got %d", got)
+	}
+}
+
+func TestAlertManagerAcknowledgedAlertIsNotRenotified(t *testing.T) {
+	notifier := &fakeNotifier{}
+	am, clock := newTestAlertManager(t, notifier)
+	job := newTestJob()
+	high := map[string]interface{}{"gpu_usage": 0.99}
+
+	if err := sustainAlerts(am, clock, job, high); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+	if got := len(notifier.received()); got != 1 {
+		t.Fatalf("expected 1 notification for the new alert, got %d", got)
+	}
+
+	const id = "default-train-HighGPUUsage"
+	if err := am.AcknowledgeAlert(id, "oncall@example.com"); err != nil {
+		t.Fatalf("AcknowledgeAlert failed: %v", err)
+	}
+	alert := am.GetAllAlerts()[id]
+	if !alert.Acknowledged || alert.AckedBy != "oncall@example.com" || alert.AckedAt == nil || !alert.AckedAt.Equal(clock.Now()) {
+		t.Errorf("unexpected acknowledgment: %+v", alert)
+	}
+
+	// Resolving the acknowledged alert sends nothing further
+	if err := am.CheckAlerts(context.Background(), job, map[string]interface{}{"gpu_usage": 0.5}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+	if got := len(notifier.received()); got != 1 {
+		t.Errorf("expected no notification after acknowledgment, got %d in total", got)
+	}
+
+	// A fresh breach is a new, unacknowledged alert
+	if err := sustainAlerts(am, clock, job, high); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+	if got := len(notifier.received()); got != 2 {
+		t.Errorf("expected the new alert to be notified, got %d in total", got)
+	}
+
+	if err := am.AcknowledgeAlert("missing", "oncall@example.com"); err == nil {
+		t.Error("expected an error acknowledging an unknown alert")
+	}
+}
+
+func TestAlertManagerSilence(t *testing.T) {
+	notifier := &fakeNotifier{}
+	am, clock := newTestAlertManager(t, notifier)
+	job := newTestJob()
+	metrics := map[string]interface{}{"cpu_usage": 0.95, "memory_usage": 0.95}
+
+	am.Silence(AlertTypeHighCPUUsage, clock.Now().Add(2*time.Hour))
+
+	// Only the unsilenced memory rule fires
+	if err := sustainAlerts(am, clock, job, metrics); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	active, _ := am.GetActiveAlerts()
+	if len(active) != 1 || active[0].Type != AlertTypeHighMemoryUsage {
+		t.Fatalf("expected only the memory alert while CPU is silenced, got %+v", active)
+	}
+
+	// The silence expires by itself and the sustained breach fires at once
+	clock.Advance(time.Hour)
+	if err := am.CheckAlerts(context.Background(), job, metrics); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	active, _ = am.GetActiveAlerts()
+	if len(active) != 2 {
+		t.Fatalf("expected the CPU alert after the silence expired, got %+v", active)
+	}
+	am.mu.RLock()
+	remaining := len(am.silences)
+	am.mu.RUnlock()
+	if remaining != 0 {
+		t.Errorf("expected the expired silence to be dropped, %d remain", remaining)
+	}
+
+	am.notifyWG.Wait()
+	if got := len(notifier.received()); got != 2 {
+		t.Errorf("expected 2 notifications, got %d", got)
+	}
+}
+
+func TestAlertManagerCheckGPUAlerts(t *testing.T) {
+	notifier := &fakeNotifier{}
+	am, clock := newTestAlertManager(t, notifier)
+	ctx := context.Background()
+
+	// gpu-2 runs a cooler profile and alerts at a lower temperature
+	var temperatureRule AlertRule
+	for _, rule := range am.GetAlertRules() {
+		if rule.Type == AlertTypeHighGPUTemperature {
+			temperatureRule = rule
+		}
+	}
+	temperatureRule.DeviceThresholds = map[string]float64{"gpu-2": 70}
+	am.RemoveAlertRule(AlertTypeHighGPUTemperature)
+	am.AddAlertRule(temperatureRule)
+
+	check := func(gpus ...*types.GPUInfo) {
+		t.Helper()
+		if err := am.CheckGPUAlerts(ctx, gpus); err != nil {
+			t.Fatalf("CheckGPUAlerts failed: %v", err)
+		}
+	}
+	activeIDs := func() map[string]bool {
+		alerts, _ := am.GetActiveAlerts()
+		ids := make(map[string]bool)
+		for _, alert := range alerts {
+			ids[alert.ID] = true
+		}
+		return ids
+	}
+
+	gpus := []*types.GPUInfo{
+		{DeviceID: "gpu-0", NodeName: "node-a", Temperature: 92, Power: 400},
+		{DeviceID: "gpu-1", NodeName: "node-a", Temperature: 60, Power: 750},
+		{DeviceID: "gpu-2", NodeName: "node-b", Temperature: 75, Power: 300},
+		{DeviceID: "gpu-3", NodeName: "node-b", Temperature: 60, Power: 300},
+	}
+
+	check(gpus...)
+	if active := activeIDs(); len(active) != 0 {
+		t.Fatalf("alerts fired before the rule duration elapsed: %v", active)
+	}
+
+	clock.Advance(time.Hour)
+	check(gpus...)
+	want := []string{"gpu-gpu-0-HighGPUTemperature", "gpu-gpu-1-HighGPUPower", "gpu-gpu-2-HighGPUTemperature"}
+	active := activeIDs()
+	if len(active) != len(want) {
+		t.Fatalf("expected alerts %v, got %v", want, active)
+	}
+	for _, id := range want {
+		if !active[id] {
+			t.Errorf("expected alert %s, got %v", id, active)
+		}
+	}
+	alert := am.GetAllAlerts()["gpu-gpu-0-HighGPUTemperature"]
+	if alert.DeviceID != "gpu-0" || alert.Severity != AlertSeverityCritical || alert.Metrics["value"] != 92.0 {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+
+	// Readings just under the threshold keep the alert open; well under resolves it
+	check(
+		&types.GPUInfo{DeviceID: "gpu-0", Temperature: 83, Power: 400},
+		&types.GPUInfo{DeviceID: "gpu-1", Temperature: 60, Power: 600},
+	)
+	active = activeIDs()
+	if !active["gpu-gpu-0-HighGPUTemperature"] {
+		t.Error("temperature alert resolved within the hysteresis band")
+	}
+	if active["gpu-gpu-1-HighGPUPower"] {
+		t.Error("expected the power alert to resolve")
+	}
+
+	check(&types.GPUInfo{DeviceID: "gpu-0", Temperature: 70, Power: 400})
+	if activeIDs()["gpu-gpu-0-HighGPUTemperature"] {
+		t.Error("expected the temperature alert to resolve")
+	}
+
+	am.notifyWG.Wait()
+	// Three alerts raised and two resolved
+	if got := len(notifier.received()); got != 5 {
+		t.Errorf("expected 5 notifications, got %d", got)
+	}
+}
+
+func TestAlertManagerDeduplicatesRenotifications(t *testing.T) {
+	notifier := &fakeNotifier{}
+	am, clock := newTestAlertManager(t, notifier)
+	job := newTestJob()
+	ctx := context.Background()
+
+	// Fire on the first sample so the alert can flap between checks
+	am.RemoveAlertRule(AlertTypeHighCPUUsage)
+	am.AddAlertRule(AlertRule{Type: AlertTypeHighCPUUsage, Severity: AlertSeverityWarning, Threshold: 0.9})
+
+	check := func(cpuUsage float64) {
+		t.Helper()
+		if err := am.CheckAlerts(ctx, job, map[string]interface{}{"cpu_usage": cpuUsage}); err != nil {
+			t.Fatalf("CheckAlerts failed: %v", err)
+		}
+		clock.Advance(10 * time.Second)
+	}
+	firing := func() int {
+		count := 0
+		for _, alert := range notifier.received() {
+			if !alert.Resolved {
+				count++
+			}
+		}
+		return count
+	}
+
+	for i := 0; i < 10; i++ {
+		check(0.95)
+		check(0.5)
+	}
+	am.notifyWG.Wait()
+
+	if got := firing(); got != 1 {
+		t.Errorf("expected 1 firing notification within the renotify interval, got %d", got)
+	}
+	// Only the resolution of the notified alert is sent
+	if got := len(notifier.received()); got != 2 {
+		t.Errorf("expected 2 notifications in total, got %d", got)
+	}
+	if got := am.GetMetrics().DeduplicatedNotifications; got != 9 {
+		t.Errorf("expected 9 deduplicated notifications, got %d", got)
+	}
+
+	clock.Advance(DefaultMinRenotifyInterval)
+	check(0.95)
+	am.notifyWG.Wait()
+	if got := firing(); got != 2 {
+		t.Errorf("expected the alert to be notified again after the interval, got %d firing notifications", got)
+	}
+}
+
+func TestAlertManagerRateLimitsNotifications(t *testing.T) {
+	notifier := &fakeNotifier{}
+	config := DefaultAlertManagerConfig()
+	config.NotificationRate = 1
+	config.NotificationBurst = 3
+	am, clock := newTestAlertManagerWithConfig(t, config, notifier)
+
+	overheating := func(from, to int) []*types.GPUInfo {
+		var gpus []*types.GPUInfo
+		for i := from; i < to; i++ {
+			gpus = append(gpus, &types.GPUInfo{DeviceID: fmt.Sprintf("gpu-%d", i), Temperature: 95})
+		}
+		return gpus
+	}
+	sustain := func(gpus []*types.GPUInfo) {
+		t.Helper()
+		for i := 0; i < 2; i++ {
+			if err := am.CheckGPUAlerts(context.Background(), gpus); err != nil {
+				t.Fatalf("CheckGPUAlerts failed: %v", err)
+			}
+			if i == 0 {
+				clock.Advance(time.Minute)
+			}
+		}
+	}
+
+	// Ten GPUs overheat at once; only the burst is sent
+	sustain(overheating(0, 10))
+	am.notifyWG.Wait()
+	if got := len(notifier.received()); got != 3 {
+		t.Errorf("expected the burst of 3 notifications, got %d", got)
+	}
+	if got := am.GetMetrics().DroppedNotifications; got != 7 {
+		t.Errorf("expected 7 dropped notifications, got %d", got)
+	}
+	if active, _ := am.GetActiveAlerts(); len(active) != 10 {
+		t.Errorf("rate limiting must not drop alerts, got %d active", len(active))
+	}
+
+	// The bucket refills at the configured rate
+	sustain(overheating(10, 20))
+	am.notifyWG.Wait()
+	if got := len(notifier.received()); got != 6 {
+		t.Errorf("expected 3 more notifications after a minute, got %d in total", got)
+	}
+}
+
+func TestNewAlertManagerRejectsInvalidConfig(t *testing.T) {
+	config := DefaultAlertManagerConfig()
+	config.NotificationRate = 0
+	if _, err := NewAlertManager(context.Background(), nil, config); err == nil {
+		t.Error("expected an error for a zero notification rate")
+	}
+}
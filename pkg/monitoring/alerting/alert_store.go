@@ -0,0 +1,102 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// alertStateKey is the ConfigMap data key holding the serialized alert state
+const alertStateKey = "alerts.json"
+
+// AlertState is the part of an AlertManager's state that survives restarts
+type AlertState struct {
+	// Alerts are the active alerts
+	Alerts []*Alert `json:"alerts"`
+	// BreachStarted is when each breaching alert key was first seen over threshold
+	BreachStarted map[string]time.Time `json:"breachStarted,omitempty"`
+	// LastNotified is when each alert key was last notified as firing
+	LastNotified map[string]time.Time `json:"lastNotified,omitempty"`
+}
+
+// AlertStore persists alert state
+type AlertStore interface {
+	// Load returns the saved state, or an empty state if none was saved
+	Load(ctx context.Context) (*AlertState, error)
+	// Save replaces the saved state
+	Save(ctx context.Context, state *AlertState) error
+}
+
+// ConfigMapAlertStore keeps alert state as JSON in a ConfigMap
+type ConfigMapAlertStore struct {
+	client client.Client
+	key    client.ObjectKey
+}
+
+// NewConfigMapAlertStore creates a store backed by the named ConfigMap, which
+// is created on the first save
+func NewConfigMapAlertStore(c client.Client, namespace, name string) *ConfigMapAlertStore {
+	return &ConfigMapAlertStore{
+		client: c,
+		key:    client.ObjectKey{Namespace: namespace, Name: name},
+	}
+}
+
+// Load reads the alert state from the ConfigMap
+func (s *ConfigMapAlertStore) Load(ctx context.Context) (*AlertState, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &AlertState{}, nil
+		}
+		return nil, fmt.Errorf("failed to get alert state configmap %s: %w", s.key, err)
+	}
+
+	data, exists := configMap.Data[alertStateKey]
+	if !exists {
+		return &AlertState{}, nil
+	}
+	state := &AlertState{}
+	if err := json.Unmarshal([]byte(data), state); err != nil {
+		return nil, fmt.Errorf("failed to decode alert state from configmap %s: %w", s.key, err)
+	}
+	return state, nil
+}
+
+// Save writes the alert state to the ConfigMap, creating it if needed
+func (s *ConfigMapAlertStore) Save(ctx context.Context, state *AlertState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert state: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.key, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get alert state configmap %s: %w", s.key, err)
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: s.key.Namespace, Name: s.key.Name},
+			Data:       map[string]string{alertStateKey: string(data)},
+		}
+		if err := s.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create alert state configmap %s: %w", s.key, err)
+		}
+		return nil
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[alertStateKey] = string(data)
+	if err := s.client.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update alert state configmap %s: %w", s.key, err)
+	}
+	return nil
+}
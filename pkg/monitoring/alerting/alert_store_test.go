@@ -0,0 +1,98 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAlertManagerRestoresStateFromStore(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().Build()
+	config := DefaultAlertManagerConfig()
+	config.Store = NewConfigMapAlertStore(k8sClient, "kaiwo-system", "kaiwo-alerts")
+	ctx := context.Background()
+	job := newTestJob()
+
+	// The first manager fires a CPU alert and starts timing a memory breach
+	am, clock := newTestAlertManagerWithConfig(t, config, &fakeNotifier{})
+	if err := sustainAlerts(am, clock, job, map[string]interface{}{"cpu_usage": 0.95}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	firedAt := clock.Now()
+	if err := am.CheckAlerts(ctx, job, map[string]interface{}{"cpu_usage": 0.95, "memory_usage": 0.95}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	am.notifyWG.Wait()
+
+	configMap := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "kaiwo-system", Name: "kaiwo-alerts"}, configMap); err != nil {
+		t.Fatalf("expected the alert state configmap: %v", err)
+	}
+
+	// A restarted manager picks up where the first left off
+	notifier := &fakeNotifier{}
+	restarted, restartedClock := newTestAlertManagerWithConfig(t, config, notifier)
+	restartedClock.Advance(firedAt.Sub(restartedClock.Now()))
+
+	active, _ := restarted.GetActiveAlerts()
+	if len(active) != 1 {
+		t.Fatalf("expected the CPU alert to be restored, got %+v", active)
+	}
+	alert := active[0]
+	if alert.ID != "default-train-HighCPUUsage" || !alert.Timestamp.Equal(firedAt) || alert.Metrics["cpu_usage"] != 0.95 {
+		t.Errorf("unexpected restored alert: %+v", alert)
+	}
+	if got := restarted.GetMetrics().ActiveAlerts; got != 1 {
+		t.Errorf("expected 1 active alert in metrics, got %d", got)
+	}
+	if started := restarted.breachStarted["default-train-HighMemoryUsage"]; !started.Equal(firedAt) {
+		t.Errorf("expected the memory breach to have started at %v, got %v", firedAt, started)
+	}
+
+	// The still-firing CPU alert is not notified again, and the restored
+	// memory breach fires once its duration has passed
+	restartedClock.Advance(5 * time.Minute)
+	if err := restarted.CheckAlerts(ctx, job, map[string]interface{}{"cpu_usage": 0.95, "memory_usage": 0.95}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	restarted.notifyWG.Wait()
+	received := notifier.received()
+	if len(received) != 1 || received[0].Type != AlertTypeHighMemoryUsage {
+		t.Fatalf("expected only the memory alert to be notified, got %+v", received)
+	}
+
+	// The restored alert was notified before the restart, so its resolution is sent
+	if err := restarted.CheckAlerts(ctx, job, map[string]interface{}{"cpu_usage": 0.5, "memory_usage": 0.95}); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	restarted.notifyWG.Wait()
+	received = notifier.received()
+	if len(received) != 2 || received[1].Type != AlertTypeHighCPUUsage || !received[1].Resolved {
+		t.Errorf("expected the CPU resolution to be notified, got %+v", received)
+	}
+
+	// Resolved alerts are dropped from the store
+	state, err := config.Store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(state.Alerts) != 1 || state.Alerts[0].Type != AlertTypeHighMemoryUsage {
+		t.Errorf("expected only the memory alert to be stored, got %+v", state.Alerts)
+	}
+}
+
+func TestConfigMapAlertStoreLoadMissing(t *testing.T) {
+	store := NewConfigMapAlertStore(fake.NewClientBuilder().Build(), "kaiwo-system", "kaiwo-alerts")
+
+	state, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(state.Alerts) != 0 || len(state.BreachStarted) != 0 {
+		t.Errorf("expected an empty state, got %+v", state)
+	}
+}
@@ -0,0 +1,101 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultNotifierTimeout bounds a single notification request
+const defaultNotifierTimeout = 10 * time.Second
+
+// Notifier delivers alerts outside the process. Notify is called when an alert
+// is created and again when it is resolved.
+type Notifier interface {
+	Notify(ctx context.Context, alert *Alert) error
+}
+
+// WebhookNotifier POSTs each alert as JSON to a URL
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that POSTs alerts to url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: defaultNotifierTimeout},
+	}
+}
+
+// Notify POSTs the alert as JSON
+func (w *WebhookNotifier) Notify(ctx context.Context, alert *Alert) error {
+	return postJSON(ctx, w.client, w.url, alert)
+}
+
+// SlackNotifier posts alerts to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackNotifier creates a notifier that posts alerts to a Slack incoming webhook
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: defaultNotifierTimeout},
+	}
+}
+
+// slackMessage is the payload of a Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Notify posts a one-line summary of the alert
+func (s *SlackNotifier) Notify(ctx context.Context, alert *Alert) error {
+	return postJSON(ctx, s.client, s.webhookURL, slackMessage{Text: formatSlackText(alert)})
+}
+
+// formatSlackText summarizes an alert for a Slack message
+func formatSlackText(alert *Alert) string {
+	target := alert.Namespace + "/" + alert.JobName
+	if alert.DeviceID != "" {
+		target = "GPU " + alert.DeviceID
+	}
+	if alert.Resolved {
+		return fmt.Sprintf(":white_check_mark: RESOLVED [%s] %s for %s: %s",
+			alert.Severity, alert.Type, target, alert.Message)
+	}
+	return fmt.Sprintf(":rotating_light: [%s] %s for %s: %s",
+		alert.Severity, alert.Type, target, alert.Message)
+}
+
+// postJSON POSTs payload as JSON and fails on a non-2xx response
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification rejected with status %s", resp.Status)
+	}
+	return nil
+}
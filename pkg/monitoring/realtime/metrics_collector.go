@@ -11,14 +11,30 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
+	"github.com/silogen/kaiwo/pkg/optimization"
+)
+
+// UsageSource is where a job's resource usage figures came from
+type UsageSource string
+
+const (
+	// UsageSourceMetrics means usage was measured by the pod metrics provider
+	UsageSourceMetrics UsageSource = "Metrics"
+	// UsageSourceRequests means live metrics were unavailable and usage is the
+	// pods' resource requests
+	UsageSourceRequests UsageSource = "Requests"
 )
 
 // MetricsCollector implements real-time metrics collection for KaiwoJobs
 type MetricsCollector struct {
-	client    client.Client
-	mu        sync.RWMutex
-	metrics   map[string]*JobMetrics
-	collector *MetricsCollectorMetrics
+	client     client.Client
+	podMetrics optimization.MetricsProvider
+	mu         sync.RWMutex
+	metrics    map[string]*JobMetrics
+	history    map[string]*metricsRing
+	config     MetricsCollectorConfig
+	collector  *MetricsCollectorMetrics
+	now        func() time.Time
 }
 
 // JobMetrics represents real-time metrics for a job
@@ -28,14 +44,25 @@ type JobMetrics struct {
 	Timestamp   time.Time
 	CPUUsage    resource.Quantity
 	MemoryUsage resource.Quantity
-	GPUUsage    int64
-	PodCount    int
-	RunningPods int
-	FailedPods  int
-	PendingPods int
-	Status      v1alpha1.WorkloadStatus
-	Performance float64
-	Efficiency  float64
+	// GPUUsage is the number of GPUs allocated to running pods
+	GPUUsage int64
+	// GPUUtilization is the average utilization (0-1) of those GPUs; only
+	// measured when UsageSource is UsageSourceMetrics
+	GPUUtilization float64
+	// UsageSource is where CPUUsage, MemoryUsage and GPUUtilization came from
+	UsageSource UsageSource
+	// CPURequests and MemoryRequests are the running pods' resource requests
+	CPURequests    resource.Quantity
+	MemoryRequests resource.Quantity
+	PodCount       int
+	RunningPods    int
+	FailedPods     int
+	PendingPods    int
+	Status         v1alpha1.WorkloadStatus
+	Performance    float64
+	// Efficiency is the job's usage relative to its requests (0-1); zero when
+	// usage was not measured
+	Efficiency float64
 }
 
 // MetricsCollectorMetrics tracks metrics collection performance
@@ -47,17 +74,30 @@ type MetricsCollectorMetrics struct {
 	mu                    sync.RWMutex
 }
 
-// NewMetricsCollector creates a new metrics collector instance
-func NewMetricsCollector(client client.Client) *MetricsCollector {
+// NewMetricsCollector creates a new metrics collector instance. Without a pod
+// metrics provider, usage is always reported from resource requests. A nil
+// config uses DefaultMetricsCollectorConfig.
+func NewMetricsCollector(client client.Client, podMetrics optimization.MetricsProvider, config *MetricsCollectorConfig) (*MetricsCollector, error) {
+	if config == nil {
+		config = DefaultMetricsCollectorConfig()
+	}
+	if err := ValidateMetricsCollectorConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid metrics collector config: %w", err)
+	}
+
 	return &MetricsCollector{
-		client:  client,
-		metrics: make(map[string]*JobMetrics),
+		client:     client,
+		podMetrics: podMetrics,
+		metrics:    make(map[string]*JobMetrics),
+		history:    make(map[string]*metricsRing),
+		config:     *config,
+		now:        time.Now,
 		collector: &MetricsCollectorMetrics{
 			TotalCollections:      0,
 			SuccessfulCollections: 0,
 			FailedCollections:     0,
 		},
-	}
+	}, nil
 }
 
 // CollectMetrics collects real-time metrics for a job
@@ -83,7 +123,7 @@ func (mc *MetricsCollector) CollectMetrics(ctx context.Context, job *v1alpha1.Ka
 	metrics := &JobMetrics{
 		JobName:   job.Name,
 		Namespace: job.Namespace,
-		Timestamp: time.Now(),
+		Timestamp: mc.now(),
 		Status:    job.Status.Status,
 	}
 
@@ -91,7 +131,7 @@ func (mc *MetricsCollector) CollectMetrics(ctx context.Context, job *v1alpha1.Ka
 	mc.calculatePodStats(pods, metrics)
 
 	// Calculate resource usage
-	mc.calculateResourceUsage(pods, metrics)
+	mc.calculateResourceUsage(ctx, pods, metrics)
 
 	// Calculate performance and efficiency
 	mc.calculatePerformanceMetrics(metrics)
@@ -99,6 +139,7 @@ func (mc *MetricsCollector) CollectMetrics(ctx context.Context, job *v1alpha1.Ka
 	// Store metrics
 	metricsKey := fmt.Sprintf("%s/%s", job.Namespace, job.Name)
 	mc.metrics[metricsKey] = metrics
+	mc.recordHistory(metricsKey, metrics)
 
 	// Update successful metrics
 	mc.updateSuccessfulMetrics(time.Since(startTime))
@@ -132,34 +173,88 @@ func (mc *MetricsCollector) calculatePodStats(pods []corev1.Pod, metrics *JobMet
 	}
 }
 
-// calculateResourceUsage calculates resource usage from pods
-func (mc *MetricsCollector) calculateResourceUsage(pods []corev1.Pod, metrics *JobMetrics) {
-	totalCPU := resource.Quantity{}
-	totalMemory := resource.Quantity{}
+// calculateResourceUsage calculates the resource usage of a job's running pods
+// from live metrics, falling back to their requests if metrics are unavailable
+func (mc *MetricsCollector) calculateResourceUsage(ctx context.Context, pods []corev1.Pod, metrics *JobMetrics) {
+	var running []corev1.Pod
 	totalGPU := int64(0)
 
 	for _, pod := range pods {
-		if pod.Status.Phase == corev1.PodRunning {
-			// Calculate resource usage from pod spec (requests)
-					for _, container := range pod.Spec.Containers {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		running = append(running, pod)
+		for _, container := range pod.Spec.Containers {
 			if container.Resources.Requests != nil {
 				if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
-					totalCPU.Add(cpu)
+					metrics.CPURequests.Add(cpu)
 				}
 				if mem, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
-					totalMemory.Add(mem)
-				}
-				if gpu, ok := container.Resources.Requests["amd.com/gpu"]; ok {
-					totalGPU += gpu.Value()
+					metrics.MemoryRequests.Add(mem)
 				}
 			}
 		}
+		totalGPU += podGPURequests(&pod)
+	}
+	metrics.GPUUsage = totalGPU
+
+	if err := mc.measureUsage(ctx, running, metrics); err != nil {
+		if mc.podMetrics != nil {
+			fmt.Printf("Falling back to resource requests for job %s/%s: %v\n", metrics.Namespace, metrics.JobName, err)
+		}
+		metrics.CPUUsage = metrics.CPURequests.DeepCopy()
+		metrics.MemoryUsage = metrics.MemoryRequests.DeepCopy()
+		metrics.GPUUtilization = 0
+		metrics.UsageSource = UsageSourceRequests
+		return
+	}
+	metrics.UsageSource = UsageSourceMetrics
+}
+
+// measureUsage sums the live usage of running pods. GPU utilization is
+// averaged over the pods' GPUs. It fails unless every pod could be measured.
+func (mc *MetricsCollector) measureUsage(ctx context.Context, running []corev1.Pod, metrics *JobMetrics) error {
+	if mc.podMetrics == nil {
+		return fmt.Errorf("no pod metrics provider")
+	}
+
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	gpuBusy := 0.0
+	gpuCount := int64(0)
+	for _, pod := range running {
+		usage, err := mc.podMetrics.GetPodMetrics(ctx, pod.Namespace, pod.Name)
+		if err != nil {
+			return err
 		}
+		cpu.Add(usage.CPU)
+		memory.Add(usage.Memory)
+
+		gpus := podGPURequests(&pod)
+		gpuBusy += usage.GPUUtilization * float64(gpus)
+		gpuCount += gpus
 	}
 
-	metrics.CPUUsage = totalCPU
-	metrics.MemoryUsage = totalMemory
-	metrics.GPUUsage = totalGPU
+	metrics.CPUUsage = cpu
+	metrics.MemoryUsage = memory
+	metrics.GPUUtilization = 0
+	if gpuCount > 0 {
+		metrics.GPUUtilization = gpuBusy / float64(gpuCount)
+	}
+	return nil
+}
+
+// podGPURequests returns the number of GPUs of any vendor a pod requests
+func podGPURequests(pod *corev1.Pod) int64 {
+	total := int64(0)
+	for _, container := range pod.Spec.Containers {
+		for _, name := range optimization.GPUResourceNames {
+			if gpu, ok := container.Resources.Requests[name]; ok {
+				total += gpu.Value()
+			}
+		}
+	}
+	return total
 }
 
 // calculatePerformanceMetrics calculates performance and efficiency metrics
@@ -174,15 +269,39 @@ func (mc *MetricsCollector) calculatePerformanceMetrics(metrics *JobMetrics) {
 	// Performance: ratio of running pods to total pods
 	metrics.Performance = float64(metrics.RunningPods) / float64(metrics.PodCount)
 
-	// Efficiency: resource utilization efficiency
-	// This would typically be calculated from actual usage vs requests
-	// For now, use a placeholder calculation
-	if metrics.RunningPods > 0 {
-		// Assume 80% efficiency for running pods
-		metrics.Efficiency = 0.8
-	} else {
-		metrics.Efficiency = 0.0
+	// Efficiency: measured usage over requests, averaged across the resources
+	// the job requests. Usage taken from requests says nothing about efficiency.
+	metrics.Efficiency = 0.0
+	if metrics.UsageSource != UsageSourceMetrics {
+		return
+	}
+
+	total := 0.0
+	resources := 0
+	if !metrics.CPURequests.IsZero() {
+		total += usageRatio(metrics.CPUUsage, metrics.CPURequests)
+		resources++
+	}
+	if !metrics.MemoryRequests.IsZero() {
+		total += usageRatio(metrics.MemoryUsage, metrics.MemoryRequests)
+		resources++
+	}
+	if metrics.GPUUsage > 0 {
+		total += metrics.GPUUtilization
+		resources++
+	}
+	if resources > 0 {
+		metrics.Efficiency = total / float64(resources)
+	}
+}
+
+// usageRatio returns usage as a fraction of requests, capped at 1
+func usageRatio(usage, requests resource.Quantity) float64 {
+	ratio := usage.AsApproximateFloat64() / requests.AsApproximateFloat64()
+	if ratio > 1 {
+		return 1
 	}
+	return ratio
 }
 
 // GetMetrics returns metrics for a specific job
@@ -213,27 +332,6 @@ func (mc *MetricsCollector) GetAllMetrics() map[string]*JobMetrics {
 	return allMetrics
 }
 
-// GetMetricsHistory returns historical metrics for a job
-func (mc *MetricsCollector) GetMetricsHistory(jobName, namespace string, duration time.Duration) ([]*JobMetrics, error) {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
-
-	metricsKey := fmt.Sprintf("%s/%s", namespace, jobName)
-	metrics, exists := mc.metrics[metricsKey]
-	if !exists {
-		return nil, fmt.Errorf("no metrics found for job %s/%s", namespace, jobName)
-	}
-
-	// For now, return the current metrics as a single entry
-	// In a real implementation, this would return historical data
-	cutoffTime := time.Now().Add(-duration)
-	if metrics.Timestamp.After(cutoffTime) {
-		return []*JobMetrics{metrics}, nil
-	}
-
-	return []*JobMetrics{}, nil
-}
-
 // GetClusterMetrics returns aggregated cluster metrics
 func (mc *MetricsCollector) GetClusterMetrics(ctx context.Context) (*ClusterMetrics, error) {
 	// Get all KaiwoJobs
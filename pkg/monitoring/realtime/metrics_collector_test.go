@@ -0,0 +1,171 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
+	"github.com/silogen/kaiwo/pkg/optimization"
+)
+
+// fakePodMetricsProvider serves fixed pod metrics keyed by pod name
+type fakePodMetricsProvider struct {
+	metrics map[string]*optimization.PodMetrics
+}
+
+func (f *fakePodMetricsProvider) GetPodMetrics(ctx context.Context, namespace, name string) (*optimization.PodMetrics, error) {
+	metrics, exists := f.metrics[name]
+	if !exists {
+		return nil, fmt.Errorf("no metrics for pod %s/%s", namespace, name)
+	}
+	return metrics, nil
+}
+
+func newTestJobPod(name, cpu, memory string, gpus int64) *corev1.Pod {
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+	if gpus > 0 {
+		requests["amd.com/gpu"] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"kaiwo.silogen.ai/name": "train"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:      "main",
+				Resources: corev1.ResourceRequirements{Requests: requests},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func newTestMetricsCollector(t *testing.T, provider optimization.MetricsProvider, config *MetricsCollectorConfig, objects ...client.Object) *MetricsCollector {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	mc, err := NewMetricsCollector(k8sClient, provider, config)
+	if err != nil {
+		t.Fatalf("NewMetricsCollector failed: %v", err)
+	}
+	return mc
+}
+
+func newTestJob() *v1alpha1.KaiwoJob {
+	return &v1alpha1.KaiwoJob{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"}}
+}
+
+func TestCollectMetricsUsesLiveUsage(t *testing.T) {
+	provider := &fakePodMetricsProvider{metrics: map[string]*optimization.PodMetrics{
+		"train-0": {CPU: resource.MustParse("1"), Memory: resource.MustParse("2Gi"), GPUUtilization: 0.9},
+		"train-1": {CPU: resource.MustParse("500m"), Memory: resource.MustParse("2Gi"), GPUUtilization: 0.3},
+	}}
+	mc := newTestMetricsCollector(t, provider, nil,
+		newTestJobPod("train-0", "2", "4Gi", 2),
+		newTestJobPod("train-1", "2", "4Gi", 1),
+	)
+
+	metrics, err := mc.CollectMetrics(context.Background(), newTestJob())
+	if err != nil {
+		t.Fatalf("CollectMetrics failed: %v", err)
+	}
+
+	if metrics.UsageSource != UsageSourceMetrics {
+		t.Errorf("expected usage from metrics, got %s", metrics.UsageSource)
+	}
+	if metrics.CPUUsage.Cmp(resource.MustParse("1500m")) != 0 {
+		t.Errorf("expected 1500m CPU usage, got %s", metrics.CPUUsage.String())
+	}
+	if metrics.MemoryUsage.Cmp(resource.MustParse("4Gi")) != 0 {
+		t.Errorf("expected 4Gi memory usage, got %s", metrics.MemoryUsage.String())
+	}
+	if metrics.GPUUsage != 3 {
+		t.Errorf("expected 3 GPUs in use, got %d", metrics.GPUUsage)
+	}
+	// Weighted by GPU count: (0.9*2 + 0.3*1) / 3
+	if math.Abs(metrics.GPUUtilization-0.7) > 1e-9 {
+		t.Errorf("expected GPU utilization 0.7, got %.3f", metrics.GPUUtilization)
+	}
+	// CPU 1.5/4, memory 4Gi/8Gi and GPU 0.7, averaged
+	want := (0.375 + 0.5 + 0.7) / 3
+	if math.Abs(metrics.Efficiency-want) > 1e-9 {
+		t.Errorf("expected efficiency %.3f, got %.3f", want, metrics.Efficiency)
+	}
+}
+
+func TestCollectMetricsCountsNvidiaGPUs(t *testing.T) {
+	provider := &fakePodMetricsProvider{metrics: map[string]*optimization.PodMetrics{
+		"train-0": {CPU: resource.MustParse("1"), Memory: resource.MustParse("2Gi"), GPUUtilization: 0.8},
+	}}
+	pod := newTestJobPod("train-0", "2", "4Gi", 0)
+	pod.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = *resource.NewQuantity(2, resource.DecimalSI)
+	mc := newTestMetricsCollector(t, provider, nil, pod)
+
+	metrics, err := mc.CollectMetrics(context.Background(), newTestJob())
+	if err != nil {
+		t.Fatalf("CollectMetrics failed: %v", err)
+	}
+
+	if metrics.GPUUsage != 2 {
+		t.Errorf("expected 2 NVIDIA GPUs in use, got %d", metrics.GPUUsage)
+	}
+	if math.Abs(metrics.GPUUtilization-0.8) > 1e-9 {
+		t.Errorf("expected GPU utilization 0.8, got %.3f", metrics.GPUUtilization)
+	}
+}
+
+func TestCollectMetricsFallsBackToRequests(t *testing.T) {
+	// train-1 has no metrics yet, so the job cannot be measured
+	provider := &fakePodMetricsProvider{metrics: map[string]*optimization.PodMetrics{
+		"train-0": {CPU: resource.MustParse("1"), Memory: resource.MustParse("2Gi")},
+	}}
+	pods := []client.Object{
+		newTestJobPod("train-0", "2", "4Gi", 1),
+		newTestJobPod("train-1", "2", "4Gi", 1),
+	}
+
+	for name, provider := range map[string]optimization.MetricsProvider{"missing pod metrics": provider, "no provider": nil} {
+		t.Run(name, func(t *testing.T) {
+			mc := newTestMetricsCollector(t, provider, nil, pods...)
+
+			metrics, err := mc.CollectMetrics(context.Background(), newTestJob())
+			if err != nil {
+				t.Fatalf("CollectMetrics failed: %v", err)
+			}
+
+			if metrics.UsageSource != UsageSourceRequests {
+				t.Errorf("expected usage from requests, got %s", metrics.UsageSource)
+			}
+			if metrics.CPUUsage.Cmp(resource.MustParse("4")) != 0 {
+				t.Errorf("expected the 4 CPU requested, got %s", metrics.CPUUsage.String())
+			}
+			if metrics.MemoryUsage.Cmp(resource.MustParse("8Gi")) != 0 {
+				t.Errorf("expected the 8Gi memory requested, got %s", metrics.MemoryUsage.String())
+			}
+			if metrics.GPUUsage != 2 || metrics.GPUUtilization != 0 {
+				t.Errorf("expected 2 GPUs with unmeasured utilization, got %d at %.2f", metrics.GPUUsage, metrics.GPUUtilization)
+			}
+			if metrics.Efficiency != 0 {
+				t.Errorf("expected no efficiency without measured usage, got %.3f", metrics.Efficiency)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package realtime
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// MetricsCollectorConfig configures how much job metrics history is kept
+type MetricsCollectorConfig struct {
+	// HistorySize is the number of snapshots kept per job
+	HistorySize int `json:"historySize"`
+
+	// HistoryRetention is how long snapshots are kept. Zero keeps them until
+	// HistorySize newer snapshots replace them.
+	HistoryRetention time.Duration `json:"historyRetention"`
+}
+
+const (
+	// DefaultHistorySize is the default number of snapshots kept per job
+	DefaultHistorySize = 120
+	// DefaultHistoryRetention is the default age after which snapshots are dropped
+	DefaultHistoryRetention = time.Hour
+)
+
+// DefaultMetricsCollectorConfig returns the default metrics collector configuration
+func DefaultMetricsCollectorConfig() *MetricsCollectorConfig {
+	return &MetricsCollectorConfig{
+		HistorySize:      DefaultHistorySize,
+		HistoryRetention: DefaultHistoryRetention,
+	}
+}
+
+// ValidateMetricsCollectorConfig validates a metrics collector configuration
+func ValidateMetricsCollectorConfig(config *MetricsCollectorConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	if config.HistorySize < 1 {
+		return fmt.Errorf("history size must be at least 1, got %d", config.HistorySize)
+	}
+	if config.HistoryRetention < 0 {
+		return fmt.Errorf("history retention cannot be negative, got %v", config.HistoryRetention)
+	}
+	return nil
+}
+
+// metricsRing is a fixed-size ring buffer of a job's snapshots
+type metricsRing struct {
+	entries []*JobMetrics
+	next    int
+	full    bool
+}
+
+func newMetricsRing(size int) *metricsRing {
+	return &metricsRing{entries: make([]*JobMetrics, size)}
+}
+
+// push adds a snapshot, overwriting the oldest once the ring is full
+func (r *metricsRing) push(metrics *JobMetrics) {
+	r.entries[r.next] = metrics
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// since returns the snapshots taken at or after cutoff, oldest first
+func (r *metricsRing) since(cutoff time.Time) []*JobMetrics {
+	var ordered []*JobMetrics
+	if r.full {
+		ordered = append(ordered, r.entries[r.next:]...)
+	}
+	ordered = append(ordered, r.entries[:r.next]...)
+
+	result := make([]*JobMetrics, 0, len(ordered))
+	for _, metrics := range ordered {
+		if !metrics.Timestamp.Before(cutoff) {
+			result = append(result, metrics)
+		}
+	}
+	return result
+}
+
+// recordHistory appends a snapshot to a job's history. Callers must hold mc.mu.
+func (mc *MetricsCollector) recordHistory(metricsKey string, metrics *JobMetrics) {
+	ring, exists := mc.history[metricsKey]
+	if !exists {
+		ring = newMetricsRing(mc.config.HistorySize)
+		mc.history[metricsKey] = ring
+	}
+	ring.push(metrics)
+}
+
+// historySince returns a job's retained snapshots taken within window of now,
+// oldest first. A zero window returns every retained snapshot. Callers must
+// hold mc.mu.
+func (mc *MetricsCollector) historySince(namespace, name string, window time.Duration) ([]*JobMetrics, error) {
+	ring, exists := mc.history[fmt.Sprintf("%s/%s", namespace, name)]
+	if !exists {
+		return nil, fmt.Errorf("no metrics found for job %s/%s", namespace, name)
+	}
+
+	now := mc.now()
+	cutoff := time.Time{}
+	if mc.config.HistoryRetention > 0 {
+		cutoff = now.Add(-mc.config.HistoryRetention)
+	}
+	if window > 0 && now.Add(-window).After(cutoff) {
+		cutoff = now.Add(-window)
+	}
+	return ring.since(cutoff), nil
+}
+
+// GetMetricsHistory returns a job's retained snapshots, oldest first
+func (mc *MetricsCollector) GetMetricsHistory(namespace, name string) ([]*JobMetrics, error) {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	return mc.historySince(namespace, name, 0)
+}
+
+// MetricsAverage is the average of a job's snapshots over a window
+type MetricsAverage struct {
+	// Samples is the number of snapshots averaged
+	Samples        int
+	CPUUsage       resource.Quantity
+	MemoryUsage    resource.Quantity
+	GPUUtilization float64
+	Performance    float64
+	Efficiency     float64
+}
+
+// GetRollingAverage averages a job's snapshots taken within window of now
+func (mc *MetricsCollector) GetRollingAverage(namespace, name string, window time.Duration) (*MetricsAverage, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive, got %v", window)
+	}
+
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	history, err := mc.historySince(namespace, name, window)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no metrics for job %s/%s in the last %v", namespace, name, window)
+	}
+
+	cpu, memory := 0.0, 0.0
+	average := &MetricsAverage{Samples: len(history)}
+	for _, metrics := range history {
+		cpu += metrics.CPUUsage.AsApproximateFloat64()
+		memory += metrics.MemoryUsage.AsApproximateFloat64()
+		average.GPUUtilization += metrics.GPUUtilization
+		average.Performance += metrics.Performance
+		average.Efficiency += metrics.Efficiency
+	}
+
+	samples := float64(len(history))
+	average.CPUUsage = *resource.NewMilliQuantity(int64(cpu*1000/samples), resource.DecimalSI)
+	average.MemoryUsage = *resource.NewQuantity(int64(memory/samples), resource.BinarySI)
+	average.GPUUtilization /= samples
+	average.Performance /= samples
+	average.Efficiency /= samples
+
+	return average, nil
+}
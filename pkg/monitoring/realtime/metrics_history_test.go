@@ -0,0 +1,100 @@
+package realtime
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/silogen/kaiwo/pkg/optimization"
+)
+
+// collectSnapshots collects one snapshot per CPU usage in cores, advancing the
+// collector's clock by a minute after each
+func collectSnapshots(t *testing.T, mc *MetricsCollector, provider *fakePodMetricsProvider, now *time.Time, cpuCores ...int64) {
+	t.Helper()
+	for _, cores := range cpuCores {
+		provider.metrics["train-0"] = &optimization.PodMetrics{
+			CPU:    *resource.NewQuantity(cores, resource.DecimalSI),
+			Memory: resource.MustParse("1Gi"),
+		}
+		if _, err := mc.CollectMetrics(context.Background(), newTestJob()); err != nil {
+			t.Fatalf("CollectMetrics failed: %v", err)
+		}
+		*now = now.Add(time.Minute)
+	}
+}
+
+func newTestHistoryCollector(t *testing.T, config *MetricsCollectorConfig) (*MetricsCollector, *fakePodMetricsProvider, *time.Time) {
+	t.Helper()
+	provider := &fakePodMetricsProvider{metrics: map[string]*optimization.PodMetrics{}}
+	mc := newTestMetricsCollector(t, provider, config, newTestJobPod("train-0", "8", "4Gi", 0))
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	mc.now = func() time.Time { return now }
+	return mc, provider, &now
+}
+
+func TestMetricsHistoryIsCapped(t *testing.T) {
+	mc, provider, now := newTestHistoryCollector(t, &MetricsCollectorConfig{HistorySize: 3})
+
+	collectSnapshots(t, mc, provider, now, 1, 2, 3, 4, 5)
+
+	history, err := mc.GetMetricsHistory("default", "train")
+	if err != nil {
+		t.Fatalf("GetMetricsHistory failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected the 3 newest snapshots, got %d", len(history))
+	}
+	for i, cores := range []int64{3, 4, 5} {
+		if got := history[i].CPUUsage.Value(); got != cores {
+			t.Errorf("snapshot %d: expected %d cores, got %d", i, cores, got)
+		}
+	}
+
+	if _, err := mc.GetMetricsHistory("default", "missing"); err == nil {
+		t.Error("expected an error for a job without metrics")
+	}
+}
+
+func TestMetricsRollingAverage(t *testing.T) {
+	mc, provider, now := newTestHistoryCollector(t, &MetricsCollectorConfig{HistorySize: 10, HistoryRetention: time.Hour})
+
+	// Snapshots at 12:00, 12:01, 12:02 and 12:03
+	collectSnapshots(t, mc, provider, now, 1, 2, 3, 4)
+	*now = now.Add(-time.Minute)
+
+	// The window from 12:00:30 holds the last three snapshots
+	average, err := mc.GetRollingAverage("default", "train", 150*time.Second)
+	if err != nil {
+		t.Fatalf("GetRollingAverage failed: %v", err)
+	}
+	if average.Samples != 3 {
+		t.Errorf("expected 3 samples in the window, got %d", average.Samples)
+	}
+	if average.CPUUsage.Cmp(resource.MustParse("3")) != 0 {
+		t.Errorf("expected an average of 3 cores, got %s", average.CPUUsage.String())
+	}
+	if average.MemoryUsage.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Errorf("expected an average of 1Gi, got %s", average.MemoryUsage.String())
+	}
+	// One of one pods running, using 3 of 8 requested cores and 1Gi of 4Gi
+	if average.Performance != 1 || math.Abs(average.Efficiency-(3.0/8+0.25)/2) > 1e-9 {
+		t.Errorf("unexpected averages: %+v", average)
+	}
+
+	// Snapshots older than the retention are no longer reported
+	*now = now.Add(2 * time.Hour)
+	history, err := mc.GetMetricsHistory("default", "train")
+	if err != nil {
+		t.Fatalf("GetMetricsHistory failed: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("expected expired snapshots to be dropped, got %d", len(history))
+	}
+	if _, err := mc.GetRollingAverage("default", "train", time.Hour); err == nil {
+		t.Error("expected an error for a window without snapshots")
+	}
+}
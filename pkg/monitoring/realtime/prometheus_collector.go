@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// jobLabels are the labels identifying a job's series
+var jobLabels = []string{"namespace", "job"}
+
+// PrometheusCollector exposes the JobMetrics held by a MetricsCollector as
+// Prometheus gauges labeled by namespace and job
+type PrometheusCollector struct {
+	metrics *MetricsCollector
+
+	cpuUsage       *prometheus.Desc
+	memoryUsage    *prometheus.Desc
+	gpuUsage       *prometheus.Desc
+	gpuUtilization *prometheus.Desc
+	pods           *prometheus.Desc
+	performance    *prometheus.Desc
+	efficiency     *prometheus.Desc
+}
+
+// NewPrometheusCollector creates a Prometheus collector for a metrics collector
+func NewPrometheusCollector(metrics *MetricsCollector) *PrometheusCollector {
+	return &PrometheusCollector{
+		metrics: metrics,
+		cpuUsage: prometheus.NewDesc("kaiwo_job_cpu_usage_cores",
+			"CPU used by the job's running pods, in cores", jobLabels, nil),
+		memoryUsage: prometheus.NewDesc("kaiwo_job_memory_usage_bytes",
+			"Memory used by the job's running pods, in bytes", jobLabels, nil),
+		gpuUsage: prometheus.NewDesc("kaiwo_job_gpus",
+			"Number of GPUs allocated to the job's running pods", jobLabels, nil),
+		gpuUtilization: prometheus.NewDesc("kaiwo_job_gpu_utilization_ratio",
+			"Average utilization (0-1) of the job's GPUs", jobLabels, nil),
+		pods: prometheus.NewDesc("kaiwo_job_pods",
+			"Number of the job's pods in each phase", append(jobLabels, "phase"), nil),
+		performance: prometheus.NewDesc("kaiwo_job_performance_ratio",
+			"Fraction (0-1) of the job's pods that are running", jobLabels, nil),
+		efficiency: prometheus.NewDesc("kaiwo_job_efficiency_ratio",
+			"The job's measured usage relative to its requests (0-1)", jobLabels, nil),
+	}
+}
+
+// Describe sends the descriptors of every metric the collector exports
+func (pc *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pc.cpuUsage
+	ch <- pc.memoryUsage
+	ch <- pc.gpuUsage
+	ch <- pc.gpuUtilization
+	ch <- pc.pods
+	ch <- pc.performance
+	ch <- pc.efficiency
+}
+
+// Collect sends the current value of every job's metrics
+func (pc *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, metrics := range pc.metrics.GetAllMetrics() {
+		labels := []string{metrics.Namespace, metrics.JobName}
+		gauge := func(desc *prometheus.Desc, value float64, extraLabels ...string) {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append(labels, extraLabels...)...)
+		}
+
+		gauge(pc.cpuUsage, metrics.CPUUsage.AsApproximateFloat64())
+		gauge(pc.memoryUsage, metrics.MemoryUsage.AsApproximateFloat64())
+		gauge(pc.gpuUsage, float64(metrics.GPUUsage))
+		gauge(pc.gpuUtilization, metrics.GPUUtilization)
+		gauge(pc.pods, float64(metrics.RunningPods), "running")
+		gauge(pc.pods, float64(metrics.PendingPods), "pending")
+		gauge(pc.pods, float64(metrics.FailedPods), "failed")
+		gauge(pc.performance, metrics.Performance)
+		gauge(pc.efficiency, metrics.Efficiency)
+	}
+}
+
+// RegisterMetrics registers a Prometheus collector for the job metrics with registry
+func (mc *MetricsCollector) RegisterMetrics(registry prometheus.Registerer) error {
+	return registry.Register(NewPrometheusCollector(mc))
+}
@@ -0,0 +1,86 @@
+package realtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPrometheusCollectorExportsJobMetrics(t *testing.T) {
+	mc := newTestMetricsCollector(t, nil, nil)
+	mc.metrics["default/train"] = &JobMetrics{
+		JobName:        "train",
+		Namespace:      "default",
+		CPUUsage:       resource.MustParse("1500m"),
+		MemoryUsage:    resource.MustParse("4Gi"),
+		GPUUsage:       3,
+		GPUUtilization: 0.7,
+		PodCount:       4,
+		RunningPods:    2,
+		PendingPods:    1,
+		FailedPods:     1,
+		Performance:    0.5,
+		Efficiency:     0.6,
+	}
+	mc.metrics["research/eval"] = &JobMetrics{
+		JobName:     "eval",
+		Namespace:   "research",
+		CPUUsage:    resource.MustParse("2"),
+		MemoryUsage: resource.MustParse("1Gi"),
+		PodCount:    1,
+		RunningPods: 1,
+		Performance: 1,
+		Efficiency:  0.25,
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := mc.RegisterMetrics(registry); err != nil {
+		t.Fatalf("RegisterMetrics failed: %v", err)
+	}
+
+	expected := `
+# HELP kaiwo_job_cpu_usage_cores CPU used by the job's running pods, in cores
+# TYPE kaiwo_job_cpu_usage_cores gauge
+kaiwo_job_cpu_usage_cores{job="eval",namespace="research"} 2
+kaiwo_job_cpu_usage_cores{job="train",namespace="default"} 1.5
+# HELP kaiwo_job_efficiency_ratio The job's measured usage relative to its requests (0-1)
+# TYPE kaiwo_job_efficiency_ratio gauge
+kaiwo_job_efficiency_ratio{job="eval",namespace="research"} 0.25
+kaiwo_job_efficiency_ratio{job="train",namespace="default"} 0.6
+# HELP kaiwo_job_gpu_utilization_ratio Average utilization (0-1) of the job's GPUs
+# TYPE kaiwo_job_gpu_utilization_ratio gauge
+kaiwo_job_gpu_utilization_ratio{job="eval",namespace="research"} 0
+kaiwo_job_gpu_utilization_ratio{job="train",namespace="default"} 0.7
+# HELP kaiwo_job_gpus Number of GPUs allocated to the job's running pods
+# TYPE kaiwo_job_gpus gauge
+kaiwo_job_gpus{job="eval",namespace="research"} 0
+kaiwo_job_gpus{job="train",namespace="default"} 3
+# HELP kaiwo_job_memory_usage_bytes Memory used by the job's running pods, in bytes
+# TYPE kaiwo_job_memory_usage_bytes gauge
+kaiwo_job_memory_usage_bytes{job="eval",namespace="research"} 1.073741824e+09
+kaiwo_job_memory_usage_bytes{job="train",namespace="default"} 4.294967296e+09
+# HELP kaiwo_job_performance_ratio Fraction (0-1) of the job's pods that are running
+# TYPE kaiwo_job_performance_ratio gauge
+kaiwo_job_performance_ratio{job="eval",namespace="research"} 1
+kaiwo_job_performance_ratio{job="train",namespace="default"} 0.5
+# HELP kaiwo_job_pods Number of the job's pods in each phase
+# TYPE kaiwo_job_pods gauge
+kaiwo_job_pods{job="eval",namespace="research",phase="failed"} 0
+kaiwo_job_pods{job="eval",namespace="research",phase="pending"} 0
+kaiwo_job_pods{job="eval",namespace="research",phase="running"} 1
+kaiwo_job_pods{job="train",namespace="default",phase="failed"} 1
+kaiwo_job_pods{job="train",namespace="default",phase="pending"} 1
+kaiwo_job_pods{job="train",namespace="default",phase="running"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+
+	// A second registration of the same metrics is rejected
+	if err := mc.RegisterMetrics(registry); err == nil {
+		t.Error("expected registering the metrics twice to fail")
+	}
+}
@@ -3,6 +3,7 @@ package optimization
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
+	"github.com/silogen/kaiwo/pkg/gpu/manager"
 )
 
 // DynamicAllocator implements dynamic resource allocation for KaiwoJobs
@@ -19,8 +21,83 @@ type DynamicAllocator struct {
 	mu          sync.RWMutex
 	allocations map[string]*DynamicAllocation
 	metrics     *DynamicAllocatorMetrics
+	podMetrics  MetricsProvider
+	config      DynamicAllocatorConfig
+	now         func() time.Time
 }
 
+// DynamicAllocatorConfig configures how eagerly a DynamicAllocator adjusts jobs
+type DynamicAllocatorConfig struct {
+	// MinAdjustmentInterval is the shortest time between two adjustments of the same job
+	MinAdjustmentInterval time.Duration `json:"minAdjustmentInterval"`
+
+	// HysteresisDelta is the fraction of its current value a resource must change
+	// by before an adjustment is made
+	HysteresisDelta float64 `json:"hysteresisDelta"`
+
+	// Partition is the MI300X partitioning that jobs sharing GPUs run on. Their
+	// GPU fraction is adjusted in the steps it allows. Defaults to CPX.
+	Partition *manager.MI300XPartitionConfig `json:"partition,omitempty"`
+
+	// MaxAdjustmentHistory is how many of the most recent adjustments are kept per job
+	MaxAdjustmentHistory int `json:"maxAdjustmentHistory,omitempty"`
+}
+
+const (
+	// DefaultMinAdjustmentInterval is the default cooldown between adjustments of a job
+	DefaultMinAdjustmentInterval = 5 * time.Minute
+	// DefaultHysteresisDelta is the default minimum relative change worth adjusting for
+	DefaultHysteresisDelta = 0.1
+	// DefaultMaxAdjustmentHistory is the default number of adjustments kept per job
+	DefaultMaxAdjustmentHistory = 100
+)
+
+// DefaultDynamicAllocatorConfig returns the default dynamic allocator configuration
+func DefaultDynamicAllocatorConfig() *DynamicAllocatorConfig {
+	return &DynamicAllocatorConfig{
+		MinAdjustmentInterval: DefaultMinAdjustmentInterval,
+		HysteresisDelta:       DefaultHysteresisDelta,
+		Partition:             defaultSharedGPUPartition(),
+		MaxAdjustmentHistory:  DefaultMaxAdjustmentHistory,
+	}
+}
+
+// defaultSharedGPUPartition is the partitioning assumed for jobs sharing GPUs
+func defaultSharedGPUPartition() *manager.MI300XPartitionConfig {
+	return &manager.MI300XPartitionConfig{
+		ComputeMode: manager.MI300XPartitionModeCPX,
+		MemoryMode:  manager.MI300XMemoryModeNPS1,
+		XCDCount:    8,
+	}
+}
+
+// ValidateDynamicAllocatorConfig validates a dynamic allocator configuration
+func ValidateDynamicAllocatorConfig(config *DynamicAllocatorConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	if config.MinAdjustmentInterval < 0 {
+		return fmt.Errorf("min adjustment interval cannot be negative, got %v", config.MinAdjustmentInterval)
+	}
+	if config.HysteresisDelta < 0 || config.HysteresisDelta >= 1 {
+		return fmt.Errorf("hysteresis delta must be in [0, 1), got %.3f", config.HysteresisDelta)
+	}
+	if config.MaxAdjustmentHistory < 0 {
+		return fmt.Errorf("max adjustment history cannot be negative, got %d", config.MaxAdjustmentHistory)
+	}
+	if config.Partition != nil {
+		switch config.Partition.ComputeMode {
+		case manager.MI300XPartitionModeSPX, manager.MI300XPartitionModeCPX, manager.MI300XPartitionModeTPX:
+		default:
+			return fmt.Errorf("unsupported partition compute mode: %s", config.Partition.ComputeMode)
+		}
+	}
+	return nil
+}
+
+// GPUResourceNames are the extended resources GPU device plugins advertise
+var GPUResourceNames = []corev1.ResourceName{"amd.com/gpu", "nvidia.com/gpu"}
+
 // DynamicAllocation represents a dynamic resource allocation for a job
 type DynamicAllocation struct {
 	JobName     string
@@ -33,9 +110,22 @@ type DynamicAllocation struct {
 	OptimalMem  resource.Quantity
 	Performance float64
 	LastUpdated time.Time
-	Adjustments []ResourceAdjustment
+	// LastAdjusted is when resources were last changed; zero if never
+	LastAdjusted time.Time
+	Adjustments  []ResourceAdjustment
+	// CurrentFraction and OptimalFraction are the GPU fraction of jobs that
+	// share GPUs; zero for jobs that use whole GPUs
+	CurrentFraction float64
+	OptimalFraction float64
 }
 
+const (
+	// lowPerformanceThreshold is the score below which a job is given more resources
+	lowPerformanceThreshold = 0.5
+	// highPerformanceThreshold is the score above which a job's resources are reduced
+	highPerformanceThreshold = 0.9
+)
+
 // ResourceAdjustment represents a resource adjustment recommendation
 type ResourceAdjustment struct {
 	Type      string
@@ -51,20 +141,41 @@ type DynamicAllocatorMetrics struct {
 	SuccessfulAdjustments int64
 	FailedAdjustments     int64
 	AverageAdjustmentTime time.Duration
-	mu                    sync.RWMutex
+	// SkippedAdjustments counts adjustments suppressed by the cooldown or hysteresis band
+	SkippedAdjustments int64
+	mu                 sync.RWMutex
 }
 
-// NewDynamicAllocator creates a new dynamic allocator instance
-func NewDynamicAllocator(client client.Client) *DynamicAllocator {
+// NewDynamicAllocator creates a new dynamic allocator instance. Pod utilization
+// is read from podMetrics; with a nil provider no pod can be scored. A nil
+// config uses DefaultDynamicAllocatorConfig.
+func NewDynamicAllocator(client client.Client, podMetrics MetricsProvider, config *DynamicAllocatorConfig) (*DynamicAllocator, error) {
+	if config == nil {
+		config = DefaultDynamicAllocatorConfig()
+	}
+	if err := ValidateDynamicAllocatorConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid dynamic allocator config: %w", err)
+	}
+	resolved := *config
+	if resolved.Partition == nil {
+		resolved.Partition = defaultSharedGPUPartition()
+	}
+	if resolved.MaxAdjustmentHistory == 0 {
+		resolved.MaxAdjustmentHistory = DefaultMaxAdjustmentHistory
+	}
+
 	return &DynamicAllocator{
 		client:      client,
+		podMetrics:  podMetrics,
+		config:      resolved,
+		now:         time.Now,
 		allocations: make(map[string]*DynamicAllocation),
 		metrics: &DynamicAllocatorMetrics{
 			TotalAdjustments:      0,
 			SuccessfulAdjustments: 0,
 			FailedAdjustments:     0,
 		},
-	}
+	}, nil
 }
 
 // AnalyzeJob analyzes a job's resource usage and performance
@@ -89,9 +200,12 @@ func (da *DynamicAllocator) AnalyzeJob(ctx context.Context, job *v1alpha1.KaiwoJ
 			JobName:     job.Name,
 			Namespace:   job.Namespace,
 			CurrentGPU:  int64(job.Spec.Gpus),
-			LastUpdated: time.Now(),
+			LastUpdated: da.now(),
 			Adjustments: make([]ResourceAdjustment, 0),
 		}
+		if fraction, sharing := jobGPUFraction(job); sharing {
+			currentAllocation.CurrentFraction = fraction
+		}
 
 		// Set initial CPU and memory
 		if job.Spec.Resources != nil && job.Spec.Resources.Requests != nil {
@@ -112,10 +226,21 @@ func (da *DynamicAllocator) AnalyzeJob(ctx context.Context, job *v1alpha1.KaiwoJ
 
 	// Determine optimal resource allocation
 	optimalGPU, optimalCPU, optimalMem := da.calculateOptimalResources(job, performance)
+	optimalFraction := currentAllocation.CurrentFraction
+	if fraction, sharing := jobGPUFraction(job); sharing {
+		// Shared GPUs are resized in partition steps rather than whole GPUs
+		optimalGPU = int64(job.Spec.Gpus)
+		optimalFraction = da.calculateOptimalFraction(fraction, performance)
+	}
 
 	// Check if adjustment is needed
-	if da.shouldAdjustResources(currentAllocation, optimalGPU, optimalCPU, optimalMem) {
-		if err := da.adjustResources(ctx, job, currentAllocation, optimalGPU, optimalCPU, optimalMem); err != nil {
+	adjust, suppressed := da.shouldAdjustResources(currentAllocation, optimalGPU, optimalFraction, optimalCPU, optimalMem)
+	if suppressed {
+		da.recordSkippedAdjustment()
+	} else if adjust {
+		if !da.canAdjust(currentAllocation) {
+			da.recordSkippedAdjustment()
+		} else if err := da.adjustResources(ctx, job, currentAllocation, optimalGPU, optimalFraction, optimalCPU, optimalMem); err != nil {
 			da.updateFailedMetrics(time.Since(startTime))
 			return fmt.Errorf("failed to adjust resources: %w", err)
 		}
@@ -131,7 +256,7 @@ func (da *DynamicAllocator) AnalyzeJob(ctx context.Context, job *v1alpha1.KaiwoJ
 func (da *DynamicAllocator) calculatePerformance(ctx context.Context, job *v1alpha1.KaiwoJob) float64 {
 	// Get job pods to analyze performance
 	var pods corev1.PodList
-	if err := da.client.List(ctx, &pods, client.MatchingLabels{"kaiwo.silogen.ai/name": job.Name}); err != nil {
+	if err := da.client.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{"kaiwo.silogen.ai/name": job.Name}); err != nil {
 		return 0.0
 	}
 
@@ -144,18 +269,38 @@ func (da *DynamicAllocator) calculatePerformance(ctx context.Context, job *v1alp
 	podCount := 0
 
 	for _, pod := range pods.Items {
-		if pod.Status.Phase == corev1.PodRunning {
-			// Calculate resource utilization
-			cpuUtilization := da.calculateCPUUtilization(&pod)
-			memUtilization := da.calculateMemoryUtilization(&pod)
-			gpuUtilization := da.calculateGPUUtilization(&pod)
-
-			// Performance score based on resource utilization
-			// Higher utilization with stable performance indicates good resource allocation
-			performance := (cpuUtilization + memUtilization + gpuUtilization) / 3.0
-			totalPerformance += performance
-			podCount++
+		if pod.Status.Phase != corev1.PodRunning || da.podMetrics == nil {
+			continue
 		}
+
+		usage, err := da.podMetrics.GetPodMetrics(ctx, pod.Namespace, pod.Name)
+		if err != nil {
+			// Pods without metrics yet are left out of the score
+			continue
+		}
+
+		// Performance score based on the utilization of each requested resource
+		// Higher utilization with stable performance indicates good resource allocation
+		var utilizations []float64
+		if cpu, ok := da.calculateCPUUtilization(&pod, usage); ok {
+			utilizations = append(utilizations, cpu)
+		}
+		if mem, ok := da.calculateMemoryUtilization(&pod, usage); ok {
+			utilizations = append(utilizations, mem)
+		}
+		if gpu, ok := da.calculateGPUUtilization(&pod, usage); ok {
+			utilizations = append(utilizations, gpu)
+		}
+		if len(utilizations) == 0 {
+			continue
+		}
+
+		performance := 0.0
+		for _, utilization := range utilizations {
+			performance += utilization
+		}
+		totalPerformance += performance / float64(len(utilizations))
+		podCount++
 	}
 
 	if podCount == 0 {
@@ -165,25 +310,61 @@ func (da *DynamicAllocator) calculatePerformance(ctx context.Context, job *v1alp
 	return totalPerformance / float64(podCount)
 }
 
-// calculateCPUUtilization calculates CPU utilization for a pod
-func (da *DynamicAllocator) calculateCPUUtilization(pod *corev1.Pod) float64 {
-	// This would typically get metrics from a metrics server
-	// For now, return a placeholder value
-	return 0.7 // 70% utilization
+// calculateCPUUtilization calculates CPU utilization for a pod relative to its
+// CPU request. It reports false if the pod requests no CPU.
+func (da *DynamicAllocator) calculateCPUUtilization(pod *corev1.Pod, usage *PodMetrics) (float64, bool) {
+	requested := podResourceRequest(pod, corev1.ResourceCPU)
+	if requested.IsZero() {
+		return 0, false
+	}
+	return clampUtilization(float64(usage.CPU.MilliValue()) / float64(requested.MilliValue())), true
+}
+
+// calculateMemoryUtilization calculates memory utilization for a pod relative
+// to its memory request. It reports false if the pod requests no memory.
+func (da *DynamicAllocator) calculateMemoryUtilization(pod *corev1.Pod, usage *PodMetrics) (float64, bool) {
+	requested := podResourceRequest(pod, corev1.ResourceMemory)
+	if requested.IsZero() {
+		return 0, false
+	}
+	return clampUtilization(float64(usage.Memory.Value()) / float64(requested.Value())), true
 }
 
-// calculateMemoryUtilization calculates memory utilization for a pod
-func (da *DynamicAllocator) calculateMemoryUtilization(pod *corev1.Pod) float64 {
-	// This would typically get metrics from a metrics server
-	// For now, return a placeholder value
-	return 0.6 // 60% utilization
+// calculateGPUUtilization returns the utilization of a pod's GPUs. It reports
+// false if the pod requests no GPUs.
+func (da *DynamicAllocator) calculateGPUUtilization(pod *corev1.Pod, usage *PodMetrics) (float64, bool) {
+	for _, name := range GPUResourceNames {
+		if gpus := podResourceRequest(pod, name); !gpus.IsZero() {
+			return clampUtilization(usage.GPUUtilization), true
+		}
+	}
+	return 0, false
 }
 
-// calculateGPUUtilization calculates GPU utilization for a pod
-func (da *DynamicAllocator) calculateGPUUtilization(pod *corev1.Pod) float64 {
-	// This would typically get metrics from a metrics server
-	// For now, return a placeholder value
-	return 0.8 // 80% utilization
+// podResourceRequest sums a resource over a pod's containers, falling back to
+// the limit for containers that set no request
+func podResourceRequest(pod *corev1.Pod, name corev1.ResourceName) resource.Quantity {
+	var total resource.Quantity
+	for _, container := range pod.Spec.Containers {
+		if quantity, ok := container.Resources.Requests[name]; ok {
+			total.Add(quantity)
+		} else if quantity, ok := container.Resources.Limits[name]; ok {
+			total.Add(quantity)
+		}
+	}
+	return total
+}
+
+// clampUtilization limits a utilization ratio to [0, 1]; pods bursting past
+// their request count as fully utilized
+func clampUtilization(utilization float64) float64 {
+	if utilization < 0 {
+		return 0
+	}
+	if utilization > 1 {
+		return 1
+	}
+	return utilization
 }
 
 // calculateOptimalResources calculates optimal resource allocation based on performance
@@ -208,14 +389,14 @@ func (da *DynamicAllocator) calculateOptimalResources(job *v1alpha1.KaiwoJob, pe
 	var optimalCPU resource.Quantity
 	var optimalMem resource.Quantity
 
-	if performance < 0.5 {
+	if performance < lowPerformanceThreshold {
 		// Low performance - increase resources
 		optimalGPU = currentGPU + 1
 		optimalCPU = currentCPU.DeepCopy()
 		optimalCPU.Add(resource.MustParse("1"))
 		optimalMem = currentMem.DeepCopy()
 		optimalMem.Add(resource.MustParse("2Gi"))
-	} else if performance > 0.9 {
+	} else if performance > highPerformanceThreshold {
 		// High performance - might be able to reduce resources
 		if currentGPU > 1 {
 			optimalGPU = currentGPU - 1
@@ -234,22 +415,73 @@ func (da *DynamicAllocator) calculateOptimalResources(job *v1alpha1.KaiwoJob, pe
 	return optimalGPU, optimalCPU, optimalMem
 }
 
-// shouldAdjustResources determines if resource adjustment is needed
-func (da *DynamicAllocator) shouldAdjustResources(allocation *DynamicAllocation, optimalGPU int64, optimalCPU, optimalMem resource.Quantity) bool {
+// calculateOptimalFraction moves a shared job's GPU fraction one partition
+// step up or down based on performance
+func (da *DynamicAllocator) calculateOptimalFraction(current, performance float64) float64 {
+	steps := da.config.Partition.ValidFractions()
+	if performance < lowPerformanceThreshold {
+		return nextFractionStep(steps, current, true)
+	}
+	if performance > highPerformanceThreshold {
+		return nextFractionStep(steps, current, false)
+	}
+	return current
+}
+
+// shouldAdjustResources determines if resource adjustment is needed. It also
+// reports whether the optimal resources differ from the current ones only
+// within the hysteresis band, so the adjustment was suppressed.
+func (da *DynamicAllocator) shouldAdjustResources(allocation *DynamicAllocation, optimalGPU int64, optimalFraction float64, optimalCPU, optimalMem resource.Quantity) (adjust, suppressed bool) {
 	// Check if optimal resources differ significantly from current
 	gpuDiff := abs(int(optimalGPU - allocation.CurrentGPU))
 	cpuDiff := optimalCPU.Cmp(allocation.CurrentCPU)
 	memDiff := optimalMem.Cmp(allocation.CurrentMem)
+	if gpuDiff == 0 && optimalFraction == allocation.CurrentFraction && cpuDiff == 0 && memDiff == 0 {
+		return false, false
+	}
+
+	// Differences inside the hysteresis band are not worth an adjustment
+	adjust = da.exceedsHysteresis(float64(allocation.CurrentGPU), float64(optimalGPU)) ||
+		da.exceedsHysteresis(allocation.CurrentFraction, optimalFraction) ||
+		da.exceedsHysteresis(float64(allocation.CurrentCPU.MilliValue()), float64(optimalCPU.MilliValue())) ||
+		da.exceedsHysteresis(float64(allocation.CurrentMem.Value()), float64(optimalMem.Value()))
+	return adjust, !adjust
+}
 
-	// Adjust if there's a significant difference
-	return gpuDiff > 0 || cpuDiff != 0 || memDiff != 0
+// exceedsHysteresis reports whether moving from current to optimal changes the
+// value by more than the configured fraction. Any change from zero counts.
+func (da *DynamicAllocator) exceedsHysteresis(current, optimal float64) bool {
+	if current == optimal {
+		return false
+	}
+	if current == 0 {
+		return true
+	}
+	return math.Abs(optimal-current)/current > da.config.HysteresisDelta
+}
+
+// canAdjust reports whether the cooldown since the allocation's last adjustment has passed
+func (da *DynamicAllocator) canAdjust(allocation *DynamicAllocation) bool {
+	if allocation.LastAdjusted.IsZero() {
+		return true
+	}
+	return da.now().Sub(allocation.LastAdjusted) >= da.config.MinAdjustmentInterval
 }
 
-// adjustResources adjusts the resources for a job
-func (da *DynamicAllocator) adjustResources(ctx context.Context, job *v1alpha1.KaiwoJob, allocation *DynamicAllocation, optimalGPU int64, optimalCPU, optimalMem resource.Quantity) error {
+// adjustResources adjusts the resources for a job. If the job cannot be
+// updated, the job and the allocation are restored to their prior state.
+func (da *DynamicAllocator) adjustResources(ctx context.Context, job *v1alpha1.KaiwoJob, allocation *DynamicAllocation, optimalGPU int64, optimalFraction float64, optimalCPU, optimalMem resource.Quantity) error {
+	// Capture prior state for rollback
+	priorJob := job.DeepCopy()
+	priorGPU := allocation.CurrentGPU
+	priorFraction := allocation.CurrentFraction
+	priorCPU := allocation.CurrentCPU
+	priorMem := allocation.CurrentMem
+	priorAdjustments := len(allocation.Adjustments)
+
 	// Create adjustment record
 	adjustment := ResourceAdjustment{
-		Timestamp: time.Now(),
+		Timestamp: da.now(),
 	}
 
 	// Update GPU allocation
@@ -263,6 +495,17 @@ func (da *DynamicAllocator) adjustResources(ctx context.Context, job *v1alpha1.K
 		allocation.CurrentGPU = optimalGPU
 	}
 
+	// Update shared GPU fraction
+	if optimalFraction != allocation.CurrentFraction {
+		adjustment.Type = "GPUFraction"
+		adjustment.From = *resource.NewMilliQuantity(int64(math.Round(allocation.CurrentFraction*1000)), resource.DecimalSI)
+		adjustment.To = *resource.NewMilliQuantity(int64(math.Round(optimalFraction*1000)), resource.DecimalSI)
+		adjustment.Reason = fmt.Sprintf("Performance-based adjustment: %f", allocation.Performance)
+
+		allocation.Adjustments = append(allocation.Adjustments, adjustment)
+		allocation.CurrentFraction = optimalFraction
+	}
+
 	// Update CPU allocation
 	if optimalCPU.Cmp(allocation.CurrentCPU) != 0 {
 		adjustment.Type = "CPU"
@@ -287,6 +530,9 @@ func (da *DynamicAllocator) adjustResources(ctx context.Context, job *v1alpha1.K
 
 	// Update job spec with new resources
 	job.Spec.Gpus = int(optimalGPU)
+	if optimalFraction > 0 {
+		setJobGPUFraction(job, optimalFraction)
+	}
 
 	if job.Spec.Resources == nil {
 		job.Spec.Resources = &corev1.ResourceRequirements{
@@ -299,17 +545,36 @@ func (da *DynamicAllocator) adjustResources(ctx context.Context, job *v1alpha1.K
 
 	// Update job in Kubernetes
 	if err := da.client.Update(ctx, job); err != nil {
+		priorJob.DeepCopyInto(job)
+		allocation.CurrentGPU = priorGPU
+		allocation.CurrentFraction = priorFraction
+		allocation.CurrentCPU = priorCPU
+		allocation.CurrentMem = priorMem
+		allocation.Adjustments = allocation.Adjustments[:priorAdjustments]
 		return fmt.Errorf("failed to update job resources: %w", err)
 	}
 
-	allocation.LastUpdated = time.Now()
+	da.trimAdjustmentHistory(allocation)
+	allocation.LastUpdated = da.now()
+	allocation.LastAdjusted = allocation.LastUpdated
 	allocation.OptimalGPU = optimalGPU
+	allocation.OptimalFraction = optimalFraction
 	allocation.OptimalCPU = optimalCPU
 	allocation.OptimalMem = optimalMem
 
 	return nil
 }
 
+// trimAdjustmentHistory drops all but the most recent adjustments of an allocation
+func (da *DynamicAllocator) trimAdjustmentHistory(allocation *DynamicAllocation) {
+	excess := len(allocation.Adjustments) - da.config.MaxAdjustmentHistory
+	if excess <= 0 {
+		return
+	}
+	// Copy so the dropped entries' backing array can be freed
+	allocation.Adjustments = append([]ResourceAdjustment(nil), allocation.Adjustments[excess:]...)
+}
+
 // abs returns the absolute value of an integer
 func abs(x int) int {
 	if x < 0 {
@@ -342,6 +607,14 @@ func (da *DynamicAllocator) updateFailedMetrics(adjustmentTime time.Duration) {
 	da.metrics.FailedAdjustments++
 }
 
+// recordSkippedAdjustment counts an adjustment suppressed by cooldown or hysteresis
+func (da *DynamicAllocator) recordSkippedAdjustment() {
+	da.metrics.mu.Lock()
+	defer da.metrics.mu.Unlock()
+
+	da.metrics.SkippedAdjustments++
+}
+
 // GetMetrics returns current dynamic allocator metrics
 func (da *DynamicAllocator) GetMetrics() DynamicAllocatorMetrics {
 	da.metrics.mu.RLock()
@@ -363,3 +636,19 @@ func (da *DynamicAllocator) GetAllocations() map[string]*DynamicAllocation {
 
 	return allocations
 }
+
+// GetAdjustmentHistory returns a copy of the recorded adjustments for a job,
+// oldest first. jobKey is the job's namespace/name.
+func (da *DynamicAllocator) GetAdjustmentHistory(jobKey string) ([]ResourceAdjustment, error) {
+	da.mu.RLock()
+	defer da.mu.RUnlock()
+
+	allocation, exists := da.allocations[jobKey]
+	if !exists {
+		return nil, fmt.Errorf("no allocation found for job %s", jobKey)
+	}
+
+	history := make([]ResourceAdjustment, len(allocation.Adjustments))
+	copy(history, allocation.Adjustments)
+	return history, nil
+}
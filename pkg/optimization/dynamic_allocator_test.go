@@ -0,0 +1,548 @@
+package optimization
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
+	"github.com/silogen/kaiwo/pkg/gpu/manager"
+	"github.com/silogen/kaiwo/pkg/gpu/types"
+)
+
+// fakeMetricsProvider returns fixed usage per pod
+type fakeMetricsProvider struct {
+	usage map[string]*PodMetrics
+}
+
+func (f *fakeMetricsProvider) GetPodMetrics(ctx context.Context, namespace, name string) (*PodMetrics, error) {
+	usage, ok := f.usage[namespace+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("no metrics for pod %s/%s", namespace, name)
+	}
+	return usage, nil
+}
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core types to scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add kaiwo types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func newTestDynamicAllocator(t *testing.T, c client.Client, provider MetricsProvider, config *DynamicAllocatorConfig) *DynamicAllocator {
+	t.Helper()
+
+	da, err := NewDynamicAllocator(c, provider, config)
+	if err != nil {
+		t.Fatalf("NewDynamicAllocator failed: %v", err)
+	}
+	return da
+}
+
+func newTestJobPod(name, jobName string, requests corev1.ResourceList, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"kaiwo.silogen.ai/name": jobName},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:      "main",
+				Resources: corev1.ResourceRequirements{Requests: requests},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestDynamicAllocatorPerformanceFromPodMetrics(t *testing.T) {
+	job := &v1alpha1.KaiwoJob{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"}}
+	job.Spec.Gpus = 1
+
+	gpuRequests := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+		"amd.com/gpu":         resource.MustParse("1"),
+	}
+	cpuRequests := corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("4"),
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithObjects(
+			job,
+			newTestJobPod("worker-0", "train", gpuRequests, corev1.PodRunning),
+			newTestJobPod("worker-1", "train", cpuRequests, corev1.PodRunning),
+			newTestJobPod("pending", "train", gpuRequests, corev1.PodPending),
+			newTestJobPod("unmeasured", "train", gpuRequests, corev1.PodRunning),
+			newTestJobPod("other-job", "other", gpuRequests, corev1.PodRunning),
+		).
+		Build()
+
+	provider := &fakeMetricsProvider{usage: map[string]*PodMetrics{
+		// CPU 1/2, memory 3Gi/4Gi, GPU 90%: (0.5 + 0.75 + 0.9) / 3
+		"default/worker-0": {CPU: resource.MustParse("1"), Memory: resource.MustParse("3Gi"), GPUUtilization: 0.9},
+		// Only CPU is requested, and usage bursts past it: clamped to 1.0
+		"default/worker-1":  {CPU: resource.MustParse("5"), Memory: resource.MustParse("1Gi")},
+		"default/pending":   {CPU: resource.MustParse("2"), Memory: resource.MustParse("4Gi"), GPUUtilization: 1},
+		"default/other-job": {CPU: resource.MustParse("2"), Memory: resource.MustParse("4Gi"), GPUUtilization: 1},
+	}}
+
+	da := newTestDynamicAllocator(t, c, provider, nil)
+	if err := da.AnalyzeJob(context.Background(), job); err != nil {
+		t.Fatalf("AnalyzeJob failed: %v", err)
+	}
+
+	allocation := da.GetAllocations()["default/train"]
+	if allocation == nil {
+		t.Fatal("expected an allocation for default/train")
+	}
+	want := ((0.5+0.75+0.9)/3 + 1.0) / 2
+	if math.Abs(allocation.Performance-want) > 1e-9 {
+		t.Errorf("Performance = %.4f, want %.4f", allocation.Performance, want)
+	}
+}
+
+func TestDynamicAllocatorWithoutMetricsProvider(t *testing.T) {
+	job := &v1alpha1.KaiwoJob{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"}}
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithObjects(job, newTestJobPod("worker-0", "train", corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		}, corev1.PodRunning)).
+		Build()
+
+	da := newTestDynamicAllocator(t, c, nil, nil)
+	if got := da.calculatePerformance(context.Background(), job); got != 0 {
+		t.Errorf("Performance without metrics = %.4f, want 0", got)
+	}
+}
+
+// fakeGPUManager reports fixed allocations and GPU utilization
+type fakeGPUManager struct {
+	manager.GPUManager
+	allocations []*types.GPUAllocation
+	gpus        map[string]*types.GPUInfo
+}
+
+func (f *fakeGPUManager) ListAllocations(ctx context.Context) ([]*types.GPUAllocation, error) {
+	return f.allocations, nil
+}
+
+func (f *fakeGPUManager) GetGPUInfo(ctx context.Context, deviceID string) (*types.GPUInfo, error) {
+	gpu, ok := f.gpus[deviceID]
+	if !ok {
+		return nil, fmt.Errorf("GPU %s not found", deviceID)
+	}
+	return gpu, nil
+}
+
+func TestMetricsServerProvider(t *testing.T) {
+	podMetrics := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "worker-0", "namespace": "default"},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "main", "usage": map[string]interface{}{"cpu": "1500m", "memory": "3Gi"}},
+			map[string]interface{}{"name": "sidecar", "usage": map[string]interface{}{"cpu": "250m", "memory": "512Mi"}},
+		},
+	}}
+	podMetrics.SetGroupVersionKind(podMetricsGVK)
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(podMetrics).Build()
+	gpus := &fakeGPUManager{
+		allocations: []*types.GPUAllocation{
+			{DeviceID: "gpu-0", PodName: "worker-0", Namespace: "default"},
+			{DeviceID: "gpu-1", PodName: "worker-0", Namespace: "default"},
+			{DeviceID: "gpu-2", PodName: "worker-1", Namespace: "default"},
+		},
+		gpus: map[string]*types.GPUInfo{
+			"gpu-0": {DeviceID: "gpu-0", Utilization: 80},
+			"gpu-1": {DeviceID: "gpu-1", Utilization: 60},
+			"gpu-2": {DeviceID: "gpu-2", Utilization: 10},
+		},
+	}
+
+	provider := NewMetricsServerProvider(c, gpus)
+	metrics, err := provider.GetPodMetrics(context.Background(), "default", "worker-0")
+	if err != nil {
+		t.Fatalf("GetPodMetrics failed: %v", err)
+	}
+
+	if want := resource.MustParse("1750m"); metrics.CPU.Cmp(want) != 0 {
+		t.Errorf("CPU = %s, want %s", metrics.CPU.String(), want.String())
+	}
+	if want := resource.MustParse("3584Mi"); metrics.Memory.Cmp(want) != 0 {
+		t.Errorf("Memory = %s, want %s", metrics.Memory.String(), want.String())
+	}
+	if math.Abs(metrics.GPUUtilization-0.7) > 1e-9 {
+		t.Errorf("GPUUtilization = %.3f, want 0.700", metrics.GPUUtilization)
+	}
+
+	if _, err := provider.GetPodMetrics(context.Background(), "default", "missing"); err == nil {
+		t.Error("expected an error for a pod without metrics")
+	}
+}
+
+// newUnderutilizedJob returns a job whose only pod is barely used, so every
+// analysis asks for more resources
+func newUnderutilizedJob(t *testing.T) (*v1alpha1.KaiwoJob, client.Client, MetricsProvider) {
+	t.Helper()
+
+	job := &v1alpha1.KaiwoJob{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"}}
+	job.Spec.Gpus = 1
+	job.Spec.Resources = &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+	}}
+
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithObjects(job, newTestJobPod("worker-0", "train", corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("4"),
+		}, corev1.PodRunning)).
+		Build()
+	provider := &fakeMetricsProvider{usage: map[string]*PodMetrics{
+		"default/worker-0": {CPU: resource.MustParse("400m")},
+	}}
+	return job, c, provider
+}
+
+func TestDynamicAllocatorCooldown(t *testing.T) {
+	job, c, provider := newUnderutilizedJob(t)
+	da := newTestDynamicAllocator(t, c, provider, &DynamicAllocatorConfig{
+		MinAdjustmentInterval: 10 * time.Minute,
+		HysteresisDelta:       0.1,
+	})
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	da.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if err := da.AnalyzeJob(ctx, job); err != nil {
+		t.Fatalf("first AnalyzeJob failed: %v", err)
+	}
+	allocation := da.GetAllocations()["default/train"]
+	if allocation.CurrentGPU != 2 || len(allocation.Adjustments) != 3 {
+		t.Fatalf("expected first analysis to adjust, got %d GPUs and %d adjustments",
+			allocation.CurrentGPU, len(allocation.Adjustments))
+	}
+
+	// A second analysis right away wants more resources but is in cooldown
+	now = now.Add(time.Minute)
+	if err := da.AnalyzeJob(ctx, job); err != nil {
+		t.Fatalf("second AnalyzeJob failed: %v", err)
+	}
+	if allocation.CurrentGPU != 2 || len(allocation.Adjustments) != 3 {
+		t.Errorf("expected second analysis to be suppressed, got %d GPUs and %d adjustments",
+			allocation.CurrentGPU, len(allocation.Adjustments))
+	}
+	if metrics := da.GetMetrics(); metrics.SkippedAdjustments != 1 {
+		t.Errorf("SkippedAdjustments = %d, want 1", metrics.SkippedAdjustments)
+	}
+
+	// Once the cooldown has passed the job is adjusted again
+	now = now.Add(10 * time.Minute)
+	if err := da.AnalyzeJob(ctx, job); err != nil {
+		t.Fatalf("third AnalyzeJob failed: %v", err)
+	}
+	if allocation.CurrentGPU != 3 {
+		t.Errorf("expected adjustment after cooldown, got %d GPUs", allocation.CurrentGPU)
+	}
+	if !allocation.LastAdjusted.Equal(now) {
+		t.Errorf("LastAdjusted = %v, want %v", allocation.LastAdjusted, now)
+	}
+}
+
+func TestDynamicAllocatorHysteresis(t *testing.T) {
+	da := newTestDynamicAllocator(t, nil, nil, &DynamicAllocatorConfig{HysteresisDelta: 0.2})
+	allocation := &DynamicAllocation{
+		CurrentGPU: 8,
+		CurrentCPU: resource.MustParse("10"),
+		CurrentMem: resource.MustParse("100Gi"),
+	}
+
+	tests := []struct {
+		name           string
+		gpu            int64
+		cpu            string
+		mem            string
+		want           bool
+		wantSuppressed bool
+	}{
+		{name: "unchanged", gpu: 8, cpu: "10", mem: "100Gi", want: false, wantSuppressed: false},
+		{name: "small changes", gpu: 9, cpu: "11", mem: "110Gi", want: false, wantSuppressed: true},
+		{name: "large cpu change", gpu: 8, cpu: "13", mem: "100Gi", want: true},
+		{name: "large memory change", gpu: 8, cpu: "10", mem: "50Gi", want: true},
+		{name: "large gpu change", gpu: 6, cpu: "10", mem: "100Gi", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, suppressed := da.shouldAdjustResources(allocation, tt.gpu, 0, resource.MustParse(tt.cpu), resource.MustParse(tt.mem))
+			if got != tt.want {
+				t.Errorf("shouldAdjustResources = %v, want %v", got, tt.want)
+			}
+			if suppressed != tt.wantSuppressed {
+				t.Errorf("shouldAdjustResources suppressed = %v, want %v", suppressed, tt.wantSuppressed)
+			}
+		})
+	}
+
+	// Any change from an unset value is significant
+	if adjust, _ := da.shouldAdjustResources(&DynamicAllocation{}, 0, 0, resource.MustParse("1"), resource.Quantity{}); !adjust {
+		t.Error("expected a change from zero CPU to trigger an adjustment")
+	}
+}
+
+func TestDynamicAllocatorCountsHysteresisSuppression(t *testing.T) {
+	job, c, provider := newUnderutilizedJob(t)
+	// The analysis asks for one more GPU and CPU and 2Gi more memory, all
+	// within half of the current allocation
+	job.Spec.Gpus = 4
+	da := newTestDynamicAllocator(t, c, provider, &DynamicAllocatorConfig{HysteresisDelta: 0.5})
+
+	if err := da.AnalyzeJob(context.Background(), job); err != nil {
+		t.Fatalf("AnalyzeJob failed: %v", err)
+	}
+	if allocation := da.GetAllocations()["default/train"]; len(allocation.Adjustments) != 0 {
+		t.Errorf("expected the adjustment to be suppressed, got %d adjustments", len(allocation.Adjustments))
+	}
+	if metrics := da.GetMetrics(); metrics.SkippedAdjustments != 1 {
+		t.Errorf("SkippedAdjustments = %d, want 1", metrics.SkippedAdjustments)
+	}
+}
+
+func TestInvalidDynamicAllocatorConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DynamicAllocatorConfig
+	}{
+		{name: "negative interval", config: DynamicAllocatorConfig{MinAdjustmentInterval: -time.Second}},
+		{name: "negative delta", config: DynamicAllocatorConfig{HysteresisDelta: -0.1}},
+		{name: "delta of one", config: DynamicAllocatorConfig{HysteresisDelta: 1}},
+		{name: "negative history", config: DynamicAllocatorConfig{MaxAdjustmentHistory: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDynamicAllocator(nil, nil, &tt.config); err == nil {
+				t.Error("expected invalid config to be rejected")
+			}
+		})
+	}
+}
+
+// newGPUJob returns a job with one running GPU pod whose utilization the
+// returned provider reports as given
+func newGPUJob(t *testing.T, annotations map[string]string, gpuUtilization float64) (*v1alpha1.KaiwoJob, client.Client, MetricsProvider) {
+	t.Helper()
+
+	job := &v1alpha1.KaiwoJob{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"}}
+	job.Spec.Gpus = 1
+	job.Spec.Resources = &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+	}}
+	job.Spec.Job = &batchv1.Job{}
+	job.Spec.Job.Spec.Template.Annotations = annotations
+
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithObjects(job, newTestJobPod("worker-0", "train", corev1.ResourceList{
+			"amd.com/gpu": resource.MustParse("1"),
+		}, corev1.PodRunning)).
+		Build()
+	provider := &fakeMetricsProvider{usage: map[string]*PodMetrics{
+		"default/worker-0": {GPUUtilization: gpuUtilization},
+	}}
+	return job, c, provider
+}
+
+func TestDynamicAllocatorFractionalAdjustment(t *testing.T) {
+	tests := []struct {
+		name         string
+		partition    *manager.MI300XPartitionConfig
+		fraction     string
+		utilization  float64
+		wantFraction string
+	}{
+		{name: "CPX grows one XCD", fraction: "0.25", utilization: 0.2, wantFraction: "0.375"},
+		{name: "CPX shrinks one XCD", fraction: "0.5", utilization: 0.95, wantFraction: "0.375"},
+		{name: "CPX at full GPU stays whole", fraction: "1", utilization: 0.2, wantFraction: "1"},
+		{
+			name:         "TPX grows to the larger partition",
+			partition:    &manager.MI300XPartitionConfig{ComputeMode: manager.MI300XPartitionModeTPX, MemoryMode: manager.MI300XMemoryModeNPS1, XCDCount: 8},
+			fraction:     "0.25",
+			utilization:  0.2,
+			wantFraction: "0.375",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, c, provider := newGPUJob(t, map[string]string{
+				"kaiwo.ai/gpu-sharing":  "true",
+				"kaiwo.ai/gpu-fraction": tt.fraction,
+			}, tt.utilization)
+			config := DefaultDynamicAllocatorConfig()
+			if tt.partition != nil {
+				config.Partition = tt.partition
+			}
+			da := newTestDynamicAllocator(t, c, provider, config)
+
+			if err := da.AnalyzeJob(context.Background(), job); err != nil {
+				t.Fatalf("AnalyzeJob failed: %v", err)
+			}
+
+			if job.Spec.Gpus != 1 {
+				t.Errorf("Gpus = %d, want whole GPU count unchanged at 1", job.Spec.Gpus)
+			}
+			if got := job.Spec.Job.Spec.Template.Annotations["kaiwo.ai/gpu-fraction"]; got != tt.wantFraction {
+				t.Errorf("gpu-fraction = %s, want %s", got, tt.wantFraction)
+			}
+			for _, adjustment := range da.GetAllocations()["default/train"].Adjustments {
+				if adjustment.Type == "GPU" {
+					t.Errorf("unexpected whole-GPU adjustment for a sharing job: %+v", adjustment)
+				}
+			}
+		})
+	}
+}
+
+func TestDynamicAllocatorWholeGPUAdjustmentWithoutSharing(t *testing.T) {
+	job, c, provider := newGPUJob(t, map[string]string{
+		"kaiwo.ai/gpu-sharing":  "false",
+		"kaiwo.ai/gpu-fraction": "0.25",
+	}, 0.2)
+	da := newTestDynamicAllocator(t, c, provider, nil)
+
+	if err := da.AnalyzeJob(context.Background(), job); err != nil {
+		t.Fatalf("AnalyzeJob failed: %v", err)
+	}
+
+	if job.Spec.Gpus != 2 {
+		t.Errorf("Gpus = %d, want 2", job.Spec.Gpus)
+	}
+	if got := job.Spec.Job.Spec.Template.Annotations["kaiwo.ai/gpu-fraction"]; got != "0.25" {
+		t.Errorf("gpu-fraction = %s, want it left at 0.25", got)
+	}
+	for _, adjustment := range da.GetAllocations()["default/train"].Adjustments {
+		if adjustment.Type == "GPUFraction" {
+			t.Errorf("unexpected fractional adjustment for a non-sharing job: %+v", adjustment)
+		}
+	}
+}
+
+func TestDynamicAllocatorRollsBackFailedUpdate(t *testing.T) {
+	job, _, provider := newUnderutilizedJob(t)
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithObjects(job, newTestJobPod("worker-0", "train", corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("4"),
+		}, corev1.PodRunning)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				return fmt.Errorf("api server unavailable")
+			},
+		}).
+		Build()
+	da := newTestDynamicAllocator(t, c, provider, nil)
+
+	if err := da.AnalyzeJob(context.Background(), job); err == nil {
+		t.Fatal("expected AnalyzeJob to fail when the job cannot be updated")
+	}
+
+	allocation := da.GetAllocations()["default/train"]
+	if allocation.CurrentGPU != 1 {
+		t.Errorf("CurrentGPU = %d, want 1", allocation.CurrentGPU)
+	}
+	if want := resource.MustParse("4"); allocation.CurrentCPU.Cmp(want) != 0 {
+		t.Errorf("CurrentCPU = %s, want %s", allocation.CurrentCPU.String(), want.String())
+	}
+	if want := resource.MustParse("16Gi"); allocation.CurrentMem.Cmp(want) != 0 {
+		t.Errorf("CurrentMem = %s, want %s", allocation.CurrentMem.String(), want.String())
+	}
+	if len(allocation.Adjustments) != 0 {
+		t.Errorf("expected no recorded adjustments, got %+v", allocation.Adjustments)
+	}
+	if !allocation.LastAdjusted.IsZero() {
+		t.Errorf("LastAdjusted = %v, want zero", allocation.LastAdjusted)
+	}
+
+	if job.Spec.Gpus != 1 {
+		t.Errorf("job Gpus = %d, want 1", job.Spec.Gpus)
+	}
+	if cpu := job.Spec.Resources.Requests[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("4")) != 0 {
+		t.Errorf("job CPU request = %s, want 4", cpu.String())
+	}
+	if metrics := da.GetMetrics(); metrics.FailedAdjustments != 1 {
+		t.Errorf("FailedAdjustments = %d, want 1", metrics.FailedAdjustments)
+	}
+}
+
+func TestDynamicAllocatorBoundsAdjustmentHistory(t *testing.T) {
+	job, c, provider := newUnderutilizedJob(t)
+	da := newTestDynamicAllocator(t, c, provider, &DynamicAllocatorConfig{MaxAdjustmentHistory: 4})
+
+	// Each analysis of the underutilized job records GPU, CPU and memory adjustments
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := da.AnalyzeJob(ctx, job); err != nil {
+			t.Fatalf("AnalyzeJob %d failed: %v", i, err)
+		}
+	}
+
+	history, err := da.GetAdjustmentHistory("default/train")
+	if err != nil {
+		t.Fatalf("GetAdjustmentHistory failed: %v", err)
+	}
+	if len(history) != 4 {
+		t.Fatalf("history has %d entries, want 4", len(history))
+	}
+
+	// The newest entries are the second analysis' memory adjustment followed by
+	// all three adjustments of the third analysis
+	want := []struct {
+		typ string
+		to  string
+	}{
+		{typ: "Memory", to: "20Gi"},
+		{typ: "GPU", to: "4"},
+		{typ: "CPU", to: "7"},
+		{typ: "Memory", to: "22Gi"},
+	}
+	for i, w := range want {
+		if history[i].Type != w.typ || history[i].To.Cmp(resource.MustParse(w.to)) != 0 {
+			t.Errorf("history[%d] = %s to %s, want %s to %s", i, history[i].Type, history[i].To.String(), w.typ, w.to)
+		}
+	}
+
+	// The returned history is a copy
+	history[0].Type = "changed"
+	if again, _ := da.GetAdjustmentHistory("default/train"); again[0].Type != "Memory" {
+		t.Error("modifying the returned history changed the allocation")
+	}
+
+	if _, err := da.GetAdjustmentHistory("default/missing"); err == nil {
+		t.Error("expected an error for an unknown job")
+	}
+}
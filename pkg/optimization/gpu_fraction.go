@@ -0,0 +1,89 @@
+package optimization
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
+)
+
+const (
+	// gpuSharingAnnotation enables fractional GPU sharing for a pod
+	gpuSharingAnnotation = "kaiwo.ai/gpu-sharing"
+	// gpuFractionAnnotation is the fraction of a GPU a shared pod is allocated
+	gpuFractionAnnotation = "kaiwo.ai/gpu-fraction"
+)
+
+// jobPodTemplates returns the pod templates a job's pods are created from
+func jobPodTemplates(job *v1alpha1.KaiwoJob) []*corev1.PodTemplateSpec {
+	if job.Spec.Job != nil {
+		return []*corev1.PodTemplateSpec{&job.Spec.Job.Spec.Template}
+	}
+	if job.Spec.RayJob != nil && job.Spec.RayJob.Spec.RayClusterSpec != nil {
+		clusterSpec := job.Spec.RayJob.Spec.RayClusterSpec
+		templates := []*corev1.PodTemplateSpec{&clusterSpec.HeadGroupSpec.Template}
+		for i := range clusterSpec.WorkerGroupSpecs {
+			templates = append(templates, &clusterSpec.WorkerGroupSpecs[i].Template)
+		}
+		return templates
+	}
+	return nil
+}
+
+// sharesGPU reports whether a pod template has GPU sharing enabled
+func sharesGPU(template *corev1.PodTemplateSpec) bool {
+	return strings.ToLower(template.Annotations[gpuSharingAnnotation]) == "true"
+}
+
+// jobGPUFraction returns the GPU fraction of a job's shared pods. It reports
+// false if none of the job's pod templates enable GPU sharing.
+func jobGPUFraction(job *v1alpha1.KaiwoJob) (float64, bool) {
+	for _, template := range jobPodTemplates(job) {
+		if !sharesGPU(template) {
+			continue
+		}
+		fraction, err := strconv.ParseFloat(template.Annotations[gpuFractionAnnotation], 64)
+		if err != nil || fraction <= 0 || fraction > 1 {
+			// Sharing without a usable fraction allocates a whole GPU
+			fraction = 1.0
+		}
+		return fraction, true
+	}
+	return 0, false
+}
+
+// setJobGPUFraction sets the GPU fraction on every job pod template that shares GPUs
+func setJobGPUFraction(job *v1alpha1.KaiwoJob, fraction float64) {
+	for _, template := range jobPodTemplates(job) {
+		if !sharesGPU(template) {
+			continue
+		}
+		if template.Annotations == nil {
+			template.Annotations = make(map[string]string)
+		}
+		template.Annotations[gpuFractionAnnotation] = strconv.FormatFloat(fraction, 'f', -1, 64)
+	}
+}
+
+// nextFractionStep returns the smallest step above current when grow is true,
+// or the largest step below it otherwise. It returns current if there is no
+// such step. steps must be sorted in ascending order.
+func nextFractionStep(steps []float64, current float64, grow bool) float64 {
+	if grow {
+		for _, step := range steps {
+			if step > current {
+				return step
+			}
+		}
+		return current
+	}
+
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i] < current {
+			return steps[i]
+		}
+	}
+	return current
+}
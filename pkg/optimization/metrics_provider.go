@@ -0,0 +1,135 @@
+package optimization
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/silogen/kaiwo/pkg/gpu/manager"
+)
+
+// podMetricsGVK is the metrics-server PodMetrics resource
+var podMetricsGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}
+
+// PodMetrics is the observed resource usage of a pod
+type PodMetrics struct {
+	// CPU is the pod's current CPU usage summed over its containers
+	CPU resource.Quantity
+	// Memory is the pod's current memory usage summed over its containers
+	Memory resource.Quantity
+	// GPUUtilization is the average utilization (0-1) of the GPUs allocated to the pod
+	GPUUtilization float64
+}
+
+// MetricsProvider supplies observed resource usage for pods
+type MetricsProvider interface {
+	// GetPodMetrics returns the current usage of a pod
+	GetPodMetrics(ctx context.Context, namespace, name string) (*PodMetrics, error)
+}
+
+// MetricsServerProvider reads CPU and memory usage from metrics-server and GPU
+// utilization from the GPU manager, since metrics-server does not report GPUs
+type MetricsServerProvider struct {
+	client     client.Reader
+	gpuManager manager.GPUManager
+}
+
+// NewMetricsServerProvider creates a metrics provider backed by metrics.k8s.io.
+// A nil GPU manager reports zero GPU utilization.
+func NewMetricsServerProvider(client client.Reader, gpuManager manager.GPUManager) *MetricsServerProvider {
+	return &MetricsServerProvider{
+		client:     client,
+		gpuManager: gpuManager,
+	}
+}
+
+// GetPodMetrics returns the current usage of a pod
+func (p *MetricsServerProvider) GetPodMetrics(ctx context.Context, namespace, name string) (*PodMetrics, error) {
+	podMetrics := &unstructured.Unstructured{}
+	podMetrics.SetGroupVersionKind(podMetricsGVK)
+	if err := p.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, podMetrics); err != nil {
+		return nil, fmt.Errorf("failed to get metrics for pod %s/%s: %w", namespace, name, err)
+	}
+
+	metrics, err := parsePodMetrics(podMetrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics for pod %s/%s: %w", namespace, name, err)
+	}
+
+	if p.gpuManager != nil {
+		utilization, err := p.podGPUUtilization(ctx, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GPU utilization for pod %s/%s: %w", namespace, name, err)
+		}
+		metrics.GPUUtilization = utilization
+	}
+
+	return metrics, nil
+}
+
+// parsePodMetrics sums the container usage in a metrics.k8s.io PodMetrics object
+func parsePodMetrics(podMetrics *unstructured.Unstructured) (*PodMetrics, error) {
+	containers, _, err := unstructured.NestedSlice(podMetrics.Object, "containers")
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &PodMetrics{}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		usage, _, err := unstructured.NestedStringMap(container, "usage")
+		if err != nil {
+			return nil, err
+		}
+		if cpu, ok := usage["cpu"]; ok {
+			quantity, err := resource.ParseQuantity(cpu)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cpu usage %q: %w", cpu, err)
+			}
+			metrics.CPU.Add(quantity)
+		}
+		if mem, ok := usage["memory"]; ok {
+			quantity, err := resource.ParseQuantity(mem)
+			if err != nil {
+				return nil, fmt.Errorf("invalid memory usage %q: %w", mem, err)
+			}
+			metrics.Memory.Add(quantity)
+		}
+	}
+
+	return metrics, nil
+}
+
+// podGPUUtilization averages the utilization of the GPUs allocated to a pod
+func (p *MetricsServerProvider) podGPUUtilization(ctx context.Context, namespace, name string) (float64, error) {
+	allocations, err := p.gpuManager.ListAllocations(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list GPU allocations: %w", err)
+	}
+
+	total := 0.0
+	devices := 0
+	for _, allocation := range allocations {
+		if allocation.Namespace != namespace || allocation.PodName != name {
+			continue
+		}
+		gpu, err := p.gpuManager.GetGPUInfo(ctx, allocation.DeviceID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get GPU %s: %w", allocation.DeviceID, err)
+		}
+		total += gpu.Utilization / 100.0
+		devices++
+	}
+
+	if devices == 0 {
+		return 0, nil
+	}
+	return total / float64(devices), nil
+}
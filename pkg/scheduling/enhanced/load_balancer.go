@@ -3,22 +3,142 @@ package enhanced
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
 )
 
+// gpuResourceNames are the extended resources GPU device plugins advertise
+var gpuResourceNames = []corev1.ResourceName{"amd.com/gpu", "nvidia.com/gpu"}
+
 // LoadBalancer implements dynamic load balancing for KaiwoJobs
 type LoadBalancer struct {
 	client    client.Client
 	mu        sync.RWMutex
 	nodeStats map[string]*NodeStats
 	metrics   *LoadBalancerMetrics
+	config    LoadBalancerConfig
+}
+
+// LoadBalancerConfig configures a LoadBalancer
+type LoadBalancerConfig struct {
+	// Weights controls how each resource contributes to a node's load score.
+	// All-zero weights mean the default 0.5 GPU, 0.3 CPU and 0.2 memory.
+	Weights LoadScoreWeights `json:"weights"`
+
+	// OverloadThreshold is the load score above which a node is overloaded;
+	// zero means DefaultOverloadThreshold
+	OverloadThreshold float64 `json:"overloadThreshold,omitempty"`
+
+	// UnderloadThreshold is the load score below which a node is underloaded;
+	// zero means DefaultUnderloadThreshold, so a threshold of zero cannot be
+	// configured. Use a small positive value to treat almost no node as underloaded.
+	UnderloadThreshold float64 `json:"underloadThreshold,omitempty"`
+
+	// MaxMovesPerRebalance caps how many pods a single rebalance may evict;
+	// zero means DefaultMaxMovesPerRebalance, so it cannot disable evictions.
+	// Use DryRun to plan rebalances without evicting.
+	MaxMovesPerRebalance int `json:"maxMovesPerRebalance,omitempty"`
+
+	// DryRun makes RebalanceCluster plan moves without evicting any pods
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// RebalanceMove is a pod eviction proposed to shift load between nodes
+type RebalanceMove struct {
+	FromNode string
+	ToNode   string
+	Pod      client.ObjectKey
+	Reason   string
+}
+
+// LoadScoreWeights are the relative weights of GPU, CPU and memory
+// utilization in a node's load score. They must sum to 1.0.
+type LoadScoreWeights struct {
+	GPU    float64 `json:"gpu"`
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+}
+
+const (
+	// DefaultOverloadThreshold is the default load score above which a node is overloaded
+	DefaultOverloadThreshold = 0.8
+	// DefaultUnderloadThreshold is the default load score below which a node is underloaded
+	DefaultUnderloadThreshold = 0.3
+	// DefaultMaxMovesPerRebalance is the default cap on evictions per rebalance
+	DefaultMaxMovesPerRebalance = 5
+)
+
+// DefaultLoadScoreWeights are the load score weights used when none are configured
+var DefaultLoadScoreWeights = LoadScoreWeights{GPU: 0.5, CPU: 0.3, Memory: 0.2}
+
+// loadScoreWeightTolerance is how far the weights may sum away from 1.0
+const loadScoreWeightTolerance = 0.001
+
+// DefaultLoadBalancerConfig returns the default load balancer configuration
+func DefaultLoadBalancerConfig() *LoadBalancerConfig {
+	return &LoadBalancerConfig{
+		Weights:              DefaultLoadScoreWeights,
+		OverloadThreshold:    DefaultOverloadThreshold,
+		UnderloadThreshold:   DefaultUnderloadThreshold,
+		MaxMovesPerRebalance: DefaultMaxMovesPerRebalance,
+	}
+}
+
+// withDefaults returns the configuration with unset weights and rebalance
+// settings filled in
+func (c LoadBalancerConfig) withDefaults() LoadBalancerConfig {
+	if c.Weights == (LoadScoreWeights{}) {
+		c.Weights = DefaultLoadScoreWeights
+	}
+	if c.OverloadThreshold == 0 {
+		c.OverloadThreshold = DefaultOverloadThreshold
+	}
+	if c.UnderloadThreshold == 0 {
+		c.UnderloadThreshold = DefaultUnderloadThreshold
+	}
+	if c.MaxMovesPerRebalance == 0 {
+		c.MaxMovesPerRebalance = DefaultMaxMovesPerRebalance
+	}
+	return c
+}
+
+// ValidateLoadBalancerConfig validates a load balancer configuration
+func ValidateLoadBalancerConfig(config *LoadBalancerConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+
+	weights := config.Weights
+	if weights.GPU < 0 || weights.CPU < 0 || weights.Memory < 0 {
+		return fmt.Errorf("load score weights cannot be negative: gpu=%.3f cpu=%.3f memory=%.3f",
+			weights.GPU, weights.CPU, weights.Memory)
+	}
+	if sum := weights.GPU + weights.CPU + weights.Memory; math.Abs(sum-1.0) > loadScoreWeightTolerance {
+		return fmt.Errorf("load score weights must sum to 1.0, got %.3f", sum)
+	}
+
+	if config.OverloadThreshold <= 0 || config.OverloadThreshold > 1 {
+		return fmt.Errorf("overload threshold must be in (0, 1], got %.3f", config.OverloadThreshold)
+	}
+	if config.UnderloadThreshold < 0 || config.UnderloadThreshold >= config.OverloadThreshold {
+		return fmt.Errorf("underload threshold must be non-negative and below the overload threshold %.3f, got %.3f",
+			config.OverloadThreshold, config.UnderloadThreshold)
+	}
+	if config.MaxMovesPerRebalance < 0 {
+		return fmt.Errorf("max moves per rebalance cannot be negative, got %d", config.MaxMovesPerRebalance)
+	}
+
+	return nil
 }
 
 // NodeStats tracks resource usage statistics for a node
@@ -32,6 +152,11 @@ type NodeStats struct {
 	UsedMemory  resource.Quantity
 	LoadScore   float64
 	LastUpdated time.Time
+
+	// Unschedulable reports whether the node is cordoned
+	Unschedulable bool
+	// Taints are the node's taints that keep pods without a matching toleration off it
+	Taints []corev1.Taint
 }
 
 // LoadBalancerMetrics tracks load balancing performance metrics
@@ -43,8 +168,17 @@ type LoadBalancerMetrics struct {
 	mu                   sync.RWMutex
 }
 
-// NewLoadBalancer creates a new load balancer instance
-func NewLoadBalancer(client client.Client) *LoadBalancer {
+// NewLoadBalancer creates a new load balancer instance. A nil config uses
+// DefaultLoadBalancerConfig.
+func NewLoadBalancer(client client.Client, config *LoadBalancerConfig) (*LoadBalancer, error) {
+	if config == nil {
+		config = DefaultLoadBalancerConfig()
+	}
+	resolved := config.withDefaults()
+	if err := ValidateLoadBalancerConfig(&resolved); err != nil {
+		return nil, fmt.Errorf("invalid load balancer config: %w", err)
+	}
+
 	return &LoadBalancer{
 		client:    client,
 		nodeStats: make(map[string]*NodeStats),
@@ -53,7 +187,8 @@ func NewLoadBalancer(client client.Client) *LoadBalancer {
 			SuccessfulRebalances: 0,
 			FailedRebalances:     0,
 		},
-	}
+		config: resolved,
+	}, nil
 }
 
 // UpdateNodeStats updates the resource statistics for a node
@@ -61,6 +196,11 @@ func (lb *LoadBalancer) UpdateNodeStats(ctx context.Context, nodeName string) er
 	lb.mu.Lock()
 	defer lb.mu.Unlock()
 
+	return lb.updateNodeStats(ctx, nodeName)
+}
+
+// updateNodeStats updates the resource statistics for a node. Callers must hold lb.mu.
+func (lb *LoadBalancer) updateNodeStats(ctx context.Context, nodeName string) error {
 	// Get node information
 	var node corev1.Node
 	if err := lb.client.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
@@ -75,8 +215,14 @@ func (lb *LoadBalancer) UpdateNodeStats(ctx context.Context, nodeName string) er
 
 	// Calculate resource usage
 	stats := &NodeStats{
-		NodeName:    nodeName,
-		LastUpdated: time.Now(),
+		NodeName:      nodeName,
+		LastUpdated:   time.Now(),
+		Unschedulable: node.Spec.Unschedulable,
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			stats.Taints = append(stats.Taints, taint)
+		}
 	}
 
 	// Get total capacity
@@ -86,10 +232,12 @@ func (lb *LoadBalancer) UpdateNodeStats(ctx context.Context, nodeName string) er
 	if mem, ok := node.Status.Capacity[corev1.ResourceMemory]; ok {
 		stats.TotalMemory = mem
 	}
+	stats.TotalGPU = nodeGPUCapacity(&node)
 
 	// Calculate used resources from pods
 	for _, pod := range pods.Items {
 		if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodPending {
+			stats.UsedGPU += podGPURequests(&pod)
 			for _, container := range pod.Spec.Containers {
 				if container.Resources.Requests != nil {
 					if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
@@ -112,6 +260,33 @@ func (lb *LoadBalancer) UpdateNodeStats(ctx context.Context, nodeName string) er
 	return nil
 }
 
+// nodeGPUCapacity returns the number of GPUs a node advertises
+func nodeGPUCapacity(node *corev1.Node) int64 {
+	var total int64
+	for _, name := range gpuResourceNames {
+		if gpu, ok := node.Status.Capacity[name]; ok {
+			total += gpu.Value()
+		}
+	}
+	return total
+}
+
+// podGPURequests returns the number of GPUs a pod's containers request. GPUs
+// cannot be overcommitted, so a limit without a request counts as the request.
+func podGPURequests(pod *corev1.Pod) int64 {
+	var total int64
+	for _, container := range pod.Spec.Containers {
+		for _, name := range gpuResourceNames {
+			if gpu, ok := container.Resources.Requests[name]; ok {
+				total += gpu.Value()
+			} else if gpu, ok := container.Resources.Limits[name]; ok {
+				total += gpu.Value()
+			}
+		}
+	}
+	return total
+}
+
 // calculateLoadScore calculates a load score for a node based on resource utilization
 func (lb *LoadBalancer) calculateLoadScore(stats *NodeStats) float64 {
 	if stats.TotalGPU == 0 && stats.TotalCPU.IsZero() && stats.TotalMemory.IsZero() {
@@ -133,14 +308,14 @@ func (lb *LoadBalancer) calculateLoadScore(stats *NodeStats) float64 {
 		memScore = float64(stats.UsedMemory.Value()) / float64(stats.TotalMemory.Value())
 	}
 
-	// Weighted average: GPU (50%), CPU (30%), Memory (20%)
-	return (gpuScore * 0.5) + (cpuScore * 0.3) + (memScore * 0.2)
+	// Weighted average using the configured weights
+	return (gpuScore * lb.config.Weights.GPU) + (cpuScore * lb.config.Weights.CPU) + (memScore * lb.config.Weights.Memory)
 }
 
 // FindOptimalNode finds the optimal node for a job based on load balancing
 func (lb *LoadBalancer) FindOptimalNode(ctx context.Context, job *v1alpha1.KaiwoJob) (string, error) {
-	lb.mu.RLock()
-	defer lb.mu.RUnlock()
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
 
 	// Update stats for all nodes if needed
 	if err := lb.updateAllNodeStats(ctx); err != nil {
@@ -151,10 +326,15 @@ func (lb *LoadBalancer) FindOptimalNode(ctx context.Context, job *v1alpha1.Kaiwo
 	requiredGPU := lb.calculateRequiredGPU(job)
 	requiredCPU := lb.calculateRequiredCPU(job)
 	requiredMem := lb.calculateRequiredMemory(job)
+	podSpecs := jobPodSpecs(job)
 
 	// Find nodes that can accommodate the job
 	var candidateNodes []string
 	for nodeName, stats := range lb.nodeStats {
+		if !stats.acceptsPodSpecs(podSpecs...) {
+			continue
+		}
+
 		// Check if node has sufficient resources
 		availableGPU := stats.TotalGPU - stats.UsedGPU
 		availableCPU := stats.TotalCPU.DeepCopy()
@@ -188,8 +368,9 @@ func (lb *LoadBalancer) FindOptimalNode(ctx context.Context, job *v1alpha1.Kaiwo
 	return optimalNode, nil
 }
 
-// RebalanceCluster performs load balancing across the cluster
-func (lb *LoadBalancer) RebalanceCluster(ctx context.Context) error {
+// RebalanceCluster performs load balancing across the cluster. It returns the
+// moves it carried out, or in dry-run mode the moves it would carry out.
+func (lb *LoadBalancer) RebalanceCluster(ctx context.Context) ([]RebalanceMove, error) {
 	startTime := time.Now()
 
 	lb.mu.Lock()
@@ -203,76 +384,178 @@ func (lb *LoadBalancer) RebalanceCluster(ctx context.Context) error {
 	// Update all node stats
 	if err := lb.updateAllNodeStats(ctx); err != nil {
 		lb.updateFailedMetrics(time.Since(startTime))
-		return fmt.Errorf("failed to update node stats: %w", err)
+		return nil, fmt.Errorf("failed to update node stats: %w", err)
 	}
 
-	// Find overloaded nodes (load score > 0.8)
-	var overloadedNodes []string
-	var underloadedNodes []string
+	plan, err := lb.planRebalance(ctx)
+	if err != nil {
+		lb.updateFailedMetrics(time.Since(startTime))
+		return nil, fmt.Errorf("failed to plan rebalance: %w", err)
+	}
 
-	for nodeName, stats := range lb.nodeStats {
-		if stats.LoadScore > 0.8 {
-			overloadedNodes = append(overloadedNodes, nodeName)
-		} else if stats.LoadScore < 0.3 {
-			underloadedNodes = append(underloadedNodes, nodeName)
+	if lb.config.DryRun {
+		lb.updateSuccessfulMetrics(time.Since(startTime))
+		return plan, nil
+	}
+
+	// Pods already in the plan are never substituted for a rejected move
+	claimed := make(map[client.ObjectKey]bool, len(plan))
+	for _, move := range plan {
+		claimed[move.Pod] = true
+	}
+
+	var executed []RebalanceMove
+	for _, move := range plan {
+		done, ok, err := lb.executeMove(ctx, move, claimed)
+		if err != nil {
+			lb.updateFailedMetrics(time.Since(startTime))
+			return executed, err
+		}
+		if ok {
+			executed = append(executed, done)
 		}
 	}
 
-	// Attempt to move jobs from overloaded to underloaded nodes
-	rebalanceCount := 0
+	// Update successful metrics
+	lb.updateSuccessfulMetrics(time.Since(startTime))
+
+	return executed, nil
+}
+
+// planRebalance proposes moves from overloaded to underloaded nodes.
+// Callers must hold lb.mu.
+func (lb *LoadBalancer) planRebalance(ctx context.Context) ([]RebalanceMove, error) {
+	overloadedNodes, underloadedNodes := lb.classifyNodes()
+
+	var plan []RebalanceMove
+	planned := make(map[client.ObjectKey]bool)
 	for _, overloadedNode := range overloadedNodes {
+		if len(underloadedNodes) == 0 {
+			break
+		}
+
+		// Get pods on the overloaded node
+		pods, err := lb.listNodePods(ctx, overloadedNode)
+		if err != nil {
+			return nil, err
+		}
+
 		for _, underloadedNode := range underloadedNodes {
-			if rebalanceCount >= 5 { // Limit rebalancing to prevent thrashing
-				break
+			if len(plan) >= lb.config.MaxMovesPerRebalance { // Limit rebalancing to prevent thrashing
+				return plan, nil
 			}
 
-			if err := lb.moveJobFromNode(ctx, overloadedNode, underloadedNode); err == nil {
-				rebalanceCount++
+			if move, ok := lb.planMove(ctx, pods, overloadedNode, underloadedNode, planned); ok {
+				planned[move.Pod] = true
+				plan = append(plan, move)
 			}
 		}
 	}
 
-	// Update successful metrics
-	lb.updateSuccessfulMetrics(time.Since(startTime))
-
-	return nil
+	return plan, nil
 }
 
-// moveJobFromNode attempts to move a job from one node to another
-func (lb *LoadBalancer) moveJobFromNode(ctx context.Context, fromNode, toNode string) error {
-	// Get pods on the overloaded node
+// listNodePods returns the pods on a node, sorted by name
+func (lb *LoadBalancer) listNodePods(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
 	var pods corev1.PodList
-	if err := lb.client.List(ctx, &pods, client.MatchingFields{"spec.nodeName": fromNode}); err != nil {
-		return fmt.Errorf("failed to list pods on node %s: %w", fromNode, err)
+	if err := lb.client.List(ctx, &pods, client.MatchingFields{"spec.nodeName": nodeName}); err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", nodeName, err)
 	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+	return pods.Items, nil
+}
 
-	// Find a suitable job to move
-	for _, pod := range pods.Items {
-		// Check if this is a KaiwoJob pod
-		if pod.Labels["kaiwo.ai/job-name"] != "" {
-			// Check if the target node can accommodate this pod
-			if lb.canNodeAccommodatePod(ctx, toNode, &pod) {
-				// Evict the pod to trigger rescheduling
-				if err := lb.client.Delete(ctx, &pod); err != nil {
-					return fmt.Errorf("failed to evict pod %s: %w", pod.Name, err)
-				}
-				return nil
-			}
+// planMove picks a KaiwoJob pod that the target node can accommodate and that
+// is not already part of the plan. Callers must hold lb.mu.
+func (lb *LoadBalancer) planMove(ctx context.Context, pods []corev1.Pod, fromNode, toNode string, planned map[client.ObjectKey]bool) (RebalanceMove, bool) {
+	for i := range pods {
+		pod := &pods[i]
+		key := client.ObjectKeyFromObject(pod)
+		// Only KaiwoJob pods are moved
+		if pod.Labels["kaiwo.ai/job-name"] == "" || planned[key] {
+			continue
+		}
+		if !lb.canNodeAccommodatePod(ctx, toNode, pod) {
+			continue
+		}
+
+		return RebalanceMove{
+			FromNode: fromNode,
+			ToNode:   toNode,
+			Pod:      key,
+			Reason: fmt.Sprintf("node %s load %.2f is above %.2f and node %s load %.2f is below %.2f",
+				fromNode, lb.nodeStats[fromNode].LoadScore, lb.config.OverloadThreshold,
+				toNode, lb.nodeStats[toNode].LoadScore, lb.config.UnderloadThreshold),
+		}, true
+	}
+	return RebalanceMove{}, false
+}
+
+// executeMove evicts a planned pod so it is rescheduled. If a
+// PodDisruptionBudget blocks the eviction, the next pod on the source node that
+// fits the target is tried instead. It returns the move that was carried out,
+// or false if every candidate was blocked. Callers must hold lb.mu.
+func (lb *LoadBalancer) executeMove(ctx context.Context, move RebalanceMove, claimed map[client.ObjectKey]bool) (RebalanceMove, bool, error) {
+	for {
+		err := lb.evictPod(ctx, move.Pod)
+		if err == nil {
+			return move, true, nil
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			return move, false, fmt.Errorf("failed to evict pod %s: %w", move.Pod, err)
+		}
+
+		// The disruption budget does not allow this pod to go; try another
+		claimed[move.Pod] = true
+		pods, err := lb.listNodePods(ctx, move.FromNode)
+		if err != nil {
+			return move, false, err
+		}
+
+		next, ok := lb.planMove(ctx, pods, move.FromNode, move.ToNode, claimed)
+		if !ok {
+			return move, false, nil
 		}
+		claimed[next.Pod] = true
+		move = next
 	}
+}
 
-	return fmt.Errorf("no suitable jobs found to move from %s to %s", fromNode, toNode)
+// evictPod evicts a pod through the Eviction API so PodDisruptionBudgets are respected
+func (lb *LoadBalancer) evictPod(ctx context.Context, key client.ObjectKey) error {
+	pod := &corev1.Pod{}
+	pod.Namespace = key.Namespace
+	pod.Name = key.Name
+	eviction := &policyv1.Eviction{}
+	eviction.Namespace = key.Namespace
+	eviction.Name = key.Name
+	return lb.client.SubResource("eviction").Create(ctx, pod, eviction)
+}
+
+// classifyNodes returns the overloaded and underloaded nodes, sorted by name.
+// Callers must hold lb.mu.
+func (lb *LoadBalancer) classifyNodes() (overloaded, underloaded []string) {
+	for nodeName, stats := range lb.nodeStats {
+		if stats.LoadScore > lb.config.OverloadThreshold {
+			overloaded = append(overloaded, nodeName)
+		} else if stats.LoadScore < lb.config.UnderloadThreshold {
+			underloaded = append(underloaded, nodeName)
+		}
+	}
+	sort.Strings(overloaded)
+	sort.Strings(underloaded)
+	return overloaded, underloaded
 }
 
 // canNodeAccommodatePod checks if a node can accommodate a pod
 func (lb *LoadBalancer) canNodeAccommodatePod(ctx context.Context, nodeName string, pod *corev1.Pod) bool {
 	stats, exists := lb.nodeStats[nodeName]
-	if !exists {
+	if !exists || !stats.acceptsPodSpecs(&pod.Spec) {
 		return false
 	}
 
 	// Calculate pod requirements
-	requiredGPU := int64(0)
+	requiredGPU := podGPURequests(pod)
 	requiredCPU := resource.Quantity{}
 	requiredMem := resource.Quantity{}
 
@@ -299,7 +582,50 @@ func (lb *LoadBalancer) canNodeAccommodatePod(ctx context.Context, nodeName stri
 		availableMem.Cmp(requiredMem) >= 0
 }
 
-// updateAllNodeStats updates statistics for all nodes
+// acceptsPodSpecs reports whether pods with the given specs may be scheduled
+// on the node: it must not be cordoned and every spec must tolerate its taints.
+func (stats *NodeStats) acceptsPodSpecs(specs ...*corev1.PodSpec) bool {
+	if stats.Unschedulable {
+		return false
+	}
+	for _, spec := range specs {
+		for i := range stats.Taints {
+			if !toleratesTaint(spec.Tolerations, &stats.Taints[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// toleratesTaint reports whether any of the tolerations tolerates the taint
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// jobPodSpecs returns the pod specs a job's pods will be created from. A job
+// without a Job or RayJob template gets pods without tolerations.
+func jobPodSpecs(job *v1alpha1.KaiwoJob) []*corev1.PodSpec {
+	if job.Spec.Job != nil {
+		return []*corev1.PodSpec{&job.Spec.Job.Spec.Template.Spec}
+	}
+	if job.Spec.RayJob != nil && job.Spec.RayJob.Spec.RayClusterSpec != nil {
+		clusterSpec := job.Spec.RayJob.Spec.RayClusterSpec
+		specs := []*corev1.PodSpec{&clusterSpec.HeadGroupSpec.Template.Spec}
+		for i := range clusterSpec.WorkerGroupSpecs {
+			specs = append(specs, &clusterSpec.WorkerGroupSpecs[i].Template.Spec)
+		}
+		return specs
+	}
+	return []*corev1.PodSpec{{}}
+}
+
+// updateAllNodeStats updates statistics for all nodes. Callers must hold lb.mu.
 func (lb *LoadBalancer) updateAllNodeStats(ctx context.Context) error {
 	var nodes corev1.NodeList
 	if err := lb.client.List(ctx, &nodes); err != nil {
@@ -307,7 +633,7 @@ func (lb *LoadBalancer) updateAllNodeStats(ctx context.Context) error {
 	}
 
 	for _, node := range nodes.Items {
-		if err := lb.UpdateNodeStats(ctx, node.Name); err != nil {
+		if err := lb.updateNodeStats(ctx, node.Name); err != nil {
 			return fmt.Errorf("failed to update stats for node %s: %w", node.Name, err)
 		}
 	}
@@ -0,0 +1,585 @@
+package enhanced
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/silogen/kaiwo/apis/kaiwo/v1alpha1"
+)
+
+func newTestNode(name string, gpuResource corev1.ResourceName, gpus int64) *corev1.Node {
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("64"),
+		corev1.ResourceMemory: resource.MustParse("512Gi"),
+	}
+	if gpus > 0 {
+		capacity[gpuResource] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NodeStatus{Capacity: capacity},
+	}
+}
+
+func newTestGPUPod(name, nodeName string, gpuResource corev1.ResourceName, gpus int64, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						gpuResource: *resource.NewQuantity(gpus, resource.DecimalSI),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func newTestJobPod(name, nodeName string, gpus int64) *corev1.Pod {
+	pod := newTestGPUPod(name, nodeName, "amd.com/gpu", gpus, corev1.PodRunning)
+	pod.Labels = map[string]string{"kaiwo.ai/job-name": name}
+	return pod
+}
+
+func newTestLoadBalancer(t *testing.T, objs ...client.Object) *LoadBalancer {
+	t.Helper()
+	return newTestLoadBalancerWithConfig(t, nil, objs...)
+}
+
+func newTestLoadBalancerWithConfig(t *testing.T, config *LoadBalancerConfig, objs ...client.Object) *LoadBalancer {
+	t.Helper()
+	return newTestLoadBalancerWithInterceptor(t, config, interceptor.Funcs{}, objs...)
+}
+
+func newTestLoadBalancerWithInterceptor(t *testing.T, config *LoadBalancerConfig, funcs interceptor.Funcs, objs ...client.Object) *LoadBalancer {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core types to scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add kaiwo types to scheme: %v", err)
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithIndex(&corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		}).
+		WithInterceptorFuncs(funcs).
+		Build()
+	lb, err := NewLoadBalancer(c, config)
+	if err != nil {
+		t.Fatalf("NewLoadBalancer failed: %v", err)
+	}
+	return lb
+}
+
+func TestLoadBalancerTracksGPUUsage(t *testing.T) {
+	lb := newTestLoadBalancer(t,
+		newTestNode("amd-node", "amd.com/gpu", 8),
+		newTestNode("nvidia-node", "nvidia.com/gpu", 4),
+		newTestGPUPod("running", "amd-node", "amd.com/gpu", 2, corev1.PodRunning),
+		newTestGPUPod("pending", "amd-node", "amd.com/gpu", 4, corev1.PodPending),
+		newTestGPUPod("finished", "amd-node", "amd.com/gpu", 2, corev1.PodSucceeded),
+		newTestGPUPod("nvidia", "nvidia-node", "nvidia.com/gpu", 1, corev1.PodRunning),
+	)
+
+	ctx := context.Background()
+	for _, node := range []string{"amd-node", "nvidia-node"} {
+		if err := lb.UpdateNodeStats(ctx, node); err != nil {
+			t.Fatalf("UpdateNodeStats(%s) failed: %v", node, err)
+		}
+	}
+
+	stats := lb.GetNodeStats()
+	if got := stats["amd-node"]; got.TotalGPU != 8 || got.UsedGPU != 6 {
+		t.Errorf("amd-node GPUs = %d/%d, want 6/8", got.UsedGPU, got.TotalGPU)
+	}
+	if got := stats["nvidia-node"]; got.TotalGPU != 4 || got.UsedGPU != 1 {
+		t.Errorf("nvidia-node GPUs = %d/%d, want 1/4", got.UsedGPU, got.TotalGPU)
+	}
+	if stats["amd-node"].LoadScore <= stats["nvidia-node"].LoadScore {
+		t.Errorf("expected amd-node to be more loaded than nvidia-node, got %.3f <= %.3f",
+			stats["amd-node"].LoadScore, stats["nvidia-node"].LoadScore)
+	}
+}
+
+func TestLoadBalancerFindOptimalNodeRespectsGPUAvailability(t *testing.T) {
+	lb := newTestLoadBalancer(t,
+		newTestNode("busy", "amd.com/gpu", 8),
+		newTestNode("idle", "amd.com/gpu", 4),
+		newTestNode("cpu-only", "", 0),
+		newTestGPUPod("training", "busy", "amd.com/gpu", 6, corev1.PodRunning),
+	)
+
+	job := &v1alpha1.KaiwoJob{ObjectMeta: metav1.ObjectMeta{Name: "job"}}
+	job.Spec.Gpus = 3
+
+	node, err := lb.FindOptimalNode(context.Background(), job)
+	if err != nil {
+		t.Fatalf("FindOptimalNode failed: %v", err)
+	}
+	if node != "idle" {
+		t.Errorf("FindOptimalNode = %q, want %q", node, "idle")
+	}
+
+	job.Spec.Gpus = 5
+	if node, err := lb.FindOptimalNode(context.Background(), job); err == nil {
+		t.Errorf("expected no node to fit 5 GPUs, got %q", node)
+	}
+}
+
+func TestLoadBalancerLoadScoreWeights(t *testing.T) {
+	// gpu-heavy has most of its GPUs in use but little CPU or memory;
+	// cpu-heavy is the reverse.
+	gpuHeavy := &NodeStats{
+		NodeName:    "gpu-heavy",
+		TotalGPU:    8,
+		UsedGPU:     7,
+		TotalCPU:    resource.MustParse("64"),
+		UsedCPU:     resource.MustParse("8"),
+		TotalMemory: resource.MustParse("512Gi"),
+		UsedMemory:  resource.MustParse("64Gi"),
+	}
+	cpuHeavy := &NodeStats{
+		NodeName:    "cpu-heavy",
+		TotalGPU:    8,
+		UsedGPU:     1,
+		TotalCPU:    resource.MustParse("64"),
+		UsedCPU:     resource.MustParse("60"),
+		TotalMemory: resource.MustParse("512Gi"),
+		UsedMemory:  resource.MustParse("480Gi"),
+	}
+
+	tests := []struct {
+		name         string
+		weights      LoadScoreWeights
+		moreLoadedID string
+	}{
+		{
+			name:         "gpu dominated",
+			weights:      LoadScoreWeights{GPU: 0.9, CPU: 0.1, Memory: 0},
+			moreLoadedID: "gpu-heavy",
+		},
+		{
+			name:         "cpu and memory dominated",
+			weights:      LoadScoreWeights{GPU: 0.1, CPU: 0.6, Memory: 0.3},
+			moreLoadedID: "cpu-heavy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb := newTestLoadBalancerWithConfig(t, &LoadBalancerConfig{Weights: tt.weights})
+
+			scores := map[string]float64{
+				gpuHeavy.NodeName: lb.calculateLoadScore(gpuHeavy),
+				cpuHeavy.NodeName: lb.calculateLoadScore(cpuHeavy),
+			}
+			moreLoaded := gpuHeavy.NodeName
+			if scores[cpuHeavy.NodeName] > scores[gpuHeavy.NodeName] {
+				moreLoaded = cpuHeavy.NodeName
+			}
+			if moreLoaded != tt.moreLoadedID {
+				t.Errorf("more loaded node = %s, want %s (scores %v)", moreLoaded, tt.moreLoadedID, scores)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerDefaultWeights(t *testing.T) {
+	lb := newTestLoadBalancer(t)
+
+	stats := &NodeStats{
+		TotalGPU:    4,
+		UsedGPU:     4,
+		TotalCPU:    resource.MustParse("10"),
+		TotalMemory: resource.MustParse("10Gi"),
+	}
+	if got := lb.calculateLoadScore(stats); got != 0.5 {
+		t.Errorf("load score with default weights = %.3f, want 0.500", got)
+	}
+
+	// A config that leaves every weight unset gets the default weights too
+	unset, err := NewLoadBalancer(nil, &LoadBalancerConfig{})
+	if err != nil {
+		t.Fatalf("NewLoadBalancer with unset weights failed: %v", err)
+	}
+	if got := unset.calculateLoadScore(stats); got != 0.5 {
+		t.Errorf("load score with unset weights = %.3f, want 0.500", got)
+	}
+}
+
+func TestInvalidLoadBalancerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights LoadScoreWeights
+	}{
+		{name: "sum too low", weights: LoadScoreWeights{GPU: 0.5, CPU: 0.2, Memory: 0.2}},
+		{name: "sum too high", weights: LoadScoreWeights{GPU: 0.8, CPU: 0.3, Memory: 0.2}},
+		{name: "negative weight", weights: LoadScoreWeights{GPU: 1.2, CPU: -0.2, Memory: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLoadBalancer(nil, &LoadBalancerConfig{Weights: tt.weights}); err == nil {
+				t.Error("expected invalid weights to be rejected")
+			}
+		})
+	}
+}
+
+func TestInvalidRebalanceConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*LoadBalancerConfig)
+	}{
+		{name: "underload above overload", modify: func(c *LoadBalancerConfig) {
+			c.OverloadThreshold = 0.5
+			c.UnderloadThreshold = 0.6
+		}},
+		{name: "underload equals overload", modify: func(c *LoadBalancerConfig) {
+			c.OverloadThreshold = 0.5
+			c.UnderloadThreshold = 0.5
+		}},
+		{name: "overload above one", modify: func(c *LoadBalancerConfig) { c.OverloadThreshold = 1.5 }},
+		{name: "negative move cap", modify: func(c *LoadBalancerConfig) { c.MaxMovesPerRebalance = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultLoadBalancerConfig()
+			tt.modify(config)
+			if _, err := NewLoadBalancer(nil, config); err == nil {
+				t.Error("expected invalid rebalance config to be rejected")
+			}
+		})
+	}
+}
+
+// gpuOnlyConfig scores nodes purely on GPU utilization so tests can set exact load scores
+func gpuOnlyConfig() *LoadBalancerConfig {
+	return &LoadBalancerConfig{Weights: LoadScoreWeights{GPU: 1.0}}
+}
+
+func TestLoadBalancerRebalanceClassification(t *testing.T) {
+	// With GPU-only weights, each node's load score is its GPU utilization.
+	objs := []client.Object{
+		newTestNode("full", "amd.com/gpu", 10),
+		newTestNode("busy", "amd.com/gpu", 10),
+		newTestNode("half", "amd.com/gpu", 10),
+		newTestNode("quiet", "amd.com/gpu", 10),
+		newTestNode("empty", "amd.com/gpu", 10),
+		newTestGPUPod("full-pod", "full", "amd.com/gpu", 9, corev1.PodRunning),
+		newTestGPUPod("busy-pod", "busy", "amd.com/gpu", 7, corev1.PodRunning),
+		newTestGPUPod("half-pod", "half", "amd.com/gpu", 5, corev1.PodRunning),
+		newTestGPUPod("quiet-pod", "quiet", "amd.com/gpu", 2, corev1.PodRunning),
+	}
+
+	tests := []struct {
+		name        string
+		overload    float64
+		underload   float64
+		overloaded  []string
+		underloaded []string
+	}{
+		{
+			name:        "defaults",
+			overloaded:  []string{"full"},
+			underloaded: []string{"empty", "quiet"},
+		},
+		{
+			name:        "aggressive",
+			overload:    0.6,
+			underload:   0.55,
+			overloaded:  []string{"busy", "full"},
+			underloaded: []string{"empty", "half", "quiet"},
+		},
+		{
+			name:        "conservative",
+			overload:    0.95,
+			underload:   0.1,
+			overloaded:  nil,
+			underloaded: []string{"empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := gpuOnlyConfig()
+			config.OverloadThreshold = tt.overload
+			config.UnderloadThreshold = tt.underload
+			lb := newTestLoadBalancerWithConfig(t, config, objs...)
+
+			if _, err := lb.RebalanceCluster(context.Background()); err != nil {
+				t.Fatalf("RebalanceCluster failed: %v", err)
+			}
+
+			overloaded, underloaded := lb.classifyNodes()
+			if !reflect.DeepEqual(overloaded, tt.overloaded) {
+				t.Errorf("overloaded = %v, want %v", overloaded, tt.overloaded)
+			}
+			if !reflect.DeepEqual(underloaded, tt.underloaded) {
+				t.Errorf("underloaded = %v, want %v", underloaded, tt.underloaded)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerRebalanceMoveCap(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxMoves int
+		want     int
+	}{
+		{name: "cap below candidates", maxMoves: 2, want: 2},
+		{name: "cap above candidates", maxMoves: 10, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// One overloaded node running eight single-GPU jobs and three
+			// empty nodes that could each take one of them.
+			objs := []client.Object{
+				newTestNode("hot", "amd.com/gpu", 8),
+				newTestNode("cold-a", "amd.com/gpu", 8),
+				newTestNode("cold-b", "amd.com/gpu", 8),
+				newTestNode("cold-c", "amd.com/gpu", 8),
+			}
+			for _, name := range []string{"job-1", "job-2", "job-3", "job-4", "job-5", "job-6", "job-7", "job-8"} {
+				objs = append(objs, newTestJobPod(name, "hot", 1))
+			}
+
+			config := gpuOnlyConfig()
+			config.MaxMovesPerRebalance = tt.maxMoves
+			lb := newTestLoadBalancerWithConfig(t, config, objs...)
+
+			if _, err := lb.RebalanceCluster(context.Background()); err != nil {
+				t.Fatalf("RebalanceCluster failed: %v", err)
+			}
+
+			var pods corev1.PodList
+			if err := lb.client.List(context.Background(), &pods); err != nil {
+				t.Fatalf("failed to list pods: %v", err)
+			}
+			if moved := 8 - len(pods.Items); moved != tt.want {
+				t.Errorf("evicted %d pods, want %d", moved, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerFindOptimalNodeSkipsCordonedAndTaintedNodes(t *testing.T) {
+	// The cordoned and tainted nodes are idle, so they would win on load
+	// score if they were considered.
+	cordoned := newTestNode("cordoned", "amd.com/gpu", 8)
+	cordoned.Spec.Unschedulable = true
+	tainted := newTestNode("tainted", "amd.com/gpu", 8)
+	tainted.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "training", Effect: corev1.TaintEffectNoSchedule}}
+	preferNoSchedule := newTestNode("prefer-no-schedule", "amd.com/gpu", 8)
+	preferNoSchedule.Spec.Taints = []corev1.Taint{{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}}
+
+	lb := newTestLoadBalancer(t,
+		cordoned,
+		tainted,
+		preferNoSchedule,
+		newTestNode("busy", "amd.com/gpu", 8),
+		newTestGPUPod("busy-pod", "busy", "amd.com/gpu", 4, corev1.PodRunning),
+		newTestGPUPod("prefer-pod", "prefer-no-schedule", "amd.com/gpu", 6, corev1.PodRunning),
+	)
+
+	job := &v1alpha1.KaiwoJob{ObjectMeta: metav1.ObjectMeta{Name: "job"}}
+	job.Spec.Gpus = 1
+
+	node, err := lb.FindOptimalNode(context.Background(), job)
+	if err != nil {
+		t.Fatalf("FindOptimalNode failed: %v", err)
+	}
+	if node != "busy" {
+		t.Errorf("FindOptimalNode = %q, want %q", node, "busy")
+	}
+
+	// A job whose pod template tolerates the taint may use the tainted node
+	job.Spec.Job = &batchv1.Job{}
+	job.Spec.Job.Spec.Template.Spec.Tolerations = []corev1.Toleration{{
+		Key:      "dedicated",
+		Operator: corev1.TolerationOpEqual,
+		Value:    "training",
+		Effect:   corev1.TaintEffectNoSchedule,
+	}}
+
+	node, err = lb.FindOptimalNode(context.Background(), job)
+	if err != nil {
+		t.Fatalf("FindOptimalNode failed: %v", err)
+	}
+	if node != "tainted" {
+		t.Errorf("FindOptimalNode with toleration = %q, want %q", node, "tainted")
+	}
+
+	pod := newTestJobPod("candidate", "", 1)
+	if lb.canNodeAccommodatePod(context.Background(), "cordoned", pod) {
+		t.Error("expected cordoned node to reject pod")
+	}
+	if lb.canNodeAccommodatePod(context.Background(), "tainted", pod) {
+		t.Error("expected tainted node to reject pod without toleration")
+	}
+	pod.Spec.Tolerations = job.Spec.Job.Spec.Template.Spec.Tolerations
+	if !lb.canNodeAccommodatePod(context.Background(), "tainted", pod) {
+		t.Error("expected tainted node to accept pod with toleration")
+	}
+}
+
+func TestLoadBalancerRebalanceDryRun(t *testing.T) {
+	newImbalancedCluster := func() []client.Object {
+		objs := []client.Object{
+			newTestNode("hot", "amd.com/gpu", 4),
+			newTestNode("cold-a", "amd.com/gpu", 4),
+			newTestNode("cold-b", "amd.com/gpu", 4),
+		}
+		for _, name := range []string{"job-1", "job-2", "job-3", "job-4"} {
+			objs = append(objs, newTestJobPod(name, "hot", 1))
+		}
+		return objs
+	}
+
+	tests := []struct {
+		name          string
+		dryRun        bool
+		wantEvictions int
+	}{
+		{name: "dry run", dryRun: true, wantEvictions: 0},
+		{name: "real run", dryRun: false, wantEvictions: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted, evicted []string
+			funcs := interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					deleted = append(deleted, obj.GetName())
+					return c.Delete(ctx, obj, opts...)
+				},
+				SubResourceCreate: func(ctx context.Context, c client.Client, subResource string, obj, sub client.Object, opts ...client.SubResourceCreateOption) error {
+					evicted = append(evicted, obj.GetName())
+					return c.SubResource(subResource).Create(ctx, obj, sub, opts...)
+				},
+			}
+
+			config := gpuOnlyConfig()
+			config.DryRun = tt.dryRun
+			lb := newTestLoadBalancerWithInterceptor(t, config, funcs, newImbalancedCluster()...)
+
+			plan, err := lb.RebalanceCluster(context.Background())
+			if err != nil {
+				t.Fatalf("RebalanceCluster failed: %v", err)
+			}
+
+			want := []RebalanceMove{
+				{FromNode: "hot", ToNode: "cold-a", Pod: client.ObjectKey{Namespace: "default", Name: "job-1"}},
+				{FromNode: "hot", ToNode: "cold-b", Pod: client.ObjectKey{Namespace: "default", Name: "job-2"}},
+			}
+			if len(plan) != len(want) {
+				t.Fatalf("plan has %d moves, want %d: %+v", len(plan), len(want), plan)
+			}
+			for i, move := range plan {
+				if move.Reason == "" {
+					t.Errorf("move %d has no reason", i)
+				}
+				move.Reason = ""
+				if move != want[i] {
+					t.Errorf("move %d = %+v, want %+v", i, move, want[i])
+				}
+			}
+
+			if len(deleted) != 0 {
+				t.Errorf("Delete called %d times (%v), want 0", len(deleted), deleted)
+			}
+			if len(evicted) != tt.wantEvictions {
+				t.Errorf("evicted %d pods (%v), want %d", len(evicted), evicted, tt.wantEvictions)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerRebalanceSkipsDisruptionBudgetRejections(t *testing.T) {
+	objs := []client.Object{
+		newTestNode("hot", "amd.com/gpu", 4),
+		newTestNode("cold", "amd.com/gpu", 4),
+	}
+	for _, name := range []string{"job-1", "job-2", "job-3", "job-4"} {
+		objs = append(objs, newTestJobPod(name, "hot", 1))
+	}
+
+	// job-1 and job-2 are protected by a disruption budget
+	protected := map[string]bool{"job-1": true, "job-2": true}
+	var attempts []string
+	funcs := interceptor.Funcs{
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResource string, obj, sub client.Object, opts ...client.SubResourceCreateOption) error {
+			attempts = append(attempts, obj.GetName())
+			if protected[obj.GetName()] {
+				return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+			}
+			return c.SubResource(subResource).Create(ctx, obj, sub, opts...)
+		},
+	}
+
+	lb := newTestLoadBalancerWithInterceptor(t, gpuOnlyConfig(), funcs, objs...)
+
+	moves, err := lb.RebalanceCluster(context.Background())
+	if err != nil {
+		t.Fatalf("RebalanceCluster failed: %v", err)
+	}
+
+	if len(moves) != 1 || moves[0].Pod.Name != "job-3" || moves[0].ToNode != "cold" {
+		t.Errorf("moves = %+v, want job-3 moved to cold", moves)
+	}
+	if want := []string{"job-1", "job-2", "job-3"}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("eviction attempts = %v, want %v", attempts, want)
+	}
+
+	var pod corev1.Pod
+	for _, name := range []string{"job-1", "job-2"} {
+		if err := lb.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, &pod); err != nil {
+			t.Errorf("protected pod %s was removed: %v", name, err)
+		}
+	}
+}
+
+func TestLoadBalancerRebalanceStopsWhenAllEvictionsRejected(t *testing.T) {
+	lb := newTestLoadBalancerWithInterceptor(t, gpuOnlyConfig(), interceptor.Funcs{
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResource string, obj, sub client.Object, opts ...client.SubResourceCreateOption) error {
+			return apierrors.NewTooManyRequests("disruption budget exhausted", 0)
+		},
+	},
+		newTestNode("hot", "amd.com/gpu", 2),
+		newTestNode("cold", "amd.com/gpu", 2),
+		newTestJobPod("job-1", "hot", 1),
+		newTestJobPod("job-2", "hot", 1),
+	)
+
+	moves, err := lb.RebalanceCluster(context.Background())
+	if err != nil {
+		t.Fatalf("RebalanceCluster failed: %v", err)
+	}
+	if len(moves) != 0 {
+		t.Errorf("moves = %+v, want none", moves)
+	}
+}